@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/sejo412/gophkeeper/internal/client"
+	"github.com/sejo412/gophkeeper/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// addCmd represents the add command
+var addCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Add record",
+	Long: `
+Encrypt and add new record. Field values are taken from flags,
+one of them may be read from stdin by passing "-" as value.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		t, err := parsedRecordType()
+		if err != nil {
+			return err
+		}
+		values, err := fieldsFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+		record := models.Record{}
+		if err = client.SetFields(&record, t, values); err != nil {
+			return err
+		}
+		c, err := connectClient()
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = c.Close()
+		}()
+		if err = c.Add(cmd.Context(), t, record); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Created %s\n", t.String())
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(addCmd)
+	addServerFlag(addCmd)
+	addTypeFlag(addCmd, true)
+	addFieldFlags(addCmd)
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// deleteCmd represents the delete command
+var deleteCmd = &cobra.Command{
+	Use:          "delete",
+	Short:        "Delete record",
+	Long:         "\nMove record by type and ID to trash, see trash command",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		t, err := parsedRecordType()
+		if err != nil {
+			return err
+		}
+		c, err := connectClient()
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = c.Close()
+		}()
+		id := models.ID(recordID)
+		if err = c.Delete(cmd.Context(), t, id); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Moved %s %d to trash\n", t.String(), id)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(deleteCmd)
+	addServerFlag(deleteCmd)
+	addTypeFlag(deleteCmd, true)
+	addIDFlag(deleteCmd)
+}
@@ -0,0 +1,107 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/sejo412/gophkeeper/internal/client"
+	"github.com/sejo412/gophkeeper/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// devicesCmd represents the devices command
+var devicesCmd = &cobra.Command{
+	Use:   "devices",
+	Short: "Manage devices of user",
+	Long:  "\nList, approve and remove devices of user",
+}
+
+// devicesListCmd represents the devices list command
+var devicesListCmd = &cobra.Command{
+	Use:          "list",
+	Short:        "List devices",
+	Long:         "\nList devices of user and devices waiting for approval",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, err := parsedOutputFormat()
+		if err != nil {
+			return err
+		}
+		c, err := connectClient()
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = c.Close()
+		}()
+		devices, pending, err := c.Devices(cmd.Context())
+		if err != nil {
+			return err
+		}
+		return client.WriteDevices(cmd.OutOrStdout(), format, devices, pending)
+	},
+}
+
+// devicesApproveCmd represents the devices approve command
+var devicesApproveCmd = &cobra.Command{
+	Use:   "approve <code> <fingerprint>",
+	Short: "Approve new device",
+	Long: "\nApprove new device by code and device fingerprint printed by enroll command and give it access to records." +
+		"\nDevice with other key is refused",
+	Args:         cobra.ExactArgs(2),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := connectClient()
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = c.Close()
+		}()
+		device, err := c.ApproveDevice(cmd.Context(), args[0], args[1])
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(
+			cmd.ErrOrStderr(), "Approved device %s with fingerprint %s\n",
+			device.Cn, client.KeyFingerprint(device.PublicKey),
+		)
+		return nil
+	},
+}
+
+// devicesRemoveCmd represents the devices remove command
+var devicesRemoveCmd = &cobra.Command{
+	Use:          "remove <id>",
+	Short:        "Remove device",
+	Long:         "\nRemove device by ID, removed device loses access to the server",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		id, err := strconv.Atoi(args[0])
+		if err != nil {
+			return fmt.Errorf("invalid device ID %q: %w", args[0], err)
+		}
+		c, err := connectClient()
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = c.Close()
+		}()
+		if err = c.RemoveDevice(cmd.Context(), models.DeviceID(id)); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Removed device: %d\n", id)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(devicesCmd)
+	devicesCmd.AddCommand(devicesListCmd, devicesApproveCmd, devicesRemoveCmd)
+	addServerFlag(devicesListCmd)
+	addOutputFlag(devicesListCmd)
+	addServerFlag(devicesApproveCmd)
+	addServerFlag(devicesRemoveCmd)
+}
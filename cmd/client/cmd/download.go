@@ -0,0 +1,39 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// downloadCmd represents the download command
+var downloadCmd = &cobra.Command{
+	Use:          "download",
+	Short:        "Download file",
+	Long:         "\nDownload data of bin record to file. Interrupted download resumes on next run",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := connectClient()
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = c.Close()
+		}()
+		id := models.ID(recordID)
+		if err = c.DownloadBin(cmd.Context(), id, filePath); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Downloaded %s %d: %s\n", models.RecordBin.String(), id, filePath)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(downloadCmd)
+	addServerFlag(downloadCmd)
+	addIDFlag(downloadCmd)
+	downloadCmd.Flags().StringVarP(&filePath, "file", "f", "", "path to file")
+	_ = downloadCmd.MarkFlagRequired("file")
+}
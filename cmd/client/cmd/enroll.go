@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+
+	"github.com/sejo412/gophkeeper/internal/client"
+	"github.com/sejo412/gophkeeper/internal/constants"
+	"github.com/spf13/cobra"
+)
+
+// enrollCmd represents the enroll command
+var enrollCmd = &cobra.Command{
+	Use:   "enroll",
+	Short: "Add this device to existing user",
+	Long: `
+Add this device to existing user. Command prints code and waits until
+device is approved by "devices approve <code>" on another device of user.
+All certificates will be overwritten!
+`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := client.NewClient(
+			client.Config{
+				PublicAddress: publicHost,
+				CacheDir:      cacheDir,
+				CAFingerprint: caFingerprint,
+				CAFile:        caFile,
+				PublicTLS:     publicTLS,
+			},
+		)
+		ctx, cancel := context.WithTimeout(cmd.Context(), constants.EnrollmentTTL)
+		defer cancel()
+		if err := c.Enroll(
+			ctx, userName, deviceName, func(code, fingerprint string) {
+				_, _ = fmt.Fprintf(
+					cmd.ErrOrStderr(), "Enrollment code: %s\nDevice fingerprint: %s\n"+
+						"Approve on another device: devices approve %s %s\nWaiting for approval...\n",
+					code, fingerprint, code, fingerprint,
+				)
+			},
+		); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(cmd.ErrOrStderr(), "Device approved")
+		printCAFingerprint(cmd, c)
+		return nil
+	},
+}
+
+func init() {
+	defaultPublicServer := net.JoinHostPort(constants.DefaultServerHost, strconv.Itoa(constants.DefaultPublicPort))
+	rootCmd.AddCommand(enrollCmd)
+	enrollCmd.Flags().StringVarP(
+		&publicHost, "server", "s", defaultPublicServer, "public server address",
+	)
+	enrollCmd.Flags().StringVarP(&userName, "user", "u", "", "user name")
+	enrollCmd.Flags().StringVarP(&deviceName, "name", "n", "", "unique name of this device")
+	_ = enrollCmd.MarkFlagRequired("user")
+	_ = enrollCmd.MarkFlagRequired("name")
+	addTrustFlags(enrollCmd)
+}
@@ -1,8 +1,30 @@
 package cmd
 
+import (
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/client"
+)
+
 var (
-	publicHost  string
-	privateHost string
-	userName    string
-	cacheDir    string
+	publicHost    string
+	privateHost   string
+	userName      string
+	deviceName    string
+	invite        string
+	caFingerprint string
+	caFile        string
+	publicTLS     bool
+	cacheDir      string
+	recordType    string
+	recordID      int
+	revision      int64
+	recordVersion int64
+	output        string
+	filePath      string
+	renewBefore   time.Duration
+	useReplica    bool
+	mergeChanges  bool
+	watchList     bool
+	fieldValues   = make(map[client.Field]*string)
 )
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"github.com/sejo412/gophkeeper/internal/client"
+	"github.com/sejo412/gophkeeper/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// getCmd represents the get command
+var getCmd = &cobra.Command{
+	Use:          "get",
+	Short:        "Get record",
+	Long:         "\nGet and decrypt record by type and ID",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		t, err := parsedRecordType()
+		if err != nil {
+			return err
+		}
+		format, err := parsedOutputFormat()
+		if err != nil {
+			return err
+		}
+		c, err := connectClient()
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = c.Close()
+		}()
+		record, err := c.Get(cmd.Context(), t, models.ID(recordID))
+		if err != nil {
+			return err
+		}
+		return client.WriteRecord(cmd.OutOrStdout(), format, t, record)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(getCmd)
+	addServerFlag(getCmd)
+	addTypeFlag(getCmd, true)
+	addIDFlag(getCmd)
+	addOutputFlag(getCmd)
+}
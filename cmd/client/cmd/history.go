@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"github.com/sejo412/gophkeeper/internal/client"
+	"github.com/sejo412/gophkeeper/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// historyCmd represents the history command
+var historyCmd = &cobra.Command{
+	Use:   "history",
+	Short: "Show previous versions of record",
+	Long: `
+List previous versions of record saved by server on every update, newest first.
+With --version all fields of given version are shown. Versions of binary records are not kept.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		t, err := parsedRecordType()
+		if err != nil {
+			return err
+		}
+		format, err := parsedOutputFormat()
+		if err != nil {
+			return err
+		}
+		c, err := connectClient()
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = c.Close()
+		}()
+		id := models.ID(recordID)
+		if cmd.Flags().Changed("version") {
+			v, err := c.Version(cmd.Context(), t, id, recordVersion)
+			if err != nil {
+				return err
+			}
+			return client.WriteRecord(cmd.OutOrStdout(), format, t, v.Record)
+		}
+		versions, err := c.History(cmd.Context(), t, id)
+		if err != nil {
+			return err
+		}
+		return client.WriteVersions(cmd.OutOrStdout(), format, t, versions)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+	addServerFlag(historyCmd)
+	addTypeFlag(historyCmd, true)
+	addIDFlag(historyCmd)
+	addOutputFlag(historyCmd)
+	historyCmd.Flags().Int64Var(&recordVersion, "version", 0, "version number to show")
+}
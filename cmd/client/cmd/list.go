@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"github.com/sejo412/gophkeeper/internal/client"
+	"github.com/sejo412/gophkeeper/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// listCmd represents the list command
+var listCmd = &cobra.Command{
+	Use:          "list",
+	Short:        "List records",
+	Long:         "\nList ID and Meta of all records or records of one type",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, err := parsedOutputFormat()
+		if err != nil {
+			return err
+		}
+		c, err := connectClient()
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = c.Close()
+		}()
+		if recordType == "" {
+			records, err := c.ListAll(cmd.Context())
+			if err != nil {
+				return err
+			}
+			return client.WriteRecords(cmd.OutOrStdout(), format, records, models.RecordTypes...)
+		}
+		t, err := parsedRecordType()
+		if err != nil {
+			return err
+		}
+		records, err := c.List(cmd.Context(), t)
+		if err != nil {
+			return err
+		}
+		return client.WriteRecords(cmd.OutOrStdout(), format, records, t)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(listCmd)
+	addServerFlag(listCmd)
+	addTypeFlag(listCmd, false)
+	addOutputFlag(listCmd)
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/sejo412/gophkeeper/internal/client"
+	"github.com/spf13/cobra"
+)
+
+// passwdCmd represents the passwd command
+var passwdCmd = &cobra.Command{
+	Use:          "passwd",
+	Short:        "Change master password",
+	Long:         "\nChange master password which protects private key (or protect legacy unencrypted key)",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := client.NewClient(
+			client.Config{
+				CacheDir: cacheDir,
+			},
+		)
+		if err := c.Passwd(); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(cmd.ErrOrStderr(), "Master password changed")
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(passwdCmd)
+}
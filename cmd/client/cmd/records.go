@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+
+	"github.com/sejo412/gophkeeper/internal/client"
+	"github.com/sejo412/gophkeeper/internal/constants"
+	"github.com/sejo412/gophkeeper/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// stdinValue is a field value which means "read value from stdin".
+const stdinValue = "-"
+
+// recordFields contains all fields which can be set by command line flags.
+var recordFields = []client.Field{
+	client.FieldLogin,
+	client.FieldPassword,
+	client.FieldText,
+	client.FieldData,
+	client.FieldNumber,
+	client.FieldName,
+	client.FieldDate,
+	client.FieldCVV,
+	client.FieldMeta,
+}
+
+func connectClient() (*client.Client, error) {
+	c := client.NewClient(
+		client.Config{
+			PrivateAddress: privateHost,
+			CacheDir:       cacheDir,
+			RenewBefore:    renewBefore,
+			Replica:        useReplica,
+		},
+	)
+	if err := c.Connect(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func parsedRecordType() (models.RecordType, error) {
+	return client.ParseRecordType(recordType)
+}
+
+func parsedOutputFormat() (client.OutputFormat, error) {
+	return client.ParseOutputFormat(output)
+}
+
+func fieldsFromFlags(cmd *cobra.Command) (map[client.Field][]byte, error) {
+	values := make(map[client.Field][]byte)
+	stdinUsed := false
+	for _, field := range recordFields {
+		if !cmd.Flags().Changed(field.Flag()) {
+			continue
+		}
+		value := *fieldValues[field]
+		if value != stdinValue {
+			values[field] = []byte(value)
+			continue
+		}
+		if stdinUsed {
+			return nil, errors.New("only one field can be read from stdin")
+		}
+		stdinUsed = true
+		data, err := io.ReadAll(cmd.InOrStdin())
+		if err != nil {
+			return nil, fmt.Errorf("failed to read %s from stdin: %w", field.String(), err)
+		}
+		if field != client.FieldData {
+			data = bytes.TrimSuffix(data, []byte("\n"))
+		}
+		values[field] = data
+	}
+	return values, nil
+}
+
+func addServerFlag(cmd *cobra.Command) {
+	defaultPrivateHost := net.JoinHostPort(constants.DefaultServerHost, strconv.Itoa(constants.DefaultPrivatePort))
+	cmd.Flags().StringVarP(&privateHost, "server", "s", defaultPrivateHost, "private server address")
+}
+
+func addTypeFlag(cmd *cobra.Command, required bool) {
+	cmd.Flags().StringVarP(&recordType, "type", "t", "", "record type (password, text, bin, bank)")
+	if required {
+		_ = cmd.MarkFlagRequired("type")
+	}
+}
+
+func addIDFlag(cmd *cobra.Command) {
+	cmd.Flags().IntVarP(&recordID, "id", "i", 0, "record ID")
+	_ = cmd.MarkFlagRequired("id")
+}
+
+func addFieldFlags(cmd *cobra.Command) {
+	for _, field := range recordFields {
+		if _, ok := fieldValues[field]; !ok {
+			fieldValues[field] = new(string)
+		}
+		cmd.Flags().StringVar(
+			fieldValues[field], field.Flag(), "",
+			fmt.Sprintf("%s value (%q to read from stdin)", field.String(), stdinValue),
+		)
+	}
+}
+
+func addMetaFlag(cmd *cobra.Command) {
+	if _, ok := fieldValues[client.FieldMeta]; !ok {
+		fieldValues[client.FieldMeta] = new(string)
+	}
+	cmd.Flags().StringVar(fieldValues[client.FieldMeta], client.FieldMeta.Flag(), "", client.FieldMeta.String()+" value")
+}
+
+func addOutputFlag(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(
+		&output, "output", "o", client.OutputTableName,
+		fmt.Sprintf("output format (%s, %s, %s)", client.OutputTableName, client.OutputJSONName, client.OutputYAMLName),
+	)
+}
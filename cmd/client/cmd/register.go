@@ -1,6 +1,7 @@
 package cmd
 
 import (
+	"fmt"
 	"net"
 	"strconv"
 
@@ -25,11 +26,22 @@ All certificates will be overwritten!
 			client.Config{
 				PublicAddress: publicHost,
 				CacheDir:      cacheDir,
+				CAFingerprint: caFingerprint,
+				CAFile:        caFile,
+				PublicTLS:     publicTLS,
 			},
 		)
-		if err := c.Register(userName); err != nil {
+		if err := c.RegisterWithOptions(
+			cmd.Context(), userName, client.RegisterOptions{
+				Invite: invite,
+				Pending: func(code string) {
+					_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Waiting for approval by server admin, code: %s\n", code)
+				},
+			},
+		); err != nil {
 			panic(err)
 		}
+		printCAFingerprint(cmd, c)
 	},
 }
 
@@ -40,5 +52,28 @@ func init() {
 		&publicHost, "server", "s", defaultPublicServer, "public server address",
 	)
 	registerCmd.Flags().StringVarP(&userName, "user", "u", "", "user name")
+	registerCmd.Flags().StringVar(&invite, "invite", "", "invite token given by server admin")
 	_ = registerCmd.MarkFlagRequired("user")
+	addTrustFlags(registerCmd)
+}
+
+func addTrustFlags(cmd *cobra.Command) {
+	cmd.Flags().StringVar(
+		&caFingerprint, "ca-fingerprint", "", "expected SHA-256 fingerprint of server CA (printed by server init)",
+	)
+	cmd.Flags().StringVar(&caFile, "ca-file", "", "pinned server CA certificate")
+	cmd.Flags().BoolVar(&publicTLS, "tls", false, "connect to public server over TLS")
+}
+
+// printCAFingerprint shows fingerprint of CA trusted on first use, so user can compare it
+// with fingerprint printed by server.
+func printCAFingerprint(cmd *cobra.Command, c *client.Client) {
+	if c.IsCAPinned() {
+		return
+	}
+	fingerprint, err := c.CAFingerprint()
+	if err != nil {
+		return
+	}
+	_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Server CA trusted on first use, fingerprint (SHA-256): %s\n", fingerprint)
 }
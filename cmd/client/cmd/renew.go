@@ -0,0 +1,44 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/client"
+	"github.com/spf13/cobra"
+)
+
+// renewCmd represents the renew command
+var renewCmd = &cobra.Command{
+	Use:          "renew",
+	Short:        "Renew client certificate",
+	Long:         "\nRenew client certificate of this device before it expires",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c := client.NewClient(
+			client.Config{
+				PrivateAddress: privateHost,
+				CacheDir:       cacheDir,
+				RenewBefore:    -1,
+			},
+		)
+		if err := c.Connect(); err != nil {
+			return err
+		}
+		defer func() {
+			_ = c.Close()
+		}()
+		cert, err := c.Renew(cmd.Context())
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Certificate renewed, valid until %s\n",
+			cert.NotAfter.Format(time.DateOnly))
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(renewCmd)
+	addServerFlag(renewCmd)
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// restoreCmd represents the restore command
+var restoreCmd = &cobra.Command{
+	Use:   "restore <version>",
+	Short: "Restore previous version of record",
+	Long: `
+Replace record by its previous version listed by history command.
+Replaced version is kept in history as new version, so restore can be undone.`,
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		number, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid version %q: %w", args[0], err)
+		}
+		t, err := parsedRecordType()
+		if err != nil {
+			return err
+		}
+		c, err := connectClient()
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = c.Close()
+		}()
+		id := models.ID(recordID)
+		if err = c.Restore(cmd.Context(), t, id, number); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Restored %s %d to version %d\n", t.String(), id, number)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(restoreCmd)
+	addServerFlag(restoreCmd)
+	addTypeFlag(restoreCmd, true)
+	addIDFlag(restoreCmd)
+}
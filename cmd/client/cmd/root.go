@@ -2,9 +2,7 @@ package cmd
 
 import (
 	"fmt"
-	"net"
 	"os"
-	"strconv"
 
 	"github.com/sejo412/gophkeeper/internal/client"
 	"github.com/sejo412/gophkeeper/internal/constants"
@@ -21,6 +19,8 @@ var rootCmd = &cobra.Command{
 			client.Config{
 				PrivateAddress: privateHost,
 				CacheDir:       cacheDir,
+				RenewBefore:    renewBefore,
+				Replica:        useReplica,
 			},
 		)
 		if err := c.Run(); err != nil {
@@ -39,7 +39,10 @@ func Execute() {
 }
 
 func init() {
-	defaultPrivateHost := net.JoinHostPort(constants.DefaultServerHost, strconv.Itoa(constants.DefaultPrivatePort))
-	rootCmd.Flags().StringVarP(&privateHost, "server", "s", defaultPrivateHost, "private server address")
+	addServerFlag(rootCmd)
 	rootCmd.PersistentFlags().StringVarP(&cacheDir, "dir", "d", client.DefaultCacheDir(), "cache directory")
+	rootCmd.PersistentFlags().DurationVar(&renewBefore, "renew-before", constants.CertRenewBefore,
+		"renew client certificate expiring within this time on connect, negative disables renewal")
+	rootCmd.PersistentFlags().BoolVar(&useReplica, "replica", true,
+		"keep local replica of encrypted records for offline usage")
 }
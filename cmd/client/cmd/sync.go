@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+
+	"github.com/sejo412/gophkeeper/internal/client"
+	"github.com/spf13/cobra"
+)
+
+// syncCmd represents the sync command
+var syncCmd = &cobra.Command{
+	Use:   "sync",
+	Short: "Synchronize local replica with server",
+	Long: `
+Send changes made offline to server and apply changes made on server since last synchronization.
+Replica is also synchronized on every connect, this command fails if server is unreachable.
+Offline update of record changed on server since is kept, both versions are shown and merged
+version is saved after confirmation or with --merge, otherwise server version is kept.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if !useReplica {
+			return fmt.Errorf("replica is disabled by --replica=false")
+		}
+		c, err := connectClient()
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = c.Close()
+		}()
+		result, err := c.Sync(cmd.Context())
+		if err != nil {
+			return err
+		}
+		for _, rejected := range result.Rejected {
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Rejected: %v\n", rejected)
+		}
+		in := bufio.NewReader(cmd.InOrStdin())
+		for _, conflict := range result.Conflicts {
+			if err = client.WriteConflict(cmd.ErrOrStderr(), conflict); err != nil {
+				return err
+			}
+			save := confirmMerge(cmd, in)
+			if err = c.ResolveConflict(cmd.Context(), conflict, save); err != nil {
+				return err
+			}
+			if !save {
+				_, _ = fmt.Fprintf(
+					cmd.ErrOrStderr(), "Kept server version of %s %d\n", conflict.Type.String(), conflict.ID,
+				)
+			}
+		}
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Synchronized, received %d changes\n", result.Pulled)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(syncCmd)
+	addServerFlag(syncCmd)
+	syncCmd.Flags().BoolVar(&mergeChanges, "merge", false, "merge offline updates with records changed on server")
+}
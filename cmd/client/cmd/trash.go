@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/sejo412/gophkeeper/internal/client"
+	"github.com/sejo412/gophkeeper/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// trashCmd represents the trash command
+var trashCmd = &cobra.Command{
+	Use:   "trash",
+	Short: "Manage deleted records",
+	Long: `
+List, restore and permanently delete records moved to trash by delete command.
+Server permanently deletes records kept in trash longer than its retention.`,
+}
+
+// trashListCmd represents the trash list command
+var trashListCmd = &cobra.Command{
+	Use:          "list",
+	Short:        "List deleted records",
+	Long:         "\nList records in trash, last deleted first",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, err := parsedOutputFormat()
+		if err != nil {
+			return err
+		}
+		c, err := connectClient()
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = c.Close()
+		}()
+		items, err := c.Trash(cmd.Context())
+		if err != nil {
+			return err
+		}
+		return client.WriteTrash(cmd.OutOrStdout(), format, items)
+	},
+}
+
+// trashRestoreCmd represents the trash restore command
+var trashRestoreCmd = &cobra.Command{
+	Use:          "restore",
+	Short:        "Restore deleted record",
+	Long:         "\nMove record by type and ID back from trash",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		t, err := parsedRecordType()
+		if err != nil {
+			return err
+		}
+		c, err := connectClient()
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = c.Close()
+		}()
+		id := models.ID(recordID)
+		if err = c.RestoreTrash(cmd.Context(), t, id); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Restored %s %d from trash\n", t.String(), id)
+		return nil
+	},
+}
+
+// trashEmptyCmd represents the trash empty command
+var trashEmptyCmd = &cobra.Command{
+	Use:          "empty",
+	Short:        "Empty trash",
+	Long:         "\nPermanently delete all records in trash, deleted records can't be restored",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := connectClient()
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = c.Close()
+		}()
+		if err = c.EmptyTrash(cmd.Context()); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(cmd.ErrOrStderr(), "Trash emptied")
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(trashCmd)
+	trashCmd.AddCommand(trashListCmd, trashRestoreCmd, trashEmptyCmd)
+	addServerFlag(trashListCmd)
+	addOutputFlag(trashListCmd)
+	addServerFlag(trashRestoreCmd)
+	addTypeFlag(trashRestoreCmd, true)
+	addIDFlag(trashRestoreCmd)
+	addServerFlag(trashEmptyCmd)
+}
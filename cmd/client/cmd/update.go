@@ -0,0 +1,104 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/sejo412/gophkeeper/internal/client"
+	"github.com/sejo412/gophkeeper/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// updateCmd represents the update command
+var updateCmd = &cobra.Command{
+	Use:   "update",
+	Short: "Update record",
+	Long: `
+Update fields of existing record. Only fields passed by flags are changed,
+one of them may be read from stdin by passing "-" as value. If record was changed
+on server since it was read or since --revision shown by get and list, both versions
+are shown and merged version is saved after confirmation or with --merge.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		t, err := parsedRecordType()
+		if err != nil {
+			return err
+		}
+		values, err := fieldsFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+		if len(values) == 0 {
+			return errors.New("nothing to update")
+		}
+		c, err := connectClient()
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = c.Close()
+		}()
+		id := models.ID(recordID)
+		record, err := c.Get(cmd.Context(), t, id)
+		if err != nil {
+			return err
+		}
+		base := record
+		if err = client.SetFields(&record, t, values); err != nil {
+			return err
+		}
+		if cmd.Flags().Changed("revision") {
+			if err = client.SetRevision(&record, t, revision); err != nil {
+				return err
+			}
+		}
+		if err = c.Update(cmd.Context(), t, id, record); errors.Is(err, client.ErrConflict) {
+			err = mergeConflict(cmd, c, t, id, base, record)
+		}
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Updated %s: %d\n", t.String(), id)
+		return nil
+	},
+}
+
+// mergeConflict shows local and server versions of record and saves merged version if confirmed.
+func mergeConflict(
+	cmd *cobra.Command, c *client.Client, t models.RecordType, id models.ID, base, local models.Record,
+) error {
+	conflict, err := c.Conflict(cmd.Context(), t, id, base, local)
+	if err != nil {
+		return err
+	}
+	if err = client.WriteConflict(cmd.ErrOrStderr(), conflict); err != nil {
+		return err
+	}
+	if !confirmMerge(cmd, bufio.NewReader(cmd.InOrStdin())) {
+		return client.ErrConflict
+	}
+	return c.Update(cmd.Context(), t, id, conflict.Merged)
+}
+
+// confirmMerge asks whether merged version is saved, it is saved without asking with --merge.
+func confirmMerge(cmd *cobra.Command, in *bufio.Reader) bool {
+	if mergeChanges {
+		return true
+	}
+	_, _ = fmt.Fprint(cmd.ErrOrStderr(), "Save merged version? [y/N]: ")
+	answer, _ := in.ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+func init() {
+	rootCmd.AddCommand(updateCmd)
+	addServerFlag(updateCmd)
+	addTypeFlag(updateCmd, true)
+	addIDFlag(updateCmd)
+	addFieldFlags(updateCmd)
+	updateCmd.Flags().BoolVar(&mergeChanges, "merge", false, "merge with record changed on server without confirmation")
+	updateCmd.Flags().Int64Var(&revision, "revision", 0, "revision of record shown by get or list, read one by default")
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/sejo412/gophkeeper/internal/client"
+	"github.com/sejo412/gophkeeper/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// uploadCmd represents the upload command
+var uploadCmd = &cobra.Command{
+	Use:          "upload",
+	Short:        "Upload file",
+	Long:         "\nUpload file as bin record. Interrupted upload of the same file resumes on next run",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := connectClient()
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = c.Close()
+		}()
+		id, err := c.UploadBin(
+			cmd.Context(), filePath, models.ID(recordID), models.Meta(*fieldValues[client.FieldMeta]), revision,
+		)
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Uploaded %s: %d\n", models.RecordBin.String(), id)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(uploadCmd)
+	addServerFlag(uploadCmd)
+	uploadCmd.Flags().StringVarP(&filePath, "file", "f", "", "path to file")
+	_ = uploadCmd.MarkFlagRequired("file")
+	uploadCmd.Flags().IntVarP(&recordID, "id", "i", 0, "record ID to replace (new record if not set)")
+	uploadCmd.Flags().Int64Var(
+		&revision, "revision", 0, "revision of replaced record shown by get or list, any revision if not set",
+	)
+	addMetaFlag(uploadCmd)
+}
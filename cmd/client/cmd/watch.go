@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+	"os/signal"
+	"slices"
+	"syscall"
+
+	"github.com/sejo412/gophkeeper/internal/client"
+	"github.com/sejo412/gophkeeper/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// watchCmd represents the watch command
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Watch changes of records",
+	Long: `
+Print changes of records made on server, also by other devices, until interrupted.
+Local replica is synchronized on every change, with --list records are listed again.`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		format, err := parsedOutputFormat()
+		if err != nil {
+			return err
+		}
+		types := models.RecordTypes
+		if recordType != "" {
+			t, err := parsedRecordType()
+			if err != nil {
+				return err
+			}
+			types = []models.RecordType{t}
+		}
+		c, err := connectClient()
+		if err != nil {
+			return err
+		}
+		defer func() {
+			_ = c.Close()
+		}()
+		ctx, stop := signal.NotifyContext(cmd.Context(), syscall.SIGINT, syscall.SIGTERM)
+		defer stop()
+		return c.Watch(
+			ctx, func(change models.Change) error {
+				if !slices.Contains(types, change.Type) {
+					return nil
+				}
+				_, _ = fmt.Fprintf(
+					cmd.OutOrStdout(), "%s %s %d (revision %d)\n",
+					change.Op.String(), change.Type.String(), change.ID, change.Revision,
+				)
+				if !watchList {
+					return nil
+				}
+				records, err := c.ListAll(ctx)
+				if err != nil {
+					return err
+				}
+				return client.WriteRecords(cmd.OutOrStdout(), format, records, types...)
+			},
+		)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+	addServerFlag(watchCmd)
+	addTypeFlag(watchCmd, false)
+	addOutputFlag(watchCmd)
+	watchCmd.Flags().BoolVar(&watchList, "list", false, "list records again on every change")
+}
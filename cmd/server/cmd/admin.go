@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+	"github.com/sejo412/gophkeeper/internal/server"
+)
+
+// newAdminServer returns Server for admin commands working with its cache dir and storage.
+func newAdminServer() *server.Server {
+	return server.NewServer(
+		server.Config{
+			CacheDir:   cacheDir,
+			StorageDSN: storageDSN,
+		},
+	)
+}
+
+// withAdmin runs fn with Admin of running server or database of stopped server.
+func withAdmin(fn func(admin server.Admin) error) error {
+	admin, err := newAdminServer().Admin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = admin.Close()
+	}()
+	return fn(admin)
+}
+
+// certificateStatus returns state of certificate for listing.
+func certificateStatus(c models.Certificate) string {
+	switch {
+	case c.Revoked:
+		return "revoked"
+	case time.Now().After(c.NotAfter):
+		return "expired"
+	default:
+		return "valid"
+	}
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/server"
+	"github.com/spf13/cobra"
+)
+
+// certsCmd represents the certs command
+var certsCmd = &cobra.Command{
+	Use:   "certs",
+	Short: "Manage client certificates",
+	Long:  "\nList issued client certificates, see also revoke and crl",
+}
+
+// certsListCmd represents the certs list command
+var certsListCmd = &cobra.Command{
+	Use:          "list",
+	Short:        "List client certificates",
+	Long:         "\nList issued client certificates with their state",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withAdmin(
+			func(admin server.Admin) error {
+				certificates, err := admin.Certificates(cmd.Context())
+				if err != nil {
+					return err
+				}
+				tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+				_, _ = fmt.Fprintln(tw, "SERIAL\tNAME\tUSER ID\tISSUED\tNOT AFTER\tSTATE")
+				for _, c := range certificates {
+					_, _ = fmt.Fprintf(
+						tw, "%s\t%s\t%d\t%s\t%s\t%s\n", c.Serial, c.Cn, c.UserID, c.Issued.Format(time.DateTime),
+						c.NotAfter.Format(time.DateTime), certificateStatus(c),
+					)
+				}
+				return tw.Flush()
+			},
+		)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(certsCmd)
+	certsCmd.AddCommand(certsListCmd)
+}
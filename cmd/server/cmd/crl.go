@@ -0,0 +1,35 @@
+package cmd
+
+import (
+	"encoding/pem"
+
+	"github.com/sejo412/gophkeeper/internal/constants"
+	"github.com/sejo412/gophkeeper/internal/helpers"
+	"github.com/spf13/cobra"
+)
+
+// crlCmd represents the crl command
+var crlCmd = &cobra.Command{
+	Use:          "crl",
+	Short:        "Generate certificate revocation list",
+	Long:         "\nGenerate PEM encoded certificate revocation list signed by CA",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s := newAdminServer()
+		crl, err := s.CRL(cmd.Context())
+		if err != nil {
+			return err
+		}
+		out := pem.EncodeToMemory(&pem.Block{Type: constants.PemCRLType, Bytes: crl})
+		if outFile == "" {
+			_, err = cmd.OutOrStdout().Write(out)
+			return err
+		}
+		return helpers.SaveRegularFile(outFile, out, 0644)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(crlCmd)
+	crlCmd.Flags().StringVarP(&outFile, "out", "o", "", "output file (stdout by default)")
+}
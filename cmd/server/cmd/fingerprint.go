@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// fingerprintCmd represents the fingerprint command
+var fingerprintCmd = &cobra.Command{
+	Use:          "fingerprint",
+	Short:        "Print CA fingerprint",
+	Long:         "\nPrint SHA-256 fingerprint of CA certificate for client register --ca-fingerprint",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		fingerprint, err := newAdminServer().CAFingerprint()
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(cmd.OutOrStdout(), fingerprint)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(fingerprintCmd)
+}
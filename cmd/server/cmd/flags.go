@@ -1,8 +1,25 @@
 package cmd
 
+import "time"
+
 var (
-	publicPort  int
-	privatePort int
-	cacheDir    string
-	dnsNames    []string
+	publicPort     int
+	privatePort    int
+	cacheDir       string
+	dnsNames       []string
+	legacyJSON     bool
+	publicTLS      bool
+	serial         string
+	commonName     string
+	reason         string
+	outFile        string
+	registration   string
+	inviteTTL      time.Duration
+	force          bool
+	storageDSN     string
+	migrateStatus  bool
+	ephemeral      bool
+	versionsKeep   int
+	versionsMaxAge time.Duration
+	trashRetention time.Duration
 )
@@ -1,6 +1,8 @@
 package cmd
 
 import (
+	"fmt"
+
 	"github.com/sejo412/gophkeeper/internal/constants"
 	"github.com/sejo412/gophkeeper/internal/server"
 	"github.com/spf13/cobra"
@@ -12,25 +14,36 @@ var initCmd = &cobra.Command{
 	Short: "Initialize a new server",
 	Long: `
          Initialize a new server
+Refuses to run if database or CA already exist.
             !!!!!!!!!!!!!!!!!
             !!! Attention !!!
             !!!!!!!!!!!!!!!!!
-All data and certificates will be overwritten!
+With --force all data and certificates will be overwritten!
 `,
-	Run: func(cmd *cobra.Command, args []string) {
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
 		s := server.NewServer(
 			server.Config{
-				CacheDir: cacheDir,
-				DNSNames: dnsNames,
+				CacheDir:   cacheDir,
+				StorageDSN: storageDSN,
+				DNSNames:   dnsNames,
+				Force:      force,
 			},
 		)
 		if err := s.Init(); err != nil {
-			panic(err)
+			return err
+		}
+		fingerprint, err := s.CAFingerprint()
+		if err != nil {
+			return err
 		}
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "CA fingerprint (SHA-256): %s\n", fingerprint)
+		return nil
 	},
 }
 
 func init() {
 	rootCmd.AddCommand(initCmd)
 	initCmd.Flags().StringSliceVar(&dnsNames, "dns", constants.DefaultDNSNames, "DNS names to serve")
+	initCmd.Flags().BoolVar(&force, "force", false, "overwrite existing data and certificates")
 }
@@ -0,0 +1,65 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/constants"
+	"github.com/spf13/cobra"
+)
+
+// inviteCmd represents the invite command
+var inviteCmd = &cobra.Command{
+	Use:   "invite",
+	Short: "Manage invite tokens",
+	Long:  "\nCreate and list single-use invite tokens for registration in invite mode",
+}
+
+// inviteCreateCmd represents the invite create command
+var inviteCreateCmd = &cobra.Command{
+	Use:          "create",
+	Short:        "Create invite token",
+	Long:         "\nCreate single-use invite token, new user passes it to client register --invite",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		invite, err := newAdminServer().NewInvite(cmd.Context(), inviteTTL)
+		if err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintln(cmd.OutOrStdout(), invite.Token)
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Valid until %s\n", invite.Expires.Format(time.DateTime))
+		return nil
+	},
+}
+
+// inviteListCmd represents the invite list command
+var inviteListCmd = &cobra.Command{
+	Use:          "list",
+	Short:        "List invite tokens",
+	Long:         "\nList invite tokens with their expiration and registered users",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		invites, err := newAdminServer().Invites(cmd.Context())
+		if err != nil {
+			return err
+		}
+		for _, i := range invites {
+			state := "unused"
+			switch {
+			case i.Cn != "":
+				state = "used by " + i.Cn
+			case time.Now().After(i.Expires):
+				state = "expired"
+			}
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s\t%s\t%s\n", i.Token, i.Expires.Format(time.DateTime), state)
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(inviteCmd)
+	inviteCmd.AddCommand(inviteCreateCmd)
+	inviteCmd.AddCommand(inviteListCmd)
+	inviteCreateCmd.Flags().DurationVar(&inviteTTL, "ttl", constants.InviteTTL, "lifetime of invite token")
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// migrateCmd represents the migrate command
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Migrate database schema",
+	Long: `
+Apply not applied database schema migrations, server must be stopped.
+With --status print applied migrations and schema versions only.
+`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		s := newAdminServer()
+		if !migrateStatus {
+			applied, err := s.Migrate(cmd.Context())
+			for _, m := range applied {
+				_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Applied migration %d: %s\n", m.Version, m.Description)
+			}
+			if err != nil {
+				return err
+			}
+		}
+		current, latest, err := s.SchemaVersion(cmd.Context())
+		if err != nil {
+			return err
+		}
+		if migrateStatus {
+			migrations, er := s.Migrations(cmd.Context())
+			if er != nil {
+				return er
+			}
+			tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			_, _ = fmt.Fprintln(tw, "VERSION\tDESCRIPTION\tAPPLIED")
+			for _, m := range migrations {
+				_, _ = fmt.Fprintf(tw, "%d\t%s\t%s\n", m.Version, m.Description, m.Applied.Format(time.DateTime))
+			}
+			if er = tw.Flush(); er != nil {
+				return er
+			}
+		}
+		_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Schema version %d, supported %d\n", current, latest)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(migrateCmd)
+	migrateCmd.Flags().BoolVar(&migrateStatus, "status", false, "print applied migrations without migrating")
+}
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+// registrationsCmd represents the registrations command
+var registrationsCmd = &cobra.Command{
+	Use:   "registrations",
+	Short: "Manage registrations waiting for approval",
+	Long:  "\nList, approve and reject registrations of new users in approval mode",
+}
+
+// registrationsListCmd represents the registrations list command
+var registrationsListCmd = &cobra.Command{
+	Use:          "list",
+	Short:        "List registrations",
+	Long:         "\nList registrations of new users waiting for approval",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		registrations, err := newAdminServer().Registrations(cmd.Context())
+		if err != nil {
+			return err
+		}
+		for _, r := range registrations {
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "%s\t%s\t%s\n", r.Code, r.Cn, r.Created.Format(time.DateTime))
+		}
+		return nil
+	},
+}
+
+// registrationsApproveCmd represents the registrations approve command
+var registrationsApproveCmd = &cobra.Command{
+	Use:          "approve <code>",
+	Short:        "Approve registration",
+	Long:         "\nApprove registration of new user, client gets certificate on next status request",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := newAdminServer().ApproveRegistration(cmd.Context(), args[0]); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Registration %s approved\n", args[0])
+		return nil
+	},
+}
+
+// registrationsRejectCmd represents the registrations reject command
+var registrationsRejectCmd = &cobra.Command{
+	Use:          "reject <code>",
+	Short:        "Reject registration",
+	Long:         "\nReject registration of new user",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if err := newAdminServer().RejectRegistration(cmd.Context(), args[0]); err != nil {
+			return err
+		}
+		_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "Registration %s rejected\n", args[0])
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(registrationsCmd)
+	registrationsCmd.AddCommand(registrationsListCmd)
+	registrationsCmd.AddCommand(registrationsApproveCmd)
+	registrationsCmd.AddCommand(registrationsRejectCmd)
+}
@@ -0,0 +1,51 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+	"github.com/spf13/cobra"
+)
+
+// revokeCmd represents the revoke command
+var revokeCmd = &cobra.Command{
+	Use:   "revoke",
+	Short: "Revoke client certificate",
+	Long: `
+Revoke client certificate by serial number or all certificates of device by its name.
+Revoked certificates are rejected by running server immediately.
+`,
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if (serial == "") == (commonName == "") {
+			return errors.New("one of --serial or --cn required")
+		}
+		r, err := models.ParseRevocationReason(reason)
+		if err != nil {
+			return err
+		}
+		s := newAdminServer()
+		revoked, err := s.Revoke(cmd.Context(), serial, commonName, r)
+		if err != nil {
+			return err
+		}
+		if len(revoked) == 0 {
+			return fmt.Errorf("no certificates issued for %q", commonName)
+		}
+		for _, rev := range revoked {
+			_, _ = fmt.Fprintf(cmd.OutOrStdout(), "Revoked %s %s (%s)\n", rev.Serial, rev.Cn, rev.Reason.String())
+		}
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(revokeCmd)
+	revokeCmd.Flags().StringVar(&serial, "serial", "", "hex serial number of certificate")
+	revokeCmd.Flags().StringVar(&commonName, "cn", "", "device name (certificate common name)")
+	revokeCmd.Flags().StringVar(
+		&reason, "reason", models.RevocationUnspecifiedName,
+		"revocation reason (unspecified, keyCompromise, affiliationChanged, superseded, cessationOfOperation)",
+	)
+}
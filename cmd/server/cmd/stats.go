@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+	"github.com/sejo412/gophkeeper/internal/server"
+	"github.com/spf13/cobra"
+)
+
+// statsTypes are record types in columns of stats.
+var statsTypes = []models.RecordType{models.RecordPassword, models.RecordText, models.RecordBin, models.RecordBank}
+
+// statsCmd represents the stats command
+var statsCmd = &cobra.Command{
+	Use:          "stats",
+	Short:        "Show records statistics",
+	Long:         "\nShow count and stored bytes of encrypted records by type for every user",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withAdmin(
+			func(admin server.Admin) error {
+				stats, err := admin.Stats(cmd.Context())
+				if err != nil {
+					return err
+				}
+				tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+				header := []string{"USER"}
+				for _, t := range statsTypes {
+					header = append(header, strings.ToUpper(t.String()))
+				}
+				_, _ = fmt.Fprintln(tw, strings.Join(append(header, "TOTAL"), "\t"))
+				total := models.UserStats{User: models.User{Cn: "total"}, Records: make(map[models.RecordType]models.RecordStats)}
+				for _, u := range stats {
+					_, _ = fmt.Fprintln(tw, statsRow(u))
+					for t, r := range u.Records {
+						sum := total.Records[t]
+						sum.Count += r.Count
+						sum.Bytes += r.Bytes
+						total.Records[t] = sum
+					}
+				}
+				_, _ = fmt.Fprintln(tw, statsRow(total))
+				return tw.Flush()
+			},
+		)
+	},
+}
+
+// statsRow returns tab separated "count/bytes" cells of user by type and total.
+func statsRow(u models.UserStats) string {
+	row := []string{u.Cn}
+	var all models.RecordStats
+	for _, t := range statsTypes {
+		r := u.Records[t]
+		all.Count += r.Count
+		all.Bytes += r.Bytes
+		row = append(row, fmt.Sprintf("%d/%dB", r.Count, r.Bytes))
+	}
+	return strings.Join(append(row, fmt.Sprintf("%d/%dB", all.Count, all.Bytes)), "\t")
+}
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+}
@@ -0,0 +1,144 @@
+package cmd
+
+import (
+	"fmt"
+	"text/tabwriter"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/server"
+	"github.com/spf13/cobra"
+)
+
+// usersCmd represents the users command
+var usersCmd = &cobra.Command{
+	Use:   "users",
+	Short: "Manage users",
+	Long:  "\nList, show, delete, disable and enable users of running or stopped server",
+}
+
+// usersListCmd represents the users list command
+var usersListCmd = &cobra.Command{
+	Use:          "list",
+	Short:        "List users",
+	Long:         "\nList users with count of devices and certificates",
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withAdmin(
+			func(admin server.Admin) error {
+				users, err := admin.Users(cmd.Context())
+				if err != nil {
+					return err
+				}
+				tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+				_, _ = fmt.Fprintln(tw, "ID\tNAME\tDEVICES\tCERTIFICATES\tDISABLED")
+				for _, u := range users {
+					_, _ = fmt.Fprintf(
+						tw, "%d\t%s\t%d\t%d\t%t\n", u.ID, u.Cn, len(u.Devices), len(u.Certificates), u.Disabled,
+					)
+				}
+				return tw.Flush()
+			},
+		)
+	},
+}
+
+// usersShowCmd represents the users show command
+var usersShowCmd = &cobra.Command{
+	Use:          "show <name>",
+	Short:        "Show user",
+	Long:         "\nShow user with its devices and certificates",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withAdmin(
+			func(admin server.Admin) error {
+				u, err := admin.User(cmd.Context(), args[0])
+				if err != nil {
+					return err
+				}
+				tw := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+				_, _ = fmt.Fprintf(tw, "ID:\t%d\nName:\t%s\nDisabled:\t%t\n", u.ID, u.Cn, u.Disabled)
+				_, _ = fmt.Fprintln(tw, "Devices:")
+				for _, d := range u.Devices {
+					_, _ = fmt.Fprintf(tw, "  %d\t%s\t%s\n", d.ID, d.Cn, d.Created.Format(time.DateTime))
+				}
+				_, _ = fmt.Fprintln(tw, "Certificates:")
+				for _, c := range u.Certificates {
+					_, _ = fmt.Fprintf(
+						tw, "  %s\t%s\t%s\t%s\n", c.Serial, c.Cn, c.NotAfter.Format(time.DateOnly), certificateStatus(c),
+					)
+				}
+				return tw.Flush()
+			},
+		)
+	},
+}
+
+// usersDeleteCmd represents the users delete command
+var usersDeleteCmd = &cobra.Command{
+	Use:          "delete <name>",
+	Short:        "Delete user",
+	Long:         "\nDelete user with all its records and devices, certificates of user are revoked",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return withAdmin(
+			func(admin server.Admin) error {
+				if err := admin.DeleteUser(cmd.Context(), args[0]); err != nil {
+					return err
+				}
+				_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "User %s deleted\n", args[0])
+				return nil
+			},
+		)
+	},
+}
+
+// usersDisableCmd represents the users disable command
+var usersDisableCmd = &cobra.Command{
+	Use:          "disable <name>",
+	Short:        "Disable user",
+	Long:         "\nDisable user, server rejects all devices of user until it is enabled",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return disableUser(cmd, args[0], true)
+	},
+}
+
+// usersEnableCmd represents the users enable command
+var usersEnableCmd = &cobra.Command{
+	Use:          "enable <name>",
+	Short:        "Enable user",
+	Long:         "\nEnable disabled user",
+	Args:         cobra.ExactArgs(1),
+	SilenceUsage: true,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return disableUser(cmd, args[0], false)
+	},
+}
+
+func disableUser(cmd *cobra.Command, name string, disabled bool) error {
+	return withAdmin(
+		func(admin server.Admin) error {
+			if err := admin.DisableUser(cmd.Context(), name, disabled); err != nil {
+				return err
+			}
+			state := "enabled"
+			if disabled {
+				state = "disabled"
+			}
+			_, _ = fmt.Fprintf(cmd.ErrOrStderr(), "User %s %s\n", name, state)
+			return nil
+		},
+	)
+}
+
+func init() {
+	rootCmd.AddCommand(usersCmd)
+	usersCmd.AddCommand(usersListCmd)
+	usersCmd.AddCommand(usersShowCmd)
+	usersCmd.AddCommand(usersDeleteCmd)
+	usersCmd.AddCommand(usersDisableCmd)
+	usersCmd.AddCommand(usersEnableCmd)
+}
@@ -3,325 +3,142 @@ package client
 import (
 	"bufio"
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"os"
 	"strconv"
+	"strings"
 
 	"github.com/sejo412/gophkeeper/internal/models"
-	"github.com/sejo412/gophkeeper/pkg/crypt"
-	pb "github.com/sejo412/gophkeeper/proto"
 )
 
 func createRecord(ctx context.Context, c *Client, t models.RecordType, scanner *bufio.Scanner) {
-	bin, err := writeRecord(ctx, c, t, scanner)
-	if err != nil {
-		fmt.Printf("error writing record: %v\n", err)
+	record := scanRecord(t, scanner)
+	if err := c.Add(ctx, t, record); err != nil {
+		fmt.Printf("Failed create %s: %v\n", t.String(), err)
 		return
 	}
-	_, err = c.client.Create(
-		ctx, &pb.AddRecordRequest{
-			Type:   protoRecordType(modelRecordTypeToProto(t)),
-			Record: bin,
-		},
-	)
-	if err != nil {
-		fmt.Printf("failed create %s: %v\n", t.String(), err)
-	}
 	fmt.Printf("Created %s\n", t.String())
 }
 
 func readRecord(ctx context.Context, c *Client, t models.RecordType, scanner *bufio.Scanner) {
-	fmt.Printf("Choose %s: ", t.String())
-	scanner.Scan()
-	val := scanner.Text()
-	id, err := strconv.Atoi(val)
+	id, err := scanID(t, scanner)
 	if err != nil {
 		fmt.Println("Invalid ID: ", err)
 		return
 	}
-	resp, err := c.client.Read(
-		ctx,
-		&pb.GetRecordRequest{
-			Type:         protoRecordType(modelRecordTypeToProto(t)),
-			RecordNumber: protoID(id),
-		},
-	)
+	record, err := c.Get(ctx, t, id)
 	if err != nil {
 		fmt.Printf("Error getting %s: %v\n", t.String(), err)
 		return
 	}
-	protoRecord := resp.GetRecord()
-	record := models.RecordEncrypted{}
-	err = json.Unmarshal(protoRecord, &record)
-	if err != nil {
-		fmt.Printf("Error unmarshalling record: %v\n", err)
-		return
-	}
-	for _, field := range fields(t) {
-		var valDec []byte
-		var err error
-		switch t {
-		case models.RecordPassword:
-			switch field {
-			case FieldLogin:
-				valDec, err = crypt.DecryptWithPrivateKey(c.privateKey, record.Password.Login)
-			case FieldPassword:
-				valDec, err = crypt.DecryptWithPrivateKey(c.privateKey, record.Password.Password)
-			case FieldMeta:
-				valDec, err = crypt.DecryptWithPrivateKey(c.privateKey, record.Password.Meta)
-			default:
-			}
-		case models.RecordText:
-			switch field {
-			case FieldText:
-				valDec, err = crypt.DecryptWithPrivateKey(c.privateKey, record.Text.Text)
-			case FieldMeta:
-				valDec, err = crypt.DecryptWithPrivateKey(c.privateKey, record.Text.Meta)
-			default:
-			}
-		case models.RecordBin:
-			switch field {
-			case FieldData:
-				valDec, err = crypt.DecryptWithPrivateKey(c.privateKey, record.Bin.Data)
-			case FieldMeta:
-				valDec, err = crypt.DecryptWithPrivateKey(c.privateKey, record.Bin.Meta)
-			default:
-			}
-		case models.RecordBank:
-			switch field {
-			case FieldNumber:
-				valDec, err = crypt.DecryptWithPrivateKey(c.privateKey, record.Bank.Number)
-			case FieldName:
-				valDec, err = crypt.DecryptWithPrivateKey(c.privateKey, record.Bank.Name)
-			case FieldDate:
-				valDec, err = crypt.DecryptWithPrivateKey(c.privateKey, record.Bank.Date)
-			case FieldCVV:
-				valDec, err = crypt.DecryptWithPrivateKey(c.privateKey, record.Bank.Cvv)
-			case FieldMeta:
-				valDec, err = crypt.DecryptWithPrivateKey(c.privateKey, record.Bank.Meta)
-			default:
-			}
-		default:
-		}
-		if err != nil {
-			fmt.Printf("Error decrypt %s: %v\n", field.String(), err)
-			return
-		}
-		fmt.Printf("%s: %s\n", field.String(), string(valDec))
+	if err = WriteRecord(os.Stdout, OutputTable, t, record); err != nil {
+		fmt.Printf("Error printing %s: %v\n", t.String(), err)
 	}
 }
 
 func updateRecord(ctx context.Context, c *Client, t models.RecordType, scanner *bufio.Scanner) {
-	fmt.Printf("Choose %s: ", t.String())
-	scanner.Scan()
-	val := scanner.Text()
-	id, err := strconv.Atoi(val)
+	id, err := scanID(t, scanner)
 	if err != nil {
 		fmt.Println("Invalid ID: ", err)
 		return
 	}
-	bin, err := writeRecord(ctx, c, t, scanner)
+	// record is read before editing to detect its change on server by other device
+	base, err := c.Get(ctx, t, id)
 	if err != nil {
-		fmt.Printf("error writing record: %v\n", err)
+		fmt.Printf("Error getting %s: %v\n", t.String(), err)
 		return
 	}
-	_, err = c.client.Update(
-		ctx, &pb.UpdateRecordRequest{
-			Type:         protoRecordType(modelRecordTypeToProto(t)),
-			RecordNumber: protoID(id),
-			Record:       bin,
-		},
-	)
+	record := scanRecord(t, scanner)
+	*recordUUID(&record, t) = *recordUUID(&base, t)
+	*recordRevision(&record, t) = *recordRevision(&base, t)
+	if err = c.Update(ctx, t, id, record); errors.Is(err, ErrConflict) {
+		err = mergeRecord(ctx, c, t, id, base, record, scanner)
+	}
 	if err != nil {
-		fmt.Printf("failed update %s with ID %d: %v\n", t.String(), id, err)
+		fmt.Printf("Failed update %s with ID %d: %v\n", t.String(), id, err)
+		return
 	}
 	fmt.Printf("Updated %s: %d\n", t.String(), id)
 }
 
-func deleteRecord(ctx context.Context, c *Client, t models.RecordType, scanner *bufio.Scanner) {
-	fmt.Printf("Choose %s: ", t.String())
+// mergeRecord shows local and server versions of record and saves merged version if confirmed.
+func mergeRecord(
+	ctx context.Context, c *Client, t models.RecordType, id models.ID, base, local models.Record,
+	scanner *bufio.Scanner,
+) error {
+	conflict, err := c.Conflict(ctx, t, id, base, local)
+	if err != nil {
+		return err
+	}
+	if err = WriteConflict(os.Stdout, conflict); err != nil {
+		return err
+	}
+	fmt.Print("Save merged version? [y/N]: ")
 	scanner.Scan()
-	val := scanner.Text()
-	id, err := strconv.Atoi(val)
+	if answer := strings.ToLower(strings.TrimSpace(scanner.Text())); answer != "y" && answer != "yes" {
+		return ErrConflict
+	}
+	return c.Update(ctx, t, id, conflict.Merged)
+}
+
+func deleteRecord(ctx context.Context, c *Client, t models.RecordType, scanner *bufio.Scanner) {
+	id, err := scanID(t, scanner)
 	if err != nil {
 		fmt.Println("Invalid ID: ", err)
 		return
 	}
-	_, err = c.client.Delete(
-		ctx, &pb.DeleteRecordRequest{
-			Type:         protoRecordType(modelRecordTypeToProto(t)),
-			RecordNumber: protoID(id),
-		},
-	)
-	if err != nil {
+	fmt.Printf("Move %s %d to trash? [y/N]: ", t.String(), id)
+	scanner.Scan()
+	if answer := strings.ToLower(strings.TrimSpace(scanner.Text())); answer != "y" && answer != "yes" {
+		fmt.Println("Canceled")
+		return
+	}
+	if err = c.Delete(ctx, t, id); err != nil {
 		fmt.Printf("Failed deleting %s with ID %d: %v\n", t.String(), id, err)
+		return
 	}
-	fmt.Printf("Deleted %s: %d\n", t.String(), id)
+	fmt.Printf("Moved %s %d to trash\n", t.String(), id)
 }
 
 func listRecords(ctx context.Context, c *Client, t models.RecordType) {
-	resp, err := c.client.List(
-		ctx, &pb.ListRequest{
-			Type: protoRecordType(modelRecordTypeToProto(t)),
-		},
-	)
+	records, err := c.List(ctx, t)
 	if err != nil {
 		fmt.Printf("Error listing records: %v\n", err)
 		return
 	}
-	records := resp.GetRecords()
-	data := models.RecordsEncrypted{}
-	if err = json.Unmarshal(records, &data); err != nil {
-		fmt.Printf("Error unmarshalling records: %v\n", err)
-		return
-	}
-	resultEnc := make(map[models.ID][]byte)
-	switch t {
-	case models.RecordPassword:
-		for _, record := range data.Password {
-			resultEnc[record.ID] = record.Meta
-		}
-	case models.RecordText:
-		for _, record := range data.Text {
-			resultEnc[record.ID] = record.Meta
-		}
-	case models.RecordBin:
-		for _, record := range data.Bin {
-			resultEnc[record.ID] = record.Meta
-		}
-	case models.RecordBank:
-		for _, record := range data.Bank {
-			resultEnc[record.ID] = record.Meta
-		}
-	default:
-	}
-	for id, record := range resultEnc {
-		decrypted, err := crypt.DecryptWithPrivateKey(c.privateKey, record)
-		if err != nil {
-			fmt.Printf("Error decrypting record: %v\n", err)
-			return
-		}
-		fmt.Printf("%d: %s\n", id, string(decrypted))
+	if err = WriteRecords(os.Stdout, OutputTable, records, t); err != nil {
+		fmt.Printf("Error printing records: %v\n", err)
 	}
 }
 
 func listAllRecords(ctx context.Context, c *Client) {
 	clearScreen()
-	resp, err := c.client.ListAll(ctx, nil)
+	records, err := c.ListAll(ctx)
 	if err != nil {
 		fmt.Printf("Error listing records: %v\n", err)
-		return
-	}
-	records := resp.GetRecords()
-	data := models.RecordsEncrypted{}
-	if err = json.Unmarshal(records, &data); err != nil {
-		fmt.Printf("Error unmarshalling records: %v\n", err)
-		return
-	}
-	fmt.Printf("%s:\n", models.RecordPassword.String())
-	for _, record := range data.Password {
-		valDec, err := crypt.DecryptWithPrivateKey(c.privateKey, record.Meta)
-		if err != nil {
-			fmt.Printf("Error decrypting record %d: %v\n", record.ID, err)
-		} else {
-			fmt.Printf("%d: %s\n", record.ID, string(valDec))
-		}
-	}
-	fmt.Printf("%s:\n", models.RecordText.String())
-	for _, record := range data.Text {
-		valDec, err := crypt.DecryptWithPrivateKey(c.privateKey, record.Meta)
-		if err != nil {
-			fmt.Printf("Error decrypting record %d: %v\n", record.ID, err)
-		} else {
-			fmt.Printf("%d: %s\n", record.ID, string(valDec))
-		}
-	}
-	fmt.Printf("%s:\n", models.RecordBin.String())
-	for _, record := range data.Bin {
-		valDec, err := crypt.DecryptWithPrivateKey(c.privateKey, record.Meta)
-		if err != nil {
-			fmt.Printf("Error decrypting record %d: %v\n", record.ID, err)
-		} else {
-			fmt.Printf("%d: %s\n", record.ID, string(valDec))
-		}
-	}
-	fmt.Printf("%s:\n", models.RecordBank.String())
-	for _, record := range data.Bank {
-		valDec, err := crypt.DecryptWithPrivateKey(c.privateKey, record.Meta)
-		if err != nil {
-			fmt.Printf("Error decrypting record %d: %v\n", record.ID, err)
-		} else {
-			fmt.Printf("%d: %s\n", record.ID, string(valDec))
-		}
+	} else if err = WriteRecords(os.Stdout, OutputTable, records, models.RecordTypes...); err != nil {
+		fmt.Printf("Error printing records: %v\n", err)
 	}
 	waitForEnter()
 }
 
-func writeRecord(_ context.Context, c *Client, t models.RecordType, scanner *bufio.Scanner) (
-	record []byte, err error,
-) {
-	encrypted := models.RecordEncrypted{
-		Password: models.PasswordEncrypted{},
-		Text:     models.TextEncrypted{},
-		Bin:      models.BinEncrypted{},
-		Bank:     models.BankEncrypted{},
+func scanID(t models.RecordType, scanner *bufio.Scanner) (models.ID, error) {
+	fmt.Printf("Choose %s: ", t.String())
+	scanner.Scan()
+	id, err := strconv.Atoi(scanner.Text())
+	if err != nil {
+		return 0, err
 	}
-	f := fields(t)
-	for _, field := range f {
+	return models.ID(id), nil
+}
+
+func scanRecord(t models.RecordType, scanner *bufio.Scanner) models.Record {
+	record := models.Record{}
+	for _, field := range fields(t) {
 		fmt.Printf("%s: ", field.String())
 		scanner.Scan()
-		val := scanner.Text()
-		valEnc, err := crypt.EncryptWithPublicKey(c.publicKey, []byte(val))
-		if err != nil {
-			return nil, err
-		}
-		switch t {
-		case models.RecordPassword:
-			switch field {
-			case FieldLogin:
-				encrypted.Password.Login = valEnc
-			case FieldPassword:
-				encrypted.Password.Password = valEnc
-			case FieldMeta:
-				encrypted.Password.Meta = valEnc
-			default:
-			}
-		case models.RecordText:
-			switch field {
-			case FieldText:
-				encrypted.Text.Text = valEnc
-			case FieldMeta:
-				encrypted.Text.Meta = valEnc
-			default:
-			}
-		case models.RecordBin:
-			switch field {
-			case FieldData:
-				encrypted.Bin.Data = valEnc
-			case FieldMeta:
-				encrypted.Bin.Meta = valEnc
-			default:
-			}
-		case models.RecordBank:
-			switch field {
-			case FieldNumber:
-				encrypted.Bank.Number = valEnc
-			case FieldName:
-				encrypted.Bank.Name = valEnc
-			case FieldDate:
-				encrypted.Bank.Date = valEnc
-			case FieldCVV:
-				encrypted.Bank.Cvv = valEnc
-			case FieldMeta:
-				encrypted.Bank.Meta = valEnc
-			default:
-			}
-		default:
-		}
-	}
-	bin, err := json.Marshal(&encrypted)
-	if err != nil {
-		return nil, err
+		setRecordField(&record, t, field, []byte(scanner.Text()))
 	}
-	return bin, nil
+	return record
 }
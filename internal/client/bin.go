@@ -0,0 +1,255 @@
+package client
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/sejo412/gophkeeper/internal/constants"
+	"github.com/sejo412/gophkeeper/internal/models"
+	"github.com/sejo412/gophkeeper/internal/protoconv"
+	"github.com/sejo412/gophkeeper/pkg/crypt"
+	pb "github.com/sejo412/gophkeeper/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+// UploadBin encrypts file by chunks and uploads it as binary Record. If id is 0 new Record
+// created, otherwise data of existing Record replaced, unless it was changed on server since revision
+// (if not 0), then ErrConflict is returned. Interrupted upload of the same file resumes from the last
+// chunk received by server.
+func (c *Client) UploadBin(
+	ctx context.Context, path string, id models.ID, meta models.Meta, revision int64,
+) (models.ID, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	info, err := file.Stat()
+	if err != nil {
+		return 0, fmt.Errorf("failed to stat file: %w", err)
+	}
+	uploadID, err := uploadID(file, info, id)
+	if err != nil {
+		return 0, err
+	}
+	statusResp, err := c.client.UploadBinStatus(ctx, &pb.UploadBinStatusRequest{UploadId: proto.String(uploadID)})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get upload status: %w", err)
+	}
+	// resumed upload keeps UUID and data key its chunks encrypted with
+	uuid, wrappedKey := statusResp.GetUuid(), models.Encrypted(statusResp.GetDataKey())
+	if uuid == "" {
+		if uuid, err = newRecordUUID(); err != nil {
+			return 0, err
+		}
+	}
+	var key []byte
+	if len(wrappedKey) == 0 {
+		key, wrappedKey, err = c.newRecordKey()
+	} else {
+		key, err = c.recordKey(wrappedKey)
+	}
+	if err != nil {
+		return 0, err
+	}
+	metaEnc, err := crypt.EncryptWithDataKey(key, []byte(meta), associatedData(models.RecordBin, FieldMeta, uuid))
+	if err != nil {
+		return 0, fmt.Errorf("failed to encrypt %s: %w", FieldMeta.String(), err)
+	}
+	firstChunk := statusResp.GetChunks()
+	if _, err = file.Seek(firstChunk*int64(constants.BinChunkSize), io.SeekStart); err != nil {
+		return 0, fmt.Errorf("failed to seek file: %w", err)
+	}
+	stream, err := c.client.UploadBin(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to start upload: %w", err)
+	}
+	if err = stream.Send(
+		&pb.UploadBinRequest{
+			Payload: &pb.UploadBinRequest_Header{
+				Header: &pb.UploadBinHeader{
+					UploadId:         proto.String(uploadID),
+					RecordNumber:     proto.Int64(int64(id)),
+					Meta:             metaEnc,
+					FirstChunk:       proto.Int64(firstChunk),
+					Uuid:             proto.String(uuid),
+					DataKey:          wrappedKey,
+					ExpectedRevision: proto.Int64(revision),
+				},
+			},
+		},
+	); err != nil {
+		return 0, fmt.Errorf("failed to send upload header: %w", uploadError(stream, err))
+	}
+	// empty file is uploaded as one empty chunk, so its last chunk is marked too
+	chunks := max(1, (info.Size()+int64(constants.BinChunkSize)-1)/int64(constants.BinChunkSize))
+	buf := make([]byte, constants.BinChunkSize)
+	for seq := firstChunk; seq < chunks; seq++ {
+		last := seq == chunks-1
+		n, err := io.ReadFull(file, buf)
+		if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+			return 0, fmt.Errorf("failed to read file: %w", err)
+		}
+		if n < constants.BinChunkSize && !last {
+			return 0, errors.New("file changed while uploading")
+		}
+		chunk, err := crypt.EncryptWithDataKey(key, buf[:n], binChunkAssociatedData(uuid, seq, last))
+		if err != nil {
+			return 0, fmt.Errorf("failed to encrypt chunk: %w", err)
+		}
+		if err = stream.Send(
+			&pb.UploadBinRequest{
+				Payload: &pb.UploadBinRequest_Chunk{Chunk: chunk},
+			},
+		); err != nil {
+			return 0, fmt.Errorf("failed to send chunk: %w", uploadError(stream, err))
+		}
+	}
+	resp, err := stream.CloseAndRecv()
+	if err != nil {
+		return 0, fmt.Errorf("failed to complete upload: %w", conflictError(err))
+	}
+	if c.replica != nil {
+		if err = c.syncChanged(ctx, models.RecordBin, models.ID(resp.GetRecordNumber())); err != nil {
+			return 0, err
+		}
+	}
+	return models.ID(resp.GetRecordNumber()), nil
+}
+
+// DownloadBin downloads and decrypts data of binary Record to file. Data is written
+// to file with constants.PartialFileSuffix which renamed to path when download
+// completes, so interrupted download resumes from the last saved chunk. Part file with all chunks
+// is checked by its last chunk downloaded again and renamed.
+func (c *Client) DownloadBin(ctx context.Context, id models.ID, path string) error {
+	resp, err := c.client.Read(
+		ctx, &pb.GetRecordRequest{
+			Type:         protoRecordType(modelRecordTypeToProto(models.RecordBin)),
+			RecordNumber: protoID(int(id)),
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to get %s with ID %d: %w", models.RecordBin.String(), id, err)
+	}
+	_, record := protoconv.RecordFromProto(resp.GetItem())
+	uuid := record.Bin.UUID
+	key, err := c.recordKey(record.Bin.DataKey)
+	if err != nil {
+		return err
+	}
+	partPath := path + constants.PartialFileSuffix
+	file, err := os.OpenFile(partPath, os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to open file: %w", err)
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+	info, err := file.Stat()
+	if err != nil {
+		return fmt.Errorf("failed to stat file: %w", err)
+	}
+	// drop incomplete chunk if previous download was interrupted while writing
+	firstChunk := info.Size() / int64(constants.BinChunkSize)
+	if firstChunk > 0 && info.Size()%int64(constants.BinChunkSize) == 0 {
+		// file may be complete, its last saved chunk is downloaded again to find out if it has last chunk mark
+		firstChunk--
+	}
+	if len(record.Bin.Data) > 0 {
+		// data saved by Create is one chunk of any size, its download starts again
+		firstChunk = 0
+	}
+	offset := firstChunk * int64(constants.BinChunkSize)
+	if err = file.Truncate(offset); err != nil {
+		return fmt.Errorf("failed to truncate file: %w", err)
+	}
+	if _, err = file.Seek(offset, io.SeekStart); err != nil {
+		return fmt.Errorf("failed to seek file: %w", err)
+	}
+	stream, err := c.client.DownloadBin(
+		ctx, &pb.DownloadBinRequest{
+			RecordNumber: proto.Int64(int64(id)),
+			FirstChunk:   proto.Int64(firstChunk),
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to start download: %w", err)
+	}
+	seq := firstChunk
+	last := false
+	for {
+		resp, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("failed to download chunk %d: %w", seq, err)
+		}
+		if last {
+			return fmt.Errorf("unexpected chunk %d after last chunk", resp.GetSeq())
+		}
+		if resp.GetSeq() != seq {
+			return fmt.Errorf("unexpected chunk %d, want %d", resp.GetSeq(), seq)
+		}
+		// count of chunks reported by server is checked by decryption of last chunk
+		last = seq == resp.GetChunks()-1
+		chunk, err := c.decryptWithKey(key, resp.GetChunk(), binChunkAssociatedData(uuid, seq, last))
+		if err != nil {
+			return fmt.Errorf("failed to decrypt chunk %d: %w: %w", seq, errFieldBinding, err)
+		}
+		if _, err = file.Write(chunk); err != nil {
+			return fmt.Errorf("failed to write file: %w", err)
+		}
+		seq++
+	}
+	// legacy bin without UUID has no marked last chunk
+	if !last && uuid != "" {
+		return fmt.Errorf("failed to download chunk %d: %w", seq, errBinTruncated)
+	}
+	if err = file.Close(); err != nil {
+		return fmt.Errorf("failed to close file: %w", err)
+	}
+	if err = os.Rename(partPath, path); err != nil {
+		return fmt.Errorf("failed to rename file: %w", err)
+	}
+	return nil
+}
+
+// uploadID returns upload ID unique for file content version and target Record.
+func uploadID(file *os.File, info os.FileInfo, id models.ID) (string, error) {
+	absPath, err := filepath.Abs(file.Name())
+	if err != nil {
+		return "", fmt.Errorf("failed to get absolute path: %w", err)
+	}
+	h := sha256.New()
+	for _, part := range []string{
+		absPath,
+		strconv.FormatInt(info.Size(), 10),
+		strconv.FormatInt(info.ModTime().UnixNano(), 10),
+		strconv.Itoa(int(id)),
+	} {
+		h.Write([]byte(part))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// uploadError returns real error of upload stream, Send returns only io.EOF on server error.
+func uploadError(stream pb.Private_UploadBinClient, err error) error {
+	if !errors.Is(err, io.EOF) {
+		return err
+	}
+	if _, er := stream.CloseAndRecv(); er != nil {
+		return er
+	}
+	return err
+}
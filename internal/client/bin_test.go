@@ -0,0 +1,207 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sejo412/gophkeeper/internal/constants"
+	"github.com/sejo412/gophkeeper/internal/models"
+)
+
+func TestClient_UploadDownloadBin(t *testing.T) {
+	c := NewClient(
+		Config{
+			PublicAddress:  testPublicAddress,
+			PrivateAddress: testPrivateAddress,
+			CacheDir:       t.TempDir(),
+			Password:       testPassword,
+		},
+	)
+	if err := c.Register("testUserBin"); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if err := c.Connect(); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer func() {
+		_ = c.Close()
+	}()
+	ctx := context.Background()
+	dir := t.TempDir()
+
+	data := make([]byte, constants.BinChunkSize*2+constants.BinChunkSize/2)
+	_, _ = rand.Read(data)
+	src := filepath.Join(dir, "src")
+	if err := os.WriteFile(src, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	id, err := c.UploadBin(ctx, src, 0, "testFile", 0)
+	if err != nil {
+		t.Fatalf("UploadBin() error = %v", err)
+	}
+	record, err := c.Get(ctx, models.RecordBin, id)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if record.Bin.Meta != "testFile" {
+		t.Errorf("Get() meta = %v, want %v", record.Bin.Meta, "testFile")
+	}
+
+	dst := filepath.Join(dir, "dst")
+	if err = c.DownloadBin(ctx, id, dst); err != nil {
+		t.Fatalf("DownloadBin() error = %v", err)
+	}
+	got, err := os.ReadFile(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("DownloadBin() data differs, got %d bytes, want %d", len(got), len(data))
+	}
+
+	// update of meta keeps chunked data
+	record.Bin.Meta = "renamedFile"
+	if err = c.Update(ctx, models.RecordBin, id, record); err != nil {
+		t.Fatalf("Update() meta error = %v", err)
+	}
+	if record, err = c.Get(ctx, models.RecordBin, id); err != nil || record.Bin.Meta != "renamedFile" {
+		t.Errorf("Get() after meta update = %v, %v, want meta %v", record.Bin.Meta, err, "renamedFile")
+	}
+	if err = c.DownloadBin(ctx, id, dst); err != nil {
+		t.Fatalf("DownloadBin() after meta update error = %v", err)
+	}
+	if got, err = os.ReadFile(dst); err != nil || !bytes.Equal(got, data) {
+		t.Errorf("DownloadBin() after meta update data differs, got %d bytes, want %d", len(got), len(data))
+	}
+
+	// interrupted download: first chunk saved and part of second one
+	partial := append(bytes.Clone(data[:constants.BinChunkSize]), []byte("garbage")...)
+	if err = os.WriteFile(dst+constants.PartialFileSuffix, partial, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err = c.DownloadBin(ctx, id, dst); err != nil {
+		t.Fatalf("DownloadBin() resume error = %v", err)
+	}
+	got, err = os.ReadFile(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("DownloadBin() resume data differs, got %d bytes, want %d", len(got), len(data))
+	}
+
+	// download interrupted before rename: all chunks of file of whole chunks saved
+	whole := make([]byte, constants.BinChunkSize*2)
+	_, _ = rand.Read(whole)
+	wholeSrc, wholeDst := filepath.Join(dir, "whole"), filepath.Join(dir, "wholeDst")
+	if err = os.WriteFile(wholeSrc, whole, 0600); err != nil {
+		t.Fatal(err)
+	}
+	wholeID, err := c.UploadBin(ctx, wholeSrc, 0, "wholeFile", 0)
+	if err != nil {
+		t.Fatalf("UploadBin() error = %v", err)
+	}
+	if err = os.WriteFile(wholeDst+constants.PartialFileSuffix, whole, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err = c.DownloadBin(ctx, wholeID, wholeDst); err != nil {
+		t.Fatalf("DownloadBin() of complete part error = %v", err)
+	}
+	if got, err = os.ReadFile(wholeDst); err != nil || !bytes.Equal(got, whole) {
+		t.Errorf("DownloadBin() of complete part data differs, got %d bytes, want %d", len(got), len(whole))
+	}
+	if _, err = os.Stat(wholeDst + constants.PartialFileSuffix); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("DownloadBin() of complete part left part file, stat error = %v", err)
+	}
+
+	// data saved by Add is one chunk, part file of other size is downloaded again
+	added := models.Record{Bin: models.Bin{Data: []byte("small"), Meta: "addedFile"}}
+	if err = c.Add(ctx, models.RecordBin, added); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	bins, err := c.List(ctx, models.RecordBin)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	addedID := bins.Bin[len(bins.Bin)-1].ID
+	if err = os.WriteFile(wholeDst+constants.PartialFileSuffix, whole, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err = c.DownloadBin(ctx, addedID, wholeDst); err != nil {
+		t.Fatalf("DownloadBin() of added record error = %v", err)
+	}
+	if got, err = os.ReadFile(wholeDst); err != nil || string(got) != "small" {
+		t.Errorf("DownloadBin() of added record = %q, %v, want %q", got, err, "small")
+	}
+
+	// replace data of existing record
+	data = data[:constants.BinChunkSize/2]
+	if err = os.WriteFile(src, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = c.UploadBin(ctx, src, id, "testFile", record.Bin.Revision-1); !errors.Is(err, ErrConflict) {
+		t.Errorf("UploadBin() replace of changed record error = %v, want %v", err, ErrConflict)
+	}
+	if _, err = c.UploadBin(ctx, src, id, "testFile", record.Bin.Revision); err != nil {
+		t.Fatalf("UploadBin() replace error = %v", err)
+	}
+	if err = c.DownloadBin(ctx, id, dst); err != nil {
+		t.Fatalf("DownloadBin() error = %v", err)
+	}
+	got, err = os.ReadFile(dst)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("DownloadBin() replaced data differs, got %d bytes, want %d", len(got), len(data))
+	}
+
+	// server dropping trailing chunks and reporting less chunks can't truncate data
+	data = make([]byte, constants.BinChunkSize*2)
+	_, _ = rand.Read(data)
+	if err = os.WriteFile(src, data, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = c.UploadBin(ctx, src, id, "testFile", 0); err != nil {
+		t.Fatalf("UploadBin() replace error = %v", err)
+	}
+	db, err := sql.Open("sqlite3", filepath.Join(testServerCacheDir, constants.DBFilename))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		_ = db.Close()
+	}()
+	if _, err = db.ExecContext(ctx, "DELETE FROM bin_chunks WHERE bid = ? AND seq = 1", id); err != nil {
+		t.Fatal(err)
+	}
+	_ = os.Remove(dst + constants.PartialFileSuffix)
+	if err = c.DownloadBin(ctx, id, dst); !errors.Is(err, errFieldBinding) {
+		t.Errorf("DownloadBin() of truncated data error = %v, want %v", err, errFieldBinding)
+	}
+
+	// empty file is uploaded as one marked last chunk
+	if err = os.WriteFile(src, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = c.UploadBin(ctx, src, id, "testFile", 0); err != nil {
+		t.Fatalf("UploadBin() empty error = %v", err)
+	}
+	_ = os.Remove(dst + constants.PartialFileSuffix)
+	if err = c.DownloadBin(ctx, id, dst); err != nil {
+		t.Fatalf("DownloadBin() empty error = %v", err)
+	}
+	if got, err = os.ReadFile(dst); err != nil || len(got) != 0 {
+		t.Errorf("DownloadBin() empty data = %d bytes, %v, want empty", len(got), err)
+	}
+
+	if err = c.DownloadBin(ctx, models.ID(42), dst); err == nil {
+		t.Errorf("DownloadBin() not found error = nil, want error")
+	}
+}
@@ -0,0 +1,51 @@
+package client
+
+import (
+	"crypto/rand"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+)
+
+// binLastChunk marks associated data of last chunk of bin.
+const binLastChunk = "last"
+
+// newRecordUUID generates random (version 4) UUID of record.
+func newRecordUUID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate record UUID: %w", err)
+	}
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
+
+// associatedData returns AEAD associated data which binds encrypted field to record type,
+// field name and record UUID. Data of bin is bound as its first and last chunk, so data
+// saved by Add can be downloaded as single chunk. Legacy record without UUID is not bound.
+func associatedData(t models.RecordType, f Field, uuid string) []byte {
+	if t == models.RecordBin && f == FieldData {
+		return binChunkAssociatedData(uuid, 0, true)
+	}
+	return bindingData(uuid, recordTypeKey(t), f.Flag())
+}
+
+// binChunkAssociatedData returns AEAD associated data of bin chunk with sequence number seq.
+// Last chunk is marked, so dropped trailing chunks are detected by download.
+func binChunkAssociatedData(uuid string, seq int64, last bool) []byte {
+	parts := []string{recordTypeKey(models.RecordBin), FieldData.Flag(), strconv.FormatInt(seq, 10)}
+	if last {
+		parts = append(parts, binLastChunk)
+	}
+	return bindingData(uuid, parts...)
+}
+
+func bindingData(uuid string, parts ...string) []byte {
+	if uuid == "" {
+		return nil
+	}
+	return []byte(strings.Join(append(parts, uuid), "\x00"))
+}
@@ -6,108 +6,281 @@ import (
 	"crypto/tls"
 	"crypto/x509"
 	"encoding/pem"
+	"errors"
 	"fmt"
 	"os"
 	"os/signal"
 	"path/filepath"
 	"syscall"
+	"time"
 
+	"github.com/sejo412/gophkeeper/internal/client/replica"
 	"github.com/sejo412/gophkeeper/internal/constants"
 	"github.com/sejo412/gophkeeper/internal/helpers"
 	"github.com/sejo412/gophkeeper/pkg/certs"
 	pb "github.com/sejo412/gophkeeper/proto"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
-	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/protobuf/proto"
 )
 
 // Client is a main client object.
 type Client struct {
-	config     *Config
-	client     pb.PrivateClient
+	config *Config
+	conn   *grpc.ClientConn
+	client pb.PrivateClient
+	// deviceKey authenticates device on the server.
+	deviceKey *rsa.PrivateKey
+	// publicKey and privateKey are vault keys which encrypt records of user.
 	publicKey  *rsa.PublicKey
 	privateKey *rsa.PrivateKey
+	keys       *keyCache
+	// replica is a local copy of records, nil if Config.Replica is disabled.
+	replica *replica.Replica
 }
 
 // NewClient constructs Client object.
 func NewClient(config Config) *Client {
-	return &Client{config: NewConfigWithOptions(config)}
+	return &Client{
+		config: NewConfigWithOptions(config),
+		keys:   newKeyCache(),
+	}
+}
+
+// RegisterOptions are optional parameters of registration.
+type RegisterOptions struct {
+	// Invite is a token given by server admin, required if server registers users by invites.
+	Invite string
+	// Pending is called with registration code if registration waits for admin approval.
+	Pending func(code string)
 }
 
 // Register registers new client on the server with creating RSA-keys and certificates.
+// Device key authenticates client, records are encrypted with new vault key.
 func (c *Client) Register(name string) error {
-	if err := os.MkdirAll(c.config.CacheDir, 0700); err != nil {
-		return fmt.Errorf("failed to create cache dir: %w", err)
-	}
-	privKey, err := certs.GenRsaKey(constants.KeyBits)
+	return c.RegisterWithOptions(context.Background(), name, RegisterOptions{})
+}
+
+// RegisterWithOptions registers new client like Register with invite token. If server registers
+// users after admin approval, it waits until admin approves registration.
+func (c *Client) RegisterWithOptions(ctx context.Context, name string, opts RegisterOptions) error {
+	device, err := c.newDevice(name)
 	if err != nil {
-		return fmt.Errorf("failed to generate private key: %w", err)
+		return err
 	}
-	keyOut, err := x509.MarshalPKCS8PrivateKey(privKey)
+	vaultKey, err := certs.GenRsaKey(constants.KeyBits)
 	if err != nil {
-		return fmt.Errorf("failed to marshal private key: %w", err)
-	}
-	if err = helpers.SaveRegularFile(
-		filepath.Join(c.config.CacheDir, constants.CertClientPrivateFilename), keyOut, 0600,
-	); err != nil {
-		return fmt.Errorf("failed to save private key: %w", err)
+		return fmt.Errorf("failed to generate vault key: %w", err)
 	}
-	csr := certs.NewCertRequest(name, nil, nil, nil, false)
-	if err = csr.Sign(keyOut); err != nil {
-		return fmt.Errorf("failed to sign certificate request: %w", err)
-	}
-	req, err := certs.RequestToBinary(*csr)
-	if err != nil {
-		return fmt.Errorf("failed to create certificate request: %w", err)
-	}
-	grpcClient, err := grpc.NewClient(c.config.PublicAddress, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	grpcClient, err := c.dialPublic()
 	if err != nil {
-		return fmt.Errorf("failed to create public client: %w", err)
+		return err
 	}
 	defer func() {
 		_ = grpcClient.Close()
 	}()
 	publicClient := pb.NewPublicClient(grpcClient)
 	resp, err := publicClient.Register(
-		context.Background(), &pb.RegisterRequest{
-			CertRequest: req,
+		ctx, &pb.RegisterRequest{
+			CertRequest: device.certRequest,
+			Invite:      proto.String(opts.Invite),
 		},
 	)
 	if err != nil {
 		return fmt.Errorf("failed to send request: %w", err)
 	}
-	if err = helpers.SaveRegularFile(
-		filepath.Join(c.config.CacheDir, constants.CertCAPublicFilename),
-		resp.GetCaCertificate(), 0644,
+	if resp.GetPendingCode() != "" {
+		if opts.Pending != nil {
+			opts.Pending(resp.GetPendingCode())
+		}
+		if resp, err = waitRegistration(ctx, publicClient, resp.GetPendingCode()); err != nil {
+			return err
+		}
+	}
+	if err = c.verifyCA(resp.GetCaCertificate()); err != nil {
+		return err
+	}
+	wrappedVaultKey, err := wrapVaultKey(&device.key.PublicKey, vaultKey)
+	if err != nil {
+		return err
+	}
+	return c.saveDevice(device, resp.GetCaCertificate(), resp.GetClientCertificate(), wrappedVaultKey)
+}
+
+// waitRegistration polls registration status until admin approves it.
+func waitRegistration(ctx context.Context, publicClient pb.PublicClient, code string) (*pb.RegisterResponse, error) {
+	ticker := time.NewTicker(constants.RegistrationPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+		resp, err := publicClient.RegisterStatus(ctx, &pb.RegisterStatusRequest{Code: proto.String(code)})
+		if err != nil {
+			return nil, fmt.Errorf("failed to get registration status: %w", err)
+		}
+		if resp.GetPendingCode() == "" {
+			return resp, nil
+		}
+	}
+}
+
+// newDevice is a generated device key with certificate request, it is saved when server accepts it.
+type newDevice struct {
+	key         *rsa.PrivateKey
+	password    []byte
+	certRequest []byte
+}
+
+// newDevice asks new master password and generates device key with certificate request.
+func (c *Client) newDevice(name string) (newDevice, error) {
+	if err := os.MkdirAll(c.config.CacheDir, 0700); err != nil {
+		return newDevice{}, fmt.Errorf("failed to create cache dir: %w", err)
+	}
+	password, err := c.newMasterPassword()
+	if err != nil {
+		return newDevice{}, err
+	}
+	privKey, err := certs.GenRsaKey(constants.KeyBits)
+	if err != nil {
+		return newDevice{}, fmt.Errorf("failed to generate private key: %w", err)
+	}
+	req, err := certRequest(name, privKey)
+	if err != nil {
+		return newDevice{}, err
+	}
+	return newDevice{
+		key:         privKey,
+		password:    password,
+		certRequest: req,
+	}, nil
+}
+
+// certRequest returns certificate request for device name signed by device key.
+func certRequest(name string, key *rsa.PrivateKey) ([]byte, error) {
+	keyOut, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal private key: %w", err)
+	}
+	csr := certs.NewCertRequest(name, nil, nil, nil, false)
+	if err = csr.Sign(keyOut); err != nil {
+		return nil, fmt.Errorf("failed to sign certificate request: %w", err)
+	}
+	req, err := certs.RequestToBinary(*csr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create certificate request: %w", err)
+	}
+	return req, nil
+}
+
+// saveDevice saves device key, certificates issued by server and vault key encrypted for device key.
+func (c *Client) saveDevice(device newDevice, caCert, clientCert, vaultKey []byte) error {
+	if err := c.savePrivateKey(device.key, device.password); err != nil {
+		return err
+	}
+	if err := helpers.SaveRegularFile(
+		filepath.Join(c.config.CacheDir, constants.CertCAPublicFilename), caCert, 0644,
 	); err != nil {
 		return fmt.Errorf("failed to save CA certificate: %w", err)
 	}
-	if err = helpers.SaveRegularFile(
-		filepath.Join(c.config.CacheDir, constants.CertClientPublicFilename), resp.GetClientCertificate(), 0644,
+	if err := helpers.SaveRegularFile(
+		filepath.Join(c.config.CacheDir, constants.CertClientPublicFilename), clientCert, 0644,
 	); err != nil {
 		return fmt.Errorf("failed to save client certificate: %w", err)
 	}
+	if err := helpers.SaveRegularFile(
+		filepath.Join(c.config.CacheDir, constants.VaultKeyFilename), vaultKey, 0600,
+	); err != nil {
+		return fmt.Errorf("failed to save vault key: %w", err)
+	}
 	return nil
 }
 
-// Run runs application's interactive mode.
-func (c *Client) Run() error {
-	tlsCfg, err := tlsConfig(c.config.CacheDir)
+// Connect loads RSA keys and certificates and connects to the private server.
+// Master password is asked if private key is encrypted. Client certificate is renewed
+// if it expires within Config.RenewBefore. If Config.Replica is enabled, local replica
+// is synchronized with server, unreachable server leaves replica for offline usage.
+func (c *Client) Connect() error {
+	if err := c.SetRSAKeys(); err != nil {
+		return fmt.Errorf("failed to set RSA keys: %w", err)
+	}
+	if err := c.dial(); err != nil {
+		return err
+	}
+	if err := c.renewIfExpiring(context.Background()); err != nil {
+		// current certificate is still valid, try again on next connect
+		_, _ = fmt.Fprintf(os.Stderr, "warning: %v\n", err)
+	}
+	if !c.config.Replica {
+		return nil
+	}
+	if err := c.openReplica(); err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), constants.SyncTimeout)
+	defer cancel()
+	result, err := c.Sync(ctx)
+	if errors.Is(err, ErrOffline) {
+		_, _ = fmt.Fprintf(os.Stderr, "warning: working offline: %v\n", err)
+		return nil
+	}
+	if errors.Is(err, ErrQueued) {
+		_, _ = fmt.Fprintf(os.Stderr, "warning: offline changes not synchronized: %v\n", err)
+		return nil
+	}
 	if err != nil {
-		return fmt.Errorf("failed to create tls config: %w", err)
+		return err
 	}
-	if err = c.SetRSAKeys(); err != nil {
-		return fmt.Errorf("failed to set RSA keys: %w", err)
+	for _, rejected := range result.Rejected {
+		_, _ = fmt.Fprintf(os.Stderr, "warning: offline change rejected: %v\n", rejected)
+	}
+	if len(result.Conflicts) > 0 {
+		_, _ = fmt.Fprintf(
+			os.Stderr, "warning: %d offline changes conflict with server, run sync to merge them\n",
+			len(result.Conflicts),
+		)
+	}
+	return nil
+}
+
+// dial connects to the private server with saved client certificate.
+func (c *Client) dial() error {
+	tlsCfg, err := tlsConfig(c.config.CacheDir, c.deviceKey)
+	if err != nil {
+		return fmt.Errorf("failed to create tls config: %w", err)
 	}
 	grpcClient, err := grpc.NewClient(c.config.PrivateAddress,
 		grpc.WithTransportCredentials(credentials.NewTLS(tlsCfg)))
 	if err != nil {
 		return fmt.Errorf("failed to create private client: %w", err)
 	}
+	c.conn = grpcClient
+	c.client = pb.NewPrivateClient(grpcClient)
+	return nil
+}
+
+// Close closes connection to the private server and local replica.
+func (c *Client) Close() error {
+	var err error
+	if c.replica != nil {
+		err = c.replica.Close()
+	}
+	if c.conn == nil {
+		return err
+	}
+	return errors.Join(err, c.conn.Close())
+}
+
+// Run runs application's interactive mode.
+func (c *Client) Run() error {
+	if err := c.Connect(); err != nil {
+		return err
+	}
 	defer func() {
-		_ = grpcClient.Close()
+		_ = c.Close()
 	}()
-	c.client = pb.NewPrivateClient(grpcClient)
 	ctx, cancel := context.WithCancel(context.Background())
 	sig := make(chan os.Signal, 1)
 	signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
@@ -125,29 +298,30 @@ func (c *Client) Run() error {
 	return nil
 }
 
-// SetRSAKeys sets readable keys to Client object.
+// SetRSAKeys sets readable device and vault keys to Client object.
 func (c *Client) SetRSAKeys() error {
-	derKey, err := os.ReadFile(filepath.Join(c.config.CacheDir, constants.CertClientPrivateFilename))
+	key, err := c.loadPrivateKey()
 	if err != nil {
-		return fmt.Errorf("could not read private key: %w", err)
+		return err
 	}
-	key, err := x509.ParsePKCS8PrivateKey(derKey)
+	c.deviceKey = key
+	vaultKey, err := c.loadVaultKey()
 	if err != nil {
-		return fmt.Errorf("could not parse private key: %w", err)
+		return err
 	}
-	c.privateKey = key.(*rsa.PrivateKey)
+	c.privateKey = vaultKey
 	c.publicKey = &c.privateKey.PublicKey
 	return nil
 }
 
-func tlsConfig(dir string) (*tls.Config, error) {
+func tlsConfig(dir string, key *rsa.PrivateKey) (*tls.Config, error) {
 	derCert, err := os.ReadFile(filepath.Join(dir, constants.CertClientPublicFilename))
 	if err != nil {
 		return nil, fmt.Errorf("could not read client certificate: %w", err)
 	}
-	derKey, err := os.ReadFile(filepath.Join(dir, constants.CertClientPrivateFilename))
+	derKey, err := x509.MarshalPKCS8PrivateKey(key)
 	if err != nil {
-		return nil, fmt.Errorf("could not read private key: %w", err)
+		return nil, fmt.Errorf("could not marshal private key: %w", err)
 	}
 	keyPair, err := tls.X509KeyPair(
 		pem.EncodeToMemory(
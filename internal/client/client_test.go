@@ -21,6 +21,7 @@ func TestNewClient(t *testing.T) {
 					PublicAddress:  testPublicAddress,
 					PrivateAddress: testPrivateAddress,
 					CacheDir:       testCacheDir,
+					Password:       testPassword,
 				},
 			},
 			want: &Client{
@@ -28,6 +29,7 @@ func TestNewClient(t *testing.T) {
 					PublicAddress:  testPublicAddress,
 					PrivateAddress: testPrivateAddress,
 					CacheDir:       testCacheDir,
+					Password:       testPassword,
 				},
 			},
 		},
@@ -3,6 +3,9 @@ package client
 import (
 	"os/user"
 	"path/filepath"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/constants"
 )
 
 // Config main configuration for Client.
@@ -13,6 +16,20 @@ type Config struct {
 	PrivateAddress string
 	// CacheDir for save certificates.
 	CacheDir string
+	// Password asks master password which protects private key, PromptPassword by default.
+	Password PasswordFunc
+	// CAFingerprint is an expected SHA-256 fingerprint of server CA, printed by server init.
+	CAFingerprint string
+	// CAFile is a path to pinned server CA certificate (DER or PEM).
+	CAFile string
+	// PublicTLS connects to public server over server-only TLS.
+	PublicTLS bool
+	// RenewBefore is a time before expiration of client certificate when Connect renews it,
+	// constants.CertRenewBefore by default, negative disables renewal.
+	RenewBefore time.Duration
+	// Replica keeps local copy of encrypted records in CacheDir, reads are served by replica
+	// and changes made offline are sent to server on next connect.
+	Replica bool
 }
 
 // NewConfig constructs Config object.
@@ -21,6 +38,8 @@ func NewConfig() *Config {
 		PublicAddress:  "",
 		PrivateAddress: "",
 		CacheDir:       "",
+		Password:       PromptPassword,
+		RenewBefore:    constants.CertRenewBefore,
 	}
 }
 
@@ -30,6 +49,16 @@ func NewConfigWithOptions(config Config) *Config {
 	c.PublicAddress = config.PublicAddress
 	c.PrivateAddress = config.PrivateAddress
 	c.CacheDir = config.CacheDir
+	if config.Password != nil {
+		c.Password = config.Password
+	}
+	c.CAFingerprint = config.CAFingerprint
+	c.CAFile = config.CAFile
+	c.PublicTLS = config.PublicTLS
+	if config.RenewBefore != 0 {
+		c.RenewBefore = config.RenewBefore
+	}
+	c.Replica = config.Replica
 	return c
 }
 
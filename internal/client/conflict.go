@@ -0,0 +1,103 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrConflict is returned by Update if record was changed on server since it was read.
+var ErrConflict = errors.New("record was changed on server since it was read")
+
+// Conflict is a local change of record rejected by server because record was changed since it was read.
+type Conflict struct {
+	Type models.RecordType
+	ID   models.ID
+	// Local is a record changed by user, Remote is a current record on server.
+	Local  models.Record
+	Remote models.Record
+	// Merged is Remote with fields changed by user, Local values are kept for Fields changed in both.
+	Merged models.Record
+	Fields []Field
+}
+
+// Conflict reads current version of record and merges it with local changes of record base read
+// before by fields.
+func (c *Client) Conflict(
+	ctx context.Context, t models.RecordType, id models.ID, base, local models.Record,
+) (Conflict, error) {
+	remote, err := c.Get(ctx, t, id)
+	if err != nil {
+		return Conflict{}, err
+	}
+	conflict := Conflict{Type: t, ID: id, Local: local, Remote: remote, Merged: remote, Fields: make([]Field, 0)}
+	for _, field := range fields(t) {
+		was, _ := recordField(base, t, field)
+		mine, _ := recordField(local, t, field)
+		theirs, _ := recordField(remote, t, field)
+		if bytes.Equal(mine, was) || bytes.Equal(mine, theirs) {
+			continue
+		}
+		if !bytes.Equal(theirs, was) {
+			conflict.Fields = append(conflict.Fields, field)
+		}
+		setRecordField(&conflict.Merged, t, field, mine)
+	}
+	return conflict, nil
+}
+
+// ResolveConflict forgets conflict of offline update returned by Sync and saves its merged version
+// if save is set.
+func (c *Client) ResolveConflict(ctx context.Context, conflict Conflict, save bool) error {
+	if c.replica == nil {
+		return errors.New("replica is disabled")
+	}
+	if err := c.replica.Resolve(ctx, conflict.Type, conflict.ID); err != nil {
+		return err
+	}
+	if !save {
+		return nil
+	}
+	return c.Update(ctx, conflict.Type, conflict.ID, conflict.Merged)
+}
+
+// WriteConflict writes local and server versions of record and fields changed in both to w.
+func WriteConflict(w io.Writer, conflict Conflict) error {
+	versions := []struct {
+		title  string
+		record models.Record
+	}{{title: "Your version", record: conflict.Local}, {title: "Server version", record: conflict.Remote}}
+	for _, v := range versions {
+		if _, err := fmt.Fprintf(w, "%s of %s %d:\n", v.title, conflict.Type.String(), conflict.ID); err != nil {
+			return err
+		}
+		if err := WriteRecord(w, OutputTable, conflict.Type, v.record); err != nil {
+			return err
+		}
+	}
+	if len(conflict.Fields) == 0 {
+		_, err := fmt.Fprintln(w, "Changes don't overlap")
+		return err
+	}
+	names := make([]string, 0, len(conflict.Fields))
+	for _, field := range conflict.Fields {
+		names = append(names, field.String())
+	}
+	_, err := fmt.Fprintf(w, "Changed in both, your values are kept on merge: %s\n", strings.Join(names, ", "))
+	return err
+}
+
+// conflictError wraps error of change rejected by server because of concurrent change by ErrConflict.
+func conflictError(err error) error {
+	if status.Code(err) == codes.Aborted {
+		return fmt.Errorf("%w: %w", ErrConflict, err)
+	}
+	return err
+}
@@ -0,0 +1,207 @@
+package client
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/constants"
+	"github.com/sejo412/gophkeeper/internal/models"
+	"github.com/sejo412/gophkeeper/pkg/crypt"
+	pb "github.com/sejo412/gophkeeper/proto"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// vaultKeyAssociatedData binds encrypted vault key to its purpose.
+var vaultKeyAssociatedData = []byte("gophkeeper vault key")
+
+// ErrFingerprintMismatch is returned when key of device waiting for approval differs from enrolled one.
+var ErrFingerprintMismatch = errors.New("device fingerprint mismatch")
+
+// EnrollFunc shows enrollment code and device fingerprint which must be entered
+// and compared on approving device.
+type EnrollFunc func(code, fingerprint string)
+
+// Enroll creates device key, asks server to add this device to existing user and waits
+// until another device of user approves it, see ApproveDevice. Device key, certificates
+// and vault key are saved after approval.
+func (c *Client) Enroll(ctx context.Context, user, name string, show EnrollFunc) error {
+	device, err := c.newDevice(name)
+	if err != nil {
+		return err
+	}
+	grpcClient, err := c.dialPublic()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = grpcClient.Close()
+	}()
+	publicClient := pb.NewPublicClient(grpcClient)
+	resp, err := publicClient.Enroll(
+		ctx, &pb.EnrollRequest{
+			User:        proto.String(user),
+			CertRequest: device.certRequest,
+		},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to enroll device: %w", err)
+	}
+	der, err := x509.MarshalPKIXPublicKey(&device.key.PublicKey)
+	if err != nil {
+		return fmt.Errorf("failed to marshal public key: %w", err)
+	}
+	show(resp.GetCode(), KeyFingerprint(der))
+	ticker := time.NewTicker(constants.EnrollmentPollInterval)
+	defer ticker.Stop()
+	for {
+		status, err := publicClient.EnrollStatus(ctx, &pb.EnrollStatusRequest{Code: resp.Code})
+		if err != nil {
+			return fmt.Errorf("failed to get enrollment status: %w", err)
+		}
+		if status.GetApproved() {
+			if err = c.verifyCA(status.GetCaCertificate()); err != nil {
+				return err
+			}
+			return c.saveDevice(device, status.GetCaCertificate(), status.GetClientCertificate(), status.GetVaultKey())
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// Devices returns devices of user and devices waiting for approval.
+func (c *Client) Devices(ctx context.Context) ([]models.Device, []models.Enrollment, error) {
+	resp, err := c.client.ListDevices(ctx, &emptypb.Empty{})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list devices: %w", err)
+	}
+	devices := make([]models.Device, 0, len(resp.GetDevices()))
+	for _, d := range resp.GetDevices() {
+		devices = append(
+			devices, models.Device{
+				ID:      models.DeviceID(d.GetId()),
+				Cn:      d.GetName(),
+				Created: time.Unix(d.GetCreated(), 0),
+				Current: d.GetCurrent(),
+			},
+		)
+	}
+	pending := make([]models.Enrollment, 0, len(resp.GetPending()))
+	for _, p := range resp.GetPending() {
+		pending = append(
+			pending, models.Enrollment{
+				Code:      p.GetCode(),
+				Cn:        p.GetName(),
+				PublicKey: p.GetPublicKey(),
+				Created:   time.Unix(p.GetCreated(), 0),
+			},
+		)
+	}
+	return devices, pending, nil
+}
+
+// ApproveDevice approves device waiting for approval by code and gives it vault key. Fingerprint
+// shown by Enroll on new device must match public key of waiting device, otherwise vault key is not given.
+func (c *Client) ApproveDevice(ctx context.Context, code, fingerprint string) (models.Enrollment, error) {
+	_, pending, err := c.Devices(ctx)
+	if err != nil {
+		return models.Enrollment{}, err
+	}
+	for _, e := range pending {
+		if !strings.EqualFold(e.Code, code) {
+			continue
+		}
+		if actual := KeyFingerprint(e.PublicKey); !strings.EqualFold(actual, strings.TrimSpace(fingerprint)) {
+			return models.Enrollment{}, fmt.Errorf(
+				"%w: device %q has fingerprint %s, want %s", ErrFingerprintMismatch, e.Cn, actual, fingerprint,
+			)
+		}
+		key, err := x509.ParsePKIXPublicKey(e.PublicKey)
+		if err != nil {
+			return models.Enrollment{}, fmt.Errorf("failed to parse device public key: %w", err)
+		}
+		pubKey, ok := key.(*rsa.PublicKey)
+		if !ok {
+			return models.Enrollment{}, errors.New("device public key is not RSA")
+		}
+		vaultKey, err := wrapVaultKey(pubKey, c.privateKey)
+		if err != nil {
+			return models.Enrollment{}, err
+		}
+		if _, err = c.client.ApproveDevice(
+			ctx, &pb.ApproveDeviceRequest{
+				Code:     proto.String(e.Code),
+				VaultKey: vaultKey,
+			},
+		); err != nil {
+			return models.Enrollment{}, fmt.Errorf("failed to approve device %q: %w", e.Cn, err)
+		}
+		return e, nil
+	}
+	return models.Enrollment{}, fmt.Errorf("device with code %q not waiting for approval", code)
+}
+
+// RemoveDevice removes device of user, removed device loses access to the server.
+func (c *Client) RemoveDevice(ctx context.Context, id models.DeviceID) error {
+	if _, err := c.client.RemoveDevice(ctx, &pb.RemoveDeviceRequest{Id: proto.Int64(int64(id))}); err != nil {
+		return fmt.Errorf("failed to remove device %d: %w", id, err)
+	}
+	return nil
+}
+
+// KeyFingerprint returns short SHA-256 fingerprint of PKIX public key.
+func KeyFingerprint(publicKey []byte) string {
+	sum := sha256.Sum256(publicKey)
+	return hex.EncodeToString(sum[:8])
+}
+
+// loadVaultKey reads vault key encrypted for device key. Device registered before vault key
+// was introduced has no vault key file, its device key encrypts records.
+func (c *Client) loadVaultKey() (*rsa.PrivateKey, error) {
+	data, err := os.ReadFile(filepath.Join(c.config.CacheDir, constants.VaultKeyFilename))
+	if errors.Is(err, os.ErrNotExist) {
+		return c.deviceKey, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("could not read vault key: %w", err)
+	}
+	der, err := crypt.DecryptWithAssociatedData(c.deviceKey, data, vaultKeyAssociatedData)
+	if err != nil {
+		return nil, fmt.Errorf("could not decrypt vault key: %w", err)
+	}
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse vault key: %w", err)
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("vault key is not RSA")
+	}
+	return rsaKey, nil
+}
+
+// wrapVaultKey encrypts vault key for device public key.
+func wrapVaultKey(pubKey *rsa.PublicKey, vaultKey *rsa.PrivateKey) ([]byte, error) {
+	der, err := x509.MarshalPKCS8PrivateKey(vaultKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal vault key: %w", err)
+	}
+	wrapped, err := crypt.EncryptWithAssociatedData(pubKey, der, vaultKeyAssociatedData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt vault key: %w", err)
+	}
+	return wrapped, nil
+}
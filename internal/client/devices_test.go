@@ -0,0 +1,187 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+	"github.com/sejo412/gophkeeper/internal/server"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestClient_Enroll(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	first := NewClient(
+		Config{
+			PublicAddress:  testPublicAddress,
+			PrivateAddress: testPrivateAddress,
+			CacheDir:       t.TempDir(),
+			Password:       testPassword,
+		},
+	)
+	if err := first.Register("testUserDevices"); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if err := first.Connect(); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer func() {
+		_ = first.Close()
+	}()
+	record := models.Record{Text: models.Text{Text: "shared", Meta: "testDevices"}}
+	if err := first.Add(ctx, models.RecordText, record); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+
+	second := NewClient(
+		Config{
+			PublicAddress:  testPublicAddress,
+			PrivateAddress: testPrivateAddress,
+			CacheDir:       t.TempDir(),
+			Password:       testPassword,
+		},
+	)
+	if err := second.Enroll(ctx, "unknownUser", "testDevice2", func(string, string) {}); err == nil {
+		t.Errorf("Enroll() to unknown user error = nil, want error")
+	}
+	codes := make(chan string, 1)
+	fingerprints := make(chan string, 1)
+	enrolled := make(chan error, 1)
+	go func() {
+		enrolled <- second.Enroll(
+			ctx, "testUserDevices", "testDevice2", func(code, fingerprint string) {
+				codes <- code
+				fingerprints <- fingerprint
+			},
+		)
+	}()
+	code, fingerprint := <-codes, <-fingerprints
+	if _, err := first.ApproveDevice(ctx, "unknown", fingerprint); err == nil {
+		t.Errorf("ApproveDevice() with unknown code error = nil, want error")
+	}
+	if _, err := first.ApproveDevice(ctx, code, "0011223344556677"); !errors.Is(err, ErrFingerprintMismatch) {
+		t.Errorf("ApproveDevice() with other fingerprint error = %v, want %v", err, ErrFingerprintMismatch)
+	}
+	if _, err := first.ApproveDevice(ctx, code, fingerprint); err != nil {
+		t.Fatalf("ApproveDevice() error = %v", err)
+	}
+	if err := <-enrolled; err != nil {
+		t.Fatalf("Enroll() error = %v", err)
+	}
+
+	if err := second.Connect(); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer func() {
+		_ = second.Close()
+	}()
+	records, err := second.List(ctx, models.RecordText)
+	if err != nil || len(records.Text) != 1 {
+		t.Fatalf("List() on enrolled device = %v, %v, want one record", records, err)
+	}
+	got, err := second.Get(ctx, models.RecordText, records.Text[0].ID)
+	if err != nil || got.Text.Text != "shared" {
+		t.Errorf("Get() on enrolled device = %v, %v, want %q", got, err, "shared")
+	}
+
+	devices, _, err := first.Devices(ctx)
+	if err != nil || len(devices) != 2 {
+		t.Fatalf("Devices() = %v, %v, want 2 devices", devices, err)
+	}
+	if !devices[0].Current || devices[1].Current {
+		t.Errorf("Devices() current = %v, %v, want first only", devices[0].Current, devices[1].Current)
+	}
+	if err = first.RemoveDevice(ctx, devices[0].ID); err == nil {
+		t.Errorf("RemoveDevice() of current device error = nil, want error")
+	}
+	if err = first.RemoveDevice(ctx, devices[1].ID); err != nil {
+		t.Fatalf("RemoveDevice() error = %v", err)
+	}
+	if _, err = second.List(ctx, models.RecordText); err == nil {
+		t.Errorf("List() on removed device error = nil, want error")
+	}
+}
+
+func TestClient_Revoked(t *testing.T) {
+	ctx := context.Background()
+	c := NewClient(
+		Config{
+			PublicAddress:  testPublicAddress,
+			PrivateAddress: testPrivateAddress,
+			CacheDir:       t.TempDir(),
+			Password:       testPassword,
+		},
+	)
+	if err := c.Register("testUserRevoked"); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if err := c.Connect(); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer func() {
+		_ = c.Close()
+	}()
+	if _, err := c.ListAll(ctx); err != nil {
+		t.Fatalf("ListAll() error = %v", err)
+	}
+	s := server.NewServer(server.Config{CacheDir: testServerCacheDir})
+	revoked, err := s.Revoke(ctx, "", "testUserRevoked", models.RevocationKeyCompromise)
+	if err != nil || len(revoked) != 1 {
+		t.Fatalf("Revoke() = %v, %v, want one revoked certificate", revoked, err)
+	}
+	if _, err = c.ListAll(ctx); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("ListAll() with revoked certificate error = %v, want Unauthenticated", err)
+	}
+}
+
+func TestClient_AdminDisabled(t *testing.T) {
+	ctx := context.Background()
+	c := NewClient(
+		Config{
+			PublicAddress:  testPublicAddress,
+			PrivateAddress: testPrivateAddress,
+			CacheDir:       t.TempDir(),
+			Password:       testPassword,
+		},
+	)
+	if err := c.Register("testUserDisabled"); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if err := c.Connect(); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer func() {
+		_ = c.Close()
+	}()
+	// running server is administrated by its admin service
+	admin, err := server.NewServer(server.Config{CacheDir: testServerCacheDir}).Admin()
+	if err != nil {
+		t.Fatalf("Admin() error = %v", err)
+	}
+	defer func() {
+		_ = admin.Close()
+	}()
+	if err = admin.DisableUser(ctx, "testUserDisabled", true); err != nil {
+		t.Fatalf("DisableUser() error = %v", err)
+	}
+	if _, err = c.ListAll(ctx); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("ListAll() of disabled user error = %v, want PermissionDenied", err)
+	}
+	if err = admin.DisableUser(ctx, "testUserDisabled", false); err != nil {
+		t.Fatalf("DisableUser() error = %v", err)
+	}
+	if _, err = c.ListAll(ctx); err != nil {
+		t.Errorf("ListAll() of enabled user error = %v", err)
+	}
+	if _, err = admin.User(ctx, "unknownUser"); status.Code(err) != codes.NotFound {
+		t.Errorf("User() of unknown user error = %v, want NotFound", err)
+	}
+	stats, err := admin.Stats(ctx)
+	if err != nil || len(stats) == 0 {
+		t.Errorf("Stats() = %v, %v, want users", stats, err)
+	}
+}
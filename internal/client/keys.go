@@ -0,0 +1,70 @@
+package client
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+	"github.com/sejo412/gophkeeper/pkg/crypt"
+)
+
+// keyCache keeps unwrapped record data keys of user, so RSA decryption is done once per record.
+type keyCache struct {
+	mu   sync.Mutex
+	keys map[string][]byte
+}
+
+func newKeyCache() *keyCache {
+	return &keyCache{keys: make(map[string][]byte)}
+}
+
+func (k *keyCache) get(wrappedKey models.Encrypted) ([]byte, bool) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	key, ok := k.keys[string(wrappedKey)]
+	return key, ok
+}
+
+func (k *keyCache) put(wrappedKey models.Encrypted, key []byte) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.keys[string(wrappedKey)] = key
+}
+
+// newRecordKey generates data key for record and returns it with wrapped one.
+func (c *Client) newRecordKey() ([]byte, models.Encrypted, error) {
+	key, err := crypt.NewDataKey()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to generate record key: %w", err)
+	}
+	wrappedKey, err := crypt.WrapKey(c.publicKey, key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to wrap record key: %w", err)
+	}
+	c.keys.put(wrappedKey, key)
+	return key, wrappedKey, nil
+}
+
+// recordKey returns unwrapped data key of record, nil for legacy record without data key.
+func (c *Client) recordKey(wrappedKey models.Encrypted) ([]byte, error) {
+	if len(wrappedKey) == 0 {
+		return nil, nil
+	}
+	if key, ok := c.keys.get(wrappedKey); ok {
+		return key, nil
+	}
+	key, err := crypt.UnwrapKey(c.privateKey, wrappedKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unwrap record key: %w", err)
+	}
+	c.keys.put(wrappedKey, key)
+	return key, nil
+}
+
+// decryptWithKey decrypts field with record data key or, for legacy record, with private RSA.
+func (c *Client) decryptWithKey(key []byte, data models.Encrypted, associatedData []byte) ([]byte, error) {
+	if key == nil {
+		return crypt.DecryptWithAssociatedData(c.privateKey, data, associatedData)
+	}
+	return crypt.DecryptWithDataKey(key, data, associatedData)
+}
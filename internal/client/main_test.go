@@ -23,10 +23,15 @@ var (
 	testPrivateAddress = net.JoinHostPort("127.0.0.1", strconv.Itoa(testPrivatePort))
 )
 
-var testClient *Client
+var (
+	testClient        *Client
+	testRecordsClient *Client
+)
 
 var (
+	testServerCacheDir   string
 	testCacheDir         string
+	testRecordsCacheDir  string
 	testUser1CertRequest []byte
 	testUser1            = models.User{
 		ID: models.UserID(1),
@@ -40,20 +45,25 @@ var (
 		ID: models.UserID(42),
 		Cn: "testUser42",
 	}
+	testMasterPassword             = []byte("testMasterPassword")
 	testRecordPasswordEncrypted    []byte
 	testNewRecordPasswordEncrypted []byte
 )
 
+func testPassword(string) ([]byte, error) {
+	return testMasterPassword, nil
+}
+
 func TestMain(m *testing.M) {
 	var err error
-	serverCacheDir, err := os.MkdirTemp(os.TempDir(), "server-cache")
+	testServerCacheDir, err = os.MkdirTemp(os.TempDir(), "server-cache")
 	if err != nil {
 		panic(err)
 	}
 	defer func() {
-		_ = os.RemoveAll(serverCacheDir)
+		_ = os.RemoveAll(testServerCacheDir)
 	}()
-	store, err := sqlite.New(filepath.Join(serverCacheDir, "test.db"))
+	store, err := sqlite.New(filepath.Join(testServerCacheDir, "test.db"))
 	if err != nil {
 		panic(err)
 	}
@@ -64,7 +74,7 @@ func TestMain(m *testing.M) {
 		server.Config{
 			PublicPort:  testPublicPort,
 			PrivatePort: testPrivatePort,
-			CacheDir:    serverCacheDir,
+			CacheDir:    testServerCacheDir,
 			DNSNames:    []string{"localhost"},
 		},
 	)
@@ -83,6 +93,13 @@ func TestMain(m *testing.M) {
 	defer func() {
 		_ = os.RemoveAll(testCacheDir)
 	}()
+	testRecordsCacheDir, err = os.MkdirTemp(os.TempDir(), "records-cache-dir")
+	if err != nil {
+		panic(err)
+	}
+	defer func() {
+		_ = os.RemoveAll(testRecordsCacheDir)
+	}()
 
 	exitVal := m.Run()
 	os.Exit(exitVal)
@@ -0,0 +1,322 @@
+package client
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+	"gopkg.in/yaml.v3"
+)
+
+// OutputFormat is a format of printed records.
+type OutputFormat int
+
+// Supported output formats.
+const (
+	OutputTable OutputFormat = iota
+	OutputJSON
+	OutputYAML
+)
+
+// Names of OutputFormats.
+const (
+	OutputTableName string = "table"
+	OutputJSONName  string = "json"
+	OutputYAMLName  string = "yaml"
+)
+
+// revisionName is a name of revision of record in table output.
+const revisionName = "Revision"
+
+type listItem struct {
+	ID       models.ID   `json:"id" yaml:"id"`
+	Revision int64       `json:"revision" yaml:"revision"`
+	Meta     models.Meta `json:"meta" yaml:"meta"`
+}
+
+// binDocument is models.Bin with base64 encoded data, same in all document formats.
+type binDocument struct {
+	ID       models.ID   `json:"id" yaml:"id"`
+	Data     string      `json:"data" yaml:"data"`
+	Meta     models.Meta `json:"meta" yaml:"meta"`
+	Revision int64       `json:"revision" yaml:"revision"`
+}
+
+type deviceItem struct {
+	ID      models.DeviceID `json:"id" yaml:"id"`
+	Name    string          `json:"name" yaml:"name"`
+	Created time.Time       `json:"created" yaml:"created"`
+	Current bool            `json:"current" yaml:"current"`
+}
+
+type versionItem struct {
+	Version int64       `json:"version" yaml:"version"`
+	Created time.Time   `json:"created" yaml:"created"`
+	Meta    models.Meta `json:"meta" yaml:"meta"`
+}
+
+type trashItem struct {
+	Type    string      `json:"type" yaml:"type"`
+	ID      models.ID   `json:"id" yaml:"id"`
+	Deleted time.Time   `json:"deleted" yaml:"deleted"`
+	Meta    models.Meta `json:"meta" yaml:"meta"`
+}
+
+type pendingItem struct {
+	Code        string    `json:"code" yaml:"code"`
+	Name        string    `json:"name" yaml:"name"`
+	Fingerprint string    `json:"fingerprint" yaml:"fingerprint"`
+	Created     time.Time `json:"created" yaml:"created"`
+}
+
+// String implements Stringer interface.
+func (o OutputFormat) String() string {
+	switch o {
+	case OutputTable:
+		return OutputTableName
+	case OutputJSON:
+		return OutputJSONName
+	case OutputYAML:
+		return OutputYAMLName
+	default:
+		return "unknown output format"
+	}
+}
+
+// ParseOutputFormat returns OutputFormat by its name.
+func ParseOutputFormat(name string) (OutputFormat, error) {
+	switch strings.ToLower(name) {
+	case OutputTableName:
+		return OutputTable, nil
+	case OutputJSONName:
+		return OutputJSON, nil
+	case OutputYAMLName:
+		return OutputYAML, nil
+	default:
+		return OutputTable, fmt.Errorf("unknown output format %q", name)
+	}
+}
+
+// WriteRecord writes all fields and revision of decrypted models.Record to w, revision is passed
+// back by update to detect concurrent changes.
+func WriteRecord(w io.Writer, format OutputFormat, t models.RecordType, record models.Record) error {
+	if fields(t) == nil {
+		return errUnknownRecordType
+	}
+	if format != OutputTable {
+		return writeDocument(w, format, typedRecord(record, t))
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 1, ' ', 0)
+	for _, field := range fields(t) {
+		val, _ := recordField(record, t, field)
+		if _, err := fmt.Fprintf(tw, "%s:\t%s\n", field.String(), string(val)); err != nil {
+			return err
+		}
+	}
+	if _, err := fmt.Fprintf(tw, "%s:\t%d\n", revisionName, *recordRevision(&record, t)); err != nil {
+		return err
+	}
+	return tw.Flush()
+}
+
+// WriteRecords writes ID, revision and Meta of records to w. In table format records grouped
+// by type with header if more than one models.RecordType passed, in other formats
+// records are written as one document with records grouped by type's short name.
+func WriteRecords(w io.Writer, format OutputFormat, records models.Records, types ...models.RecordType) error {
+	for _, t := range types {
+		if fields(t) == nil {
+			return errUnknownRecordType
+		}
+	}
+	if format != OutputTable {
+		doc := make(map[string][]listItem, len(types))
+		for _, t := range types {
+			doc[recordTypeKey(t)] = listItems(records, t)
+		}
+		return writeDocument(w, format, doc)
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, t := range types {
+		if len(types) > 1 {
+			if _, err := fmt.Fprintf(tw, "%s:\n", t.String()); err != nil {
+				return err
+			}
+		}
+		if _, err := fmt.Fprintf(tw, "%s\t%s\t%s\n", FieldIDName, revisionName, FieldMetaName); err != nil {
+			return err
+		}
+		for _, item := range listItems(records, t) {
+			if _, err := fmt.Fprintf(tw, "%d\t%d\t%s\n", item.ID, item.Revision, item.Meta); err != nil {
+				return err
+			}
+		}
+	}
+	return tw.Flush()
+}
+
+// WriteDevices writes devices of user and devices waiting for approval to w.
+func WriteDevices(w io.Writer, format OutputFormat, devices []models.Device, pending []models.Enrollment) error {
+	doc := struct {
+		Devices []deviceItem  `json:"devices" yaml:"devices"`
+		Pending []pendingItem `json:"pending" yaml:"pending"`
+	}{
+		Devices: make([]deviceItem, 0, len(devices)),
+		Pending: make([]pendingItem, 0, len(pending)),
+	}
+	for _, d := range devices {
+		doc.Devices = append(doc.Devices, deviceItem{ID: d.ID, Name: d.Cn, Created: d.Created, Current: d.Current})
+	}
+	for _, e := range pending {
+		doc.Pending = append(
+			doc.Pending, pendingItem{
+				Code: e.Code, Name: e.Cn, Fingerprint: KeyFingerprint(e.PublicKey), Created: e.Created,
+			},
+		)
+	}
+	if format != OutputTable {
+		return writeDocument(w, format, doc)
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintf(tw, "id\tname\tcreated\t\n"); err != nil {
+		return err
+	}
+	for _, d := range doc.Devices {
+		current := ""
+		if d.Current {
+			current = "(current)"
+		}
+		if _, err := fmt.Fprintf(
+			tw, "%d\t%s\t%s\t%s\n", d.ID, d.Name, d.Created.Format(time.DateTime), current,
+		); err != nil {
+			return err
+		}
+	}
+	if len(doc.Pending) > 0 {
+		if _, err := fmt.Fprintf(tw, "waiting for approval:\ncode\tname\tfingerprint\tcreated\n"); err != nil {
+			return err
+		}
+		for _, e := range doc.Pending {
+			if _, err := fmt.Fprintf(
+				tw, "%s\t%s\t%s\t%s\n", e.Code, e.Name, e.Fingerprint, e.Created.Format(time.DateTime),
+			); err != nil {
+				return err
+			}
+		}
+	}
+	return tw.Flush()
+}
+
+// WriteVersions writes previous versions of record to w.
+func WriteVersions(w io.Writer, format OutputFormat, t models.RecordType, versions []Version) error {
+	items := make([]versionItem, 0, len(versions))
+	for _, v := range versions {
+		meta, _ := recordField(v.Record, t, FieldMeta)
+		items = append(items, versionItem{Version: v.Number, Created: v.Created, Meta: models.Meta(meta)})
+	}
+	if format != OutputTable {
+		return writeDocument(w, format, items)
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintf(tw, "Version\tCreated\t%s\n", FieldMetaName); err != nil {
+		return err
+	}
+	for _, item := range items {
+		if _, err := fmt.Fprintf(tw, "%d\t%s\t%s\n", item.Version, item.Created.Format(time.DateTime), item.Meta); err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
+
+// WriteTrash writes deleted records to w.
+func WriteTrash(w io.Writer, format OutputFormat, items []TrashItem) error {
+	docs := make([]trashItem, 0, len(items))
+	for _, item := range items {
+		meta, _ := recordField(item.Record, item.Type, FieldMeta)
+		docs = append(
+			docs, trashItem{Type: recordTypeKey(item.Type), ID: item.ID, Deleted: item.Deleted, Meta: models.Meta(meta)},
+		)
+	}
+	if format != OutputTable {
+		return writeDocument(w, format, docs)
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintf(tw, "Type\t%s\tDeleted\t%s\n", FieldIDName, FieldMetaName); err != nil {
+		return err
+	}
+	for _, doc := range docs {
+		if _, err := fmt.Fprintf(
+			tw, "%s\t%d\t%s\t%s\n", doc.Type, doc.ID, doc.Deleted.Format(time.DateTime), doc.Meta,
+		); err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
+
+func writeDocument(w io.Writer, format OutputFormat, doc any) error {
+	switch format {
+	case OutputJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(doc)
+	case OutputYAML:
+		enc := yaml.NewEncoder(w)
+		enc.SetIndent(2)
+		if err := enc.Encode(doc); err != nil {
+			return err
+		}
+		return enc.Close()
+	default:
+		return fmt.Errorf("unsupported output format %q", format.String())
+	}
+}
+
+func typedRecord(record models.Record, t models.RecordType) any {
+	switch t {
+	case models.RecordPassword:
+		return record.Password
+	case models.RecordText:
+		return record.Text
+	case models.RecordBin:
+		return binDocument{
+			ID:       record.Bin.ID,
+			Data:     base64.StdEncoding.EncodeToString(record.Bin.Data),
+			Meta:     record.Bin.Meta,
+			Revision: record.Bin.Revision,
+		}
+	case models.RecordBank:
+		return record.Bank
+	default:
+		return nil
+	}
+}
+
+func listItems(records models.Records, t models.RecordType) []listItem {
+	items := make([]listItem, 0)
+	switch t {
+	case models.RecordPassword:
+		for _, r := range records.Password {
+			items = append(items, listItem{ID: r.ID, Revision: r.Revision, Meta: r.Meta})
+		}
+	case models.RecordText:
+		for _, r := range records.Text {
+			items = append(items, listItem{ID: r.ID, Revision: r.Revision, Meta: r.Meta})
+		}
+	case models.RecordBin:
+		for _, r := range records.Bin {
+			items = append(items, listItem{ID: r.ID, Revision: r.Revision, Meta: r.Meta})
+		}
+	case models.RecordBank:
+		for _, r := range records.Bank {
+			items = append(items, listItem{ID: r.ID, Revision: r.Revision, Meta: r.Meta})
+		}
+	default:
+	}
+	return items
+}
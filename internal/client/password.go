@@ -0,0 +1,130 @@
+package client
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/sejo412/gophkeeper/internal/constants"
+	"github.com/sejo412/gophkeeper/internal/helpers"
+	"github.com/sejo412/gophkeeper/pkg/crypt"
+	"golang.org/x/term"
+)
+
+const (
+	promptMasterPassword       = "Master password: "
+	promptNewMasterPassword    = "New master password: "
+	promptRepeatMasterPassword = "Repeat master password: "
+)
+
+var errPasswordMismatch = errors.New("passwords do not match")
+
+// PasswordFunc returns password requested by prompt.
+type PasswordFunc func(prompt string) ([]byte, error)
+
+// PromptPassword reads password from terminal without echo. If stdin is not a terminal
+// password is taken from constants.MasterPasswordEnv environment variable.
+func PromptPassword(prompt string) ([]byte, error) {
+	fd := int(os.Stdin.Fd())
+	if !term.IsTerminal(fd) {
+		password, ok := os.LookupEnv(constants.MasterPasswordEnv)
+		if !ok {
+			return nil, fmt.Errorf("stdin is not a terminal and %s is not set", constants.MasterPasswordEnv)
+		}
+		return []byte(password), nil
+	}
+	_, _ = fmt.Fprint(os.Stderr, prompt)
+	password, err := term.ReadPassword(fd)
+	_, _ = fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read password: %w", err)
+	}
+	return password, nil
+}
+
+// Passwd changes master password which protects private key.
+func (c *Client) Passwd() error {
+	key, err := c.loadPrivateKey()
+	if err != nil {
+		return err
+	}
+	password, err := c.newMasterPassword()
+	if err != nil {
+		return err
+	}
+	return c.savePrivateKey(key, password)
+}
+
+func (c *Client) newMasterPassword() ([]byte, error) {
+	password, err := c.config.Password(promptNewMasterPassword)
+	if err != nil {
+		return nil, err
+	}
+	if len(password) == 0 {
+		return nil, errors.New("empty master password")
+	}
+	repeated, err := c.config.Password(promptRepeatMasterPassword)
+	if err != nil {
+		return nil, err
+	}
+	if !bytes.Equal(password, repeated) {
+		return nil, errPasswordMismatch
+	}
+	return password, nil
+}
+
+// savePrivateKey saves private key encrypted with master password.
+func (c *Client) savePrivateKey(key *rsa.PrivateKey, password []byte) error {
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return fmt.Errorf("failed to marshal private key: %w", err)
+	}
+	params, err := crypt.DefaultArgon2idParams()
+	if err != nil {
+		return err
+	}
+	encrypted, err := crypt.EncryptWithPassword(password, der, params)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt private key: %w", err)
+	}
+	// replace key atomically, so interrupted passwd doesn't lose it
+	path := filepath.Join(c.config.CacheDir, constants.CertClientPrivateFilename)
+	if err = helpers.SaveRegularFile(path+".tmp", encrypted, 0600); err != nil {
+		return fmt.Errorf("failed to save private key: %w", err)
+	}
+	if err = os.Rename(path+".tmp", path); err != nil {
+		return fmt.Errorf("failed to save private key: %w", err)
+	}
+	return nil
+}
+
+// loadPrivateKey reads private key and asks master password if key is encrypted.
+// Legacy unencrypted key is loaded as is.
+func (c *Client) loadPrivateKey() (*rsa.PrivateKey, error) {
+	der, err := os.ReadFile(filepath.Join(c.config.CacheDir, constants.CertClientPrivateFilename))
+	if err != nil {
+		return nil, fmt.Errorf("could not read private key: %w", err)
+	}
+	if crypt.IsPasswordEncrypted(der) {
+		password, er := c.config.Password(promptMasterPassword)
+		if er != nil {
+			return nil, er
+		}
+		if der, er = crypt.DecryptWithPassword(password, der); er != nil {
+			return nil, fmt.Errorf("could not decrypt private key: %w", er)
+		}
+	}
+	key, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse private key: %w", err)
+	}
+	rsaKey, ok := key.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("private key is not RSA")
+	}
+	return rsaKey, nil
+}
@@ -0,0 +1,99 @@
+package client
+
+import (
+	"crypto/x509"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sejo412/gophkeeper/internal/constants"
+	"github.com/sejo412/gophkeeper/pkg/certs"
+	"github.com/sejo412/gophkeeper/pkg/crypt"
+)
+
+// testPasswords returns PasswordFunc which answers passwords in order.
+func testPasswords(passwords ...string) PasswordFunc {
+	return func(string) ([]byte, error) {
+		if len(passwords) == 0 {
+			return nil, errors.New("no more passwords")
+		}
+		password := passwords[0]
+		passwords = passwords[1:]
+		return []byte(password), nil
+	}
+}
+
+func TestClient_Passwd(t *testing.T) {
+	dir := t.TempDir()
+	keyPath := filepath.Join(dir, constants.CertClientPrivateFilename)
+	key, err := certs.GenRsaKey(constants.KeyBits)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// legacy unencrypted key
+	if err = os.WriteFile(keyPath, der, 0600); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name      string
+		passwords PasswordFunc
+		wantErr   bool
+	}{
+		{
+			name:      "protect legacy key",
+			passwords: testPasswords("first", "first"),
+			wantErr:   false,
+		},
+		{
+			name:      "wrong password",
+			passwords: testPasswords("wrong"),
+			wantErr:   true,
+		},
+		{
+			name:      "new passwords mismatch",
+			passwords: testPasswords("first", "second", "third"),
+			wantErr:   true,
+		},
+		{
+			name:      "empty new password",
+			passwords: testPasswords("first", ""),
+			wantErr:   true,
+		},
+		{
+			name:      "change password",
+			passwords: testPasswords("first", "second", "second"),
+			wantErr:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := NewClient(Config{CacheDir: dir, Password: tt.passwords})
+			if err := c.Passwd(); (err != nil) != tt.wantErr {
+				t.Fatalf("Passwd() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			data, err := os.ReadFile(keyPath)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !crypt.IsPasswordEncrypted(data) {
+				t.Errorf("Passwd() saved unencrypted key")
+			}
+		})
+	}
+	c := NewClient(Config{CacheDir: dir, Password: testPasswords("first")})
+	if err = c.SetRSAKeys(); !errors.Is(err, crypt.ErrInvalidPassword) {
+		t.Errorf("SetRSAKeys() with old password error = %v, want %v", err, crypt.ErrInvalidPassword)
+	}
+	c = NewClient(Config{CacheDir: dir, Password: testPasswords("second")})
+	if err = c.SetRSAKeys(); err != nil {
+		t.Fatalf("SetRSAKeys() error = %v", err)
+	}
+	if !c.privateKey.Equal(key) {
+		t.Errorf("SetRSAKeys() loaded another key")
+	}
+}
@@ -0,0 +1,337 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+	"github.com/sejo412/gophkeeper/internal/protoconv"
+	"github.com/sejo412/gophkeeper/pkg/crypt"
+	pb "github.com/sejo412/gophkeeper/proto"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+var (
+	errUnknownRecordType = errors.New("unknown record type")
+	errFieldBinding      = errors.New("field is not bound to record or corrupted")
+	errBinTruncated      = errors.New("binary data is truncated")
+)
+
+// ListAll returns ID and decrypted Meta for all records.
+func (c *Client) ListAll(ctx context.Context) (models.Records, error) {
+	if c.replica != nil {
+		encrypted, err := c.replica.ListAll(ctx)
+		if err != nil {
+			return models.Records{}, err
+		}
+		return c.decryptRecords(encrypted)
+	}
+	resp, err := c.client.ListAll(ctx, &emptypb.Empty{})
+	if err != nil {
+		return models.Records{}, fmt.Errorf("failed to list records: %w", err)
+	}
+	return c.decryptRecords(protoconv.RecordsFromProto(resp.GetItems()))
+}
+
+// List returns ID and decrypted Meta for all records by models.RecordType.
+func (c *Client) List(ctx context.Context, t models.RecordType) (models.Records, error) {
+	if fields(t) == nil {
+		return models.Records{}, errUnknownRecordType
+	}
+	if c.replica != nil {
+		encrypted, err := c.replica.List(ctx, t)
+		if err != nil {
+			return models.Records{}, err
+		}
+		return c.decryptRecords(encrypted)
+	}
+	resp, err := c.client.List(
+		ctx, &pb.ListRequest{
+			Type: protoRecordType(modelRecordTypeToProto(t)),
+		},
+	)
+	if err != nil {
+		return models.Records{}, fmt.Errorf("failed to list %s: %w", t.String(), err)
+	}
+	return c.decryptRecords(protoconv.RecordsFromProto(resp.GetItems()))
+}
+
+// Get returns decrypted models.Record by models.RecordType and models.ID.
+func (c *Client) Get(ctx context.Context, t models.RecordType, id models.ID) (models.Record, error) {
+	if fields(t) == nil {
+		return models.Record{}, errUnknownRecordType
+	}
+	if c.replica != nil {
+		record, err := c.replica.Get(ctx, t, id)
+		if err != nil {
+			return models.Record{}, err
+		}
+		return c.decryptRecord(t, id, record)
+	}
+	record, err := c.read(ctx, t, id)
+	if err != nil {
+		return models.Record{}, err
+	}
+	return c.decryptRecord(t, id, record)
+}
+
+// read returns encrypted models.RecordEncrypted from server.
+func (c *Client) read(ctx context.Context, t models.RecordType, id models.ID) (models.RecordEncrypted, error) {
+	resp, err := c.client.Read(
+		ctx, &pb.GetRecordRequest{
+			Type:         protoRecordType(modelRecordTypeToProto(t)),
+			RecordNumber: protoID(int(id)),
+		},
+	)
+	if err != nil {
+		return models.RecordEncrypted{}, fmt.Errorf("failed to get %s with ID %d: %w", t.String(), id, err)
+	}
+	itemType, record := protoconv.RecordFromProto(resp.GetItem())
+	if itemType != t {
+		return models.RecordEncrypted{}, fmt.Errorf("server returned %s instead of %s", itemType.String(), t.String())
+	}
+	return record, nil
+}
+
+// Add encrypts and creates new models.Record by models.RecordType.
+func (c *Client) Add(ctx context.Context, t models.RecordType, record models.Record) error {
+	encrypted, err := c.encryptRecord(t, record)
+	if err != nil {
+		return err
+	}
+	if c.replica != nil {
+		id, er := c.replica.Create(ctx, t, encrypted)
+		if er != nil {
+			return er
+		}
+		return c.syncChanged(ctx, t, id)
+	}
+	if _, err = c.client.Create(
+		ctx, &pb.AddRecordRequest{
+			Type: protoRecordType(modelRecordTypeToProto(t)),
+			Item: protoconv.RecordToProto(t, encrypted),
+		},
+	); err != nil {
+		return fmt.Errorf("failed to create %s: %w", t.String(), err)
+	}
+	return nil
+}
+
+// Update encrypts and replaces models.Record by models.RecordType and models.ID. Record read from
+// server and changed there since is not replaced, ErrConflict is returned.
+func (c *Client) Update(ctx context.Context, t models.RecordType, id models.ID, record models.Record) error {
+	encrypted, err := c.encryptUpdate(ctx, t, id, record)
+	if err != nil {
+		return err
+	}
+	*encryptedRevision(&encrypted, t) = *recordRevision(&record, t)
+	if c.replica != nil {
+		if err = c.replica.Update(ctx, t, id, encrypted); err != nil {
+			return err
+		}
+		return c.syncChanged(ctx, t, id)
+	}
+	if _, err = c.client.Update(
+		ctx, &pb.UpdateRecordRequest{
+			Type:             protoRecordType(modelRecordTypeToProto(t)),
+			RecordNumber:     protoID(int(id)),
+			Item:             protoconv.RecordToProto(t, encrypted),
+			ExpectedRevision: proto.Int64(*recordRevision(&record, t)),
+		},
+	); err != nil {
+		return fmt.Errorf("failed to update %s with ID %d: %w", t.String(), id, conflictError(err))
+	}
+	return nil
+}
+
+// Delete deletes models.Record by models.RecordType and models.ID.
+func (c *Client) Delete(ctx context.Context, t models.RecordType, id models.ID) error {
+	if fields(t) == nil {
+		return errUnknownRecordType
+	}
+	if c.replica != nil {
+		if err := c.replica.Delete(ctx, t, id); err != nil {
+			return err
+		}
+		return c.syncChanged(ctx, t, id)
+	}
+	if _, err := c.client.Delete(
+		ctx, &pb.DeleteRecordRequest{
+			Type:         protoRecordType(modelRecordTypeToProto(t)),
+			RecordNumber: protoID(int(id)),
+		},
+	); err != nil {
+		return fmt.Errorf("failed to delete %s with ID %d: %w", t.String(), id, err)
+	}
+	return nil
+}
+
+// SetFields sets values of fields to models.Record by models.RecordType.
+func SetFields(record *models.Record, t models.RecordType, values map[Field][]byte) error {
+	if fields(t) == nil {
+		return errUnknownRecordType
+	}
+	for field, value := range values {
+		if !setRecordField(record, t, field, value) {
+			return fmt.Errorf("field %q not supported for %s", field.String(), t.String())
+		}
+	}
+	return nil
+}
+
+// SetRevision sets revision of models.Record expected by Update, record changed on server since
+// is not replaced.
+func SetRevision(record *models.Record, t models.RecordType, revision int64) error {
+	r := recordRevision(record, t)
+	if r == nil {
+		return errUnknownRecordType
+	}
+	*r = revision
+	return nil
+}
+
+func (c *Client) encryptRecord(t models.RecordType, record models.Record) (models.RecordEncrypted, error) {
+	if fields(t) == nil {
+		return models.RecordEncrypted{}, errUnknownRecordType
+	}
+	var err error
+	uuid := *recordUUID(&record, t)
+	if uuid == "" {
+		if uuid, err = newRecordUUID(); err != nil {
+			return models.RecordEncrypted{}, err
+		}
+	}
+	key, wrappedKey, err := c.newRecordKey()
+	if err != nil {
+		return models.RecordEncrypted{}, err
+	}
+	encrypted := models.RecordEncrypted{}
+	*encryptedUUID(&encrypted, t) = uuid
+	*encryptedDataKey(&encrypted, t) = wrappedKey
+	for _, field := range fields(t) {
+		val, _ := recordField(record, t, field)
+		valEnc, err := crypt.EncryptWithDataKey(key, val, associatedData(t, field, uuid))
+		if err != nil {
+			return models.RecordEncrypted{}, fmt.Errorf("failed to encrypt %s: %w", field.String(), err)
+		}
+		*encryptedField(&encrypted, t, field) = valEnc
+	}
+	return encrypted, nil
+}
+
+// encryptUpdate encrypts record for Update. Bin without data is chunked and its data is available only by
+// DownloadBin, so only its meta is encrypted by the key of stored bin and server keeps data and chunks.
+func (c *Client) encryptUpdate(ctx context.Context, t models.RecordType, id models.ID, record models.Record) (
+	models.RecordEncrypted, error,
+) {
+	if t != models.RecordBin || len(record.Bin.Data) > 0 {
+		return c.encryptRecord(t, record)
+	}
+	var stored models.RecordEncrypted
+	var err error
+	if c.replica != nil {
+		stored, err = c.replica.Get(ctx, t, id)
+	} else {
+		stored, err = c.read(ctx, t, id)
+	}
+	if err != nil {
+		return models.RecordEncrypted{}, err
+	}
+	if len(stored.Bin.DataKey) == 0 {
+		// legacy bin is never chunked
+		return c.encryptRecord(t, record)
+	}
+	key, err := c.recordKey(stored.Bin.DataKey)
+	if err != nil {
+		return models.RecordEncrypted{}, err
+	}
+	meta, err := crypt.EncryptWithDataKey(
+		key, []byte(record.Bin.Meta), associatedData(models.RecordBin, FieldMeta, stored.Bin.UUID),
+	)
+	if err != nil {
+		return models.RecordEncrypted{}, fmt.Errorf("failed to encrypt %s: %w", FieldMeta.String(), err)
+	}
+	return models.RecordEncrypted{
+		Bin: models.BinEncrypted{Meta: meta, UUID: stored.Bin.UUID, DataKey: stored.Bin.DataKey},
+	}, nil
+}
+
+func (c *Client) decryptRecord(t models.RecordType, id models.ID, encrypted models.RecordEncrypted) (
+	models.Record, error,
+) {
+	record := models.Record{}
+	setRecordID(&record, t, id)
+	uuid := *encryptedUUID(&encrypted, t)
+	*recordUUID(&record, t) = uuid
+	*recordRevision(&record, t) = *encryptedRevision(&encrypted, t)
+	key, err := c.recordKey(*encryptedDataKey(&encrypted, t))
+	if err != nil {
+		return models.Record{}, err
+	}
+	for _, field := range fields(t) {
+		valEnc := *encryptedField(&encrypted, t, field)
+		if len(valEnc) == 0 {
+			// data of chunked bin is available only by DownloadBin
+			continue
+		}
+		valDec, err := c.decryptWithKey(key, valEnc, associatedData(t, field, uuid))
+		if err != nil {
+			return models.Record{}, fmt.Errorf("failed to decrypt %s: %w: %w", field.String(), errFieldBinding, err)
+		}
+		setRecordField(&record, t, field, valDec)
+	}
+	return record, nil
+}
+
+func (c *Client) decryptRecords(encrypted models.RecordsEncrypted) (models.Records, error) {
+	result := models.Records{
+		Password: make([]models.Password, 0, len(encrypted.Password)),
+		Text:     make([]models.Text, 0, len(encrypted.Text)),
+		Bin:      make([]models.Bin, 0, len(encrypted.Bin)),
+		Bank:     make([]models.Bank, 0, len(encrypted.Bank)),
+	}
+	decryptMeta := func(t models.RecordType, id models.ID, uuid string, wrappedKey, meta models.Encrypted) (
+		models.Meta, error,
+	) {
+		key, err := c.recordKey(wrappedKey)
+		if err != nil {
+			return "", fmt.Errorf("failed to decrypt %s %d: %w", t.String(), id, err)
+		}
+		valDec, err := c.decryptWithKey(key, meta, associatedData(t, FieldMeta, uuid))
+		if err != nil {
+			return "", fmt.Errorf("failed to decrypt %s %d: %w: %w", t.String(), id, errFieldBinding, err)
+		}
+		return models.Meta(valDec), nil
+	}
+	for _, r := range encrypted.Password {
+		meta, err := decryptMeta(models.RecordPassword, r.ID, r.UUID, r.DataKey, r.Meta)
+		if err != nil {
+			return models.Records{}, err
+		}
+		result.Password = append(result.Password, models.Password{ID: r.ID, Meta: meta, UUID: r.UUID})
+	}
+	for _, r := range encrypted.Text {
+		meta, err := decryptMeta(models.RecordText, r.ID, r.UUID, r.DataKey, r.Meta)
+		if err != nil {
+			return models.Records{}, err
+		}
+		result.Text = append(result.Text, models.Text{ID: r.ID, Meta: meta, UUID: r.UUID})
+	}
+	for _, r := range encrypted.Bin {
+		meta, err := decryptMeta(models.RecordBin, r.ID, r.UUID, r.DataKey, r.Meta)
+		if err != nil {
+			return models.Records{}, err
+		}
+		result.Bin = append(result.Bin, models.Bin{ID: r.ID, Meta: meta, UUID: r.UUID})
+	}
+	for _, r := range encrypted.Bank {
+		meta, err := decryptMeta(models.RecordBank, r.ID, r.UUID, r.DataKey, r.Meta)
+		if err != nil {
+			return models.Records{}, err
+		}
+		result.Bank = append(result.Bank, models.Bank{ID: r.ID, Meta: meta, UUID: r.UUID})
+	}
+	return result, nil
+}
@@ -0,0 +1,559 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+	"github.com/sejo412/gophkeeper/pkg/crypt"
+)
+
+func TestClient_Connect(t *testing.T) {
+	testRecordsClient = NewClient(
+		Config{
+			PublicAddress:  testPublicAddress,
+			PrivateAddress: testPrivateAddress,
+			CacheDir:       testRecordsCacheDir,
+			Password:       testPassword,
+		},
+	)
+	if err := testRecordsClient.Register(testUser2.Cn); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if err := testRecordsClient.Connect(); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+}
+
+func TestClient_Add(t *testing.T) {
+	tests := []struct {
+		name    string
+		t       models.RecordType
+		record  models.Record
+		wantErr bool
+	}{
+		{
+			name: "password",
+			t:    models.RecordPassword,
+			record: models.Record{
+				Password: models.Password{
+					Login:    "testLogin",
+					Password: "testPassword",
+					Meta:     "testMeta",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "bank",
+			t:    models.RecordBank,
+			record: models.Record{
+				Bank: models.Bank{
+					Number: "1234 5678 9012 3456",
+					Name:   "TEST USER",
+					Date:   "01/30",
+					Cvv:    "123",
+					Meta:   "testCard",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "unknown type",
+			t:       models.RecordUnknown,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := testRecordsClient.Add(context.Background(), tt.t, tt.record); (err != nil) != tt.wantErr {
+				t.Errorf("Add() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestClient_List(t *testing.T) {
+	got, err := testRecordsClient.List(context.Background(), models.RecordPassword)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	want := []models.Password{{ID: 1, Meta: "testMeta"}}
+	for i := range got.Password {
+		if got.Password[i].UUID == "" {
+			t.Errorf("List() got empty UUID")
+		}
+		got.Password[i].UUID = ""
+	}
+	if !reflect.DeepEqual(got.Password, want) {
+		t.Errorf("List() got = %v, want %v", got.Password, want)
+	}
+	all, err := testRecordsClient.ListAll(context.Background())
+	if err != nil {
+		t.Fatalf("ListAll() error = %v", err)
+	}
+	if len(all.Password) != 1 || len(all.Bank) != 1 {
+		t.Errorf("ListAll() got = %v", all)
+	}
+}
+
+func TestClient_Get(t *testing.T) {
+	tests := []struct {
+		name    string
+		t       models.RecordType
+		id      models.ID
+		want    models.Record
+		wantErr bool
+	}{
+		{
+			name: "success",
+			t:    models.RecordPassword,
+			id:   1,
+			want: models.Record{
+				Password: models.Password{
+					ID:       1,
+					Login:    "testLogin",
+					Password: "testPassword",
+					Meta:     "testMeta",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "not found",
+			t:       models.RecordPassword,
+			id:      42,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := testRecordsClient.Get(context.Background(), tt.t, tt.id)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Get() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if uuid := recordUUID(&got, tt.t); !tt.wantErr && *uuid == "" {
+				t.Errorf("Get() got empty UUID")
+			}
+			if revision := recordRevision(&got, tt.t); !tt.wantErr && *revision == 0 {
+				t.Errorf("Get() got zero revision")
+			}
+			*recordUUID(&got, tt.t) = ""
+			*recordRevision(&got, tt.t) = 0
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Get() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_Update(t *testing.T) {
+	ctx := context.Background()
+	record, err := testRecordsClient.Get(ctx, models.RecordBank, 1)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if err = SetFields(&record, models.RecordBank, map[Field][]byte{FieldCVV: []byte("999")}); err != nil {
+		t.Fatalf("SetFields() error = %v", err)
+	}
+	if err = testRecordsClient.Update(ctx, models.RecordBank, 1, record); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	got, err := testRecordsClient.Get(ctx, models.RecordBank, 1)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.Bank.Revision <= record.Bank.Revision {
+		t.Errorf("Update() revision = %d, want greater than %d", got.Bank.Revision, record.Bank.Revision)
+	}
+	record.Bank.Revision = got.Bank.Revision
+	if !reflect.DeepEqual(got, record) {
+		t.Errorf("Update() got = %v, want %v", got, record)
+	}
+}
+
+func TestClient_Delete(t *testing.T) {
+	tests := []struct {
+		name    string
+		id      models.ID
+		wantErr bool
+	}{
+		{
+			name:    "success",
+			id:      1,
+			wantErr: false,
+		},
+		{
+			name:    "already deleted",
+			id:      1,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := testRecordsClient.Delete(context.Background(), models.RecordPassword, tt.id); (err != nil) != tt.wantErr {
+				t.Errorf("Delete() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSetFields(t *testing.T) {
+	tests := []struct {
+		name    string
+		t       models.RecordType
+		values  map[Field][]byte
+		want    models.Record
+		wantErr bool
+	}{
+		{
+			name:   "success",
+			t:      models.RecordText,
+			values: map[Field][]byte{FieldText: []byte("text"), FieldMeta: []byte("meta")},
+			want: models.Record{
+				Text: models.Text{Text: "text", Meta: "meta"},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "foreign field",
+			t:       models.RecordText,
+			values:  map[Field][]byte{FieldLogin: []byte("login")},
+			wantErr: true,
+		},
+		{
+			name:    "unknown type",
+			t:       models.RecordUnknown,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := models.Record{}
+			err := SetFields(&got, tt.t, tt.values)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("SetFields() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SetFields() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteRecords(t *testing.T) {
+	records := models.Records{
+		Password: []models.Password{{ID: 1, Meta: "one", Revision: 5}},
+		Text:     []models.Text{{ID: 2, Meta: "two", Revision: 12}},
+	}
+	tests := []struct {
+		name   string
+		format OutputFormat
+		want   string
+	}{
+		{
+			name:   "table",
+			format: OutputTable,
+			want:   "password:\nID  Revision  Meta\n1   5         one\ntext:\nID  Revision  Meta\n2   12        two\n",
+		},
+		{
+			name:   "json",
+			format: OutputJSON,
+			want: "{\n  \"password\": [\n    {\n      \"id\": 1,\n      \"revision\": 5,\n      \"meta\": \"one\"\n" +
+				"    }\n  ],\n  \"text\": [\n    {\n      \"id\": 2,\n      \"revision\": 12,\n" +
+				"      \"meta\": \"two\"\n    }\n  ]\n}\n",
+		},
+		{
+			name:   "yaml",
+			format: OutputYAML,
+			want: "password:\n  - id: 1\n    revision: 5\n    meta: one\ntext:\n  - id: 2\n    revision: 12\n" +
+				"    meta: two\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteRecords(&buf, tt.format, records, models.RecordPassword, models.RecordText); err != nil {
+				t.Fatalf("WriteRecords() error = %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("WriteRecords() got = %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteRecord(t *testing.T) {
+	record := models.Record{
+		Bin: models.Bin{ID: 1, Data: []byte("data"), Meta: "meta", Revision: 3},
+	}
+	tests := []struct {
+		name   string
+		format OutputFormat
+		want   string
+	}{
+		{
+			name:   "table",
+			format: OutputTable,
+			want:   "Data:     data\nMeta:     meta\nRevision: 3\n",
+		},
+		{
+			name:   "json",
+			format: OutputJSON,
+			want:   "{\n  \"id\": 1,\n  \"data\": \"ZGF0YQ==\",\n  \"meta\": \"meta\",\n  \"revision\": 3\n}\n",
+		},
+		{
+			name:   "yaml",
+			format: OutputYAML,
+			want:   "id: 1\ndata: ZGF0YQ==\nmeta: meta\nrevision: 3\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteRecord(&buf, tt.format, models.RecordBin, record); err != nil {
+				t.Fatalf("WriteRecord() error = %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Errorf("WriteRecord() got = %q, want %q", buf.String(), tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_decryptRecordBinding(t *testing.T) {
+	c := testRecordsClient
+	first, err := c.encryptRecord(
+		models.RecordPassword, models.Record{Password: models.Password{Login: "first", Password: "1", Meta: "m1"}},
+	)
+	if err != nil {
+		t.Fatalf("encryptRecord() error = %v", err)
+	}
+	second, err := c.encryptRecord(
+		models.RecordPassword, models.Record{Password: models.Password{Login: "second", Password: "2", Meta: "m2"}},
+	)
+	if err != nil {
+		t.Fatalf("encryptRecord() error = %v", err)
+	}
+	if first.Password.UUID == "" || first.Password.UUID == second.Password.UUID {
+		t.Fatalf("encryptRecord() UUIDs = %q, %q, want unique", first.Password.UUID, second.Password.UUID)
+	}
+	legacyLogin, err := crypt.EncryptWithPublicKey(c.publicKey, []byte("legacy"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	legacyMeta, err := crypt.EncryptWithPublicKey(c.publicKey, []byte("legacyMeta"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	swappedRecord := second
+	swappedRecord.Password.Password = first.Password.Password
+	swappedField := first
+	swappedField.Password.Login, swappedField.Password.Password = first.Password.Password, first.Password.Login
+	movedUUID := first
+	movedUUID.Password.UUID = second.Password.UUID
+	tests := []struct {
+		name      string
+		encrypted models.RecordEncrypted
+		want      models.Password
+		wantErr   bool
+	}{
+		{
+			name:      "bound",
+			encrypted: first,
+			want:      models.Password{ID: 1, Login: "first", Password: "1", Meta: "m1", UUID: first.Password.UUID},
+			wantErr:   false,
+		},
+		{
+			name:      "field of another record",
+			encrypted: swappedRecord,
+			wantErr:   true,
+		},
+		{
+			name:      "another field of record",
+			encrypted: swappedField,
+			wantErr:   true,
+		},
+		{
+			name:      "another uuid",
+			encrypted: movedUUID,
+			wantErr:   true,
+		},
+		{
+			name: "legacy without uuid",
+			encrypted: models.RecordEncrypted{
+				Password: models.PasswordEncrypted{Login: legacyLogin, Password: legacyLogin, Meta: legacyMeta},
+			},
+			want:    models.Password{ID: 1, Login: "legacy", Password: "legacy", Meta: "legacyMeta"},
+			wantErr: false,
+		},
+		{
+			name: "bound field in legacy record",
+			encrypted: models.RecordEncrypted{
+				Password: models.PasswordEncrypted{Login: legacyLogin, Password: first.Password.Password, Meta: legacyMeta},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := c.decryptRecord(models.RecordPassword, 1, tt.encrypted)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("decryptRecord() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				if !errors.Is(err, errFieldBinding) {
+					t.Errorf("decryptRecord() error = %v, want %v", err, errFieldBinding)
+				}
+				return
+			}
+			if !reflect.DeepEqual(got.Password, tt.want) {
+				t.Errorf("decryptRecord() got = %v, want %v", got.Password, tt.want)
+			}
+		})
+	}
+}
+
+func TestClient_recordKey(t *testing.T) {
+	c := testRecordsClient
+	record := models.Record{
+		Bank: models.Bank{Number: "1234", Name: "TEST", Date: "01/30", Cvv: "123", Meta: "card"},
+	}
+	encrypted, err := c.encryptRecord(models.RecordBank, record)
+	if err != nil {
+		t.Fatalf("encryptRecord() error = %v", err)
+	}
+	if len(encrypted.Bank.DataKey) == 0 {
+		t.Fatalf("encryptRecord() got empty data key")
+	}
+	for _, field := range fields(models.RecordBank) {
+		// fields don't contain RSA wrapped keys
+		if size := len(*encryptedField(&encrypted, models.RecordBank, field)); size >= c.privateKey.Size() {
+			t.Errorf("encryptRecord() %s size = %d, want less than %d", field.String(), size, c.privateKey.Size())
+		}
+	}
+	fresh := &Client{publicKey: c.publicKey, privateKey: c.privateKey, keys: newKeyCache()}
+	got, err := fresh.decryptRecord(models.RecordBank, 1, encrypted)
+	if err != nil {
+		t.Fatalf("decryptRecord() error = %v", err)
+	}
+	record.Bank.ID = 1
+	record.Bank.UUID = encrypted.Bank.UUID
+	if !reflect.DeepEqual(got.Bank, record.Bank) {
+		t.Errorf("decryptRecord() got = %v, want %v", got.Bank, record.Bank)
+	}
+	if _, ok := fresh.keys.get(encrypted.Bank.DataKey); !ok {
+		t.Errorf("decryptRecord() didn't cache record key")
+	}
+}
+
+func TestClient_Conflict(t *testing.T) {
+	ctx := context.Background()
+	c := NewClient(
+		Config{
+			PublicAddress: testPublicAddress, PrivateAddress: testPrivateAddress, CacheDir: t.TempDir(),
+			Password: testPassword,
+		},
+	)
+	if err := c.Register("testConflict"); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if err := c.Connect(); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer func() {
+		_ = c.Close()
+	}()
+	if err := c.Add(
+		ctx, models.RecordPassword, models.Record{Password: models.Password{Login: "login", Password: "v0", Meta: "site"}},
+	); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	list, err := c.List(ctx, models.RecordPassword)
+	if err != nil || len(list.Password) != 1 {
+		t.Fatalf("List() = %v, %v, want 1 record", list, err)
+	}
+	id := list.Password[0].ID
+	base, err := c.Get(ctx, models.RecordPassword, id)
+	if err != nil || base.Password.Revision == 0 {
+		t.Fatalf("Get() = %+v, %v, want record with revision", base.Password, err)
+	}
+
+	// other device changes password, this one changes meta of record read before
+	other := base
+	other.Password.Password = "v1"
+	if err = c.Update(ctx, models.RecordPassword, id, other); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	local := base
+	local.Password.Meta = "new site"
+	if err = c.Update(ctx, models.RecordPassword, id, local); !errors.Is(err, ErrConflict) {
+		t.Fatalf("Update() of stale record error = %v, want %v", err, ErrConflict)
+	}
+	conflict, err := c.Conflict(ctx, models.RecordPassword, id, base, local)
+	if err != nil {
+		t.Fatalf("Conflict() error = %v", err)
+	}
+	if len(conflict.Fields) != 0 || conflict.Merged.Password.Password != "v1" ||
+		conflict.Merged.Password.Meta != "new site" {
+		t.Errorf("Conflict() = %+v, want merged changes without conflicting fields", conflict)
+	}
+	var buf bytes.Buffer
+	if err = WriteConflict(&buf, conflict); err != nil || !strings.Contains(buf.String(), "Server version") {
+		t.Errorf("WriteConflict() = %q, %v, want both versions", buf.String(), err)
+	}
+	if err = c.Update(ctx, models.RecordPassword, id, conflict.Merged); err != nil {
+		t.Fatalf("Update() of merged record error = %v", err)
+	}
+	got, err := c.Get(ctx, models.RecordPassword, id)
+	if err != nil || got.Password.Password != "v1" || got.Password.Meta != "new site" {
+		t.Errorf("Get() after merge = %+v, %v, want merged record", got.Password, err)
+	}
+
+	// both devices change password
+	base = got
+	other = base
+	other.Password.Password = "theirs"
+	if err = c.Update(ctx, models.RecordPassword, id, other); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	local = base
+	local.Password.Password = "mine"
+	if err = c.Update(ctx, models.RecordPassword, id, local); !errors.Is(err, ErrConflict) {
+		t.Fatalf("Update() of stale record error = %v, want %v", err, ErrConflict)
+	}
+	if conflict, err = c.Conflict(ctx, models.RecordPassword, id, base, local); err != nil {
+		t.Fatalf("Conflict() error = %v", err)
+	}
+	if len(conflict.Fields) != 1 || conflict.Fields[0] != FieldPassword || conflict.Merged.Password.Password != "mine" {
+		t.Errorf("Conflict() = %+v, want conflicting password with local value merged", conflict)
+	}
+	// revision shown by get is passed back by update
+	current, err := c.Get(ctx, models.RecordPassword, id)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	stale := current
+	if err = SetRevision(&stale, models.RecordPassword, base.Password.Revision); err != nil {
+		t.Fatalf("SetRevision() error = %v", err)
+	}
+	if err = c.Update(ctx, models.RecordPassword, id, stale); !errors.Is(err, ErrConflict) {
+		t.Errorf("Update() with stale revision error = %v, want %v", err, ErrConflict)
+	}
+	if err = SetRevision(&stale, models.RecordPassword, current.Password.Revision); err != nil {
+		t.Fatalf("SetRevision() error = %v", err)
+	}
+	if err = c.Update(ctx, models.RecordPassword, id, stale); err != nil {
+		t.Errorf("Update() with current revision error = %v", err)
+	}
+	if err = SetRevision(&stale, models.RecordUnknown, 1); err == nil {
+		t.Errorf("SetRevision() of unknown type error = nil, want error")
+	}
+}
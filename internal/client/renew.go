@@ -0,0 +1,76 @@
+package client
+
+import (
+	"context"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/constants"
+	"github.com/sejo412/gophkeeper/internal/helpers"
+	pb "github.com/sejo412/gophkeeper/proto"
+)
+
+// Certificate returns saved client certificate.
+func (c *Client) Certificate() (*x509.Certificate, error) {
+	der, err := os.ReadFile(filepath.Join(c.config.CacheDir, constants.CertClientPublicFilename))
+	if err != nil {
+		return nil, fmt.Errorf("could not read client certificate: %w", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse client certificate: %w", err)
+	}
+	return cert, nil
+}
+
+// Renew asks server to issue new certificate for device key, saves it and reconnects
+// with new certificate. Device key and vault key stay the same.
+func (c *Client) Renew(ctx context.Context) (*x509.Certificate, error) {
+	current, err := c.Certificate()
+	if err != nil {
+		return nil, err
+	}
+	req, err := certRequest(current.Subject.CommonName, c.deviceKey)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := c.client.Renew(ctx, &pb.RenewRequest{CertRequest: req})
+	if err != nil {
+		return nil, fmt.Errorf("failed to renew certificate: %w", err)
+	}
+	cert, err := x509.ParseCertificate(resp.GetClientCertificate())
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse renewed certificate: %w", err)
+	}
+	if err = helpers.SaveRegularFile(
+		filepath.Join(c.config.CacheDir, constants.CertClientPublicFilename), resp.GetClientCertificate(), 0644,
+	); err != nil {
+		return nil, fmt.Errorf("failed to save client certificate: %w", err)
+	}
+	if err = c.Close(); err != nil {
+		return nil, fmt.Errorf("failed to close connection: %w", err)
+	}
+	if err = c.dial(); err != nil {
+		return nil, err
+	}
+	return cert, nil
+}
+
+// renewIfExpiring renews client certificate which expires within Config.RenewBefore.
+func (c *Client) renewIfExpiring(ctx context.Context) error {
+	if c.config.RenewBefore < 0 {
+		return nil
+	}
+	cert, err := c.Certificate()
+	if err != nil {
+		return err
+	}
+	if time.Until(cert.NotAfter) > c.config.RenewBefore {
+		return nil
+	}
+	_, err = c.Renew(ctx)
+	return err
+}
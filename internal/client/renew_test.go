@@ -0,0 +1,63 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestClient_Renew(t *testing.T) {
+	ctx := context.Background()
+	config := Config{
+		PublicAddress:  testPublicAddress,
+		PrivateAddress: testPrivateAddress,
+		CacheDir:       t.TempDir(),
+		Password:       testPassword,
+		RenewBefore:    -1,
+	}
+	c := NewClient(config)
+	if err := c.Register("testUserRenew"); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if err := c.Connect(); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer func() {
+		_ = c.Close()
+	}()
+	before, err := c.Certificate()
+	if err != nil {
+		t.Fatalf("Certificate() error = %v", err)
+	}
+	renewed, err := c.Renew(ctx)
+	if err != nil {
+		t.Fatalf("Renew() error = %v", err)
+	}
+	if renewed.SerialNumber.Cmp(before.SerialNumber) == 0 || renewed.Subject.CommonName != "testUserRenew" {
+		t.Errorf("Renew() = %v %q, want new certificate of %q", renewed.SerialNumber, renewed.Subject.CommonName,
+			"testUserRenew")
+	}
+	if _, err = c.ListAll(ctx); err != nil {
+		t.Errorf("ListAll() after Renew() error = %v", err)
+	}
+
+	// certificate expires within a year, it must be renewed on connect
+	config.RenewBefore = 2 * 365 * 24 * time.Hour
+	auto := NewClient(config)
+	if err = auto.Connect(); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer func() {
+		_ = auto.Close()
+	}()
+	got, err := auto.Certificate()
+	if err != nil {
+		t.Fatalf("Certificate() error = %v", err)
+	}
+	if got.SerialNumber.Cmp(renewed.SerialNumber) == 0 {
+		t.Errorf("Connect() did not renew expiring certificate")
+	}
+	if _, err = auto.ListAll(ctx); err != nil {
+		t.Errorf("ListAll() after automatic renewal error = %v", err)
+	}
+}
@@ -0,0 +1,555 @@
+// Package replica keeps local copy of encrypted records of user in SQLite file for offline access
+// and queue of changes made offline until they are sent to server. Records are stored as received
+// from server, so replica holds no decrypted data.
+package replica
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/sejo412/gophkeeper/internal/models"
+	"github.com/sejo412/gophkeeper/internal/protoconv"
+	pb "github.com/sejo412/gophkeeper/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+var (
+	// ErrNotFound is returned for record not existing in replica.
+	ErrNotFound = errors.New("record not found in replica")
+	// ErrNotSynced is returned for change of record created offline, sent to server and not
+	// received back with server ID yet.
+	ErrNotSynced = errors.New("record is not synchronized with server")
+)
+
+// Op is an operation of queued change.
+type Op int
+
+// Operations of queued changes.
+const (
+	OpCreate Op = iota + 1
+	OpUpdate
+	OpDelete
+)
+
+// Change is a change of record made locally and not sent to server yet. Record created offline
+// has negative ID until it is sent to server.
+type Change struct {
+	Seq    int64
+	Op     Op
+	Type   models.RecordType
+	ID     models.ID
+	Record models.RecordEncrypted
+	// Base is a record before update, it is empty for updates queued before it was kept.
+	Base models.RecordEncrypted
+}
+
+// Replica is a local copy of encrypted records.
+type Replica struct {
+	db *sql.DB
+}
+
+var schema = []string{
+	"CREATE TABLE IF NOT EXISTS records(type INTEGER NOT NULL, id INTEGER NOT NULL, item BLOB NOT NULL, " +
+		"PRIMARY KEY(type, id))",
+	"CREATE TABLE IF NOT EXISTS changes(seq INTEGER PRIMARY KEY AUTOINCREMENT, op INTEGER NOT NULL, " +
+		"type INTEGER NOT NULL, id INTEGER NOT NULL, item BLOB)",
+	"CREATE TABLE IF NOT EXISTS conflicts(type INTEGER NOT NULL, id INTEGER NOT NULL, item BLOB NOT NULL, " +
+		"base BLOB, PRIMARY KEY(type, id))",
+	"CREATE TABLE IF NOT EXISTS state(key TEXT PRIMARY KEY, value INTEGER NOT NULL)",
+}
+
+// keys of state
+const (
+	stateSynced   = "synced"
+	stateRevision = "revision"
+)
+
+// Open opens or creates replica file readable by owner only.
+func Open(path string) (*Replica, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create replica: %w", err)
+	}
+	_ = f.Close()
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open replica: %w", err)
+	}
+	for _, q := range schema {
+		if _, err = db.Exec(q); err != nil {
+			_ = db.Close()
+			return nil, fmt.Errorf("failed to create replica schema: %w", err)
+		}
+	}
+	// changes queued by previous versions are kept
+	var hasBase bool
+	if err = db.QueryRow(
+		"SELECT COUNT(*) > 0 FROM pragma_table_info('changes') WHERE name = 'base'",
+	).Scan(&hasBase); err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to check replica schema: %w", err)
+	}
+	if !hasBase {
+		if _, err = db.Exec("ALTER TABLE changes ADD COLUMN base BLOB"); err != nil {
+			_ = db.Close()
+			return nil, fmt.Errorf("failed to upgrade replica schema: %w", err)
+		}
+	}
+	return &Replica{db: db}, nil
+}
+
+// Close closes replica.
+func (r *Replica) Close() error {
+	return r.db.Close()
+}
+
+// List returns records of type t ordered by ID.
+func (r *Replica) List(ctx context.Context, t models.RecordType) (models.RecordsEncrypted, error) {
+	rows, err := r.db.QueryContext(ctx, "SELECT item FROM records WHERE type = ? ORDER BY id", int(t))
+	if err != nil {
+		return models.RecordsEncrypted{}, fmt.Errorf("failed to query %s: %w", t.String(), err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+	result := models.RecordsEncrypted{
+		Password: []models.PasswordEncrypted{},
+		Text:     []models.TextEncrypted{},
+		Bin:      []models.BinEncrypted{},
+		Bank:     []models.BankEncrypted{},
+	}
+	for rows.Next() {
+		var item []byte
+		if err = rows.Scan(&item); err != nil {
+			return models.RecordsEncrypted{}, fmt.Errorf("failed to scan %s: %w", t.String(), err)
+		}
+		_, rec, er := unmarshal(item)
+		if er != nil {
+			return models.RecordsEncrypted{}, er
+		}
+		switch t {
+		case models.RecordPassword:
+			result.Password = append(result.Password, rec.Password)
+		case models.RecordText:
+			result.Text = append(result.Text, rec.Text)
+		case models.RecordBin:
+			result.Bin = append(result.Bin, rec.Bin)
+		case models.RecordBank:
+			result.Bank = append(result.Bank, rec.Bank)
+		default:
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return models.RecordsEncrypted{}, fmt.Errorf("failed to iterate %s: %w", t.String(), err)
+	}
+	return result, nil
+}
+
+// ListAll returns records of all types.
+func (r *Replica) ListAll(ctx context.Context) (models.RecordsEncrypted, error) {
+	result := models.RecordsEncrypted{
+		Password: []models.PasswordEncrypted{},
+		Text:     []models.TextEncrypted{},
+		Bin:      []models.BinEncrypted{},
+		Bank:     []models.BankEncrypted{},
+	}
+	for _, t := range models.RecordTypes {
+		records, err := r.List(ctx, t)
+		if err != nil {
+			return models.RecordsEncrypted{}, err
+		}
+		result.Password = append(result.Password, records.Password...)
+		result.Text = append(result.Text, records.Text...)
+		result.Bin = append(result.Bin, records.Bin...)
+		result.Bank = append(result.Bank, records.Bank...)
+	}
+	return result, nil
+}
+
+// Get returns record by type and ID.
+func (r *Replica) Get(ctx context.Context, t models.RecordType, id models.ID) (models.RecordEncrypted, error) {
+	var item []byte
+	err := r.db.QueryRowContext(ctx, "SELECT item FROM records WHERE type = ? AND id = ?", int(t), id).Scan(&item)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.RecordEncrypted{}, fmt.Errorf("%s with ID %d: %w", t.String(), id, ErrNotFound)
+	}
+	if err != nil {
+		return models.RecordEncrypted{}, fmt.Errorf("failed to get %s with ID %d: %w", t.String(), id, err)
+	}
+	_, rec, err := unmarshal(item)
+	return rec, err
+}
+
+// Create saves new record with local negative ID and queues its creation.
+func (r *Replica) Create(ctx context.Context, t models.RecordType, rec models.RecordEncrypted) (models.ID, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	var id models.ID
+	if err = tx.QueryRowContext(ctx, "SELECT COALESCE(MIN(id), 0) - 1 FROM records WHERE id < 0").Scan(&id); err != nil {
+		return 0, fmt.Errorf("failed to get local ID: %w", err)
+	}
+	rec = withID(t, rec, id)
+	item, err := marshal(t, rec)
+	if err != nil {
+		return 0, err
+	}
+	if _, err = tx.ExecContext(
+		ctx, "INSERT INTO records(type, id, item) VALUES (?, ?, ?)", int(t), id, item,
+	); err != nil {
+		return 0, fmt.Errorf("failed to save %s: %w", t.String(), err)
+	}
+	if err = queue(ctx, tx, OpCreate, t, id, item, nil); err != nil {
+		return 0, err
+	}
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit %s: %w", t.String(), err)
+	}
+	return id, nil
+}
+
+// Update replaces record and queues its update with record before first queued update, so
+// update conflicting with change on server can be merged. Update of record created offline
+// replaces its queued creation.
+func (r *Replica) Update(ctx context.Context, t models.RecordType, id models.ID, rec models.RecordEncrypted) error {
+	rec = withID(t, rec, id)
+	item, err := marshal(t, rec)
+	if err != nil {
+		return err
+	}
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	var base []byte
+	if id > 0 {
+		err = tx.QueryRowContext(
+			ctx, "SELECT COALESCE((SELECT base FROM changes WHERE op = ? AND type = ? AND id = ?), "+
+				"(SELECT item FROM records WHERE type = ? AND id = ?))", OpUpdate, int(t), id, int(t), id,
+		).Scan(&base)
+		if err != nil {
+			return fmt.Errorf("failed to get %s with ID %d: %w", t.String(), id, err)
+		}
+	}
+	res, err := tx.ExecContext(ctx, "UPDATE records SET item = ? WHERE type = ? AND id = ?", item, int(t), id)
+	if err != nil {
+		return fmt.Errorf("failed to update %s with ID %d: %w", t.String(), id, err)
+	}
+	if count, er := res.RowsAffected(); er != nil || count == 0 {
+		return fmt.Errorf("%s with ID %d: %w", t.String(), id, ErrNotFound)
+	}
+	if id < 0 {
+		res, err = tx.ExecContext(
+			ctx, "UPDATE changes SET item = ? WHERE op = ? AND type = ? AND id = ?", item, OpCreate, int(t), id,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to queue update of %s with ID %d: %w", t.String(), id, err)
+		}
+		if count, er := res.RowsAffected(); er != nil || count == 0 {
+			return fmt.Errorf("%s with ID %d: %w", t.String(), id, ErrNotSynced)
+		}
+	} else {
+		// only last update of record is sent to server
+		if _, err = tx.ExecContext(
+			ctx, "DELETE FROM changes WHERE op = ? AND type = ? AND id = ?", OpUpdate, int(t), id,
+		); err != nil {
+			return fmt.Errorf("failed to replace queued update of %s with ID %d: %w", t.String(), id, err)
+		}
+		if err = queue(ctx, tx, OpUpdate, t, id, item, base); err != nil {
+			return err
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit %s: %w", t.String(), err)
+	}
+	return nil
+}
+
+// Delete deletes record and queues its deletion. Record created offline is deleted with its
+// queued creation.
+func (r *Replica) Delete(ctx context.Context, t models.RecordType, id models.ID) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	// deletion is queued with deleted record, so server can check that record is not changed since
+	var item []byte
+	err = tx.QueryRowContext(ctx, "SELECT item FROM records WHERE type = ? AND id = ?", int(t), id).Scan(&item)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("%s with ID %d: %w", t.String(), id, ErrNotFound)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to get %s with ID %d: %w", t.String(), id, err)
+	}
+	if _, err = tx.ExecContext(ctx, "DELETE FROM records WHERE type = ? AND id = ?", int(t), id); err != nil {
+		return fmt.Errorf("failed to delete %s with ID %d: %w", t.String(), id, err)
+	}
+	// queued changes and conflicts of deleted record are not needed
+	if _, err = tx.ExecContext(ctx, "DELETE FROM conflicts WHERE type = ? AND id = ?", int(t), id); err != nil {
+		return fmt.Errorf("failed to delete conflict of %s with ID %d: %w", t.String(), id, err)
+	}
+	res, err := tx.ExecContext(ctx, "DELETE FROM changes WHERE type = ? AND id = ?", int(t), id)
+	if err != nil {
+		return fmt.Errorf("failed to delete queued changes of %s with ID %d: %w", t.String(), id, err)
+	}
+	if id < 0 {
+		if count, er := res.RowsAffected(); er != nil || count == 0 {
+			return fmt.Errorf("%s with ID %d: %w", t.String(), id, ErrNotSynced)
+		}
+	} else {
+		if err = queue(ctx, tx, OpDelete, t, id, item, nil); err != nil {
+			return err
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit %s: %w", t.String(), err)
+	}
+	return nil
+}
+
+// Changes returns queued changes in order they were made.
+func (r *Replica) Changes(ctx context.Context) ([]Change, error) {
+	return r.changes(ctx, "SELECT seq, op, type, id, item, base FROM changes ORDER BY seq")
+}
+
+// Conflict moves queued update rejected by server because record was changed on server since
+// from queue to conflicts, which are kept until Resolve.
+func (r *Replica) Conflict(ctx context.Context, seq int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	res, err := tx.ExecContext(
+		ctx, "INSERT OR REPLACE INTO conflicts(type, id, item, base) "+
+			"SELECT type, id, item, base FROM changes WHERE seq = ? AND op = ?", seq, OpUpdate,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to save conflict of change %d: %w", seq, err)
+	}
+	if count, er := res.RowsAffected(); er != nil || count == 0 {
+		return fmt.Errorf("update %d not found in queue", seq)
+	}
+	if _, err = tx.ExecContext(ctx, "DELETE FROM changes WHERE seq = ?", seq); err != nil {
+		return fmt.Errorf("failed to remove change %d: %w", seq, err)
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit conflict of change %d: %w", seq, err)
+	}
+	return nil
+}
+
+// Conflicts returns updates moved to conflicts as changes without sequence number.
+func (r *Replica) Conflicts(ctx context.Context) ([]Change, error) {
+	return r.changes(
+		ctx, "SELECT 0, ?, type, id, item, base FROM conflicts ORDER BY type, id", OpUpdate,
+	)
+}
+
+// Resolve forgets conflict of record, it is not an error if record has no conflict.
+func (r *Replica) Resolve(ctx context.Context, t models.RecordType, id models.ID) error {
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM conflicts WHERE type = ? AND id = ?", int(t), id); err != nil {
+		return fmt.Errorf("failed to resolve conflict of %s with ID %d: %w", t.String(), id, err)
+	}
+	return nil
+}
+
+// changes returns changes selected by query of seq, op, type, id, item and base.
+func (r *Replica) changes(ctx context.Context, query string, args ...any) ([]Change, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query changes: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+	changes := make([]Change, 0)
+	for rows.Next() {
+		var change Change
+		var item, base []byte
+		if err = rows.Scan(&change.Seq, &change.Op, &change.Type, &change.ID, &item, &base); err != nil {
+			return nil, fmt.Errorf("failed to scan changes: %w", err)
+		}
+		if item != nil {
+			if _, change.Record, err = unmarshal(item); err != nil {
+				return nil, err
+			}
+		}
+		if base != nil {
+			if _, change.Base, err = unmarshal(base); err != nil {
+				return nil, err
+			}
+		}
+		changes = append(changes, change)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to iterate changes: %w", err)
+	}
+	return changes, nil
+}
+
+// Done removes change sent to server or rejected by it from queue.
+func (r *Replica) Done(ctx context.Context, seq int64) error {
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM changes WHERE seq = ?", seq); err != nil {
+		return fmt.Errorf("failed to remove change %d: %w", seq, err)
+	}
+	return nil
+}
+
+// Apply applies changes of records received from server, saves revision of last change and
+// time of synchronization. Reset replaces all records by created and updated ones, it is used
+// for changes since zero revision. Queue of changes must be empty, records created offline are
+// deleted as they are received back with server IDs.
+func (r *Replica) Apply(ctx context.Context, changes []models.Change, reset bool, synced time.Time) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	var pending int
+	if err = tx.QueryRowContext(ctx, "SELECT COUNT(*) FROM changes").Scan(&pending); err != nil {
+		return fmt.Errorf("failed to count changes: %w", err)
+	}
+	if pending > 0 {
+		return fmt.Errorf("failed to apply changes: %d changes not sent", pending)
+	}
+	deleteRecords := "DELETE FROM records WHERE id < 0"
+	if reset {
+		deleteRecords = "DELETE FROM records"
+	}
+	if _, err = tx.ExecContext(ctx, deleteRecords); err != nil {
+		return fmt.Errorf("failed to delete records: %w", err)
+	}
+	revision, err := stateValue(ctx, tx, stateRevision)
+	if err != nil {
+		return err
+	}
+	if reset {
+		revision = 0
+	}
+	for _, change := range changes {
+		revision = max(revision, change.Revision)
+		if change.Op == models.ChangeDelete {
+			if _, err = tx.ExecContext(
+				ctx, "DELETE FROM records WHERE type = ? AND id = ?", int(change.Type), change.ID,
+			); err != nil {
+				return fmt.Errorf("failed to delete %s with ID %d: %w", change.Type.String(), change.ID, err)
+			}
+			continue
+		}
+		item, er := marshal(change.Type, change.Record)
+		if er != nil {
+			return er
+		}
+		if _, err = tx.ExecContext(
+			ctx, "INSERT OR REPLACE INTO records(type, id, item) VALUES (?, ?, ?)", int(change.Type), change.ID, item,
+		); err != nil {
+			return fmt.Errorf("failed to save %s with ID %d: %w", change.Type.String(), change.ID, err)
+		}
+	}
+	for key, value := range map[string]int64{stateRevision: revision, stateSynced: synced.Unix()} {
+		if _, err = tx.ExecContext(
+			ctx, "INSERT OR REPLACE INTO state(key, value) VALUES (?, ?)", key, value,
+		); err != nil {
+			return fmt.Errorf("failed to save state %q: %w", key, err)
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit changes: %w", err)
+	}
+	return nil
+}
+
+// Revision returns server revision of last applied change, zero if replica was never synchronized.
+func (r *Replica) Revision(ctx context.Context) (int64, error) {
+	return stateValue(ctx, r.db, stateRevision)
+}
+
+// Synced returns time of last synchronization, zero time if replica was never synchronized.
+func (r *Replica) Synced(ctx context.Context) (time.Time, error) {
+	synced, err := stateValue(ctx, r.db, stateSynced)
+	if err != nil || synced == 0 {
+		return time.Time{}, err
+	}
+	return time.Unix(synced, 0), nil
+}
+
+// queryRower is sql.DB or sql.Tx.
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// stateValue returns value of state by key, zero for missing key.
+func stateValue(ctx context.Context, db queryRower, key string) (int64, error) {
+	var value int64
+	err := db.QueryRowContext(ctx, "SELECT value FROM state WHERE key = ?", key).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to get state %q: %w", key, err)
+	}
+	return value, nil
+}
+
+func queue(ctx context.Context, tx *sql.Tx, op Op, t models.RecordType, id models.ID, item, base []byte) error {
+	if _, err := tx.ExecContext(
+		ctx, "INSERT INTO changes(op, type, id, item, base) VALUES (?, ?, ?, ?, ?)", op, int(t), id, item, base,
+	); err != nil {
+		return fmt.Errorf("failed to queue change of %s with ID %d: %w", t.String(), id, err)
+	}
+	return nil
+}
+
+// marshal encodes record as proto Record, fields of record are already encrypted.
+func marshal(t models.RecordType, rec models.RecordEncrypted) ([]byte, error) {
+	item, err := proto.Marshal(protoconv.RecordToProto(t, rec))
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s: %w", t.String(), err)
+	}
+	return item, nil
+}
+
+func unmarshal(item []byte) (models.RecordType, models.RecordEncrypted, error) {
+	r := &pb.Record{}
+	if err := proto.Unmarshal(item, r); err != nil {
+		return models.RecordUnknown, models.RecordEncrypted{}, fmt.Errorf("failed to unmarshal record: %w", err)
+	}
+	t, rec := protoconv.RecordFromProto(r)
+	return t, rec, nil
+}
+
+// withID sets id of record of type t.
+func withID(t models.RecordType, rec models.RecordEncrypted, id models.ID) models.RecordEncrypted {
+	switch t {
+	case models.RecordPassword:
+		rec.Password.ID = id
+	case models.RecordText:
+		rec.Text.ID = id
+	case models.RecordBin:
+		rec.Bin.ID = id
+	case models.RecordBank:
+		rec.Bank.ID = id
+	default:
+	}
+	return rec
+}
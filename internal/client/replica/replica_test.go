@@ -0,0 +1,243 @@
+package replica
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+)
+
+func text(id models.ID, value string) models.RecordEncrypted {
+	return models.RecordEncrypted{
+		Text: models.TextEncrypted{ID: id, Text: []byte(value), Meta: []byte("meta"), UUID: "uuid-" + value},
+	}
+}
+
+func change(revision int64, op models.ChangeOp, id models.ID, value string) models.Change {
+	c := models.Change{Revision: revision, Op: op, Type: models.RecordText, ID: id}
+	if op != models.ChangeDelete {
+		c.Record = text(id, value)
+	}
+	return c
+}
+
+func TestReplica(t *testing.T) {
+	ctx := context.Background()
+	r, err := Open(filepath.Join(t.TempDir(), "replica.db"))
+	if err != nil {
+		t.Fatalf("Open() error = %v", err)
+	}
+	defer func() {
+		_ = r.Close()
+	}()
+	if synced, _ := r.Synced(ctx); !synced.IsZero() {
+		t.Errorf("Synced() of new replica = %v, want zero", synced)
+	}
+	now := time.Unix(time.Now().Unix(), 0)
+	initial := []models.Change{
+		change(1, models.ChangeCreate, 7, "old"), change(2, models.ChangeCreate, 3, "v0"),
+		change(3, models.ChangeDelete, 7, ""),
+	}
+	if err = r.Apply(ctx, initial, true, now); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	if synced, _ := r.Synced(ctx); !synced.Equal(now) {
+		t.Errorf("Synced() = %v, want %v", synced, now)
+	}
+	if revision, _ := r.Revision(ctx); revision != 3 {
+		t.Errorf("Revision() = %d, want 3", revision)
+	}
+	if got, er := r.Get(ctx, models.RecordText, 3); er != nil || string(got.Text.Text) != "v0" {
+		t.Errorf("Get() = %+v, %v, want v0", got.Text, er)
+	}
+	if _, err = r.Get(ctx, models.RecordText, 4); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Get() of missing record error = %v, want %v", err, ErrNotFound)
+	}
+
+	first, err := r.Create(ctx, models.RecordText, text(0, "new"))
+	if err != nil || first != -1 {
+		t.Fatalf("Create() = %d, %v, want -1", first, err)
+	}
+	second, err := r.Create(ctx, models.RecordText, text(0, "dropped"))
+	if err != nil || second != -2 {
+		t.Fatalf("Create() = %d, %v, want -2", second, err)
+	}
+	if err = r.Update(ctx, models.RecordText, first, text(0, "new1")); err != nil {
+		t.Fatalf("Update() of created record error = %v", err)
+	}
+	if err = r.Delete(ctx, models.RecordText, second); err != nil {
+		t.Fatalf("Delete() of created record error = %v", err)
+	}
+	for _, v := range []string{"v1", "v2"} {
+		if err = r.Update(ctx, models.RecordText, 3, text(0, v)); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+	}
+	if err = r.Update(ctx, models.RecordText, 4, text(0, "v1")); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Update() of missing record error = %v, want %v", err, ErrNotFound)
+	}
+	list, err := r.List(ctx, models.RecordText)
+	if err != nil || len(list.Text) != 2 || list.Text[0].ID != first || string(list.Text[1].Text) != "v2" {
+		t.Errorf("List() = %+v, %v, want created and updated records", list.Text, err)
+	}
+
+	changes, err := r.Changes(ctx)
+	if err != nil || len(changes) != 2 {
+		t.Fatalf("Changes() = %+v, %v, want 2 changes", changes, err)
+	}
+	if c := changes[0]; c.Op != OpCreate || c.ID != first || string(c.Record.Text.Text) != "new1" {
+		t.Errorf("Changes()[0] = %+v, want create with last value", c)
+	}
+	if c := changes[1]; c.Op != OpUpdate || c.ID != 3 || string(c.Record.Text.Text) != "v2" {
+		t.Errorf("Changes()[1] = %+v, want last update", c)
+	}
+	if err = r.Apply(ctx, nil, false, now); err == nil {
+		t.Errorf("Apply() with queued changes error = nil, want error")
+	}
+	if err = r.Done(ctx, changes[0].Seq); err != nil {
+		t.Fatalf("Done() error = %v", err)
+	}
+	// created record is sent to server and has no server ID until apply
+	if err = r.Update(ctx, models.RecordText, first, text(0, "new2")); !errors.Is(err, ErrNotSynced) {
+		t.Errorf("Update() of sent record error = %v, want %v", err, ErrNotSynced)
+	}
+	if err = r.Delete(ctx, models.RecordText, 3); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	changes, _ = r.Changes(ctx)
+	if len(changes) != 1 || changes[0].Op != OpDelete || changes[0].ID != 3 {
+		t.Errorf("Changes() after Delete() = %+v, want delete only", changes)
+	}
+	if err = r.Done(ctx, changes[0].Seq); err != nil {
+		t.Fatalf("Done() error = %v", err)
+	}
+	received := []models.Change{change(5, models.ChangeCreate, 5, "new1"), change(6, models.ChangeDelete, 3, "")}
+	if err = r.Apply(ctx, received, false, now); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	all, err := r.ListAll(ctx)
+	if err != nil || len(all.Text) != 1 || all.Text[0].ID != 5 || len(all.Password) != 0 {
+		t.Errorf("ListAll() after Apply() = %+v, %v, want server records only", all, err)
+	}
+	if revision, _ := r.Revision(ctx); revision != 6 {
+		t.Errorf("Revision() after Apply() = %d, want 6", revision)
+	}
+	if err = r.Apply(ctx, nil, false, now); err != nil {
+		t.Fatalf("Apply() without changes error = %v", err)
+	}
+	if revision, _ := r.Revision(ctx); revision != 6 {
+		t.Errorf("Revision() after Apply() without changes = %d, want 6", revision)
+	}
+	if err = r.Apply(ctx, nil, true, now); err != nil {
+		t.Fatalf("Apply() with reset error = %v", err)
+	}
+	if all, _ = r.ListAll(ctx); len(all.Text) != 0 {
+		t.Errorf("ListAll() after reset = %+v, want empty", all)
+	}
+	if revision, _ := r.Revision(ctx); revision != 0 {
+		t.Errorf("Revision() after reset = %d, want 0", revision)
+	}
+}
+
+func TestReplica_Conflict(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "replica.db")
+	// replica of previous version has queue without base of updates
+	db, err := sql.Open("sqlite3", path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = db.Exec(
+		"CREATE TABLE changes(seq INTEGER PRIMARY KEY AUTOINCREMENT, op INTEGER NOT NULL, " +
+			"type INTEGER NOT NULL, id INTEGER NOT NULL, item BLOB)",
+	); err != nil {
+		t.Fatal(err)
+	}
+	item, err := marshal(models.RecordText, text(9, "legacy"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = db.Exec(
+		"INSERT INTO changes(op, type, id, item) VALUES (?, ?, 9, ?)", OpUpdate, int(models.RecordText), item,
+	); err != nil {
+		t.Fatal(err)
+	}
+	_ = db.Close()
+
+	r, err := Open(path)
+	if err != nil {
+		t.Fatalf("Open() of previous version error = %v", err)
+	}
+	defer func() {
+		_ = r.Close()
+	}()
+	changes, err := r.Changes(ctx)
+	if err != nil || len(changes) != 1 || string(changes[0].Record.Text.Text) != "legacy" ||
+		changes[0].Base.Text.Text != nil {
+		t.Fatalf("Changes() after upgrade = %+v, %v, want queued update without base", changes, err)
+	}
+	if err = r.Done(ctx, changes[0].Seq); err != nil {
+		t.Fatalf("Done() error = %v", err)
+	}
+	if err = r.Apply(ctx, []models.Change{change(1, models.ChangeCreate, 3, "v0")}, true, time.Now()); err != nil {
+		t.Fatalf("Apply() error = %v", err)
+	}
+	for _, v := range []string{"v1", "v2"} {
+		if err = r.Update(ctx, models.RecordText, 3, text(0, v)); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+	}
+	changes, _ = r.Changes(ctx)
+	if len(changes) != 1 || string(changes[0].Base.Text.Text) != "v0" || string(changes[0].Record.Text.Text) != "v2" {
+		t.Fatalf("Changes() = %+v, want last update with record before first update", changes)
+	}
+	if err = r.Conflict(ctx, changes[0].Seq); err != nil {
+		t.Fatalf("Conflict() error = %v", err)
+	}
+	if err = r.Conflict(ctx, changes[0].Seq); err == nil {
+		t.Errorf("Conflict() of moved change error = nil, want error")
+	}
+	if changes, _ = r.Changes(ctx); len(changes) != 0 {
+		t.Errorf("Changes() after Conflict() = %+v, want empty", changes)
+	}
+	// conflicts are kept, so changes made on server are applied
+	if err = r.Apply(ctx, []models.Change{change(2, models.ChangeUpdate, 3, "server")}, true, time.Now()); err != nil {
+		t.Fatalf("Apply() with conflicts error = %v", err)
+	}
+	conflicts, err := r.Conflicts(ctx)
+	if err != nil || len(conflicts) != 1 {
+		t.Fatalf("Conflicts() = %+v, %v, want 1 conflict", conflicts, err)
+	}
+	if c := conflicts[0]; c.Op != OpUpdate || c.ID != 3 || string(c.Base.Text.Text) != "v0" ||
+		string(c.Record.Text.Text) != "v2" {
+		t.Errorf("Conflicts()[0] = %+v, want update of v0 to v2", c)
+	}
+	if err = r.Resolve(ctx, models.RecordText, 3); err != nil {
+		t.Fatalf("Resolve() error = %v", err)
+	}
+	if conflicts, _ = r.Conflicts(ctx); len(conflicts) != 0 {
+		t.Errorf("Conflicts() after Resolve() = %+v, want empty", conflicts)
+	}
+
+	// conflict of deleted record is forgotten
+	if err = r.Update(ctx, models.RecordText, 3, text(0, "v3")); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	changes, _ = r.Changes(ctx)
+	if len(changes) != 1 || string(changes[0].Base.Text.Text) != "server" {
+		t.Fatalf("Changes() = %+v, want update of record received from server", changes)
+	}
+	if err = r.Conflict(ctx, changes[0].Seq); err != nil {
+		t.Fatalf("Conflict() error = %v", err)
+	}
+	if err = r.Delete(ctx, models.RecordText, 3); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if conflicts, _ = r.Conflicts(ctx); len(conflicts) != 0 {
+		t.Errorf("Conflicts() after Delete() = %+v, want empty", conflicts)
+	}
+}
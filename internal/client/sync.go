@@ -0,0 +1,242 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/client/replica"
+	"github.com/sejo412/gophkeeper/internal/constants"
+	"github.com/sejo412/gophkeeper/internal/models"
+	"github.com/sejo412/gophkeeper/internal/protoconv"
+	pb "github.com/sejo412/gophkeeper/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// ErrOffline is returned by Sync if server is unreachable, queued changes are kept until next Sync.
+var ErrOffline = errors.New("server is unreachable")
+
+// ErrQueued is returned by Sync if server failed to apply change without rejecting it, the change
+// and changes after it are kept until next Sync.
+var ErrQueued = errors.New("change is kept queued")
+
+// SyncResult is a result of synchronization of local replica with server.
+type SyncResult struct {
+	// Pushed is a count of queued changes accepted by server.
+	Pushed int
+	// Rejected are errors of queued changes definitively rejected by server, these changes are dropped.
+	Rejected []error
+	// Pulled is a count of changes of records received from server.
+	Pulled int
+	// Conflicts are updates made offline to records changed on server since, they are kept until
+	// ResolveConflict.
+	Conflicts []Conflict
+}
+
+// openReplica opens local replica of records in cache dir.
+func (c *Client) openReplica() error {
+	r, err := replica.Open(filepath.Join(c.config.CacheDir, constants.ReplicaFilename))
+	if err != nil {
+		return err
+	}
+	c.replica = r
+	return nil
+}
+
+// Sync sends changes made offline to server in order they were made and applies to local
+// replica changes of records made on server since last synchronization.
+func (c *Client) Sync(ctx context.Context) (SyncResult, error) {
+	if c.replica == nil {
+		return SyncResult{}, errors.New("replica is disabled")
+	}
+	result := SyncResult{Rejected: make([]error, 0), Conflicts: make([]Conflict, 0)}
+	changes, err := c.replica.Changes(ctx)
+	if err != nil {
+		return result, err
+	}
+	var conflicted bool
+	for _, change := range changes {
+		err = c.push(ctx, change)
+		if isOffline(err) {
+			return result, fmt.Errorf("%w: %w", ErrOffline, err)
+		}
+		// change is kept in order, so next changes wait for it too
+		if err != nil && !isRejected(err) {
+			return result, fmt.Errorf("%w: %w", ErrQueued, err)
+		}
+		if errors.Is(err, ErrConflict) && change.Op == replica.OpUpdate {
+			if err = c.replica.Conflict(ctx, change.Seq); err != nil {
+				return result, err
+			}
+			conflicted = true
+			continue
+		}
+		if err != nil {
+			result.Rejected = append(result.Rejected, err)
+		} else {
+			result.Pushed++
+		}
+		if err = c.replica.Done(ctx, change.Seq); err != nil {
+			return result, err
+		}
+	}
+	// replica keeps local state of rejected changes, so it is downloaded again
+	if result.Pulled, err = c.pull(ctx, len(result.Rejected) > 0 || conflicted); isOffline(err) {
+		return result, fmt.Errorf("%w: %w", ErrOffline, err)
+	}
+	if err != nil {
+		return result, err
+	}
+	return result, c.conflicts(ctx, &result)
+}
+
+// conflicts merges kept conflicting updates with records received from server. Conflict of record
+// deleted on server or not decrypted is forgotten and returned as rejected change.
+func (c *Client) conflicts(ctx context.Context, result *SyncResult) error {
+	changes, err := c.replica.Conflicts(ctx)
+	if err != nil {
+		return err
+	}
+	for _, change := range changes {
+		conflict, er := c.conflict(ctx, change)
+		if er == nil {
+			result.Conflicts = append(result.Conflicts, conflict)
+			continue
+		}
+		if !errors.Is(er, replica.ErrNotFound) && !errors.Is(er, errFieldBinding) {
+			return er
+		}
+		result.Rejected = append(
+			result.Rejected, fmt.Errorf("failed to sync %s with ID %d: %w", change.Type.String(), change.ID, er),
+		)
+		if err = c.replica.Resolve(ctx, change.Type, change.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// conflict decrypts kept conflicting update and merges it with current record.
+func (c *Client) conflict(ctx context.Context, change replica.Change) (Conflict, error) {
+	// base of update queued by previous version is empty, so all local changes are kept on merge
+	base, err := c.decryptRecord(change.Type, change.ID, change.Base)
+	if err != nil {
+		return Conflict{}, err
+	}
+	local, err := c.decryptRecord(change.Type, change.ID, change.Record)
+	if err != nil {
+		return Conflict{}, err
+	}
+	return c.Conflict(ctx, change.Type, change.ID, base, local)
+}
+
+// push sends queued change to server.
+func (c *Client) push(ctx context.Context, change replica.Change) error {
+	var err error
+	t := protoRecordType(modelRecordTypeToProto(change.Type))
+	switch change.Op {
+	case replica.OpCreate:
+		_, err = c.client.Create(
+			ctx, &pb.AddRecordRequest{
+				Type: t,
+				Item: protoconv.RecordToProto(change.Type, change.Record),
+			},
+		)
+	case replica.OpUpdate:
+		_, err = c.client.Update(
+			ctx, &pb.UpdateRecordRequest{
+				Type:             t,
+				RecordNumber:     protoID(int(change.ID)),
+				Item:             protoconv.RecordToProto(change.Type, change.Record),
+				ExpectedRevision: proto.Int64(*encryptedRevision(&change.Record, change.Type)),
+			},
+		)
+	case replica.OpDelete:
+		// record deleted offline is not deleted if it was changed on server since
+		_, err = c.client.Delete(
+			ctx, &pb.DeleteRecordRequest{
+				Type:             t,
+				RecordNumber:     protoID(int(change.ID)),
+				ExpectedRevision: proto.Int64(*encryptedRevision(&change.Record, change.Type)),
+			},
+		)
+	default:
+		return fmt.Errorf("unknown operation %d", change.Op)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to sync %s with ID %d: %w", change.Type.String(), change.ID, conflictError(err))
+	}
+	return nil
+}
+
+// pull applies changes of records made on server since last pull to replica, all records are
+// downloaded again on reset. It returns count of received changes.
+func (c *Client) pull(ctx context.Context, reset bool) (int, error) {
+	var since int64
+	if !reset {
+		var err error
+		if since, err = c.replica.Revision(ctx); err != nil {
+			return 0, err
+		}
+	}
+	resp, err := c.client.Changes(ctx, &pb.ChangesRequest{SinceRevision: proto.Int64(since)})
+	if err != nil {
+		return 0, fmt.Errorf("failed to get changes: %w", err)
+	}
+	changes := make([]models.Change, 0, len(resp.GetChanges()))
+	for _, change := range resp.GetChanges() {
+		changes = append(changes, protoconv.ChangeFromProto(change))
+	}
+	if err = c.replica.Apply(ctx, changes, since == 0, time.Now()); err != nil {
+		return 0, err
+	}
+	return len(changes), nil
+}
+
+// syncChanged sends change of record just queued in replica to server, unreachable server is not
+// an error. Conflict of the change is forgotten and returned as ErrConflict, so caller merges it.
+func (c *Client) syncChanged(ctx context.Context, t models.RecordType, id models.ID) error {
+	result, err := c.Sync(ctx)
+	if errors.Is(err, ErrOffline) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	errs := result.Rejected
+	for _, conflict := range result.Conflicts {
+		if conflict.Type != t || conflict.ID != id {
+			continue
+		}
+		if err = c.replica.Resolve(ctx, t, id); err != nil {
+			return err
+		}
+		errs = append(errs, fmt.Errorf("failed to sync %s with ID %d: %w", t.String(), id, ErrConflict))
+	}
+	return errors.Join(errs...)
+}
+
+// isOffline reports whether err is caused by unreachable server.
+func isOffline(err error) bool {
+	switch status.Code(err) {
+	case codes.Unavailable, codes.DeadlineExceeded:
+		return true
+	default:
+		return false
+	}
+}
+
+// isRejected reports whether err means server rejected change, so sending it again fails too.
+func isRejected(err error) bool {
+	switch status.Code(err) {
+	case codes.InvalidArgument, codes.NotFound, codes.AlreadyExists, codes.FailedPrecondition, codes.OutOfRange,
+		codes.Aborted:
+		return true
+	default:
+		return false
+	}
+}
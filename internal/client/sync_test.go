@@ -0,0 +1,309 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+	pb "github.com/sejo412/gophkeeper/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func TestClient_Sync(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	connect := func(address string) *Client {
+		c := NewClient(
+			Config{
+				PublicAddress:  testPublicAddress,
+				PrivateAddress: address,
+				CacheDir:       dir,
+				Password:       testPassword,
+				Replica:        true,
+			},
+		)
+		if err := c.Connect(); err != nil {
+			t.Fatalf("Connect() error = %v", err)
+		}
+		return c
+	}
+	c := NewClient(
+		Config{PublicAddress: testPublicAddress, PrivateAddress: testPrivateAddress, CacheDir: dir, Password: testPassword},
+	)
+	if err := c.Register("testReplica"); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	online := connect(testPrivateAddress)
+	if err := online.Add(
+		ctx, models.RecordPassword, models.Record{Password: models.Password{Login: "login", Password: "v0", Meta: "site"}},
+	); err != nil {
+		t.Fatalf("Add() online error = %v", err)
+	}
+	list, err := online.List(ctx, models.RecordPassword)
+	if err != nil || len(list.Password) != 1 || list.Password[0].ID <= 0 {
+		t.Fatalf("List() online = %v, %v, want record with server ID", list, err)
+	}
+	password := list.Password[0].ID
+	_ = online.Close()
+
+	offline := connect("127.0.0.1:1")
+	if _, err = offline.Sync(ctx); !errors.Is(err, ErrOffline) {
+		t.Errorf("Sync() offline error = %v, want %v", err, ErrOffline)
+	}
+	if got, er := offline.Get(ctx, models.RecordPassword, password); er != nil || got.Password.Password != "v0" {
+		t.Errorf("Get() offline = %+v, %v, want record from replica", got.Password, er)
+	}
+	if err = offline.Update(
+		ctx, models.RecordPassword, password,
+		models.Record{Password: models.Password{Login: "login", Password: "v1", Meta: "site"}},
+	); err != nil {
+		t.Fatalf("Update() offline error = %v", err)
+	}
+	for _, text := range []models.Meta{"kept", "dropped"} {
+		if err = offline.Add(ctx, models.RecordText, models.Record{Text: models.Text{Text: "note", Meta: text}}); err != nil {
+			t.Fatalf("Add() offline error = %v", err)
+		}
+	}
+	texts, err := offline.List(ctx, models.RecordText)
+	if err != nil || len(texts.Text) != 2 || texts.Text[0].ID >= 0 {
+		t.Fatalf("List() offline = %v, %v, want 2 records with local IDs", texts, err)
+	}
+	for _, text := range texts.Text {
+		if text.Meta != "dropped" {
+			continue
+		}
+		if err = offline.Delete(ctx, models.RecordText, text.ID); err != nil {
+			t.Fatalf("Delete() offline error = %v", err)
+		}
+	}
+	_ = offline.Close()
+
+	online = connect(testPrivateAddress)
+	defer func() {
+		_ = online.Close()
+	}()
+	if changes, _ := online.replica.Changes(ctx); len(changes) != 0 {
+		t.Errorf("Changes() after Connect() = %v, want empty", changes)
+	}
+	texts, err = online.List(ctx, models.RecordText)
+	if err != nil || len(texts.Text) != 1 || texts.Text[0].ID <= 0 || texts.Text[0].Meta != "kept" {
+		t.Fatalf("List() after sync = %v, %v, want kept record with server ID", texts, err)
+	}
+	// server has changes made offline
+	server := NewClient(Config{PrivateAddress: testPrivateAddress, CacheDir: dir, Password: testPassword})
+	if err = server.Connect(); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer func() {
+		_ = server.Close()
+	}()
+	if got, er := server.Get(ctx, models.RecordPassword, password); er != nil || got.Password.Password != "v1" {
+		t.Errorf("Get() from server = %+v, %v, want record updated offline", got.Password, er)
+	}
+	if got, er := server.Get(ctx, models.RecordText, texts.Text[0].ID); er != nil || got.Text.Text != "note" {
+		t.Errorf("Get() from server = %+v, %v, want record created offline", got.Text, er)
+	}
+
+	// update by other device is pulled by next sync
+	if err = server.Update(
+		ctx, models.RecordPassword, password,
+		models.Record{Password: models.Password{Login: "login", Password: "v2", Meta: "site"}},
+	); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	result, err := online.Sync(ctx)
+	if err != nil || result.Pulled != 1 || result.Pushed != 0 {
+		t.Errorf("Sync() = %+v, %v, want 1 change pulled", result, err)
+	}
+	if got, er := online.Get(ctx, models.RecordPassword, password); er != nil || got.Password.Password != "v2" {
+		t.Errorf("Get() after Sync() = %+v, %v, want record updated on server", got.Password, er)
+	}
+	if err = server.Delete(ctx, models.RecordText, texts.Text[0].ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if result, err = online.Sync(ctx); err != nil || result.Pulled != 1 {
+		t.Errorf("Sync() = %+v, %v, want 1 change pulled", result, err)
+	}
+	if texts, err = online.List(ctx, models.RecordText); err != nil || len(texts.Text) != 0 {
+		t.Errorf("List() after Sync() = %v, %v, want record deleted on server removed", texts, err)
+	}
+}
+
+// failingClient fails every change of records with code.
+type failingClient struct {
+	pb.PrivateClient
+	code codes.Code
+}
+
+func (f failingClient) Create(context.Context, *pb.AddRecordRequest, ...grpc.CallOption) (*emptypb.Empty, error) {
+	return nil, status.Error(f.code, f.code.String())
+}
+
+func TestClient_SyncFailed(t *testing.T) {
+	ctx := context.Background()
+	c := NewClient(
+		Config{
+			PublicAddress:  testPublicAddress,
+			PrivateAddress: testPrivateAddress,
+			CacheDir:       t.TempDir(),
+			Password:       testPassword,
+			Replica:        true,
+		},
+	)
+	if err := c.Register("testSyncFailed"); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if err := c.Connect(); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer func() {
+		_ = c.Close()
+	}()
+	server := c.client
+	add := func(meta models.Meta) error {
+		return c.Add(ctx, models.RecordText, models.Record{Text: models.Text{Text: "note", Meta: meta}})
+	}
+	queued := func() int {
+		t.Helper()
+		changes, err := c.replica.Changes(ctx)
+		if err != nil {
+			t.Fatalf("Changes() error = %v", err)
+		}
+		return len(changes)
+	}
+
+	// transient failure keeps change and changes after it
+	c.client = failingClient{PrivateClient: server, code: codes.Internal}
+	if err := add("first"); !errors.Is(err, ErrQueued) || status.Code(err) != codes.Internal {
+		t.Errorf("Add() error = %v, want %v with %v", err, ErrQueued, codes.Internal)
+	}
+	if err := add("second"); !errors.Is(err, ErrQueued) {
+		t.Errorf("Add() error = %v, want %v", err, ErrQueued)
+	}
+	if got := queued(); got != 2 {
+		t.Errorf("Changes() after failed sync = %d, want 2 queued", got)
+	}
+	c.client = server
+	result, err := c.Sync(ctx)
+	if err != nil || result.Pushed != 2 || len(result.Rejected) != 0 {
+		t.Errorf("Sync() = %+v, %v, want 2 changes pushed", result, err)
+	}
+	texts, err := c.List(ctx, models.RecordText)
+	if err != nil || len(texts.Text) != 2 || texts.Text[0].ID <= 0 {
+		t.Errorf("List() after Sync() = %v, %v, want 2 records with server ID", texts, err)
+	}
+
+	// rejected change is dropped
+	c.client = failingClient{PrivateClient: server, code: codes.InvalidArgument}
+	if err = add("rejected"); err == nil || errors.Is(err, ErrQueued) {
+		t.Errorf("Add() error = %v, want rejected", err)
+	}
+	if got := queued(); got != 0 {
+		t.Errorf("Changes() after rejected sync = %d, want 0 queued", got)
+	}
+}
+
+func TestClient_SyncConflict(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	connect := func(address string, replica bool) *Client {
+		c := NewClient(
+			Config{PrivateAddress: address, CacheDir: dir, Password: testPassword, Replica: replica},
+		)
+		if err := c.Connect(); err != nil {
+			t.Fatalf("Connect() error = %v", err)
+		}
+		return c
+	}
+	c := NewClient(
+		Config{PublicAddress: testPublicAddress, PrivateAddress: testPrivateAddress, CacheDir: dir, Password: testPassword},
+	)
+	if err := c.Register("testSyncConflict"); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	server := connect(testPrivateAddress, false)
+	defer func() {
+		_ = server.Close()
+	}()
+	if err := server.Add(
+		ctx, models.RecordPassword, models.Record{Password: models.Password{Login: "login", Password: "v0", Meta: "site"}},
+	); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	list, err := server.List(ctx, models.RecordPassword)
+	if err != nil || len(list.Password) != 1 {
+		t.Fatalf("List() = %v, %v, want 1 record", list, err)
+	}
+	id := list.Password[0].ID
+	// conflict updates password offline and meta on server
+	conflict := func(password string, meta models.Meta) Conflict {
+		t.Helper()
+		online := connect(testPrivateAddress, true)
+		_ = online.Close()
+		offline := connect("127.0.0.1:1", true)
+		record, er := offline.Get(ctx, models.RecordPassword, id)
+		if er != nil {
+			t.Fatalf("Get() offline error = %v", er)
+		}
+		record.Password.Password = password
+		if er = offline.Update(ctx, models.RecordPassword, id, record); er != nil {
+			t.Fatalf("Update() offline error = %v", er)
+		}
+		_ = offline.Close()
+		if record, er = server.Get(ctx, models.RecordPassword, id); er != nil {
+			t.Fatalf("Get() error = %v", er)
+		}
+		record.Password.Meta = meta
+		if er = server.Update(ctx, models.RecordPassword, id, record); er != nil {
+			t.Fatalf("Update() error = %v", er)
+		}
+		// conflict is kept by sync on connect
+		online = connect(testPrivateAddress, true)
+		defer func() {
+			_ = online.Close()
+		}()
+		result, er := online.Sync(ctx)
+		if er != nil || len(result.Conflicts) != 1 || len(result.Rejected) != 0 {
+			t.Fatalf("Sync() = %+v, %v, want 1 conflict", result, er)
+		}
+		return result.Conflicts[0]
+	}
+	resolve := func(conflict Conflict, save bool) {
+		t.Helper()
+		online := connect(testPrivateAddress, true)
+		defer func() {
+			_ = online.Close()
+		}()
+		if er := online.ResolveConflict(ctx, conflict, save); er != nil {
+			t.Fatalf("ResolveConflict() error = %v", er)
+		}
+		if result, er := online.Sync(ctx); er != nil || len(result.Conflicts) != 0 {
+			t.Errorf("Sync() after ResolveConflict() = %+v, %v, want no conflicts", result, er)
+		}
+	}
+
+	merged := conflict("offline", "server")
+	if merged.ID != id || merged.Local.Password.Password != "offline" || merged.Remote.Password.Meta != "server" ||
+		len(merged.Fields) != 0 {
+		t.Errorf("Sync() conflict = %+v, want local password and server meta", merged)
+	}
+	if p := merged.Merged.Password; p.Password != "offline" || p.Meta != "server" {
+		t.Errorf("Sync() merged = %+v, want local password and server meta", p)
+	}
+	resolve(merged, true)
+	if got, er := server.Get(ctx, models.RecordPassword, id); er != nil || got.Password.Password != "offline" ||
+		got.Password.Meta != "server" {
+		t.Errorf("Get() after merge = %+v, %v, want merged record", got.Password, er)
+	}
+
+	resolve(conflict("dropped", "kept"), false)
+	if got, er := server.Get(ctx, models.RecordPassword, id); er != nil || got.Password.Password != "offline" ||
+		got.Password.Meta != "kept" {
+		t.Errorf("Get() after drop = %+v, %v, want server record", got.Password, er)
+	}
+}
@@ -0,0 +1,67 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+	"github.com/sejo412/gophkeeper/internal/protoconv"
+	pb "github.com/sejo412/gophkeeper/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// TrashItem is a deleted record kept in trash on server until restore or purge.
+type TrashItem struct {
+	Type    models.RecordType
+	ID      models.ID
+	Deleted time.Time
+	// Record has decrypted Meta only.
+	Record models.Record
+}
+
+// Trash returns deleted records with decrypted Meta only, last deleted first.
+func (c *Client) Trash(ctx context.Context) ([]TrashItem, error) {
+	resp, err := c.client.ListTrash(ctx, &emptypb.Empty{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list trash: %w", err)
+	}
+	items := make([]TrashItem, 0, len(resp.GetItems()))
+	for _, item := range resp.GetItems() {
+		t, encrypted := protoconv.RecordFromProto(item.GetItem())
+		id := encryptedID(&encrypted, t)
+		record, er := c.decryptRecord(t, id, encrypted)
+		if er != nil {
+			return nil, fmt.Errorf("failed to decrypt %s with ID %d: %w", t.String(), id, er)
+		}
+		items = append(items, TrashItem{Type: t, ID: id, Deleted: time.Unix(item.GetDeleted(), 0), Record: record})
+	}
+	return items, nil
+}
+
+// RestoreTrash moves deleted record back from trash.
+func (c *Client) RestoreTrash(ctx context.Context, t models.RecordType, id models.ID) error {
+	if fields(t) == nil {
+		return errUnknownRecordType
+	}
+	if _, err := c.client.RestoreTrash(
+		ctx, &pb.RestoreTrashRequest{
+			Type:         protoRecordType(modelRecordTypeToProto(t)),
+			RecordNumber: protoID(int(id)),
+		},
+	); err != nil {
+		return fmt.Errorf("failed to restore %s with ID %d from trash: %w", t.String(), id, err)
+	}
+	if c.replica != nil {
+		return c.syncChanged(ctx, t, id)
+	}
+	return nil
+}
+
+// EmptyTrash permanently deletes all records in trash.
+func (c *Client) EmptyTrash(ctx context.Context) error {
+	if _, err := c.client.EmptyTrash(ctx, &emptypb.Empty{}); err != nil {
+		return fmt.Errorf("failed to empty trash: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+)
+
+func TestClient_Trash(t *testing.T) {
+	ctx := context.Background()
+	c := testRecordsClient
+	if err := c.Add(ctx, models.RecordText, models.Record{Text: models.Text{Text: "secret", Meta: "trashed"}}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	list, err := c.List(ctx, models.RecordText)
+	if err != nil || len(list.Text) == 0 {
+		t.Fatalf("List() = %v, %v, want text record", list, err)
+	}
+	id := list.Text[len(list.Text)-1].ID
+	if err = c.Delete(ctx, models.RecordText, id); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err = c.Get(ctx, models.RecordText, id); err == nil {
+		t.Errorf("Get() of record in trash error = nil, want error")
+	}
+	trash, err := c.Trash(ctx)
+	if err != nil || len(trash) == 0 {
+		t.Fatalf("Trash() = %v, %v, want deleted record", trash, err)
+	}
+	found := false
+	for _, item := range trash {
+		if item.Type != models.RecordText || item.ID != id {
+			continue
+		}
+		found = true
+		if item.Record.Text.Meta != "trashed" || item.Record.Text.Text != "" || item.Deleted.IsZero() {
+			t.Errorf("Trash() item = %+v, want deleted text with meta only", item)
+		}
+	}
+	if !found {
+		t.Errorf("Trash() = %v, want deleted text %d", trash, id)
+	}
+
+	if err = c.RestoreTrash(ctx, models.RecordText, id); err != nil {
+		t.Fatalf("RestoreTrash() error = %v", err)
+	}
+	if got, er := c.Get(ctx, models.RecordText, id); er != nil || got.Text.Text != "secret" {
+		t.Errorf("Get() after RestoreTrash() = %+v, %v, want restored record", got.Text, er)
+	}
+	if err = c.RestoreTrash(ctx, models.RecordText, id); err == nil {
+		t.Errorf("RestoreTrash() of restored record error = nil, want error")
+	}
+
+	if err = c.Delete(ctx, models.RecordText, id); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err = c.EmptyTrash(ctx); err != nil {
+		t.Fatalf("EmptyTrash() error = %v", err)
+	}
+	if trash, _ = c.Trash(ctx); len(trash) != 0 {
+		t.Errorf("Trash() after EmptyTrash() = %v, want empty", trash)
+	}
+	if err = c.RestoreTrash(ctx, models.RecordText, id); err == nil {
+		t.Errorf("RestoreTrash() after EmptyTrash() error = nil, want error")
+	}
+}
+
+func TestWriteTrash(t *testing.T) {
+	deleted := time.Date(2025, 1, 2, 3, 4, 5, 0, time.Local)
+	items := []TrashItem{
+		{Type: models.RecordBank, ID: 3, Deleted: deleted, Record: models.Record{Bank: models.Bank{Meta: "card"}}},
+	}
+	var buf bytes.Buffer
+	if err := WriteTrash(&buf, OutputTable, items); err != nil {
+		t.Fatalf("WriteTrash() error = %v", err)
+	}
+	if want := "Type  ID  Deleted              Meta\nbank  3   2025-01-02 03:04:05  card\n"; buf.String() != want {
+		t.Errorf("WriteTrash() got = %q, want %q", buf.String(), want)
+	}
+	buf.Reset()
+	if err := WriteTrash(&buf, OutputJSON, items); err != nil {
+		t.Fatalf("WriteTrash() error = %v", err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte(`"type": "bank"`)) || !bytes.Contains(buf.Bytes(), []byte(`"meta": "card"`)) {
+		t.Errorf("WriteTrash() got = %q, want type and meta", buf.String())
+	}
+}
@@ -0,0 +1,133 @@
+package client
+
+import (
+	"bytes"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/sejo412/gophkeeper/internal/constants"
+	"github.com/sejo412/gophkeeper/pkg/certs"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+)
+
+// CAFingerprint returns SHA-256 fingerprint of saved server CA certificate.
+func (c *Client) CAFingerprint() (string, error) {
+	der, err := os.ReadFile(filepath.Join(c.config.CacheDir, constants.CertCAPublicFilename))
+	if err != nil {
+		return "", fmt.Errorf("could not read CA certificate: %w", err)
+	}
+	return certs.Fingerprint(der), nil
+}
+
+// IsCAPinned returns true if server CA is verified by fingerprint or pinned CA file,
+// otherwise CA is trusted on first use.
+func (c *Client) IsCAPinned() bool {
+	return c.config.CAFingerprint != "" || c.config.CAFile != ""
+}
+
+// dialPublic connects to the public server, over server-only TLS if Config.PublicTLS is set.
+func (c *Client) dialPublic() (*grpc.ClientConn, error) {
+	creds := insecure.NewCredentials()
+	if c.config.PublicTLS {
+		pinned, err := c.pinnedCA()
+		if err != nil {
+			return nil, err
+		}
+		creds = credentials.NewTLS(
+			&tls.Config{
+				MinVersion: tls.VersionTLS12,
+				// client has no CA certificate yet, chain is verified by pinned CA in verifyServerChain
+				InsecureSkipVerify: true,
+				VerifyPeerCertificate: func(rawCerts [][]byte, _ [][]*x509.Certificate) error {
+					return c.verifyServerChain(rawCerts, pinned)
+				},
+			},
+		)
+	}
+	conn, err := grpc.NewClient(c.config.PublicAddress, grpc.WithTransportCredentials(creds))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create public client: %w", err)
+	}
+	return conn, nil
+}
+
+// verifyCA checks CA certificate received from public server by pinned CA file and fingerprint.
+func (c *Client) verifyCA(caCert []byte) error {
+	pinned, err := c.pinnedCA()
+	if err != nil {
+		return err
+	}
+	if pinned != nil && !bytes.Equal(pinned, caCert) {
+		return errors.New("server CA certificate does not match pinned CA")
+	}
+	if c.config.CAFingerprint != "" && !certs.FingerprintMatch(caCert, c.config.CAFingerprint) {
+		return fmt.Errorf("server CA fingerprint %s does not match %s", certs.Fingerprint(caCert),
+			c.config.CAFingerprint)
+	}
+	return nil
+}
+
+// verifyServerChain verifies certificate chain of public server by pinned CA or CA from chain
+// matching fingerprint. Without pinned CA any chain is trusted on first use.
+func (c *Client) verifyServerChain(rawCerts [][]byte, pinned []byte) error {
+	if !c.IsCAPinned() {
+		return nil
+	}
+	if len(rawCerts) == 0 {
+		return errors.New("server sent no certificate")
+	}
+	caCert := pinned
+	if caCert == nil {
+		for _, raw := range rawCerts[1:] {
+			if certs.FingerprintMatch(raw, c.config.CAFingerprint) {
+				caCert = raw
+				break
+			}
+		}
+	}
+	if caCert == nil {
+		return errors.New("server certificate chain has no CA matching fingerprint")
+	}
+	if err := c.verifyCA(caCert); err != nil {
+		return err
+	}
+	ca, err := x509.ParseCertificate(caCert)
+	if err != nil {
+		return fmt.Errorf("could not parse CA certificate: %w", err)
+	}
+	leaf, err := x509.ParseCertificate(rawCerts[0])
+	if err != nil {
+		return fmt.Errorf("could not parse server certificate: %w", err)
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+	if _, err = leaf.Verify(x509.VerifyOptions{Roots: roots}); err != nil {
+		return fmt.Errorf("could not verify server certificate: %w", err)
+	}
+	return nil
+}
+
+// pinnedCA returns DER encoded CA certificate from Config.CAFile, nil if it is not set.
+func (c *Client) pinnedCA() ([]byte, error) {
+	if c.config.CAFile == "" {
+		return nil, nil
+	}
+	data, err := os.ReadFile(c.config.CAFile)
+	if err != nil {
+		return nil, fmt.Errorf("could not read pinned CA: %w", err)
+	}
+	if block, _ := pem.Decode(data); block != nil {
+		data = block.Bytes
+	}
+	if _, err = x509.ParseCertificate(data); err != nil {
+		return nil, fmt.Errorf("could not parse pinned CA: %w", err)
+	}
+	return data, nil
+}
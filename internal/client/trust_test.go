@@ -0,0 +1,133 @@
+package client
+
+import (
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/constants"
+	"github.com/sejo412/gophkeeper/internal/server"
+	"github.com/sejo412/gophkeeper/pkg/certs"
+)
+
+func TestClient_RegisterCAFingerprint(t *testing.T) {
+	caFile := filepath.Join(testServerCacheDir, constants.CertCAPublicFilename)
+	caCert, err := os.ReadFile(caFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherCA, _, err := certs.GenRsaCert(*certs.NewCertRequest("other", nil, nil, nil, true), certs.CASigner{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherCAFile := filepath.Join(t.TempDir(), "other.crt")
+	if err = os.WriteFile(otherCAFile, otherCA, 0600); err != nil {
+		t.Fatal(err)
+	}
+	fingerprint := certs.Fingerprint(caCert)
+	tests := []struct {
+		name        string
+		fingerprint string
+		caFile      string
+		wantErr     bool
+	}{
+		{name: "fingerprint", fingerprint: fingerprint},
+		{name: "fingerprint without colons", fingerprint: strings.ToLower(strings.ReplaceAll(fingerprint, ":", ""))},
+		{name: "pinned CA", caFile: caFile},
+		{name: "wrong fingerprint", fingerprint: certs.Fingerprint(otherCA), wantErr: true},
+		{name: "wrong pinned CA", caFile: otherCAFile, wantErr: true},
+	}
+	for i, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				dir := t.TempDir()
+				c := NewClient(
+					Config{
+						PublicAddress: testPublicAddress,
+						CacheDir:      dir,
+						Password:      testPassword,
+						CAFingerprint: tt.fingerprint,
+						CAFile:        tt.caFile,
+					},
+				)
+				err := c.Register("testUserFingerprint" + strconv.Itoa(i))
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("Register() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				_, err = os.Stat(filepath.Join(dir, constants.CertCAPublicFilename))
+				if tt.wantErr && err == nil {
+					t.Errorf("Register() saved CA certificate which does not match")
+				}
+			},
+		)
+	}
+}
+
+func TestClient_RegisterPublicTLS(t *testing.T) {
+	const publicPort, privatePort = 7202, 7203
+	dir := t.TempDir()
+	s := server.NewServer(
+		server.Config{
+			PublicPort:  publicPort,
+			PrivatePort: privatePort,
+			CacheDir:    dir,
+			DNSNames:    []string{"localhost"},
+			PublicTLS:   true,
+		},
+	)
+	if err := s.Init(); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	go func() {
+		_ = s.Start()
+	}()
+	address := net.JoinHostPort("127.0.0.1", strconv.Itoa(publicPort))
+	for i := 0; ; i++ {
+		conn, err := net.Dial("tcp", address)
+		if err == nil {
+			_ = conn.Close()
+			break
+		}
+		if i == 50 {
+			t.Fatalf("server not started: %v", err)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+	fingerprint, err := s.CAFingerprint()
+	if err != nil {
+		t.Fatalf("CAFingerprint() error = %v", err)
+	}
+	tests := []struct {
+		name        string
+		fingerprint string
+		publicTLS   bool
+		wantErr     bool
+	}{
+		{name: "fingerprint", fingerprint: fingerprint, publicTLS: true},
+		{name: "trust on first use", publicTLS: true},
+		{name: "wrong fingerprint", fingerprint: strings.Repeat("00", 32), publicTLS: true, wantErr: true},
+		{name: "without TLS", fingerprint: fingerprint, wantErr: true},
+	}
+	for i, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				c := NewClient(
+					Config{
+						PublicAddress: address,
+						CacheDir:      t.TempDir(),
+						Password:      testPassword,
+						CAFingerprint: tt.fingerprint,
+						PublicTLS:     tt.publicTLS,
+					},
+				)
+				if err := c.Register("testUserTLS" + strconv.Itoa(i)); (err != nil) != tt.wantErr {
+					t.Errorf("Register() error = %v, wantErr %v", err, tt.wantErr)
+				}
+			},
+		)
+	}
+}
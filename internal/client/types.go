@@ -1,9 +1,12 @@
 package client
 
 import (
+	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/sejo412/gophkeeper/internal/models"
+	"github.com/sejo412/gophkeeper/internal/protoconv"
 	pb "github.com/sejo412/gophkeeper/proto"
 )
 
@@ -73,6 +76,13 @@ const (
 	ActionDeleteName string = "Delete"
 )
 
+const (
+	RecordPasswordKey string = "password"
+	RecordTextKey     string = "text"
+	RecordBinKey      string = "bin"
+	RecordBankKey     string = "bank"
+)
+
 const (
 	FieldID Field = iota
 	FieldLogin
@@ -232,18 +242,7 @@ func protoRecordType(value pb.RecordType) *pb.RecordType {
 }
 
 func modelRecordTypeToProto(value models.RecordType) pb.RecordType {
-	switch value {
-	case models.RecordPassword:
-		return pb.RecordType_PASSWORD
-	case models.RecordText:
-		return pb.RecordType_TEXT
-	case models.RecordBin:
-		return pb.RecordType_BIN
-	case models.RecordBank:
-		return pb.RecordType_BANK
-	default:
-		return pb.RecordType_UNKNOWN
-	}
+	return protoconv.RecordTypeToProto(value)
 }
 
 func protoID(value int) *int64 {
@@ -282,3 +281,289 @@ func fields(t models.RecordType) []Field {
 		return nil
 	}
 }
+
+// ParseRecordType returns models.RecordType by its short name (password, text, bin, bank).
+func ParseRecordType(name string) (models.RecordType, error) {
+	switch strings.ToLower(name) {
+	case RecordPasswordKey:
+		return models.RecordPassword, nil
+	case RecordTextKey:
+		return models.RecordText, nil
+	case RecordBinKey:
+		return models.RecordBin, nil
+	case RecordBankKey:
+		return models.RecordBank, nil
+	default:
+		return models.RecordUnknown, fmt.Errorf("%w: %q", errUnknownRecordType, name)
+	}
+}
+
+func recordTypeKey(t models.RecordType) string {
+	switch t {
+	case models.RecordPassword:
+		return RecordPasswordKey
+	case models.RecordText:
+		return RecordTextKey
+	case models.RecordBin:
+		return RecordBinKey
+	case models.RecordBank:
+		return RecordBankKey
+	default:
+		return t.String()
+	}
+}
+
+// Flag returns name of command line flag for Field.
+func (f Field) Flag() string {
+	return strings.ToLower(f.String())
+}
+
+func recordField(r models.Record, t models.RecordType, f Field) ([]byte, bool) {
+	switch t {
+	case models.RecordPassword:
+		switch f {
+		case FieldLogin:
+			return []byte(r.Password.Login), true
+		case FieldPassword:
+			return []byte(r.Password.Password), true
+		case FieldMeta:
+			return []byte(r.Password.Meta), true
+		default:
+		}
+	case models.RecordText:
+		switch f {
+		case FieldText:
+			return []byte(r.Text.Text), true
+		case FieldMeta:
+			return []byte(r.Text.Meta), true
+		default:
+		}
+	case models.RecordBin:
+		switch f {
+		case FieldData:
+			return r.Bin.Data, true
+		case FieldMeta:
+			return []byte(r.Bin.Meta), true
+		default:
+		}
+	case models.RecordBank:
+		switch f {
+		case FieldNumber:
+			return []byte(r.Bank.Number), true
+		case FieldName:
+			return []byte(r.Bank.Name), true
+		case FieldDate:
+			return []byte(r.Bank.Date), true
+		case FieldCVV:
+			return []byte(r.Bank.Cvv), true
+		case FieldMeta:
+			return []byte(r.Bank.Meta), true
+		default:
+		}
+	default:
+	}
+	return nil, false
+}
+
+func setRecordField(r *models.Record, t models.RecordType, f Field, value []byte) bool {
+	switch t {
+	case models.RecordPassword:
+		switch f {
+		case FieldLogin:
+			r.Password.Login = string(value)
+		case FieldPassword:
+			r.Password.Password = string(value)
+		case FieldMeta:
+			r.Password.Meta = models.Meta(value)
+		default:
+			return false
+		}
+	case models.RecordText:
+		switch f {
+		case FieldText:
+			r.Text.Text = string(value)
+		case FieldMeta:
+			r.Text.Meta = models.Meta(value)
+		default:
+			return false
+		}
+	case models.RecordBin:
+		switch f {
+		case FieldData:
+			r.Bin.Data = value
+		case FieldMeta:
+			r.Bin.Meta = models.Meta(value)
+		default:
+			return false
+		}
+	case models.RecordBank:
+		switch f {
+		case FieldNumber:
+			r.Bank.Number = string(value)
+		case FieldName:
+			r.Bank.Name = string(value)
+		case FieldDate:
+			r.Bank.Date = string(value)
+		case FieldCVV:
+			r.Bank.Cvv = string(value)
+		case FieldMeta:
+			r.Bank.Meta = models.Meta(value)
+		default:
+			return false
+		}
+	default:
+		return false
+	}
+	return true
+}
+
+func setRecordID(r *models.Record, t models.RecordType, id models.ID) {
+	switch t {
+	case models.RecordPassword:
+		r.Password.ID = id
+	case models.RecordText:
+		r.Text.ID = id
+	case models.RecordBin:
+		r.Bin.ID = id
+	case models.RecordBank:
+		r.Bank.ID = id
+	default:
+	}
+}
+
+func recordUUID(r *models.Record, t models.RecordType) *string {
+	switch t {
+	case models.RecordPassword:
+		return &r.Password.UUID
+	case models.RecordText:
+		return &r.Text.UUID
+	case models.RecordBin:
+		return &r.Bin.UUID
+	case models.RecordBank:
+		return &r.Bank.UUID
+	default:
+		return nil
+	}
+}
+
+func recordRevision(r *models.Record, t models.RecordType) *int64 {
+	switch t {
+	case models.RecordPassword:
+		return &r.Password.Revision
+	case models.RecordText:
+		return &r.Text.Revision
+	case models.RecordBin:
+		return &r.Bin.Revision
+	case models.RecordBank:
+		return &r.Bank.Revision
+	default:
+		return nil
+	}
+}
+
+func encryptedRevision(r *models.RecordEncrypted, t models.RecordType) *int64 {
+	switch t {
+	case models.RecordPassword:
+		return &r.Password.Revision
+	case models.RecordText:
+		return &r.Text.Revision
+	case models.RecordBin:
+		return &r.Bin.Revision
+	case models.RecordBank:
+		return &r.Bank.Revision
+	default:
+		return nil
+	}
+}
+
+func encryptedID(r *models.RecordEncrypted, t models.RecordType) models.ID {
+	switch t {
+	case models.RecordPassword:
+		return r.Password.ID
+	case models.RecordText:
+		return r.Text.ID
+	case models.RecordBin:
+		return r.Bin.ID
+	case models.RecordBank:
+		return r.Bank.ID
+	default:
+		return 0
+	}
+}
+
+func encryptedUUID(r *models.RecordEncrypted, t models.RecordType) *string {
+	switch t {
+	case models.RecordPassword:
+		return &r.Password.UUID
+	case models.RecordText:
+		return &r.Text.UUID
+	case models.RecordBin:
+		return &r.Bin.UUID
+	case models.RecordBank:
+		return &r.Bank.UUID
+	default:
+		return nil
+	}
+}
+
+func encryptedDataKey(r *models.RecordEncrypted, t models.RecordType) *models.Encrypted {
+	switch t {
+	case models.RecordPassword:
+		return &r.Password.DataKey
+	case models.RecordText:
+		return &r.Text.DataKey
+	case models.RecordBin:
+		return &r.Bin.DataKey
+	case models.RecordBank:
+		return &r.Bank.DataKey
+	default:
+		return nil
+	}
+}
+
+func encryptedField(r *models.RecordEncrypted, t models.RecordType, f Field) *models.Encrypted {
+	switch t {
+	case models.RecordPassword:
+		switch f {
+		case FieldLogin:
+			return &r.Password.Login
+		case FieldPassword:
+			return &r.Password.Password
+		case FieldMeta:
+			return &r.Password.Meta
+		default:
+		}
+	case models.RecordText:
+		switch f {
+		case FieldText:
+			return &r.Text.Text
+		case FieldMeta:
+			return &r.Text.Meta
+		default:
+		}
+	case models.RecordBin:
+		switch f {
+		case FieldData:
+			return &r.Bin.Data
+		case FieldMeta:
+			return &r.Bin.Meta
+		default:
+		}
+	case models.RecordBank:
+		switch f {
+		case FieldNumber:
+			return &r.Bank.Number
+		case FieldName:
+			return &r.Bank.Name
+		case FieldDate:
+			return &r.Bank.Date
+		case FieldCVV:
+			return &r.Bank.Cvv
+		case FieldMeta:
+			return &r.Bank.Meta
+		default:
+		}
+	default:
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+	"github.com/sejo412/gophkeeper/internal/protoconv"
+	pb "github.com/sejo412/gophkeeper/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+// Version is a decrypted previous version of record.
+type Version struct {
+	Number int64
+	// Created is a time when version was replaced.
+	Created time.Time
+	Record  models.Record
+}
+
+// History returns previous versions of record with decrypted Meta only, newest first.
+// Server keeps no versions of binary records.
+func (c *Client) History(ctx context.Context, t models.RecordType, id models.ID) ([]Version, error) {
+	if fields(t) == nil {
+		return nil, errUnknownRecordType
+	}
+	resp, err := c.client.ListVersions(
+		ctx, &pb.ListVersionsRequest{
+			Type:         protoRecordType(modelRecordTypeToProto(t)),
+			RecordNumber: protoID(int(id)),
+		},
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list versions of %s with ID %d: %w", t.String(), id, err)
+	}
+	versions := make([]Version, 0, len(resp.GetVersions()))
+	for _, r := range resp.GetVersions() {
+		version, err := c.versionFromProto(t, id, r)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+	return versions, nil
+}
+
+// Version returns decrypted previous version of record by version number.
+func (c *Client) Version(ctx context.Context, t models.RecordType, id models.ID, number int64) (Version, error) {
+	if fields(t) == nil {
+		return Version{}, errUnknownRecordType
+	}
+	resp, err := c.client.GetVersion(
+		ctx, &pb.GetVersionRequest{
+			Type:         protoRecordType(modelRecordTypeToProto(t)),
+			RecordNumber: protoID(int(id)),
+			Version:      proto.Int64(number),
+		},
+	)
+	if err != nil {
+		return Version{}, fmt.Errorf("failed to get version %d of %s with ID %d: %w", number, t.String(), id, err)
+	}
+	return c.versionFromProto(t, id, resp)
+}
+
+// Restore replaces record by its previous version, replaced version is kept in history.
+func (c *Client) Restore(ctx context.Context, t models.RecordType, id models.ID, number int64) error {
+	version, err := c.Version(ctx, t, id, number)
+	if err != nil {
+		return err
+	}
+	return c.Update(ctx, t, id, version.Record)
+}
+
+func (c *Client) versionFromProto(t models.RecordType, id models.ID, r *pb.Version) (Version, error) {
+	itemType, encrypted := protoconv.RecordFromProto(r.GetItem())
+	if itemType != t {
+		return Version{}, fmt.Errorf("server returned %s instead of %s", itemType.String(), t.String())
+	}
+	record, err := c.decryptRecord(t, id, encrypted)
+	if err != nil {
+		return Version{}, fmt.Errorf("failed to decrypt version %d: %w", r.GetVersion(), err)
+	}
+	return Version{Number: r.GetVersion(), Created: time.Unix(r.GetCreated(), 0), Record: record}, nil
+}
@@ -0,0 +1,76 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+)
+
+func TestClient_HistoryRestore(t *testing.T) {
+	ctx := context.Background()
+	c := testRecordsClient
+	if err := c.Add(ctx, models.RecordText, models.Record{Text: models.Text{Text: "first", Meta: "v1"}}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	list, err := c.List(ctx, models.RecordText)
+	if err != nil || len(list.Text) == 0 {
+		t.Fatalf("List() = %v, %v, want text record", list, err)
+	}
+	id := list.Text[len(list.Text)-1].ID
+	record, err := c.Get(ctx, models.RecordText, id)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	record.Text.Text, record.Text.Meta = "second", "v2"
+	if err = c.Update(ctx, models.RecordText, id, record); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+
+	history, err := c.History(ctx, models.RecordText, id)
+	if err != nil || len(history) != 1 {
+		t.Fatalf("History() = %v, %v, want one version", history, err)
+	}
+	if history[0].Number != 1 || history[0].Record.Text.Meta != "v1" || history[0].Record.Text.Text != "" {
+		t.Errorf("History() = %+v, want version 1 with meta only", history[0])
+	}
+	version, err := c.Version(ctx, models.RecordText, id, 1)
+	if err != nil || version.Record.Text.Text != "first" {
+		t.Errorf("Version() = %+v, %v, want first version", version, err)
+	}
+	if _, err = c.Version(ctx, models.RecordText, id, 42); err == nil {
+		t.Errorf("Version() of unknown version error = nil, want error")
+	}
+
+	if err = c.Restore(ctx, models.RecordText, id, 1); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	got, err := c.Get(ctx, models.RecordText, id)
+	if err != nil || got.Text.Text != "first" || got.Text.Meta != "v1" || got.Text.UUID != record.Text.UUID {
+		t.Errorf("Get() after Restore() = %+v, %v, want first version with same UUID", got.Text, err)
+	}
+	if history, _ = c.History(ctx, models.RecordText, id); len(history) != 2 || history[0].Record.Text.Meta != "v2" {
+		t.Errorf("History() after Restore() = %v, want replaced version first", history)
+	}
+}
+
+func TestWriteVersions(t *testing.T) {
+	created := time.Date(2025, 1, 2, 3, 4, 5, 0, time.Local)
+	versions := []Version{{Number: 2, Created: created, Record: models.Record{Password: models.Password{Meta: "old"}}}}
+	var buf bytes.Buffer
+	if err := WriteVersions(&buf, OutputTable, models.RecordPassword, versions); err != nil {
+		t.Fatalf("WriteVersions() error = %v", err)
+	}
+	if want := "Version  Created              Meta\n2        2025-01-02 03:04:05  old\n"; buf.String() != want {
+		t.Errorf("WriteVersions() got = %q, want %q", buf.String(), want)
+	}
+	buf.Reset()
+	if err := WriteVersions(&buf, OutputYAML, models.RecordPassword, versions); err != nil {
+		t.Fatalf("WriteVersions() error = %v", err)
+	}
+	if !bytes.Contains(buf.Bytes(), []byte("- version: 2\n")) || !bytes.Contains(buf.Bytes(), []byte("meta: old\n")) {
+		t.Errorf("WriteVersions() got = %q, want version and meta", buf.String())
+	}
+}
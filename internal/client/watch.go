@@ -0,0 +1,85 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/constants"
+	"github.com/sejo412/gophkeeper/internal/models"
+	"github.com/sejo412/gophkeeper/internal/protoconv"
+	pb "github.com/sejo412/gophkeeper/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// Watch calls fn for every change of records made on server until ctx is done or fn fails.
+// Local replica is synchronized before fn is called. Watch reconnects to unreachable server
+// and resumes from last received change.
+func (c *Client) Watch(ctx context.Context, fn func(models.Change) error) error {
+	var since int64
+	if c.replica != nil {
+		var err error
+		if since, err = c.replica.Revision(ctx); err != nil {
+			return err
+		}
+	}
+	for {
+		var err error
+		since, err = c.watch(ctx, since, fn)
+		if ctx.Err() != nil {
+			return nil
+		}
+		if !isWatchRetryable(err) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(constants.WatchRetryInterval):
+		}
+	}
+}
+
+// watch receives changes since revision until stream fails, it returns revision of last received change.
+func (c *Client) watch(ctx context.Context, since int64, fn func(models.Change) error) (int64, error) {
+	stream, err := c.client.Watch(ctx, &pb.WatchRequest{SinceRevision: proto.Int64(since)})
+	if err != nil {
+		return since, fmt.Errorf("failed to watch changes: %w", err)
+	}
+	for {
+		event, err := stream.Recv()
+		if err != nil {
+			return since, fmt.Errorf("failed to watch changes: %w", err)
+		}
+		change := protoconv.ChangeFromProto(event)
+		if err = c.refresh(ctx, change.Revision); err != nil {
+			return since, err
+		}
+		if err = fn(change); err != nil {
+			return since, err
+		}
+		since = change.Revision
+	}
+}
+
+// refresh synchronizes replica if it has not received change with revision yet.
+func (c *Client) refresh(ctx context.Context, revision int64) error {
+	if c.replica == nil {
+		return nil
+	}
+	synced, err := c.replica.Revision(ctx)
+	if err != nil || synced >= revision {
+		return err
+	}
+	_, err = c.Sync(ctx)
+	return err
+}
+
+// isWatchRetryable reports whether watching may be resumed after err.
+func isWatchRetryable(err error) bool {
+	return errors.Is(err, io.EOF) || isOffline(err) || status.Code(err) == codes.ResourceExhausted
+}
@@ -0,0 +1,117 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+)
+
+func TestClient_Watch(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	c := NewClient(
+		Config{PublicAddress: testPublicAddress, PrivateAddress: testPrivateAddress, CacheDir: dir, Password: testPassword},
+	)
+	if err := c.Register("testWatch"); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	writer := NewClient(Config{PrivateAddress: testPrivateAddress, CacheDir: dir, Password: testPassword})
+	if err := writer.Connect(); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer func() {
+		_ = writer.Close()
+	}()
+	if err := writer.Add(ctx, models.RecordText, models.Record{Text: models.Text{Text: "v0", Meta: "note"}}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	// watcher resumes from revision of replica, so changes made before stream is opened are received
+	watcher := NewClient(
+		Config{PrivateAddress: testPrivateAddress, CacheDir: dir, Password: testPassword, Replica: true},
+	)
+	if err := watcher.Connect(); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	defer func() {
+		_ = watcher.Close()
+	}()
+	list, err := watcher.List(ctx, models.RecordText)
+	if err != nil || len(list.Text) != 1 {
+		t.Fatalf("List() = %v, %v, want 1 record", list, err)
+	}
+	id := list.Text[0].ID
+
+	watchCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	events := make(chan models.Change)
+	done := make(chan error, 1)
+	go func() {
+		done <- watcher.Watch(
+			watchCtx, func(change models.Change) error {
+				select {
+				case events <- change:
+				case <-watchCtx.Done():
+				}
+				return nil
+			},
+		)
+	}()
+	var revision int64
+	receive := func(op models.ChangeOp) models.Change {
+		t.Helper()
+		select {
+		case change := <-events:
+			if change.Op != op || change.Type != models.RecordText || change.Revision <= revision {
+				t.Errorf("Watch() change = %+v, want %v of text after revision %d", change, op, revision)
+			}
+			revision = change.Revision
+			return change
+		case err := <-done:
+			t.Fatalf("Watch() error = %v", err)
+		case <-time.After(5 * time.Second):
+			t.Fatalf("Watch() no change %v received", op)
+		}
+		return models.Change{}
+	}
+
+	if err = writer.Update(
+		ctx, models.RecordText, id, models.Record{Text: models.Text{Text: "v1", Meta: "note"}},
+	); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if updated := receive(models.ChangeUpdate); updated.ID != id {
+		t.Errorf("Watch() ID = %d, want %d", updated.ID, id)
+	}
+	// replica is synchronized before change is reported
+	if got, er := watcher.Get(ctx, models.RecordText, id); er != nil || got.Text.Text != "v1" {
+		t.Errorf("Get() from replica = %+v, %v, want record updated on server", got.Text, er)
+	}
+	if err = writer.Add(ctx, models.RecordText, models.Record{Text: models.Text{Text: "new", Meta: "other"}}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	created := receive(models.ChangeCreate)
+	if list, err = watcher.List(ctx, models.RecordText); err != nil || len(list.Text) != 2 {
+		t.Errorf("List() from replica = %v, %v, want created record", list, err)
+	}
+	if err = writer.Delete(ctx, models.RecordText, created.ID); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if deleted := receive(models.ChangeDelete); deleted.ID != created.ID {
+		t.Errorf("Watch() ID = %d, want %d", deleted.ID, created.ID)
+	}
+	if list, err = watcher.List(ctx, models.RecordText); err != nil || len(list.Text) != 1 {
+		t.Errorf("List() from replica = %v, %v, want deleted record removed", list, err)
+	}
+
+	cancel()
+	select {
+	case err = <-done:
+		if err != nil {
+			t.Errorf("Watch() after cancel error = %v, want nil", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Errorf("Watch() not returned after cancel")
+	}
+}
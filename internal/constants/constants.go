@@ -3,6 +3,7 @@ package constants
 import (
 	"os"
 	"syscall"
+	"time"
 )
 
 const (
@@ -13,6 +14,65 @@ const (
 
 const (
 	DBFilename string = "database.db"
+	// AdminSocketDir is a directory accessible by server owner only, it contains AdminSocketFilename.
+	AdminSocketDir string = "admin"
+	// AdminSocketFilename is a unix socket of admin service of running server.
+	AdminSocketFilename string = "admin.sock"
+	// EphemeralFilename marks cache dir of ephemeral server, its storage exists only in server memory.
+	EphemeralFilename string = "ephemeral"
+	// StorageMaxOpenConns is a maximum of open connections in pool of PostgreSQL storage.
+	StorageMaxOpenConns int = 25
+	// StorageMaxIdleConns is a maximum of idle connections in pool of PostgreSQL storage.
+	StorageMaxIdleConns int = 5
+	// StorageConnMaxLifetime is a maximum lifetime of connection in pool of PostgreSQL storage.
+	StorageConnMaxLifetime = 30 * time.Minute
+)
+
+const (
+	// BinChunkSize is a size of plain binary data chunk for streaming upload/download.
+	BinChunkSize int = 1 << 20
+	// PartialFileSuffix is a suffix of partially downloaded file.
+	PartialFileSuffix string = ".part"
+)
+
+const (
+	// MasterPasswordEnv is an environment variable with master password for non-interactive usage.
+	MasterPasswordEnv string = "GOPHKEEPER_MASTER_PASSWORD"
+	// StorageDSNEnv is an environment variable with storage DSN of server, keeps password out of command line.
+	StorageDSNEnv string = "GOPHKEEPER_STORAGE"
+)
+
+const (
+	// VaultKeyFilename is a file with vault key encrypted for device key, records are encrypted with vault key.
+	VaultKeyFilename string = "vault.key"
+	// ReplicaFilename is a local replica of encrypted records for offline usage of client.
+	ReplicaFilename string = "replica.db"
+	// SyncTimeout is a timeout of synchronization of replica with server on connect.
+	SyncTimeout = 10 * time.Second
+	// EnrollmentTTL is a time while new device waits for approval.
+	EnrollmentTTL = time.Hour
+	// EnrollmentPollInterval is an interval of checking enrollment status by new device.
+	EnrollmentPollInterval = 2 * time.Second
+	// InviteTTL is a default lifetime of invite token.
+	InviteTTL = 24 * time.Hour
+	// RegistrationTTL is a time while new user waits for admin approval.
+	RegistrationTTL = 7 * 24 * time.Hour
+	// RegistrationPollInterval is an interval of checking registration status by new user.
+	RegistrationPollInterval = 2 * time.Second
+	// CertRenewBefore is a time before expiration of client certificate when client renews it.
+	CertRenewBefore = 30 * 24 * time.Hour
+	// VersionsKeep is a default count of kept previous versions of every record.
+	VersionsKeep = 10
+	// VersionsMaxAge is a default time while previous versions of records are kept.
+	VersionsMaxAge = 90 * 24 * time.Hour
+	// VersionsPurgeInterval is an interval of deletion of previous versions out of max age.
+	VersionsPurgeInterval = time.Hour
+	// TrashRetention is a default time while deleted records are kept in trash.
+	TrashRetention = 30 * 24 * time.Hour
+	// TrashPurgeInterval is an interval of permanent deletion of records out of trash retention.
+	TrashPurgeInterval = time.Hour
+	// WatchRetryInterval is an interval of reconnection of client watching changes to server.
+	WatchRetryInterval = 5 * time.Second
 )
 
 const (
@@ -28,6 +88,9 @@ const (
 	KeyBits                   int    = 2048
 	PemCertType               string = "CERTIFICATE"
 	PemKeyType                string = "RSA PRIVATE KEY"
+	PemCRLType                string = "X509 CRL"
+	// CRLValidity is a time until next update of generated CRL.
+	CRLValidity = 7 * 24 * time.Hour
 )
 
 var (
@@ -1,5 +1,12 @@
 package models
 
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
 // RecordType is a type of record (password, text, etc).
 type RecordType int
 
@@ -12,6 +19,14 @@ const (
 	RecordBank
 )
 
+// RecordTypes contains all known RecordTypes.
+var RecordTypes = []RecordType{
+	RecordPassword,
+	RecordText,
+	RecordBin,
+	RecordBank,
+}
+
 // Names of RecordTypes.
 const (
 	RecordUnknownName  string = "unknown type"
@@ -63,12 +78,23 @@ type RecordsEncrypted struct {
 	Bank     []BankEncrypted
 }
 
+// Records type for mass clear records, includes all RecordType.
+type Records struct {
+	Password []Password
+	Text     []Text
+	Bin      []Bin
+	Bank     []Bank
+}
+
 // Password type for password field in Record.
 type Password struct {
-	ID       ID
-	Login    string
-	Password string
-	Meta     Meta
+	ID       ID     `json:"id" yaml:"id"`
+	Login    string `json:"login" yaml:"login"`
+	Password string `json:"password" yaml:"password"`
+	Meta     Meta   `json:"meta" yaml:"meta"`
+	UUID     string `json:"-" yaml:"-"`
+	// Revision is a server revision of record when it was read, used to detect concurrent changes.
+	Revision int64 `json:"revision" yaml:"revision"`
 }
 
 // PasswordEncrypted type for password field in RecordEncrypted.
@@ -77,54 +103,169 @@ type PasswordEncrypted struct {
 	Login    Encrypted
 	Password Encrypted
 	Meta     Encrypted
+	UUID     string
+	DataKey  Encrypted
+	// Revision is a server revision of last change of record, revisions grow monotonically.
+	Revision int64
 }
 
 // Text type for text field in Record.
 type Text struct {
-	ID   ID
-	Text string
-	Meta Meta
+	ID       ID     `json:"id" yaml:"id"`
+	Text     string `json:"text" yaml:"text"`
+	Meta     Meta   `json:"meta" yaml:"meta"`
+	UUID     string `json:"-" yaml:"-"`
+	Revision int64  `json:"revision" yaml:"revision"`
 }
 
 // TextEncrypted type for text field in RecordEncrypted.
 type TextEncrypted struct {
-	ID   ID
-	Text Encrypted
-	Meta Encrypted
+	ID       ID
+	Text     Encrypted
+	Meta     Encrypted
+	UUID     string
+	DataKey  Encrypted
+	Revision int64
 }
 
 // Bin type for bin field in Record.
 type Bin struct {
-	ID   ID
-	Data []byte
-	Meta Meta
+	ID       ID     `json:"id" yaml:"id"`
+	Data     []byte `json:"data" yaml:"data"`
+	Meta     Meta   `json:"meta" yaml:"meta"`
+	UUID     string `json:"-" yaml:"-"`
+	Revision int64  `json:"revision" yaml:"revision"`
 }
 
 // BinEncrypted type for bin field in RecordEncrypted.
 type BinEncrypted struct {
-	ID   ID
-	Data Encrypted
-	Meta Encrypted
+	ID       ID
+	Data     Encrypted
+	Meta     Encrypted
+	UUID     string
+	DataKey  Encrypted
+	Revision int64
 }
 
 // Bank type for bank field in Record.
 type Bank struct {
-	ID     ID
-	Number string
-	Name   string
-	Date   string
-	Cvv    string
-	Meta   Meta
+	ID       ID     `json:"id" yaml:"id"`
+	Number   string `json:"number" yaml:"number"`
+	Name     string `json:"name" yaml:"name"`
+	Date     string `json:"date" yaml:"date"`
+	Cvv      string `json:"cvv" yaml:"cvv"`
+	Meta     Meta   `json:"meta" yaml:"meta"`
+	UUID     string `json:"-" yaml:"-"`
+	Revision int64  `json:"revision" yaml:"revision"`
 }
 
 // BankEncrypted type for bank field in RecordEncrypted.
 type BankEncrypted struct {
-	ID     ID
-	Number Encrypted
-	Name   Encrypted
-	Date   Encrypted
-	Cvv    Encrypted
-	Meta   Encrypted
+	ID       ID
+	Number   Encrypted
+	Name     Encrypted
+	Date     Encrypted
+	Cvv      Encrypted
+	Meta     Encrypted
+	UUID     string
+	DataKey  Encrypted
+	Revision int64
+}
+
+// Upload is a started upload of chunked binary data.
+type Upload struct {
+	// ID identifies upload, chosen by client.
+	ID string
+	// RecordID is an ID of binary record which data is replaced, 0 for new record.
+	RecordID ID
+	Meta     Encrypted
+	UUID     string
+	DataKey  Encrypted
+	// Chunks is a count of received chunks.
+	Chunks int64
+}
+
+// Version is a previous version of encrypted record saved on its update.
+type Version struct {
+	// Number is a number of version, versions of record are numbered from 1.
+	Number  int64
+	Created time.Time
+	Record  RecordEncrypted
+}
+
+// ChangeOp is an operation of record change.
+type ChangeOp int
+
+// Operations of record changes.
+const (
+	ChangeUnknown ChangeOp = iota
+	ChangeCreate
+	ChangeUpdate
+	// ChangeDelete is a tombstone of record moved to trash or deleted permanently.
+	ChangeDelete
+)
+
+// String returns name of ChangeOp.
+func (op ChangeOp) String() string {
+	switch op {
+	case ChangeCreate:
+		return "created"
+	case ChangeUpdate:
+		return "updated"
+	case ChangeDelete:
+		return "deleted"
+	default:
+		return "unknown"
+	}
+}
+
+// ErrConflict is returned for update or deletion of record changed since expected revision.
+var ErrConflict = errors.New("record was changed since expected revision")
+
+// Change is a last change of record of user with server revision.
+type Change struct {
+	Revision int64
+	Op       ChangeOp
+	Type     RecordType
+	ID       ID
+	// Record is empty for ChangeDelete.
+	Record RecordEncrypted
+}
+
+// TrashItem is a deleted encrypted record kept in trash of user until restore or purge.
+type TrashItem struct {
+	Type    RecordType
+	Deleted time.Time
+	// Record has id, meta and keys only.
+	Record RecordEncrypted
+}
+
+// DeviceID type for device's ID field.
+type DeviceID int
+
+// Device is a registered device of User, its certificate CommonName maps to UserID.
+type Device struct {
+	ID      DeviceID
+	UserID  UserID
+	Cn      string
+	Created time.Time
+	// Current is true for device which made request.
+	Current bool
+}
+
+// Enrollment is a new device waiting for approval by existing device of User.
+type Enrollment struct {
+	// Code identifies enrollment, it is shown on new device and entered on approving one.
+	Code        string
+	UserID      UserID
+	Cn          string
+	CertRequest []byte
+	// PublicKey is a PKIX public key of CertRequest.
+	PublicKey []byte
+	Created   time.Time
+	Approved  bool
+	// VaultKey is a vault key wrapped by approving device for new device's public key.
+	VaultKey Encrypted
 }
 
 // String implements Stringer interface.
@@ -144,3 +285,148 @@ func (r RecordType) String() string {
 		return RecordUnknownName
 	}
 }
+
+// RevocationReason is a reason code of revoked certificate (RFC 5280).
+type RevocationReason int
+
+// Revocation reasons supported by server.
+const (
+	RevocationUnspecified          RevocationReason = 0
+	RevocationKeyCompromise        RevocationReason = 1
+	RevocationAffiliationChanged   RevocationReason = 3
+	RevocationSuperseded           RevocationReason = 4
+	RevocationCessationOfOperation RevocationReason = 5
+)
+
+// Names of RevocationReasons.
+const (
+	RevocationUnspecifiedName          string = "unspecified"
+	RevocationKeyCompromiseName        string = "keyCompromise"
+	RevocationAffiliationChangedName   string = "affiliationChanged"
+	RevocationSupersededName           string = "superseded"
+	RevocationCessationOfOperationName string = "cessationOfOperation"
+)
+
+// Certificate is a client certificate issued by server.
+type Certificate struct {
+	// Serial is a hex serial number of certificate.
+	Serial   string
+	UserID   UserID
+	Cn       string
+	Issued   time.Time
+	NotAfter time.Time
+	Revoked  bool
+}
+
+// Revocation is a revoked certificate.
+type Revocation struct {
+	Serial  string
+	Cn      string
+	Reason  RevocationReason
+	Revoked time.Time
+}
+
+// String implements Stringer interface.
+func (r RevocationReason) String() string {
+	switch r {
+	case RevocationKeyCompromise:
+		return RevocationKeyCompromiseName
+	case RevocationAffiliationChanged:
+		return RevocationAffiliationChangedName
+	case RevocationSuperseded:
+		return RevocationSupersededName
+	case RevocationCessationOfOperation:
+		return RevocationCessationOfOperationName
+	default:
+		return RevocationUnspecifiedName
+	}
+}
+
+// ParseRevocationReason returns RevocationReason by its name.
+func ParseRevocationReason(name string) (RevocationReason, error) {
+	for _, r := range []RevocationReason{
+		RevocationUnspecified,
+		RevocationKeyCompromise,
+		RevocationAffiliationChanged,
+		RevocationSuperseded,
+		RevocationCessationOfOperation,
+	} {
+		if strings.EqualFold(name, r.String()) {
+			return r, nil
+		}
+	}
+	return RevocationUnspecified, fmt.Errorf("unknown revocation reason %q", name)
+}
+
+// RegistrationMode is a mode of registration of new users on the server.
+type RegistrationMode string
+
+// Registration modes supported by server.
+const (
+	// RegistrationOpen registers anyone who can reach public server.
+	RegistrationOpen RegistrationMode = "open"
+	// RegistrationInvite registers user with single-use Invite token.
+	RegistrationInvite RegistrationMode = "invite"
+	// RegistrationApproval registers user after admin approves pending Registration.
+	RegistrationApproval RegistrationMode = "approval"
+)
+
+// String implements Stringer interface.
+func (m RegistrationMode) String() string {
+	return string(m)
+}
+
+// ParseRegistrationMode returns RegistrationMode by its name.
+func ParseRegistrationMode(name string) (RegistrationMode, error) {
+	for _, m := range []RegistrationMode{RegistrationOpen, RegistrationInvite, RegistrationApproval} {
+		if strings.EqualFold(name, m.String()) {
+			return m, nil
+		}
+	}
+	return RegistrationOpen, fmt.Errorf("unknown registration mode %q", name)
+}
+
+// Invite is a single-use token which allows registration of one user.
+type Invite struct {
+	Token   string
+	Created time.Time
+	Expires time.Time
+	// Cn is a name of user registered with invite, empty if invite is not used.
+	Cn string
+}
+
+// Registration is a registration of new user waiting for admin approval.
+type Registration struct {
+	Code        string
+	Cn          string
+	CertRequest []byte
+	Created     time.Time
+	Approved    bool
+}
+
+// UserInfo is a user with its state, devices and certificates for server administration.
+type UserInfo struct {
+	User
+	Disabled     bool
+	Devices      []Device
+	Certificates []Certificate
+}
+
+// RecordStats is a count and stored size of encrypted records of one type.
+type RecordStats struct {
+	Count int64
+	Bytes int64
+}
+
+// UserStats is a statistics of records of user by type.
+type UserStats struct {
+	User
+	Records map[RecordType]RecordStats
+}
+
+// Migration is an applied schema change of database.
+type Migration struct {
+	Version     int
+	Description string
+	Applied     time.Time
+}
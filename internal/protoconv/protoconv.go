@@ -0,0 +1,265 @@
+// Package protoconv converts encrypted records between models and proto messages.
+package protoconv
+
+import (
+	"github.com/sejo412/gophkeeper/internal/models"
+	pb "github.com/sejo412/gophkeeper/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+// RecordTypeToProto converts models.RecordType to proto RecordType.
+func RecordTypeToProto(t models.RecordType) pb.RecordType {
+	switch t {
+	case models.RecordPassword:
+		return pb.RecordType_PASSWORD
+	case models.RecordText:
+		return pb.RecordType_TEXT
+	case models.RecordBin:
+		return pb.RecordType_BIN
+	case models.RecordBank:
+		return pb.RecordType_BANK
+	default:
+		return pb.RecordType_UNKNOWN
+	}
+}
+
+// RecordTypeFromProto converts proto RecordType to models.RecordType.
+func RecordTypeFromProto(t pb.RecordType) models.RecordType {
+	switch t {
+	case pb.RecordType_PASSWORD:
+		return models.RecordPassword
+	case pb.RecordType_TEXT:
+		return models.RecordText
+	case pb.RecordType_BIN:
+		return models.RecordBin
+	case pb.RecordType_BANK:
+		return models.RecordBank
+	default:
+		return models.RecordUnknown
+	}
+}
+
+// RecordToProto converts models.RecordEncrypted of type t to proto Record.
+// Returns nil for unknown models.RecordType.
+func RecordToProto(t models.RecordType, r models.RecordEncrypted) *pb.Record {
+	switch t {
+	case models.RecordPassword:
+		return &pb.Record{
+			Record: &pb.Record_Password{
+				Password: &pb.PasswordRecord{
+					Id:       proto.Int64(int64(r.Password.ID)),
+					Login:    r.Password.Login,
+					Password: r.Password.Password,
+					Meta:     r.Password.Meta,
+					Uuid:     uuid(r.Password.UUID),
+					DataKey:  r.Password.DataKey,
+					Revision: proto.Int64(r.Password.Revision),
+				},
+			},
+		}
+	case models.RecordText:
+		return &pb.Record{
+			Record: &pb.Record_Text{
+				Text: &pb.TextRecord{
+					Id:       proto.Int64(int64(r.Text.ID)),
+					Text:     r.Text.Text,
+					Meta:     r.Text.Meta,
+					Uuid:     uuid(r.Text.UUID),
+					DataKey:  r.Text.DataKey,
+					Revision: proto.Int64(r.Text.Revision),
+				},
+			},
+		}
+	case models.RecordBin:
+		return &pb.Record{
+			Record: &pb.Record_Bin{
+				Bin: &pb.BinRecord{
+					Id:       proto.Int64(int64(r.Bin.ID)),
+					Data:     r.Bin.Data,
+					Meta:     r.Bin.Meta,
+					Uuid:     uuid(r.Bin.UUID),
+					DataKey:  r.Bin.DataKey,
+					Revision: proto.Int64(r.Bin.Revision),
+				},
+			},
+		}
+	case models.RecordBank:
+		return &pb.Record{
+			Record: &pb.Record_Bank{
+				Bank: &pb.BankRecord{
+					Id:       proto.Int64(int64(r.Bank.ID)),
+					Number:   r.Bank.Number,
+					Name:     r.Bank.Name,
+					Date:     r.Bank.Date,
+					Cvv:      r.Bank.Cvv,
+					Meta:     r.Bank.Meta,
+					Uuid:     uuid(r.Bank.UUID),
+					DataKey:  r.Bank.DataKey,
+					Revision: proto.Int64(r.Bank.Revision),
+				},
+			},
+		}
+	default:
+		return nil
+	}
+}
+
+// RecordFromProto converts proto Record to models.RecordEncrypted and returns its models.RecordType.
+func RecordFromProto(r *pb.Record) (models.RecordType, models.RecordEncrypted) {
+	result := models.RecordEncrypted{}
+	switch rec := r.GetRecord().(type) {
+	case *pb.Record_Password:
+		result.Password = models.PasswordEncrypted{
+			ID:       models.ID(rec.Password.GetId()),
+			Login:    rec.Password.GetLogin(),
+			Password: rec.Password.GetPassword(),
+			Meta:     rec.Password.GetMeta(),
+			UUID:     rec.Password.GetUuid(),
+			DataKey:  rec.Password.GetDataKey(),
+			Revision: rec.Password.GetRevision(),
+		}
+		return models.RecordPassword, result
+	case *pb.Record_Text:
+		result.Text = models.TextEncrypted{
+			ID:       models.ID(rec.Text.GetId()),
+			Text:     rec.Text.GetText(),
+			Meta:     rec.Text.GetMeta(),
+			UUID:     rec.Text.GetUuid(),
+			DataKey:  rec.Text.GetDataKey(),
+			Revision: rec.Text.GetRevision(),
+		}
+		return models.RecordText, result
+	case *pb.Record_Bin:
+		result.Bin = models.BinEncrypted{
+			ID:       models.ID(rec.Bin.GetId()),
+			Data:     rec.Bin.GetData(),
+			Meta:     rec.Bin.GetMeta(),
+			UUID:     rec.Bin.GetUuid(),
+			DataKey:  rec.Bin.GetDataKey(),
+			Revision: rec.Bin.GetRevision(),
+		}
+		return models.RecordBin, result
+	case *pb.Record_Bank:
+		result.Bank = models.BankEncrypted{
+			ID:       models.ID(rec.Bank.GetId()),
+			Number:   rec.Bank.GetNumber(),
+			Name:     rec.Bank.GetName(),
+			Date:     rec.Bank.GetDate(),
+			Cvv:      rec.Bank.GetCvv(),
+			Meta:     rec.Bank.GetMeta(),
+			UUID:     rec.Bank.GetUuid(),
+			DataKey:  rec.Bank.GetDataKey(),
+			Revision: rec.Bank.GetRevision(),
+		}
+		return models.RecordBank, result
+	default:
+		return models.RecordUnknown, result
+	}
+}
+
+// uuid returns proto optional string for record UUID, nil for legacy record without UUID.
+func uuid(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return proto.String(s)
+}
+
+// RecordsToProto converts models.RecordsEncrypted to slice of proto Record.
+func RecordsToProto(r models.RecordsEncrypted) []*pb.Record {
+	result := make([]*pb.Record, 0, len(r.Password)+len(r.Text)+len(r.Bin)+len(r.Bank))
+	for _, rec := range r.Password {
+		result = append(result, RecordToProto(models.RecordPassword, models.RecordEncrypted{Password: rec}))
+	}
+	for _, rec := range r.Text {
+		result = append(result, RecordToProto(models.RecordText, models.RecordEncrypted{Text: rec}))
+	}
+	for _, rec := range r.Bin {
+		result = append(result, RecordToProto(models.RecordBin, models.RecordEncrypted{Bin: rec}))
+	}
+	for _, rec := range r.Bank {
+		result = append(result, RecordToProto(models.RecordBank, models.RecordEncrypted{Bank: rec}))
+	}
+	return result
+}
+
+// RecordsFromProto converts slice of proto Record to models.RecordsEncrypted.
+func RecordsFromProto(r []*pb.Record) models.RecordsEncrypted {
+	result := models.RecordsEncrypted{
+		Password: []models.PasswordEncrypted{},
+		Text:     []models.TextEncrypted{},
+		Bin:      []models.BinEncrypted{},
+		Bank:     []models.BankEncrypted{},
+	}
+	for _, rec := range r {
+		t, encrypted := RecordFromProto(rec)
+		switch t {
+		case models.RecordPassword:
+			result.Password = append(result.Password, encrypted.Password)
+		case models.RecordText:
+			result.Text = append(result.Text, encrypted.Text)
+		case models.RecordBin:
+			result.Bin = append(result.Bin, encrypted.Bin)
+		case models.RecordBank:
+			result.Bank = append(result.Bank, encrypted.Bank)
+		default:
+		}
+	}
+	return result
+}
+
+// ChangeOpToProto converts models.ChangeOp to proto ChangeOp.
+func ChangeOpToProto(op models.ChangeOp) pb.ChangeOp {
+	switch op {
+	case models.ChangeCreate:
+		return pb.ChangeOp_CHANGE_CREATE
+	case models.ChangeUpdate:
+		return pb.ChangeOp_CHANGE_UPDATE
+	case models.ChangeDelete:
+		return pb.ChangeOp_CHANGE_DELETE
+	default:
+		return pb.ChangeOp_CHANGE_UNKNOWN
+	}
+}
+
+// ChangeOpFromProto converts proto ChangeOp to models.ChangeOp.
+func ChangeOpFromProto(op pb.ChangeOp) models.ChangeOp {
+	switch op {
+	case pb.ChangeOp_CHANGE_CREATE:
+		return models.ChangeCreate
+	case pb.ChangeOp_CHANGE_UPDATE:
+		return models.ChangeUpdate
+	case pb.ChangeOp_CHANGE_DELETE:
+		return models.ChangeDelete
+	default:
+		return models.ChangeUnknown
+	}
+}
+
+// ChangeToProto converts models.Change to proto Change, tombstone is sent without item.
+func ChangeToProto(c models.Change) *pb.Change {
+	change := &pb.Change{
+		Revision:     proto.Int64(c.Revision),
+		Op:           ChangeOpToProto(c.Op).Enum(),
+		Type:         RecordTypeToProto(c.Type).Enum(),
+		RecordNumber: proto.Int64(int64(c.ID)),
+	}
+	if c.Op != models.ChangeDelete {
+		change.Item = RecordToProto(c.Type, c.Record)
+	}
+	return change
+}
+
+// ChangeFromProto converts proto Change to models.Change.
+func ChangeFromProto(c *pb.Change) models.Change {
+	change := models.Change{
+		Revision: c.GetRevision(),
+		Op:       ChangeOpFromProto(c.GetOp()),
+		Type:     RecordTypeFromProto(c.GetType()),
+		ID:       models.ID(c.GetRecordNumber()),
+	}
+	if item := c.GetItem(); item != nil {
+		_, change.Record = RecordFromProto(item)
+	}
+	return change
+}
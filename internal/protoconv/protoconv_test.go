@@ -0,0 +1,117 @@
+package protoconv
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+	pb "github.com/sejo412/gophkeeper/proto"
+)
+
+func TestRecordToProto(t *testing.T) {
+	tests := []struct {
+		name   string
+		t      models.RecordType
+		record models.RecordEncrypted
+	}{
+		{
+			name: "password",
+			t:    models.RecordPassword,
+			record: models.RecordEncrypted{
+				Password: models.PasswordEncrypted{
+					ID:       1,
+					Login:    []byte("login"),
+					Password: []byte("password"),
+					Meta:     []byte("meta"),
+					UUID:     "uuid",
+				},
+			},
+		},
+		{
+			name: "text",
+			t:    models.RecordText,
+			record: models.RecordEncrypted{
+				Text: models.TextEncrypted{ID: 2, Text: []byte("text"), Meta: []byte("meta")},
+			},
+		},
+		{
+			name: "bin",
+			t:    models.RecordBin,
+			record: models.RecordEncrypted{
+				Bin: models.BinEncrypted{ID: 3, Data: []byte("data"), Meta: []byte("meta")},
+			},
+		},
+		{
+			name: "bank",
+			t:    models.RecordBank,
+			record: models.RecordEncrypted{
+				Bank: models.BankEncrypted{
+					ID:     4,
+					Number: []byte("number"),
+					Name:   []byte("name"),
+					Date:   []byte("date"),
+					Cvv:    []byte("cvv"),
+					Meta:   []byte("meta"),
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotType, got := RecordFromProto(RecordToProto(tt.t, tt.record))
+			if gotType != tt.t {
+				t.Errorf("RecordFromProto() type = %v, want %v", gotType, tt.t)
+			}
+			if !reflect.DeepEqual(got, tt.record) {
+				t.Errorf("RecordFromProto() got = %v, want %v", got, tt.record)
+			}
+		})
+	}
+}
+
+func TestRecordToProto_unknown(t *testing.T) {
+	if got := RecordToProto(models.RecordUnknown, models.RecordEncrypted{}); got != nil {
+		t.Errorf("RecordToProto() = %v, want nil", got)
+	}
+	if gotType, _ := RecordFromProto(nil); gotType != models.RecordUnknown {
+		t.Errorf("RecordFromProto() type = %v, want %v", gotType, models.RecordUnknown)
+	}
+}
+
+func TestRecordsToProto(t *testing.T) {
+	records := models.RecordsEncrypted{
+		Password: []models.PasswordEncrypted{{ID: 1, Meta: []byte("one")}},
+		Text:     []models.TextEncrypted{},
+		Bin:      []models.BinEncrypted{{ID: 2, Meta: []byte("two")}},
+		Bank:     []models.BankEncrypted{},
+	}
+	got := RecordsToProto(records)
+	if len(got) != 2 {
+		t.Fatalf("RecordsToProto() len = %d, want 2", len(got))
+	}
+	if _, ok := got[1].GetRecord().(*pb.Record_Bin); !ok {
+		t.Errorf("RecordsToProto() got[1] = %v, want bin", got[1])
+	}
+	if back := RecordsFromProto(got); !reflect.DeepEqual(back, records) {
+		t.Errorf("RecordsFromProto() got = %v, want %v", back, records)
+	}
+}
+
+func TestChangeToProto(t *testing.T) {
+	changes := []models.Change{
+		{
+			Revision: 3, Op: models.ChangeUpdate, Type: models.RecordText, ID: 2,
+			Record: models.RecordEncrypted{Text: models.TextEncrypted{ID: 2, Meta: []byte("meta"), Revision: 3}},
+		},
+		{Revision: 4, Op: models.ChangeDelete, Type: models.RecordBin, ID: 5},
+	}
+	for _, change := range changes {
+		got := ChangeToProto(change)
+		if (got.GetItem() == nil) != (change.Op == models.ChangeDelete) {
+			t.Errorf("ChangeToProto(%v) item = %v, want item only for not deleted record", change, got.GetItem())
+		}
+		if back := ChangeFromProto(got); !reflect.DeepEqual(back, change) {
+			t.Errorf("ChangeFromProto() got = %v, want %v", back, change)
+		}
+	}
+}
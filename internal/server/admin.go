@@ -0,0 +1,442 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"path/filepath"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/constants"
+	"github.com/sejo412/gophkeeper/internal/models"
+	"github.com/sejo412/gophkeeper/internal/protoconv"
+	pb "github.com/sejo412/gophkeeper/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// ErrUserNotFound is returned by Admin for unknown user name.
+var ErrUserNotFound = errors.New("user not found")
+
+// Admin administrates users and certificates of server.
+type Admin interface {
+	// Users returns all users with their devices and certificates.
+	Users(ctx context.Context) ([]models.UserInfo, error)
+	// User returns user by name.
+	User(ctx context.Context, name string) (models.UserInfo, error)
+	// DeleteUser deletes user with all its records and revokes its certificates.
+	DeleteUser(ctx context.Context, name string) error
+	// DisableUser disables or enables user, devices of disabled user are rejected.
+	DisableUser(ctx context.Context, name string, disabled bool) error
+	// Stats returns count and stored size of records by type for all users.
+	Stats(ctx context.Context) ([]models.UserStats, error)
+	// Certificates returns all issued client certificates.
+	Certificates(ctx context.Context) ([]models.Certificate, error)
+	// Close releases storage or connection to running server.
+	Close() error
+}
+
+// Admin returns Admin of server. Running server (ephemeral one too) is administrated by its admin
+// service on unix socket in cache dir, stopped server by its database directly.
+func (s *Server) Admin() (Admin, error) {
+	socket := s.adminSocket()
+	if s.running() {
+		grpcConn, er := grpc.NewClient("unix://"+socket, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if er != nil {
+			return nil, fmt.Errorf("could not connect to admin service: %w", er)
+		}
+		return &remoteAdmin{conn: grpcConn, client: pb.NewAdminClient(grpcConn)}, nil
+	}
+	if err := s.checkNotEphemeral(); err != nil {
+		return nil, err
+	}
+	store, err := s.storage()
+	if err != nil {
+		return nil, err
+	}
+	return &storageAdmin{store: store}, nil
+}
+
+func (s *Server) adminSocket() string {
+	return filepath.Join(s.config.CacheDir, constants.AdminSocketDir, constants.AdminSocketFilename)
+}
+
+// running returns true if server with same cache dir listens admin socket.
+func (s *Server) running() bool {
+	conn, err := net.Dial("unix", s.adminSocket())
+	if err != nil {
+		return false
+	}
+	_ = conn.Close()
+	return true
+}
+
+// storageAdmin administrates server by its Storage.
+type storageAdmin struct {
+	store Storage
+}
+
+func (a *storageAdmin) Users(ctx context.Context) ([]models.UserInfo, error) {
+	users, err := a.store.Users(ctx)
+	if err != nil {
+		return nil, err
+	}
+	certificates, err := a.store.Certificates(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]models.UserInfo, 0, len(users))
+	for _, u := range users {
+		info, er := a.userInfo(ctx, u, certificates)
+		if er != nil {
+			return nil, er
+		}
+		result = append(result, info)
+	}
+	return result, nil
+}
+
+func (a *storageAdmin) User(ctx context.Context, name string) (models.UserInfo, error) {
+	uid, err := a.findUser(ctx, name)
+	if err != nil {
+		return models.UserInfo{}, err
+	}
+	certificates, err := a.store.Certificates(ctx)
+	if err != nil {
+		return models.UserInfo{}, err
+	}
+	return a.userInfo(ctx, models.User{ID: uid, Cn: name}, certificates)
+}
+
+func (a *storageAdmin) DeleteUser(ctx context.Context, name string) error {
+	uid, err := a.findUser(ctx, name)
+	if err != nil {
+		return err
+	}
+	if err = a.store.DeleteUser(ctx, uid); err != nil {
+		return err
+	}
+	certificates, err := a.store.Certificates(ctx)
+	if err != nil {
+		return err
+	}
+	for _, c := range certificates {
+		if c.UserID != uid || c.Revoked {
+			continue
+		}
+		if err = a.store.Revoke(
+			ctx, models.Revocation{
+				Serial:  c.Serial,
+				Cn:      c.Cn,
+				Reason:  models.RevocationCessationOfOperation,
+				Revoked: time.Now(),
+			},
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (a *storageAdmin) DisableUser(ctx context.Context, name string, disabled bool) error {
+	uid, err := a.findUser(ctx, name)
+	if err != nil {
+		return err
+	}
+	return a.store.DisableUser(ctx, uid, disabled)
+}
+
+func (a *storageAdmin) Stats(ctx context.Context) ([]models.UserStats, error) {
+	return a.store.Stats(ctx)
+}
+
+func (a *storageAdmin) Certificates(ctx context.Context) ([]models.Certificate, error) {
+	return a.store.Certificates(ctx)
+}
+
+func (a *storageAdmin) Close() error {
+	return a.store.Close()
+}
+
+func (a *storageAdmin) findUser(ctx context.Context, name string) (models.UserID, error) {
+	uid, err := a.store.FindUser(ctx, name)
+	if err != nil {
+		return -1, err
+	}
+	if uid < 0 {
+		return -1, fmt.Errorf("%w: %q", ErrUserNotFound, name)
+	}
+	return uid, nil
+}
+
+func (a *storageAdmin) userInfo(
+	ctx context.Context, u models.User, certificates []models.Certificate,
+) (models.UserInfo, error) {
+	disabled, err := a.store.IsUserDisabled(ctx, u.ID)
+	if err != nil {
+		return models.UserInfo{}, err
+	}
+	devices, err := a.store.Devices(ctx, u.ID)
+	if err != nil {
+		return models.UserInfo{}, err
+	}
+	info := models.UserInfo{User: u, Disabled: disabled, Devices: devices, Certificates: make([]models.Certificate, 0)}
+	for _, c := range certificates {
+		if c.UserID == u.ID {
+			info.Certificates = append(info.Certificates, c)
+		}
+	}
+	return info, nil
+}
+
+// GRPCAdmin implements proto admin server on local unix socket of running server.
+type GRPCAdmin struct {
+	pb.UnimplementedAdminServer
+	admin *storageAdmin
+}
+
+// NewGRPCAdmin constructs new GRPCAdmin object for Server.
+func NewGRPCAdmin(config Config) *GRPCAdmin {
+	return &GRPCAdmin{
+		admin: &storageAdmin{store: config.Storage},
+	}
+}
+
+// ListUsers returns all users.
+func (a *GRPCAdmin) ListUsers(ctx context.Context, _ *emptypb.Empty) (*pb.AdminListUsersResponse, error) {
+	users, err := a.admin.Users(ctx)
+	if err != nil {
+		return nil, adminError(err)
+	}
+	resp := &pb.AdminListUsersResponse{Users: make([]*pb.AdminUser, 0, len(users))}
+	for _, u := range users {
+		resp.Users = append(resp.Users, userInfoToProto(u))
+	}
+	return resp, nil
+}
+
+// GetUser returns user by name.
+func (a *GRPCAdmin) GetUser(ctx context.Context, in *pb.AdminUserRequest) (*pb.AdminUser, error) {
+	user, err := a.admin.User(ctx, in.GetName())
+	if err != nil {
+		return nil, adminError(err)
+	}
+	return userInfoToProto(user), nil
+}
+
+// DeleteUser deletes user with all its records and revokes its certificates.
+func (a *GRPCAdmin) DeleteUser(ctx context.Context, in *pb.AdminUserRequest) (*emptypb.Empty, error) {
+	if err := a.admin.DeleteUser(ctx, in.GetName()); err != nil {
+		return nil, adminError(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// DisableUser disables or enables user.
+func (a *GRPCAdmin) DisableUser(ctx context.Context, in *pb.AdminDisableUserRequest) (*emptypb.Empty, error) {
+	if err := a.admin.DisableUser(ctx, in.GetName(), in.GetDisabled()); err != nil {
+		return nil, adminError(err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// Stats returns count and stored size of records by type for all users.
+func (a *GRPCAdmin) Stats(ctx context.Context, _ *emptypb.Empty) (*pb.AdminStatsResponse, error) {
+	stats, err := a.admin.Stats(ctx)
+	if err != nil {
+		return nil, adminError(err)
+	}
+	resp := &pb.AdminStatsResponse{Users: make([]*pb.AdminUserStats, 0, len(stats))}
+	for _, u := range stats {
+		item := &pb.AdminUserStats{
+			Id:   proto.Int64(int64(u.ID)),
+			Name: proto.String(u.Cn),
+		}
+		for t, r := range u.Records {
+			item.Records = append(
+				item.Records, &pb.AdminRecordStats{
+					Type:  protoconv.RecordTypeToProto(t).Enum(),
+					Count: proto.Int64(r.Count),
+					Bytes: proto.Int64(r.Bytes),
+				},
+			)
+		}
+		resp.Users = append(resp.Users, item)
+	}
+	return resp, nil
+}
+
+// ListCertificates returns all issued client certificates.
+func (a *GRPCAdmin) ListCertificates(ctx context.Context, _ *emptypb.Empty) (
+	*pb.AdminListCertificatesResponse, error,
+) {
+	certificates, err := a.admin.Certificates(ctx)
+	if err != nil {
+		return nil, adminError(err)
+	}
+	return &pb.AdminListCertificatesResponse{Certificates: certificatesToProto(certificates)}, nil
+}
+
+func adminError(err error) error {
+	if errors.Is(err, ErrUserNotFound) {
+		return status.Error(codes.NotFound, err.Error())
+	}
+	slog.Error("admin request", "error", err)
+	return status.Error(codes.Internal, err.Error())
+}
+
+// remoteAdmin administrates running server by its admin service.
+type remoteAdmin struct {
+	conn   *grpc.ClientConn
+	client pb.AdminClient
+}
+
+func (a *remoteAdmin) Users(ctx context.Context) ([]models.UserInfo, error) {
+	resp, err := a.client.ListUsers(ctx, &emptypb.Empty{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list users: %w", err)
+	}
+	users := make([]models.UserInfo, 0, len(resp.GetUsers()))
+	for _, u := range resp.GetUsers() {
+		users = append(users, userInfoFromProto(u))
+	}
+	return users, nil
+}
+
+func (a *remoteAdmin) User(ctx context.Context, name string) (models.UserInfo, error) {
+	resp, err := a.client.GetUser(ctx, &pb.AdminUserRequest{Name: proto.String(name)})
+	if err != nil {
+		return models.UserInfo{}, fmt.Errorf("failed to get user: %w", err)
+	}
+	return userInfoFromProto(resp), nil
+}
+
+func (a *remoteAdmin) DeleteUser(ctx context.Context, name string) error {
+	if _, err := a.client.DeleteUser(ctx, &pb.AdminUserRequest{Name: proto.String(name)}); err != nil {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+	return nil
+}
+
+func (a *remoteAdmin) DisableUser(ctx context.Context, name string, disabled bool) error {
+	if _, err := a.client.DisableUser(
+		ctx, &pb.AdminDisableUserRequest{Name: proto.String(name), Disabled: proto.Bool(disabled)},
+	); err != nil {
+		return fmt.Errorf("failed to disable user: %w", err)
+	}
+	return nil
+}
+
+func (a *remoteAdmin) Stats(ctx context.Context) ([]models.UserStats, error) {
+	resp, err := a.client.Stats(ctx, &emptypb.Empty{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get stats: %w", err)
+	}
+	stats := make([]models.UserStats, 0, len(resp.GetUsers()))
+	for _, u := range resp.GetUsers() {
+		item := models.UserStats{
+			User:    models.User{ID: models.UserID(u.GetId()), Cn: u.GetName()},
+			Records: make(map[models.RecordType]models.RecordStats),
+		}
+		for _, r := range u.GetRecords() {
+			item.Records[protoconv.RecordTypeFromProto(r.GetType())] = models.RecordStats{
+				Count: r.GetCount(),
+				Bytes: r.GetBytes(),
+			}
+		}
+		stats = append(stats, item)
+	}
+	return stats, nil
+}
+
+func (a *remoteAdmin) Certificates(ctx context.Context) ([]models.Certificate, error) {
+	resp, err := a.client.ListCertificates(ctx, &emptypb.Empty{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list certificates: %w", err)
+	}
+	return certificatesFromProto(resp.GetCertificates()), nil
+}
+
+func (a *remoteAdmin) Close() error {
+	return a.conn.Close()
+}
+
+func userInfoToProto(u models.UserInfo) *pb.AdminUser {
+	user := &pb.AdminUser{
+		Id:           proto.Int64(int64(u.ID)),
+		Name:         proto.String(u.Cn),
+		Disabled:     proto.Bool(u.Disabled),
+		Devices:      make([]*pb.Device, 0, len(u.Devices)),
+		Certificates: certificatesToProto(u.Certificates),
+	}
+	for _, d := range u.Devices {
+		user.Devices = append(
+			user.Devices, &pb.Device{
+				Id:      proto.Int64(int64(d.ID)),
+				Name:    proto.String(d.Cn),
+				Created: proto.Int64(d.Created.Unix()),
+			},
+		)
+	}
+	return user
+}
+
+func userInfoFromProto(u *pb.AdminUser) models.UserInfo {
+	user := models.UserInfo{
+		User:         models.User{ID: models.UserID(u.GetId()), Cn: u.GetName()},
+		Disabled:     u.GetDisabled(),
+		Devices:      make([]models.Device, 0, len(u.GetDevices())),
+		Certificates: certificatesFromProto(u.GetCertificates()),
+	}
+	for _, d := range u.GetDevices() {
+		user.Devices = append(
+			user.Devices, models.Device{
+				ID:      models.DeviceID(d.GetId()),
+				UserID:  user.ID,
+				Cn:      d.GetName(),
+				Created: time.Unix(d.GetCreated(), 0),
+			},
+		)
+	}
+	return user
+}
+
+func certificatesToProto(certificates []models.Certificate) []*pb.AdminCertificate {
+	result := make([]*pb.AdminCertificate, 0, len(certificates))
+	for _, c := range certificates {
+		result = append(
+			result, &pb.AdminCertificate{
+				Serial:   proto.String(c.Serial),
+				UserId:   proto.Int64(int64(c.UserID)),
+				Name:     proto.String(c.Cn),
+				Issued:   proto.Int64(c.Issued.Unix()),
+				NotAfter: proto.Int64(c.NotAfter.Unix()),
+				Revoked:  proto.Bool(c.Revoked),
+			},
+		)
+	}
+	return result
+}
+
+func certificatesFromProto(certificates []*pb.AdminCertificate) []models.Certificate {
+	result := make([]models.Certificate, 0, len(certificates))
+	for _, c := range certificates {
+		result = append(
+			result, models.Certificate{
+				Serial:   c.GetSerial(),
+				UserID:   models.UserID(c.GetUserId()),
+				Cn:       c.GetName(),
+				Issued:   time.Unix(c.GetIssued(), 0),
+				NotAfter: time.Unix(c.GetNotAfter(), 0),
+				Revoked:  c.GetRevoked(),
+			},
+		)
+	}
+	return result
+}
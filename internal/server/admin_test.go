@@ -0,0 +1,68 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+)
+
+func TestServer_Admin(t *testing.T) {
+	ctx := context.Background()
+	s := NewServer(Config{CacheDir: t.TempDir()})
+	if err := s.Init(); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	store, err := s.storage()
+	if err != nil {
+		t.Fatalf("storage() error = %v", err)
+	}
+	uid, err := store.NewUser(ctx, "laptop")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = store.AddCertificate(
+		ctx, models.Certificate{
+			Serial: "a1", UserID: uid, Cn: "laptop", Issued: time.Now(), NotAfter: time.Now().Add(time.Hour),
+		},
+	); err != nil {
+		t.Fatal(err)
+	}
+	_ = store.Close()
+
+	admin, err := s.Admin()
+	if err != nil {
+		t.Fatalf("Admin() error = %v", err)
+	}
+	defer func() {
+		_ = admin.Close()
+	}()
+	if _, ok := admin.(*storageAdmin); !ok {
+		t.Fatalf("Admin() of stopped server = %T, want *storageAdmin", admin)
+	}
+	users, err := admin.Users(ctx)
+	if err != nil || len(users) != 1 || len(users[0].Devices) != 1 || len(users[0].Certificates) != 1 {
+		t.Fatalf("Users() = %v, %v, want one user with device and certificate", users, err)
+	}
+	if _, err = admin.User(ctx, "unknown"); !errors.Is(err, ErrUserNotFound) {
+		t.Errorf("User() of unknown user error = %v, want %v", err, ErrUserNotFound)
+	}
+	if err = admin.DisableUser(ctx, "laptop", true); err != nil {
+		t.Fatalf("DisableUser() error = %v", err)
+	}
+	if u, _ := admin.User(ctx, "laptop"); !u.Disabled {
+		t.Errorf("User() disabled = false, want true")
+	}
+	if err = admin.DeleteUser(ctx, "laptop"); err != nil {
+		t.Fatalf("DeleteUser() error = %v", err)
+	}
+	if users, _ = admin.Users(ctx); len(users) != 0 {
+		t.Errorf("Users() after delete = %v, want empty", users)
+	}
+	certificates, err := admin.Certificates(ctx)
+	if err != nil || len(certificates) != 1 || !certificates[0].Revoked {
+		t.Errorf("Certificates() after delete = %v, %v, want revoked certificate", certificates, err)
+	}
+}
@@ -5,12 +5,11 @@ import (
 	"crypto/x509"
 	"fmt"
 	"net"
-	"os"
+	"time"
 
 	"github.com/sejo412/gophkeeper/internal/constants"
 	"github.com/sejo412/gophkeeper/internal/helpers"
 	"github.com/sejo412/gophkeeper/internal/models"
-	"github.com/sejo412/gophkeeper/internal/storage/sqlite"
 	"github.com/sejo412/gophkeeper/pkg/certs"
 )
 
@@ -18,6 +17,14 @@ import (
 type Storage interface {
 	// Init creates and fills store with init data.
 	Init(ctx context.Context) error
+	// Reset deletes all data and schema of store.
+	Reset(ctx context.Context) error
+	// SchemaVersion returns current schema version of store and latest version supported by server.
+	SchemaVersion(ctx context.Context) (int, int, error)
+	// Migrate applies not applied schema migrations and returns them.
+	Migrate(ctx context.Context) ([]models.Migration, error)
+	// Migrations returns applied schema migrations.
+	Migrations(ctx context.Context) ([]models.Migration, error)
 	// Close closes connection.
 	Close() error
 	// ListAll returns all id and meta by owner.
@@ -28,37 +35,126 @@ type Storage interface {
 	Get(ctx context.Context, uid models.UserID, t models.RecordType, id models.ID) (models.RecordEncrypted, error)
 	// Add creates new Record for User.
 	Add(ctx context.Context, uid models.UserID, t models.RecordType, record models.RecordEncrypted) error
-	// Update updates Record for User by Record ID.
+	// Update updates Record for User by Record ID, previous version is saved in history.
+	// Non-zero rev is expected revision of Record, models.ErrConflict is returned for other revision.
 	Update(
 		ctx context.Context, uid models.UserID, t models.RecordType, id models.ID,
-		record models.RecordEncrypted,
+		record models.RecordEncrypted, rev int64,
 	) error
-	// Delete deletes Record.
-	Delete(ctx context.Context, uid models.UserID, t models.RecordType, id models.ID) error
+	// Delete moves Record to trash of User.
+	// Non-zero rev is expected revision of Record, models.ErrConflict is returned for other revision.
+	Delete(ctx context.Context, uid models.UserID, t models.RecordType, id models.ID, rev int64) error
+	// Trash returns deleted records of User with id, meta and keys only, last deleted first.
+	Trash(ctx context.Context, uid models.UserID) ([]models.TrashItem, error)
+	// RestoreTrash moves deleted Record back from trash of User.
+	RestoreTrash(ctx context.Context, uid models.UserID, t models.RecordType, id models.ID) error
+	// EmptyTrash permanently deletes all records in trash of User.
+	EmptyTrash(ctx context.Context, uid models.UserID) error
+	// PurgeTrash permanently deletes records of all users deleted before given time.
+	PurgeTrash(ctx context.Context, before time.Time) error
+	// Changes returns last changes of records of User with revision greater than since ordered by
+	// revision, deleted records are returned as tombstones.
+	Changes(ctx context.Context, uid models.UserID, since int64) ([]models.Change, error)
+	// LastRevision returns revision of last change of records of User, zero if User has no changes.
+	LastRevision(ctx context.Context, uid models.UserID) (int64, error)
+	// Versions returns previous versions of Record with id, meta and keys only, newest first.
+	Versions(ctx context.Context, uid models.UserID, t models.RecordType, id models.ID) ([]models.Version, error)
+	// Version returns previous version of Record by version number.
+	Version(
+		ctx context.Context, uid models.UserID, t models.RecordType, id models.ID, number int64,
+	) (models.Version, error)
+	// TrimVersions keeps only keep newest versions of Record.
+	TrimVersions(ctx context.Context, t models.RecordType, id models.ID, keep int) error
+	// PurgeVersions deletes versions of all Records created before given time.
+	PurgeVersions(ctx context.Context, before time.Time) error
 	// IsExist returns true if Record exists in Storage.
 	IsExist(ctx context.Context, user models.UserID, t models.RecordType, id models.ID) (bool, error)
 	// Users returns all registered users.
 	Users(ctx context.Context) ([]models.User, error)
-	// NewUser creates new user in Storage.
+	// NewUser creates new user with its first device in Storage.
 	NewUser(ctx context.Context, cn string) (models.UserID, error)
 	// IsUserExist return true if user registered in Storage.
 	IsUserExist(ctx context.Context, uid models.UserID) (bool, error)
-	// GetUserID returns User ID by CommonName.
+	// GetUserID returns User ID by CommonName of device, -1 if device not exists.
 	GetUserID(ctx context.Context, cn string) (models.UserID, error)
+	// FindUser returns User ID by user name, -1 if user not exists.
+	FindUser(ctx context.Context, name string) (models.UserID, error)
+	// Devices returns all devices of User.
+	Devices(ctx context.Context, uid models.UserID) ([]models.Device, error)
+	// DeleteDevice deletes device of User.
+	DeleteDevice(ctx context.Context, uid models.UserID, id models.DeviceID) error
+	// NewEnrollment saves new device waiting for approval.
+	NewEnrollment(ctx context.Context, enrollment models.Enrollment) error
+	// Enrollment returns enrollment by code.
+	Enrollment(ctx context.Context, code string) (models.Enrollment, error)
+	// Enrollments returns not approved enrollments of User.
+	Enrollments(ctx context.Context, uid models.UserID) ([]models.Enrollment, error)
+	// ApproveEnrollment saves wrapped vault key of enrolled device and adds device to User.
+	ApproveEnrollment(ctx context.Context, uid models.UserID, code string, vaultKey models.Encrypted) error
+	// CompleteEnrollment deletes approved enrollment, so certificate of device is issued once.
+	CompleteEnrollment(ctx context.Context, code string) error
+	// PurgeEnrollments deletes enrollments created before given time.
+	PurgeEnrollments(ctx context.Context, before time.Time) error
+	// AddCertificate saves issued client certificate.
+	AddCertificate(ctx context.Context, cert models.Certificate) error
+	// Certificates returns all issued client certificates with their revocation state.
+	Certificates(ctx context.Context) ([]models.Certificate, error)
+	// Revoke saves revoked certificate.
+	Revoke(ctx context.Context, revocation models.Revocation) error
+	// IsRevoked returns true if certificate with serial number is revoked.
+	IsRevoked(ctx context.Context, serial string) (bool, error)
+	// Revocations returns all revoked certificates.
+	Revocations(ctx context.Context) ([]models.Revocation, error)
+	// DisableUser disables or enables User.
+	DisableUser(ctx context.Context, uid models.UserID, disabled bool) error
+	// IsUserDisabled returns true if User is disabled.
+	IsUserDisabled(ctx context.Context, uid models.UserID) (bool, error)
+	// DeleteUser deletes User with all its records and devices.
+	DeleteUser(ctx context.Context, uid models.UserID) error
+	// Stats returns count and stored size of records by type for all users.
+	Stats(ctx context.Context) ([]models.UserStats, error)
+	// NewInvite saves new invite token.
+	NewInvite(ctx context.Context, invite models.Invite) error
+	// UseInvite marks not used and not expired invite as used by user cn.
+	UseInvite(ctx context.Context, token, cn string, now time.Time) error
+	// Invites returns all invites.
+	Invites(ctx context.Context) ([]models.Invite, error)
+	// NewRegistration saves registration of new user waiting for admin approval.
+	NewRegistration(ctx context.Context, registration models.Registration) error
+	// Registration returns registration by code.
+	Registration(ctx context.Context, code string) (models.Registration, error)
+	// Registrations returns registrations waiting for admin approval.
+	Registrations(ctx context.Context) ([]models.Registration, error)
+	// ApproveRegistration approves registration waiting for admin approval.
+	ApproveRegistration(ctx context.Context, code string) error
+	// DeleteRegistration deletes rejected or completed registration.
+	DeleteRegistration(ctx context.Context, code string) error
+	// CompleteRegistration spends approved registration, creates its user and saves certificate cert
+	// issued for user in one transaction. Returns ID of created user.
+	CompleteRegistration(ctx context.Context, code string, cert models.Certificate) (models.UserID, error)
+	// PurgeRegistrations deletes registrations created before given time.
+	PurgeRegistrations(ctx context.Context, before time.Time) error
+	// StartUpload begins or resumes upload of chunked binary data and returns count of received chunks.
+	StartUpload(ctx context.Context, uid models.UserID, upload models.Upload) (int64, error)
+	// UploadStatus returns started upload with count of received chunks.
+	UploadStatus(ctx context.Context, uid models.UserID, uploadID string) (models.Upload, error)
+	// UploadChunk saves chunk of upload.
+	UploadChunk(ctx context.Context, uid models.UserID, uploadID string, seq int64, chunk []byte) error
+	// CompleteUpload creates (or replaces data of) binary Record from upload and returns its ID.
+	// Replacing fails with models.ErrConflict if Record has other revision than rev (if not 0).
+	CompleteUpload(ctx context.Context, uid models.UserID, uploadID string, rev int64) (models.ID, error)
+	// BinChunks returns count of chunks of binary Record.
+	BinChunks(ctx context.Context, uid models.UserID, id models.ID) (int64, error)
+	// BinChunk returns chunk of binary Record by sequence number.
+	BinChunk(ctx context.Context, uid models.UserID, id models.ID, seq int64) ([]byte, error)
 }
 
-func createDatabase(ctx context.Context, dbFile string) error {
-	if _, err := os.Create(dbFile); err != nil {
-		return fmt.Errorf("could not create database: %w", err)
+// createDatabase creates schema of new storage, existing data is deleted.
+func createDatabase(ctx context.Context, store Storage) error {
+	if err := store.Reset(ctx); err != nil {
+		return fmt.Errorf("could not reset database: %w", err)
 	}
-	store, err := sqlite.New(dbFile)
-	if err != nil {
-		return fmt.Errorf("could not open database: %w", err)
-	}
-	defer func() {
-		_ = store.Close()
-	}()
-	if err = store.Init(ctx); err != nil {
+	if err := store.Init(ctx); err != nil {
 		return fmt.Errorf("could not initialize database: %w", err)
 	}
 	return nil
@@ -0,0 +1,141 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+	pb "github.com/sejo412/gophkeeper/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	errorUpload   = "error uploading binary data"
+	errorDownload = "error downloading binary data"
+)
+
+const maxUploadIDLength = 128
+
+// UploadBin receives chunks of binary data and saves them as binary Record.
+// Broken stream keeps received chunks, so upload can be resumed with same upload ID.
+func (s *GRPCPrivate) UploadBin(stream pb.Private_UploadBinServer) error {
+	ctx := stream.Context()
+	ctxUID, _ := ctx.Value(ctxUIDKey).(int)
+	uid := models.UserID(ctxUID)
+	req, err := stream.Recv()
+	if err != nil {
+		return err
+	}
+	header := req.GetHeader()
+	if header == nil {
+		return status.Error(codes.InvalidArgument, "upload header expected")
+	}
+	uploadID := header.GetUploadId()
+	if uploadID == "" || len(uploadID) > maxUploadIDLength {
+		return status.Error(codes.InvalidArgument, "invalid upload id")
+	}
+	seq, err := s.config.store.StartUpload(
+		ctx, uid, models.Upload{
+			ID:       uploadID,
+			RecordID: models.ID(header.GetRecordNumber()),
+			Meta:     header.GetMeta(),
+			UUID:     header.GetUuid(),
+			DataKey:  header.GetDataKey(),
+		},
+	)
+	if err != nil {
+		slog.Info(errorUpload, "error", err)
+		return status.Error(codes.InvalidArgument, errorUpload)
+	}
+	if header.GetFirstChunk() != seq {
+		return status.Errorf(codes.FailedPrecondition, "upload expects chunk %d", seq)
+	}
+	for {
+		req, err = stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			slog.Info(errorUpload, "error", err, "upload", uploadID, "chunks", seq)
+			return err
+		}
+		if req.GetHeader() != nil {
+			return status.Error(codes.InvalidArgument, "unexpected upload header")
+		}
+		if err = s.config.store.UploadChunk(ctx, uid, uploadID, seq, req.GetChunk()); err != nil {
+			slog.Info(errorUpload, "error", err)
+			return status.Error(codes.Internal, errorUpload)
+		}
+		seq++
+	}
+	id, err := s.config.store.CompleteUpload(ctx, uid, uploadID, header.GetExpectedRevision())
+	if errors.Is(err, models.ErrConflict) {
+		slog.Info(errorConflict, "error", err)
+		return status.Error(codes.Aborted, errorConflict)
+	} else if err != nil {
+		slog.Info(errorUpload, "error", err)
+		return status.Error(codes.Internal, errorUpload)
+	}
+	s.notify(ctx, uid)
+	return stream.SendAndClose(
+		&pb.UploadBinResponse{
+			RecordNumber: proto.Int64(int64(id)),
+			Chunks:       proto.Int64(seq),
+		},
+	)
+}
+
+// UploadBinStatus returns count of chunks received for upload, its record UUID and data key.
+func (s *GRPCPrivate) UploadBinStatus(ctx context.Context, in *pb.UploadBinStatusRequest) (
+	*pb.UploadBinStatusResponse, error,
+) {
+	ctxUID, _ := ctx.Value(ctxUIDKey).(int)
+	uid := models.UserID(ctxUID)
+	upload, err := s.config.store.UploadStatus(ctx, uid, in.GetUploadId())
+	if err != nil {
+		slog.Info(errorUpload, "error", err)
+		return nil, status.Error(codes.Internal, errorUpload)
+	}
+	return &pb.UploadBinStatusResponse{
+		Chunks:  proto.Int64(upload.Chunks),
+		Uuid:    proto.String(upload.UUID),
+		DataKey: upload.DataKey,
+	}, nil
+}
+
+// DownloadBin sends chunks of binary Record starting from requested chunk.
+func (s *GRPCPrivate) DownloadBin(in *pb.DownloadBinRequest, stream pb.Private_DownloadBinServer) error {
+	ctx := stream.Context()
+	ctxUID, _ := ctx.Value(ctxUIDKey).(int)
+	uid := models.UserID(ctxUID)
+	id := models.ID(in.GetRecordNumber())
+	chunks, err := s.config.store.BinChunks(ctx, uid, id)
+	if err != nil {
+		slog.Info(errorDownload, "error", err)
+		return status.Error(codes.NotFound, errorDownload)
+	}
+	if in.GetFirstChunk() < 0 || (in.GetFirstChunk() > 0 && in.GetFirstChunk() >= chunks) {
+		return status.Errorf(codes.OutOfRange, "record has %d chunks", chunks)
+	}
+	for seq := in.GetFirstChunk(); seq < chunks; seq++ {
+		chunk, err := s.config.store.BinChunk(ctx, uid, id, seq)
+		if err != nil {
+			slog.Info(errorDownload, "error", err)
+			return status.Error(codes.Internal, errorDownload)
+		}
+		if err = stream.Send(
+			&pb.DownloadBinResponse{
+				Seq:    proto.Int64(seq),
+				Chunk:  chunk,
+				Chunks: proto.Int64(chunks),
+			},
+		); err != nil {
+			return err
+		}
+	}
+	return nil
+}
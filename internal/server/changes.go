@@ -0,0 +1,31 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+	"github.com/sejo412/gophkeeper/internal/protoconv"
+	pb "github.com/sejo412/gophkeeper/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	errorChanges = "error listing changes"
+)
+
+// Changes returns last changes of records of User made after since_revision ordered by revision.
+func (s *GRPCPrivate) Changes(ctx context.Context, in *pb.ChangesRequest) (*pb.ChangesResponse, error) {
+	ctxUID, _ := ctx.Value(ctxUIDKey).(int)
+	changes, err := s.config.store.Changes(ctx, models.UserID(ctxUID), in.GetSinceRevision())
+	if err != nil {
+		slog.Error(errorChanges, "error", err)
+		return nil, status.Error(codes.Internal, errorChanges)
+	}
+	resp := &pb.ChangesResponse{Changes: make([]*pb.Change, 0, len(changes))}
+	for _, change := range changes {
+		resp.Changes = append(resp.Changes, protoconv.ChangeToProto(change))
+	}
+	return resp, nil
+}
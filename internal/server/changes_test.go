@@ -0,0 +1,114 @@
+package server
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+	"github.com/sejo412/gophkeeper/internal/protoconv"
+	"github.com/sejo412/gophkeeper/internal/storage/memory"
+	pb "github.com/sejo412/gophkeeper/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestGRPCPrivate_Changes(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	if err := store.Init(ctx); err != nil {
+		t.Fatal(err)
+	}
+	uid, err := store.NewUser(ctx, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, meta := range []string{"one", "two"} {
+		if err = store.Add(
+			ctx, uid, models.RecordText, models.RecordEncrypted{Text: models.TextEncrypted{Meta: []byte(meta)}},
+		); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = store.Delete(ctx, uid, models.RecordText, 1, 0); err != nil {
+		t.Fatal(err)
+	}
+	s := &GRPCPrivate{config: privateConfig{store: store}}
+	userCtx := context.WithValue(ctx, ctxUIDKey, int(uid))
+	otherCtx := context.WithValue(ctx, ctxUIDKey, 42)
+
+	resp, err := s.Changes(userCtx, &pb.ChangesRequest{SinceRevision: proto.Int64(0)})
+	if err != nil || len(resp.GetChanges()) != 2 {
+		t.Fatalf("Changes() = %v, %v, want 2 changes", resp, err)
+	}
+	created, deleted := resp.GetChanges()[0], resp.GetChanges()[1]
+	if created.GetOp() != pb.ChangeOp_CHANGE_CREATE || created.GetRecordNumber() != 2 ||
+		string(created.GetItem().GetText().GetMeta()) != "two" {
+		t.Errorf("Changes() first = %v, want created text 2", created)
+	}
+	if deleted.GetOp() != pb.ChangeOp_CHANGE_DELETE || deleted.GetRecordNumber() != 1 || deleted.GetItem() != nil {
+		t.Errorf("Changes() second = %v, want tombstone of text 1", deleted)
+	}
+	if deleted.GetRevision() <= created.GetRevision() {
+		t.Errorf("Changes() revisions = %d, %d, want growing", created.GetRevision(), deleted.GetRevision())
+	}
+
+	since := &pb.ChangesRequest{SinceRevision: proto.Int64(deleted.GetRevision())}
+	if resp, err = s.Changes(userCtx, since); err != nil || len(resp.GetChanges()) != 0 {
+		t.Errorf("Changes() since last revision = %v, %v, want empty", resp, err)
+	}
+	if resp, _ = s.Changes(otherCtx, &pb.ChangesRequest{}); len(resp.GetChanges()) != 0 {
+		t.Errorf("Changes() of other user = %v, want empty", resp)
+	}
+}
+
+func TestGRPCPrivate_Conflict(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	if err := store.Init(ctx); err != nil {
+		t.Fatal(err)
+	}
+	uid, err := store.NewUser(ctx, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := models.RecordEncrypted{Text: models.TextEncrypted{Text: []byte("text"), Meta: []byte("meta")}}
+	if err = store.Add(ctx, uid, models.RecordText, text); err != nil {
+		t.Fatal(err)
+	}
+	s := &GRPCPrivate{config: privateConfig{store: store}}
+	userCtx := context.WithValue(ctx, ctxUIDKey, int(uid))
+	read, err := s.Read(userCtx, &pb.GetRecordRequest{Type: pb.RecordType_TEXT.Enum(), RecordNumber: proto.Int64(1)})
+	if err != nil || read.GetItem().GetText().GetRevision() == 0 {
+		t.Fatalf("Read() = %v, %v, want record with revision", read, err)
+	}
+	rev := read.GetItem().GetText().GetRevision()
+	update := func(expected int64) error {
+		_, er := s.Update(
+			userCtx, &pb.UpdateRecordRequest{
+				Type: pb.RecordType_TEXT.Enum(), RecordNumber: proto.Int64(1),
+				Item: protoconv.RecordToProto(models.RecordText, text), ExpectedRevision: proto.Int64(expected),
+			},
+		)
+		return er
+	}
+	if err = update(rev); err != nil {
+		t.Fatalf("Update() with current revision error = %v", err)
+	}
+	if err = update(rev); status.Code(err) != codes.Aborted {
+		t.Errorf("Update() with stale revision error = %v, want %v", err, codes.Aborted)
+	}
+	del := &pb.DeleteRecordRequest{
+		Type: pb.RecordType_TEXT.Enum(), RecordNumber: proto.Int64(1), ExpectedRevision: proto.Int64(rev),
+	}
+	if _, err = s.Delete(userCtx, del); status.Code(err) != codes.Aborted {
+		t.Errorf("Delete() with stale revision error = %v, want %v", err, codes.Aborted)
+	}
+	if err = update(0); err != nil {
+		t.Errorf("Update() without revision error = %v", err)
+	}
+	del.ExpectedRevision = nil
+	if _, err = s.Delete(userCtx, del); err != nil {
+		t.Errorf("Delete() without revision error = %v", err)
+	}
+}
@@ -3,6 +3,10 @@ package server
 import (
 	"os/user"
 	"path/filepath"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/constants"
+	"github.com/sejo412/gophkeeper/internal/models"
 )
 
 // Config main settings for Server.
@@ -17,16 +21,43 @@ type Config struct {
 	DNSNames []string
 	// Storage used store.
 	Storage Storage
+	// StorageDSN is a DSN of PostgreSQL storage (postgres://...), SQLite database in CacheDir if empty.
+	StorageDSN string
+	// LegacyJSON fills deprecated JSON encoded records in responses for old clients.
+	LegacyJSON bool
+	// PublicTLS serves public server over server-only TLS.
+	PublicTLS bool
+	// Registration is a mode of registration of new users, models.RegistrationOpen by default.
+	Registration models.RegistrationMode
+	// Force allows Init to overwrite existing database and certificates.
+	Force bool
+	// Ephemeral starts Server with in-memory storage and new certificates, StorageDSN is ignored.
+	Ephemeral bool
+	// VersionsKeep is a count of kept previous versions of every record, 0 keeps all.
+	VersionsKeep int
+	// VersionsMaxAge is a time while previous versions of records are kept, 0 keeps versions of any age.
+	VersionsMaxAge time.Duration
+	// TrashRetention is a time while deleted records are kept in trash, 0 keeps them until trash is emptied.
+	TrashRetention time.Duration
 }
 
 // NewConfig constructs new Config object.
 func NewConfig() *Config {
 	return &Config{
-		PublicPort:  -1,
-		PrivatePort: -1,
-		CacheDir:    "",
-		Storage:     nil,
-		DNSNames:    nil,
+		PublicPort:     -1,
+		PrivatePort:    -1,
+		CacheDir:       "",
+		Storage:        nil,
+		StorageDSN:     "",
+		DNSNames:       nil,
+		LegacyJSON:     false,
+		PublicTLS:      false,
+		Registration:   models.RegistrationOpen,
+		Force:          false,
+		Ephemeral:      false,
+		VersionsKeep:   constants.VersionsKeep,
+		VersionsMaxAge: constants.VersionsMaxAge,
+		TrashRetention: constants.TrashRetention,
 	}
 }
 
@@ -37,7 +68,18 @@ func NewConfigWithOptions(opts Config) *Config {
 	c.PrivatePort = opts.PrivatePort
 	c.CacheDir = opts.CacheDir
 	c.Storage = opts.Storage
+	c.StorageDSN = opts.StorageDSN
 	c.DNSNames = opts.DNSNames
+	c.LegacyJSON = opts.LegacyJSON
+	c.PublicTLS = opts.PublicTLS
+	c.Force = opts.Force
+	c.Ephemeral = opts.Ephemeral
+	c.VersionsKeep = opts.VersionsKeep
+	c.VersionsMaxAge = opts.VersionsMaxAge
+	c.TrashRetention = opts.TrashRetention
+	if opts.Registration != "" {
+		c.Registration = opts.Registration
+	}
 	return c
 }
 
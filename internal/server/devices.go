@@ -0,0 +1,246 @@
+package server
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/constants"
+	"github.com/sejo412/gophkeeper/internal/models"
+	"github.com/sejo412/gophkeeper/pkg/certs"
+	pb "github.com/sejo412/gophkeeper/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const (
+	errorEnroll  = "error enrolling device"
+	errorDevices = "error listing devices"
+	errorApprove = "error approving device"
+	errorRemove  = "error removing device"
+)
+
+// enrollmentCodeSize is a count of random bytes in enrollment code.
+const enrollmentCodeSize = 5
+
+// Enroll saves certificate request of new device of existing user. Device gets certificate
+// after approval by another device of user, see EnrollStatus.
+func (sp *GRPCPublic) Enroll(ctx context.Context, in *pb.EnrollRequest) (*pb.EnrollResponse, error) {
+	certRequest, err := certs.BinaryToRequest(in.GetCertRequest())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if _, err = requestPublicKey(certRequest); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	uid, err := sp.config.store.FindUser(ctx, in.GetUser())
+	if err != nil {
+		slog.Error(errorEnroll, "error", err)
+		return nil, status.Error(codes.Internal, errorInternal)
+	}
+	if uid < 0 {
+		return nil, status.Errorf(codes.NotFound, "user %q not found", in.GetUser())
+	}
+	if err = sp.config.store.PurgeEnrollments(ctx, time.Now().Add(-constants.EnrollmentTTL)); err != nil {
+		slog.Error(errorEnroll, "error", err)
+		return nil, status.Error(codes.Internal, errorInternal)
+	}
+	code, err := newEnrollmentCode()
+	if err != nil {
+		slog.Error(errorEnroll, "error", err)
+		return nil, status.Error(codes.Internal, errorInternal)
+	}
+	if err = sp.config.store.NewEnrollment(
+		ctx, models.Enrollment{
+			Code:        code,
+			UserID:      uid,
+			Cn:          certRequest.CommonName,
+			CertRequest: in.GetCertRequest(),
+			Created:     time.Now(),
+		},
+	); err != nil {
+		slog.Info(errorEnroll, "error", err)
+		return nil, status.Error(codes.AlreadyExists, err.Error())
+	}
+	return &pb.EnrollResponse{Code: proto.String(code)}, nil
+}
+
+// EnrollStatus returns certificate and wrapped vault key of approved device once, enrollment
+// is not found afterwards.
+func (sp *GRPCPublic) EnrollStatus(ctx context.Context, in *pb.EnrollStatusRequest) (*pb.EnrollStatusResponse, error) {
+	enrollment, err := sp.config.store.Enrollment(ctx, in.GetCode())
+	if err != nil || enrollmentExpired(enrollment) {
+		return nil, status.Error(codes.NotFound, "enrollment not found")
+	}
+	if !enrollment.Approved {
+		return &pb.EnrollStatusResponse{Approved: proto.Bool(false)}, nil
+	}
+	certRequest, err := certs.BinaryToRequest(enrollment.CertRequest)
+	if err != nil {
+		slog.Error(errorEnroll, "error", err)
+		return nil, status.Error(codes.Internal, errorInternal)
+	}
+	// enrollment is spent before certificate is issued, so concurrent requests get one certificate
+	if err = sp.config.store.CompleteEnrollment(ctx, enrollment.Code); err != nil {
+		slog.Info(errorEnroll, "error", err)
+		return nil, status.Error(codes.NotFound, "enrollment not found")
+	}
+	cert, err := issueCertificate(ctx, sp.config.store, sp.config.signer, enrollment.UserID, certRequest)
+	if err != nil {
+		slog.Error("generating certificate", "error", err)
+		return nil, status.Error(codes.Internal, errorInternal)
+	}
+	return &pb.EnrollStatusResponse{
+		Approved:          proto.Bool(true),
+		CaCertificate:     sp.config.signer.CACert,
+		ClientCertificate: cert,
+		VaultKey:          enrollment.VaultKey,
+	}, nil
+}
+
+// ListDevices returns devices of User and devices waiting for approval.
+func (s *GRPCPrivate) ListDevices(ctx context.Context, _ *emptypb.Empty) (*pb.ListDevicesResponse, error) {
+	ctxUID, _ := ctx.Value(ctxUIDKey).(int)
+	uid := models.UserID(ctxUID)
+	cn, _ := ctx.Value(ctxCNKey).(string)
+	devices, err := s.config.store.Devices(ctx, uid)
+	if err != nil {
+		slog.Info(errorDevices, "error", err)
+		return nil, status.Error(codes.Internal, errorDevices)
+	}
+	enrollments, err := s.config.store.Enrollments(ctx, uid)
+	if err != nil {
+		slog.Info(errorDevices, "error", err)
+		return nil, status.Error(codes.Internal, errorDevices)
+	}
+	resp := &pb.ListDevicesResponse{
+		Devices: make([]*pb.Device, 0, len(devices)),
+		Pending: make([]*pb.PendingDevice, 0, len(enrollments)),
+	}
+	for _, d := range devices {
+		resp.Devices = append(
+			resp.Devices, &pb.Device{
+				Id:      proto.Int64(int64(d.ID)),
+				Name:    proto.String(d.Cn),
+				Created: proto.Int64(d.Created.Unix()),
+				Current: proto.Bool(d.Cn == cn),
+			},
+		)
+	}
+	for _, e := range enrollments {
+		if enrollmentExpired(e) {
+			continue
+		}
+		certRequest, er := certs.BinaryToRequest(e.CertRequest)
+		if er != nil {
+			continue
+		}
+		publicKey, er := requestPublicKey(certRequest)
+		if er != nil {
+			continue
+		}
+		resp.Pending = append(
+			resp.Pending, &pb.PendingDevice{
+				Code:      proto.String(e.Code),
+				Name:      proto.String(e.Cn),
+				PublicKey: publicKey,
+				Created:   proto.Int64(e.Created.Unix()),
+			},
+		)
+	}
+	return resp, nil
+}
+
+// ApproveDevice approves enrollment of new device with vault key wrapped for it.
+func (s *GRPCPrivate) ApproveDevice(ctx context.Context, in *pb.ApproveDeviceRequest) (*emptypb.Empty, error) {
+	ctxUID, _ := ctx.Value(ctxUIDKey).(int)
+	uid := models.UserID(ctxUID)
+	if len(in.GetVaultKey()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "vault key required")
+	}
+	enrollment, err := s.config.store.Enrollment(ctx, in.GetCode())
+	if err != nil || enrollment.UserID != uid || enrollment.Approved || enrollmentExpired(enrollment) {
+		return nil, status.Error(codes.NotFound, "enrollment not found")
+	}
+	if err = s.config.store.ApproveEnrollment(ctx, uid, in.GetCode(), in.GetVaultKey()); err != nil {
+		slog.Info(errorApprove, "error", err)
+		return nil, status.Error(codes.FailedPrecondition, errorApprove)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// RemoveDevice removes device of User and revokes its certificates.
+// Device can't remove itself.
+func (s *GRPCPrivate) RemoveDevice(ctx context.Context, in *pb.RemoveDeviceRequest) (*emptypb.Empty, error) {
+	ctxUID, _ := ctx.Value(ctxUIDKey).(int)
+	uid := models.UserID(ctxUID)
+	cn, _ := ctx.Value(ctxCNKey).(string)
+	devices, err := s.config.store.Devices(ctx, uid)
+	if err != nil {
+		slog.Info(errorRemove, "error", err)
+		return nil, status.Error(codes.Internal, errorRemove)
+	}
+	removed := ""
+	for _, d := range devices {
+		if int64(d.ID) == in.GetId() {
+			removed = d.Cn
+		}
+	}
+	if removed == "" {
+		return nil, status.Error(codes.NotFound, errorRemove)
+	}
+	if removed == cn {
+		return nil, status.Error(codes.FailedPrecondition, "current device can't be removed")
+	}
+	if err = s.config.store.DeleteDevice(ctx, uid, models.DeviceID(in.GetId())); err != nil {
+		slog.Info(errorRemove, "error", err)
+		return nil, status.Error(codes.NotFound, errorRemove)
+	}
+	if _, err = revokeCertificates(ctx, s.config.store, "", removed, models.RevocationCessationOfOperation); err != nil {
+		slog.Error(errorRemove, "error", err)
+		return nil, status.Error(codes.Internal, errorRemove)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// requestPublicKey verifies signed certificate request and returns its PKIX public key.
+func requestPublicKey(request certs.CertRequest) ([]byte, error) {
+	if request.CommonName == "" {
+		return nil, errors.New("empty common name")
+	}
+	csr, err := x509.ParseCertificateRequest(request.Signed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse certificate request: %w", err)
+	}
+	if err = csr.CheckSignature(); err != nil {
+		return nil, fmt.Errorf("invalid certificate request signature: %w", err)
+	}
+	if csr.Subject.CommonName != request.CommonName {
+		return nil, errors.New("common name does not match certificate request")
+	}
+	return x509.MarshalPKIXPublicKey(csr.PublicKey)
+}
+
+func newEnrollmentCode() (string, error) {
+	return randomCode(enrollmentCodeSize)
+}
+
+// randomCode returns base32 encoded random bytes.
+func randomCode(size int) (string, error) {
+	buf := make([]byte, size)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate code: %w", err)
+	}
+	return base32.StdEncoding.EncodeToString(buf), nil
+}
+
+func enrollmentExpired(e models.Enrollment) bool {
+	return time.Since(e.Created) > constants.EnrollmentTTL
+}
@@ -0,0 +1,74 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+
+	"github.com/sejo412/gophkeeper/internal/constants"
+	"github.com/sejo412/gophkeeper/internal/storage/memory"
+)
+
+// initEphemeral creates in-memory storage and certificates of ephemeral server. Without cache dir
+// certificates are created in temporary directory removed by returned cleanup function, so nothing
+// is kept on disk after server stops. Given cache dir must not be initialized and is kept, it is
+// marked as ephemeral until cleanup, so admin commands don't open storage of their own in it.
+func (s *Server) initEphemeral() (Storage, func(), error) {
+	ctx := context.Background()
+	cleanup := func() {}
+	if s.config.CacheDir == "" {
+		dir, err := os.MkdirTemp(os.TempDir(), "gophkeeper-ephemeral")
+		if err != nil {
+			return nil, cleanup, fmt.Errorf("could not create temporary cache dir: %w", err)
+		}
+		s.config.CacheDir = dir
+		cleanup = func() {
+			_ = os.RemoveAll(dir)
+		}
+	} else {
+		if err := os.MkdirAll(s.config.CacheDir, 0755); err != nil {
+			return nil, cleanup, fmt.Errorf("could not create cache dir: %w", err)
+		}
+		for _, name := range []string{constants.CertCAPublicFilename, constants.CertCAPrivateFilename} {
+			if err := checkNotExist(filepath.Join(s.config.CacheDir, name)); err != nil {
+				return nil, cleanup, err
+			}
+		}
+	}
+	store := memory.New()
+	if err := store.Init(ctx); err != nil {
+		return nil, cleanup, fmt.Errorf("could not initialize storage: %w", err)
+	}
+	if err := s.createCertificates(ctx); err != nil {
+		return nil, cleanup, err
+	}
+	marker := filepath.Join(s.config.CacheDir, constants.EphemeralFilename)
+	if err := os.WriteFile(marker, nil, 0600); err != nil {
+		return nil, cleanup, fmt.Errorf("could not mark cache dir as ephemeral: %w", err)
+	}
+	removeDir := cleanup
+	cleanup = func() {
+		_ = os.Remove(marker)
+		removeDir()
+	}
+	fingerprint, err := s.CAFingerprint()
+	if err != nil {
+		return nil, cleanup, err
+	}
+	slog.Warn(
+		"ephemeral server, all data is lost on stop",
+		"dir", s.config.CacheDir, "ca_fingerprint", fingerprint,
+	)
+	return store, cleanup, nil
+}
+
+// checkNotEphemeral returns ErrEphemeral if cache dir belongs to ephemeral server: its storage exists
+// only in memory of server process and can't be opened by admin command.
+func (s *Server) checkNotEphemeral() error {
+	if _, err := os.Stat(filepath.Join(s.config.CacheDir, constants.EphemeralFilename)); err == nil {
+		return ErrEphemeral
+	}
+	return nil
+}
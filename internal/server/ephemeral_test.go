@@ -0,0 +1,62 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/constants"
+)
+
+func TestServer_initEphemeral(t *testing.T) {
+	tests := []struct {
+		name     string
+		cacheDir string
+		keepDir  bool
+		wantErr  error
+	}{
+		{name: "temporary dir", cacheDir: "", keepDir: false},
+		{name: "given dir", cacheDir: t.TempDir(), keepDir: true},
+		{name: "initialized dir", cacheDir: testCacheDir, wantErr: ErrInitialized},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				s := NewServer(Config{CacheDir: tt.cacheDir, StorageDSN: "postgres://unused"})
+				store, cleanup, err := s.initEphemeral()
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("initEphemeral() error = %v, want %v", err, tt.wantErr)
+				}
+				if err == nil {
+					// admin commands must not open storage of their own instead of in-memory one
+					if _, er := s.NewInvite(context.Background(), time.Hour); !errors.Is(er, ErrEphemeral) {
+						t.Errorf("NewInvite() of ephemeral server error = %v, want %v", er, ErrEphemeral)
+					}
+					if _, er := s.Admin(); !errors.Is(er, ErrEphemeral) {
+						t.Errorf("Admin() of stopped ephemeral server error = %v, want %v", er, ErrEphemeral)
+					}
+				}
+				cleanup()
+				if err != nil {
+					return
+				}
+				if er := s.checkNotEphemeral(); er != nil {
+					t.Errorf("checkNotEphemeral() after cleanup error = %v, want nil", er)
+				}
+				if current, latest, er := store.SchemaVersion(context.Background()); er != nil || current != latest {
+					t.Errorf("SchemaVersion() = %d, %d, %v, want latest", current, latest, er)
+				}
+				_, err = os.Stat(filepath.Join(s.config.CacheDir, constants.CertServerPublicFilename))
+				if tt.keepDir && err != nil {
+					t.Errorf("server certificate in given dir error = %v, want created", err)
+				}
+				if !tt.keepDir && !errors.Is(err, os.ErrNotExist) {
+					t.Errorf("temporary dir after cleanup error = %v, want removed", err)
+				}
+			},
+		)
+	}
+}
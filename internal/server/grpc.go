@@ -2,13 +2,16 @@ package server
 
 import (
 	"context"
+	"crypto/x509"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log/slog"
 	"path/filepath"
 
 	"github.com/sejo412/gophkeeper/internal/constants"
 	"github.com/sejo412/gophkeeper/internal/models"
+	"github.com/sejo412/gophkeeper/internal/protoconv"
 	"github.com/sejo412/gophkeeper/pkg/certs"
 	pb "github.com/sejo412/gophkeeper/proto"
 	"google.golang.org/grpc"
@@ -30,6 +33,7 @@ const (
 	errorAdd       = "error adding record"
 	errorGet       = "error getting record"
 	errorUpdate    = "error updating record"
+	errorConflict  = "record was changed since expected revision"
 )
 
 type ctxKey string
@@ -38,15 +42,19 @@ const ctxCNKey ctxKey = "CommonName"
 const ctxUIDKey ctxKey = "UserID"
 
 type publicConfig struct {
-	port   int
-	caCert *certs.Cert // Deprecated: use signer
-	signer certs.CASigner
-	store  Storage
+	port         int
+	caCert       *certs.Cert // Deprecated: use signer
+	signer       certs.CASigner
+	store        Storage
+	registration models.RegistrationMode
 }
 
 type privateConfig struct {
-	port  int
-	store Storage
+	port         int
+	signer       certs.CASigner
+	store        Storage
+	legacyJSON   bool
+	versionsKeep int
 }
 
 // NewGRPCPublic constructs new GRPCPublic object for Server.
@@ -60,20 +68,32 @@ func NewGRPCPublic(config Config) (*GRPCPublic, error) {
 	if err != nil {
 		return nil, fmt.Errorf("error create public config: %w", err)
 	}
+	cfg.registration = config.Registration
 	return &GRPCPublic{
 		config: cfg,
 	}, nil
 }
 
 // NewGRPCPrivate constructs new GRPCPrivate object for Server.
-func NewGRPCPrivate(config Config) *GRPCPrivate {
+func NewGRPCPrivate(config Config) (*GRPCPrivate, error) {
 	cfg := newPrivateConfig(
 		config.PrivatePort,
 		&config.Storage,
 	)
-	return &GRPCPrivate{
-		config: cfg,
+	cfg.legacyJSON = config.LegacyJSON
+	cfg.versionsKeep = config.VersionsKeep
+	signer, err := certs.GetCASigner(
+		filepath.Join(config.CacheDir, constants.CertCAPublicFilename),
+		filepath.Join(config.CacheDir, constants.CertCAPrivateFilename),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("error loading CA signer: %w", err)
 	}
+	cfg.signer = signer
+	return &GRPCPrivate{
+		config:   cfg,
+		watchers: newWatchHub(),
+	}, nil
 }
 
 func newPublicConfig(port int, caCertFile, caKeyFile string, store *Storage) (publicConfig, error) {
@@ -104,7 +124,8 @@ type GRPCPublic struct {
 // GRPCPrivate implements proto private server.
 type GRPCPrivate struct {
 	pb.UnimplementedPrivateServer
-	config privateConfig
+	config   privateConfig
+	watchers *watchHub
 }
 
 // ListAll returns ID and Meta for all records by User ID.
@@ -119,12 +140,7 @@ func (s *GRPCPrivate) ListAll(ctx context.Context, in *emptypb.Empty) (
 		slog.Info(errorList, "error", err)
 		return nil, status.Error(codes.Internal, errorList)
 	}
-	data, err := json.Marshal(r)
-	if err != nil {
-		slog.Info(errorMarshal, "error", err)
-		return nil, status.Error(codes.Internal, errorMarshal)
-	}
-	return &pb.ListResponse{Records: data}, nil
+	return s.listResponse(r)
 }
 
 // List returns ID and Meta for all records by User ID and models.RecordType.
@@ -136,27 +152,24 @@ func (s *GRPCPrivate) List(ctx context.Context, in *pb.ListRequest) (*pb.ListRes
 		slog.Info(errorList, "error", err)
 		return nil, status.Error(codes.Internal, errorList)
 	}
-	data, err := json.Marshal(r)
-	if err != nil {
-		slog.Info(errorMarshal, "error", err)
-		return nil, status.Error(codes.Internal, errorMarshal)
-	}
-	return &pb.ListResponse{Records: data}, nil
+	return s.listResponse(r)
 }
 
 // Create creates new models.RecordEncrypted for User by models.RecordType.
 func (s *GRPCPrivate) Create(ctx context.Context, in *pb.AddRecordRequest) (*emptypb.Empty, error) {
 	ctxUID, _ := ctx.Value(ctxUIDKey).(int)
 	uid := models.UserID(ctxUID)
-	var record models.RecordEncrypted
-	if err := json.Unmarshal(in.GetRecord(), &record); err != nil {
+	t := protoRecordTypeToModel(in.GetType())
+	record, err := requestRecord(t, in.GetItem(), in.GetRecord())
+	if err != nil {
 		slog.Info(errorUnmarshal, "error", err)
 		return nil, status.Errorf(codes.InvalidArgument, errorUnmarshal)
 	}
-	if err := s.config.store.Add(ctx, uid, protoRecordTypeToModel(in.GetType()), record); err != nil {
+	if err = s.config.store.Add(ctx, uid, t, record); err != nil {
 		slog.Info(errorAdd, "error", err)
 		return nil, status.Errorf(codes.InvalidArgument, errorAdd)
 	}
+	s.notify(ctx, uid)
 	return &emptypb.Empty{}, nil
 }
 
@@ -164,57 +177,81 @@ func (s *GRPCPrivate) Create(ctx context.Context, in *pb.AddRecordRequest) (*emp
 func (s *GRPCPrivate) Read(ctx context.Context, in *pb.GetRecordRequest) (*pb.GetRecordResponse, error) {
 	ctxUID, _ := ctx.Value(ctxUIDKey).(int)
 	uid := models.UserID(ctxUID)
-	r, err := s.config.store.Get(ctx, uid, protoRecordTypeToModel(in.GetType()), models.ID(in.GetRecordNumber()))
+	t := protoRecordTypeToModel(in.GetType())
+	r, err := s.config.store.Get(ctx, uid, t, models.ID(in.GetRecordNumber()))
 	if err != nil {
 		slog.Error(errorGet, "error", err)
 		return nil, status.Error(codes.Internal, errorGet)
 	}
-	data, err := json.Marshal(r)
-	if err != nil {
-		slog.Error(errorMarshal, "error", err)
-		return nil, status.Error(codes.Internal, errorMarshal)
+	resp := &pb.GetRecordResponse{
+		Type:  in.Type,
+		Item:  protoconv.RecordToProto(t, r),
+		Error: nil,
 	}
-	return &pb.GetRecordResponse{
-		Type:   in.Type,
-		Record: data,
-		Error:  nil,
-	}, nil
+	if s.config.legacyJSON {
+		data, err := json.Marshal(r)
+		if err != nil {
+			slog.Error(errorMarshal, "error", err)
+			return nil, status.Error(codes.Internal, errorMarshal)
+		}
+		resp.Record = data
+	}
+	return resp, nil
 }
 
-// Update updates models.Record for User by models.RecordType and models.ID.
+// Update updates models.Record for User by models.RecordType and models.ID. Update of record
+// changed since expected revision fails with codes.Aborted.
 func (s *GRPCPrivate) Update(ctx context.Context, in *pb.UpdateRecordRequest) (*emptypb.Empty, error) {
 	ctxUID, _ := ctx.Value(ctxUIDKey).(int)
 	uid := models.UserID(ctxUID)
-	var record models.RecordEncrypted
-	if err := json.Unmarshal(in.GetRecord(), &record); err != nil {
+	t := protoRecordTypeToModel(in.GetType())
+	record, err := requestRecord(t, in.GetItem(), in.GetRecord())
+	if err != nil {
 		slog.Info(errorUnmarshal, "error", err)
 		return nil, status.Errorf(codes.InvalidArgument, errorUnmarshal)
 	}
-	if err := s.config.store.Update(
-		ctx, uid, protoRecordTypeToModel(in.GetType()), models.ID(in.GetRecordNumber()),
-		record,
-	); err != nil {
+	if err = s.config.store.Update(
+		ctx, uid, t, models.ID(in.GetRecordNumber()), record, in.GetExpectedRevision(),
+	); errors.Is(err, models.ErrConflict) {
+		slog.Info(errorConflict, "error", err)
+		return nil, status.Error(codes.Aborted, errorConflict)
+	} else if err != nil {
 		slog.Info(errorUpdate, "error", err)
 		return nil, status.Errorf(codes.InvalidArgument, errorUpdate)
 	}
+	s.notify(ctx, uid)
+	// versions out of age are purged in background, see purgeVersions
+	if s.config.versionsKeep > 0 {
+		if err = s.config.store.TrimVersions(
+			ctx, t, models.ID(in.GetRecordNumber()), s.config.versionsKeep,
+		); err != nil {
+			slog.Error(errorPurge, "error", err)
+		}
+	}
 	return &emptypb.Empty{}, nil
 }
 
-// Delete deletes models.Record for User by models.RecordType and models.ID.
+// Delete deletes models.Record for User by models.RecordType and models.ID. Deletion of record
+// changed since expected revision fails with codes.Aborted.
 func (s *GRPCPrivate) Delete(ctx context.Context, in *pb.DeleteRecordRequest) (*emptypb.Empty, error) {
 	ctxUID, _ := ctx.Value(ctxUIDKey).(int)
 	uid := models.UserID(ctxUID)
 	if err := s.config.store.Delete(
 		ctx, uid, protoRecordTypeToModel(in.GetType()),
-		models.ID(in.GetRecordNumber()),
-	); err != nil {
+		models.ID(in.GetRecordNumber()), in.GetExpectedRevision(),
+	); errors.Is(err, models.ErrConflict) {
+		slog.Info(errorConflict, "error", err)
+		return nil, status.Error(codes.Aborted, errorConflict)
+	} else if err != nil {
 		slog.Info(errorDelete, "error", err)
 		return nil, status.Errorf(codes.InvalidArgument, errorDelete)
 	}
+	s.notify(ctx, uid)
 	return &emptypb.Empty{}, nil
 }
 
-// Register creates new models.User by certificate request.
+// Register creates new models.User by certificate request. Depending on registration mode
+// request must have invite token or waits for admin approval, see RegisterStatus.
 func (sp *GRPCPublic) Register(ctx context.Context, in *pb.RegisterRequest) (*pb.RegisterResponse, error) {
 	msg := new(string)
 	certRequest, err := certs.BinaryToRequest(in.GetCertRequest())
@@ -223,12 +260,21 @@ func (sp *GRPCPublic) Register(ctx context.Context, in *pb.RegisterRequest) (*pb
 		slog.Error("parsing certificate request", "error", err)
 		return &pb.RegisterResponse{}, status.Error(codes.InvalidArgument, err.Error())
 	}
-	if _, err = sp.config.store.NewUser(ctx, certRequest.CommonName); err != nil {
+	switch sp.config.registration {
+	case models.RegistrationInvite:
+		if err = sp.useInvite(ctx, in.GetInvite(), certRequest.CommonName); err != nil {
+			return &pb.RegisterResponse{}, err
+		}
+	case models.RegistrationApproval:
+		return sp.newRegistration(ctx, in.GetCertRequest(), certRequest)
+	}
+	uid, err := sp.config.store.NewUser(ctx, certRequest.CommonName)
+	if err != nil {
 		*msg = err.Error()
 		slog.Error("creating new user", "error", err)
 		return &pb.RegisterResponse{}, status.Error(codes.InvalidArgument, err.Error())
 	}
-	cert, _, err := certs.GenRsaCert(certRequest, sp.config.signer)
+	cert, err := issueCertificate(ctx, sp.config.store, sp.config.signer, uid, certRequest)
 	if err != nil {
 		*msg = err.Error()
 		slog.Error("generating certificate", "error", err)
@@ -240,6 +286,39 @@ func (sp *GRPCPublic) Register(ctx context.Context, in *pb.RegisterRequest) (*pb
 	}, nil
 }
 
+// issueCertificate signs certificate request of User's device and saves issued certificate.
+func issueCertificate(
+	ctx context.Context, store Storage, signer certs.CASigner, uid models.UserID, request certs.CertRequest,
+) ([]byte, error) {
+	cert, issued, err := signCertificate(signer, request)
+	if err != nil {
+		return nil, err
+	}
+	issued.UserID = uid
+	if err = store.AddCertificate(ctx, issued); err != nil {
+		return nil, err
+	}
+	return cert, nil
+}
+
+// signCertificate signs certificate request without saving issued certificate.
+func signCertificate(signer certs.CASigner, request certs.CertRequest) ([]byte, models.Certificate, error) {
+	cert, _, err := certs.GenRsaCert(request, signer)
+	if err != nil {
+		return nil, models.Certificate{}, err
+	}
+	parsed, err := x509.ParseCertificate(cert)
+	if err != nil {
+		return nil, models.Certificate{}, fmt.Errorf("failed to parse issued certificate: %w", err)
+	}
+	return cert, models.Certificate{
+		Serial:   certs.SerialText(parsed.SerialNumber),
+		Cn:       parsed.Subject.CommonName,
+		Issued:   parsed.NotBefore,
+		NotAfter: parsed.NotAfter,
+	}, nil
+}
+
 func loggerInterceptor() logging.Logger {
 	return logging.LoggerFunc(
 		func(ctx context.Context, lvl logging.Level, msg string, keyvals ...any) {
@@ -262,6 +341,36 @@ func loggerInterceptor() logging.Logger {
 func (s *GRPCPrivate) authInterceptor(
 	ctx context.Context, req interface{}, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler,
 ) (interface{}, error) {
+	ctx, err := s.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func (s *GRPCPrivate) authStreamInterceptor(
+	srv interface{}, ss grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler,
+) error {
+	ctx, err := s.authenticate(ss.Context())
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authServerStream{ServerStream: ss, ctx: ctx})
+}
+
+// authServerStream overrides context of grpc.ServerStream with authenticated one.
+type authServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns authenticated context.
+func (a *authServerStream) Context() context.Context {
+	return a.ctx
+}
+
+// authenticate returns context with User ID and CommonName from verified client certificate.
+func (s *GRPCPrivate) authenticate(ctx context.Context) (context.Context, error) {
 	message := func(msg string, args ...any) string {
 		return "[auth] " + fmt.Sprintf(msg, args...)
 	}
@@ -275,7 +384,7 @@ func (s *GRPCPrivate) authInterceptor(
 	if !ok {
 		msg := message("client credentials not found")
 		slog.Info(msg)
-		return nil, status.Error(codes.Unauthenticated, message(msg))
+		return nil, status.Error(codes.Unauthenticated, msg)
 	}
 	if len(tlsInfo.State.VerifiedChains) == 0 || len(tlsInfo.State.VerifiedChains[0]) == 0 {
 		msg := message("client verified certificate not found")
@@ -290,25 +399,46 @@ func (s *GRPCPrivate) authInterceptor(
 		slog.Info(msg)
 		return nil, status.Error(codes.Unauthenticated, msg)
 	}
+	revoked, err := s.config.store.IsRevoked(ctx, certs.SerialText(cert.SerialNumber))
+	if err != nil || revoked {
+		msg := message("certificate %s of %q revoked", certs.SerialText(cert.SerialNumber), commonName)
+		slog.Info(msg, "error", err)
+		return nil, status.Error(codes.Unauthenticated, msg)
+	}
 	uid, err := s.config.store.GetUserID(ctx, commonName)
-	if err != nil {
+	if err != nil || uid < 0 {
 		msg := message("user id for %q not found", commonName)
 		slog.Info(msg)
 		return nil, status.Error(codes.Unauthenticated, msg)
 	}
+	disabled, err := s.config.store.IsUserDisabled(ctx, uid)
+	if err != nil || disabled {
+		msg := message("user of %q disabled", commonName)
+		slog.Info(msg, "error", err)
+		return nil, status.Error(codes.PermissionDenied, msg)
+	}
 	ctx = context.WithValue(ctx, ctxUIDKey, int(uid))
 	ctx = context.WithValue(ctx, ctxCNKey, commonName)
-	return handler(ctx, req)
+	return ctx, nil
 }
 
-func grpcPublicServerOptions(_ *GRPCPublic) []grpc.ServerOption {
+func grpcPublicServerOptions(_ *GRPCPublic, creds credentials.TransportCredentials) []grpc.ServerOption {
 	opts := make([]grpc.ServerOption, 0)
+	if creds != nil {
+		opts = append(opts, grpc.Creds(creds))
+	}
 	unaryInterceptors := make([]grpc.UnaryServerInterceptor, 0)
 	unaryInterceptors = append(unaryInterceptors, logging.UnaryServerInterceptor(loggerInterceptor()))
 	opts = append(opts, grpc.ChainUnaryInterceptor(unaryInterceptors...))
 	return opts
 }
 
+func grpcAdminServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.ChainUnaryInterceptor(logging.UnaryServerInterceptor(loggerInterceptor())),
+	}
+}
+
 func registerGRPCPublicServer(grpc *grpc.Server, server *GRPCPublic) {
 	pb.RegisterPublicServer(grpc, server)
 }
@@ -318,8 +448,12 @@ func grpcPrivateServerOptions(server *GRPCPrivate, creds credentials.TransportCr
 	unaryInterceptors := make([]grpc.UnaryServerInterceptor, 0)
 	unaryInterceptors = append(unaryInterceptors, server.authInterceptor)
 	unaryInterceptors = append(unaryInterceptors, logging.UnaryServerInterceptor(loggerInterceptor()))
+	streamInterceptors := make([]grpc.StreamServerInterceptor, 0)
+	streamInterceptors = append(streamInterceptors, server.authStreamInterceptor)
+	streamInterceptors = append(streamInterceptors, logging.StreamServerInterceptor(loggerInterceptor()))
 	opts = append(opts, grpc.Creds(creds))
 	opts = append(opts, grpc.ChainUnaryInterceptor(unaryInterceptors...))
+	opts = append(opts, grpc.ChainStreamInterceptor(streamInterceptors...))
 	return opts
 }
 
@@ -327,17 +461,40 @@ func registerGRPCPrivateServer(grpc *grpc.Server, server *GRPCPrivate) {
 	pb.RegisterPrivateServer(grpc, server)
 }
 
-func protoRecordTypeToModel(r pb.RecordType) models.RecordType {
-	switch r {
-	case pb.RecordType_PASSWORD:
-		return models.RecordPassword
-	case pb.RecordType_TEXT:
-		return models.RecordText
-	case pb.RecordType_BIN:
-		return models.RecordBin
-	case pb.RecordType_BANK:
-		return models.RecordBank
-	default:
-		return models.RecordUnknown
+func (s *GRPCPrivate) listResponse(r models.RecordsEncrypted) (*pb.ListResponse, error) {
+	resp := &pb.ListResponse{
+		Items: protoconv.RecordsToProto(r),
+	}
+	if s.config.legacyJSON {
+		data, err := json.Marshal(r)
+		if err != nil {
+			slog.Info(errorMarshal, "error", err)
+			return nil, status.Error(codes.Internal, errorMarshal)
+		}
+		resp.Records = data
 	}
+	return resp, nil
+}
+
+// requestRecord returns record from typed item or, for old clients, from deprecated JSON field.
+func requestRecord(t models.RecordType, item *pb.Record, legacy []byte) (models.RecordEncrypted, error) {
+	if item != nil {
+		itemType, record := protoconv.RecordFromProto(item)
+		if itemType != t {
+			return models.RecordEncrypted{}, fmt.Errorf(
+				"record type %q does not match requested type %q", itemType.String(), t.String(),
+			)
+		}
+		return record, nil
+	}
+	var record models.RecordEncrypted
+	if err := json.Unmarshal(legacy, &record); err != nil {
+		return models.RecordEncrypted{}, err
+	}
+	slog.Warn("deprecated JSON record received, client should be upgraded")
+	return record, nil
+}
+
+func protoRecordTypeToModel(r pb.RecordType) models.RecordType {
+	return protoconv.RecordTypeFromProto(r)
 }
@@ -8,12 +8,23 @@ import (
 
 	"github.com/sejo412/gophkeeper/internal/constants"
 	"github.com/sejo412/gophkeeper/internal/models"
+	"github.com/sejo412/gophkeeper/internal/protoconv"
 	pb "github.com/sejo412/gophkeeper/proto"
 	"google.golang.org/protobuf/types/known/emptypb"
 )
 
 var (
 	testRecordTypePassword = pb.RecordType_PASSWORD
+	testRecordTypeText     = pb.RecordType_TEXT
+	testRecordPasswordItem = protoconv.RecordToProto(
+		models.RecordPassword, models.RecordEncrypted{
+			Password: models.PasswordEncrypted{
+				Login:    []byte("testLogin"),
+				Password: []byte("testPassword"),
+				Meta:     []byte("testMeta"),
+			},
+		},
+	)
 )
 
 func Test_protoRecordTypeToModel(t *testing.T) {
@@ -258,6 +269,34 @@ func TestGRPCPrivate_Create(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "success typed",
+			fields: fields{
+				config: testServer.grpcPrivate.config,
+			},
+			args: args{
+				ctx: validCtx,
+				in: &pb.AddRecordRequest{
+					Type: &testRecordTypePassword,
+					Item: testRecordPasswordItem,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "error type mismatch",
+			fields: fields{
+				config: testServer.grpcPrivate.config,
+			},
+			args: args{
+				ctx: validCtx,
+				in: &pb.AddRecordRequest{
+					Type: &testRecordTypeText,
+					Item: testRecordPasswordItem,
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "error unauthorized",
 			fields: fields{
@@ -461,11 +500,14 @@ func TestGRPCPrivate_Read(t *testing.T) {
 				UnimplementedPrivateServer: tt.fields.UnimplementedPrivateServer,
 				config:                     tt.fields.config,
 			}
-			_, err := s.Read(tt.args.ctx, tt.args.in)
+			got, err := s.Read(tt.args.ctx, tt.args.in)
 			if (err != nil) != tt.wantErr {
 				t.Errorf("Read() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
+			if !tt.wantErr && got.GetItem().GetPassword() == nil {
+				t.Errorf("Read() got empty item")
+			}
 		})
 	}
 }
@@ -577,6 +619,45 @@ func TestGRPCPrivate_ListAll(t *testing.T) {
 	}
 }
 
+func TestGRPCPrivate_legacyJSON(t *testing.T) {
+	validCtx := context.WithValue(context.Background(), ctxUIDKey, 1)
+	tests := []struct {
+		name       string
+		legacyJSON bool
+		wantLegacy bool
+	}{
+		{
+			name:       "legacy enabled",
+			legacyJSON: true,
+			wantLegacy: true,
+		},
+		{
+			name:       "legacy disabled",
+			legacyJSON: false,
+			wantLegacy: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := testServer.grpcPrivate.config
+			cfg.legacyJSON = tt.legacyJSON
+			s := &GRPCPrivate{
+				config: cfg,
+			}
+			got, err := s.List(validCtx, &pb.ListRequest{Type: &testRecordTypePassword})
+			if err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+			if len(got.GetItems()) == 0 {
+				t.Errorf("List() got empty items")
+			}
+			if (len(got.GetRecords()) > 0) != tt.wantLegacy {
+				t.Errorf("List() got legacy records = %q, want %v", got.GetRecords(), tt.wantLegacy)
+			}
+		})
+	}
+}
+
 func TestGRPCPrivate_Delete(t *testing.T) {
 	validCtx := context.Background()
 	validCtx = context.WithValue(validCtx, ctxUIDKey, 1)
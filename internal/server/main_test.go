@@ -5,11 +5,10 @@ import (
 	"crypto/x509"
 	"encoding/json"
 	"os"
-	"path/filepath"
 	"testing"
 
 	"github.com/sejo412/gophkeeper/internal/models"
-	"github.com/sejo412/gophkeeper/internal/storage/sqlite"
+	"github.com/sejo412/gophkeeper/internal/storage/memory"
 	"github.com/sejo412/gophkeeper/pkg/certs"
 )
 
@@ -53,10 +52,7 @@ func TestMain(m *testing.M) {
 	defer func() {
 		_ = os.RemoveAll(testUserCacheDir)
 	}()
-	store, err := sqlite.New(filepath.Join(testCacheDir, "test.db"))
-	if err != nil {
-		panic(err)
-	}
+	store := memory.New()
 	testServer = NewServer(Config{
 		CacheDir:    testCacheDir,
 		PublicPort:  6200,
@@ -73,8 +69,8 @@ func TestMain(m *testing.M) {
 	if err != nil {
 		panic(err)
 	}
-	testServer.grpcPrivate = NewGRPCPrivate(*testServer.config)
-	if err = testServer.Init(); err != nil {
+	testServer.grpcPrivate, err = NewGRPCPrivate(*testServer.config)
+	if err != nil {
 		panic(err)
 	}
 	userPrivKey, err := certs.GenRsaKey(2048)
@@ -0,0 +1,106 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/sejo412/gophkeeper/internal/constants"
+	"github.com/sejo412/gophkeeper/internal/models"
+)
+
+var (
+	// ErrInitialized is returned by Init if cache dir already has database or CA.
+	ErrInitialized = errors.New("server already initialized, use force to overwrite all data and certificates")
+	// ErrNotInitialized is returned if cache dir has no database.
+	ErrNotInitialized = errors.New("server not initialized, run server init")
+	// ErrRunning is returned if operation requires stopped server.
+	ErrRunning = errors.New("server is running, stop it first")
+	// ErrSchemaVersion is returned if database schema version differs from version supported by server.
+	ErrSchemaVersion = errors.New("unsupported database schema version")
+	// ErrEphemeral is returned by admin command which opens storage, if cache dir belongs to ephemeral server.
+	ErrEphemeral = errors.New("server is ephemeral, its in-memory storage is not available to this command")
+)
+
+// SchemaVersion returns current schema version of database and latest version supported by server.
+func (s *Server) SchemaVersion(ctx context.Context) (int, int, error) {
+	store, err := s.openStorage()
+	if err != nil {
+		return 0, 0, err
+	}
+	defer func() {
+		_ = store.Close()
+	}()
+	return store.SchemaVersion(ctx)
+}
+
+// Migrate applies not applied schema migrations to database and returns them.
+func (s *Server) Migrate(ctx context.Context) ([]models.Migration, error) {
+	if s.running() {
+		return nil, ErrRunning
+	}
+	store, err := s.openStorage()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = store.Close()
+	}()
+	applied, err := store.Migrate(ctx)
+	if err != nil {
+		return applied, fmt.Errorf("could not migrate database: %w", err)
+	}
+	return applied, nil
+}
+
+// Migrations returns applied schema migrations of database.
+func (s *Server) Migrations(ctx context.Context) ([]models.Migration, error) {
+	store, err := s.openStorage()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = store.Close()
+	}()
+	return store.Migrations(ctx)
+}
+
+// checkNotInitialized returns ErrInitialized if cache dir has CA or storage has data.
+func (s *Server) checkNotInitialized() error {
+	for _, name := range []string{constants.CertCAPublicFilename, constants.CertCAPrivateFilename} {
+		if err := checkNotExist(filepath.Join(s.config.CacheDir, name)); err != nil {
+			return err
+		}
+	}
+	if s.config.StorageDSN == "" {
+		return checkNotExist(filepath.Join(s.config.CacheDir, constants.DBFilename))
+	}
+	store, err := s.newStorage()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = store.Close()
+	}()
+	current, _, err := store.SchemaVersion(context.Background())
+	if err != nil {
+		return fmt.Errorf("could not get schema version: %w", err)
+	}
+	if current > 0 {
+		return fmt.Errorf("%w: storage has schema version %d", ErrInitialized, current)
+	}
+	return nil
+}
+
+func checkNotExist(path string) error {
+	_, err := os.Stat(path)
+	if err == nil {
+		return fmt.Errorf("%w: %s exists", ErrInitialized, filepath.Base(path))
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("could not check %s: %w", filepath.Base(path), err)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/sejo412/gophkeeper/internal/constants"
+	"github.com/sejo412/gophkeeper/internal/models"
+)
+
+func TestServer_Migrate(t *testing.T) {
+	ctx := context.Background()
+	s := NewServer(Config{CacheDir: t.TempDir()})
+	if _, err := s.storage(); !errors.Is(err, ErrNotInitialized) {
+		t.Errorf("storage() before init error = %v, want %v", err, ErrNotInitialized)
+	}
+	if err := s.Init(); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	if err := s.Init(); !errors.Is(err, ErrInitialized) {
+		t.Errorf("second Init() error = %v, want %v", err, ErrInitialized)
+	}
+	current, latest, err := s.SchemaVersion(ctx)
+	if err != nil || current != latest {
+		t.Fatalf("SchemaVersion() after init = %d, %d, %v, want latest", current, latest, err)
+	}
+	store, err := s.storage()
+	if err != nil {
+		t.Fatal(err)
+	}
+	uid, err := store.NewUser(ctx, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := models.RecordEncrypted{Text: models.TextEncrypted{Text: []byte("text"), Meta: []byte("meta")}}
+	if err = store.Add(ctx, uid, models.RecordText, text); err != nil {
+		t.Fatal(err)
+	}
+	_ = store.Close()
+
+	// simulate database of older server without migration "record changes" and later ones
+	const changesVersion = 4
+	db, err := sql.Open("sqlite3", filepath.Join(s.config.CacheDir, constants.DBFilename))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, table := range []string{"passwords", "texts", "bins", "banks"} {
+		if _, err = db.ExecContext(ctx, "ALTER TABLE "+table+" DROP COLUMN revision"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err = db.ExecContext(ctx, "DROP TABLE changes"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = db.ExecContext(ctx, "DELETE FROM schema_version WHERE version >= ?", changesVersion); err != nil {
+		t.Fatal(err)
+	}
+	_ = db.Close()
+	if _, err = s.storage(); !errors.Is(err, ErrSchemaVersion) {
+		t.Errorf("storage() of not migrated database error = %v, want %v", err, ErrSchemaVersion)
+	}
+	applied, err := s.Migrate(ctx)
+	if err != nil || len(applied) != latest-changesVersion+1 || applied[0].Version != changesVersion {
+		t.Fatalf("Migrate() = %v, %v, want migrations %d-%d", applied, err, changesVersion, latest)
+	}
+	if store, err = s.storage(); err != nil {
+		t.Fatalf("storage() after migrate error = %v", err)
+	}
+	changes, err := store.Changes(ctx, uid, 0)
+	if err != nil || len(changes) != 1 || changes[0].Op != models.ChangeCreate || changes[0].Revision != 1 {
+		t.Errorf("Changes() after migrate = %v, %v, want creation of existing record", changes, err)
+	}
+	_ = store.Close()
+}
@@ -0,0 +1,178 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/constants"
+	"github.com/sejo412/gophkeeper/internal/models"
+	"github.com/sejo412/gophkeeper/pkg/certs"
+	pb "github.com/sejo412/gophkeeper/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const errorRegister = "error registering user"
+
+// inviteTokenSize is a count of random bytes in invite token.
+const inviteTokenSize = 20
+
+// RegisterStatus returns certificates of user whose registration was approved by admin.
+// User is created on first request after approval.
+func (sp *GRPCPublic) RegisterStatus(ctx context.Context, in *pb.RegisterStatusRequest) (*pb.RegisterResponse, error) {
+	registration, err := sp.config.store.Registration(ctx, in.GetCode())
+	if err != nil || registrationExpired(registration) {
+		return nil, status.Error(codes.NotFound, "registration not found")
+	}
+	if !registration.Approved {
+		return &pb.RegisterResponse{PendingCode: proto.String(registration.Code)}, nil
+	}
+	certRequest, err := certs.BinaryToRequest(registration.CertRequest)
+	if err != nil {
+		slog.Error(errorRegister, "error", err)
+		return nil, status.Error(codes.Internal, errorInternal)
+	}
+	cert, issued, err := signCertificate(sp.config.signer, certRequest)
+	if err != nil {
+		slog.Error("generating certificate", "error", err)
+		return nil, status.Error(codes.Internal, errorInternal)
+	}
+	// registration is spent only with user and its certificate, so failed attempt can be repeated
+	if _, err = sp.config.store.CompleteRegistration(ctx, registration.Code, issued); err != nil {
+		slog.Error("creating new user", "error", err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	return &pb.RegisterResponse{
+		CaCertificate:     sp.config.signer.CACert,
+		ClientCertificate: cert,
+	}, nil
+}
+
+// useInvite spends invite token on registration of user cn.
+func (sp *GRPCPublic) useInvite(ctx context.Context, token, cn string) error {
+	if token == "" {
+		return status.Error(codes.PermissionDenied, "invite required")
+	}
+	// don't spend invite on name which can't be registered
+	uid, err := sp.config.store.GetUserID(ctx, cn)
+	if err != nil {
+		slog.Error(errorRegister, "error", err)
+		return status.Error(codes.Internal, errorInternal)
+	}
+	if uid >= 0 {
+		return status.Errorf(codes.AlreadyExists, "name %q already used", cn)
+	}
+	if err = sp.config.store.UseInvite(ctx, token, cn, time.Now()); err != nil {
+		slog.Info(errorRegister, "error", err)
+		return status.Error(codes.PermissionDenied, err.Error())
+	}
+	return nil
+}
+
+// newRegistration saves certificate request of new user until admin approves it.
+func (sp *GRPCPublic) newRegistration(ctx context.Context, data []byte, request certs.CertRequest) (
+	*pb.RegisterResponse, error,
+) {
+	if _, err := requestPublicKey(request); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if err := sp.config.store.PurgeRegistrations(ctx, time.Now().Add(-constants.RegistrationTTL)); err != nil {
+		slog.Error(errorRegister, "error", err)
+		return nil, status.Error(codes.Internal, errorInternal)
+	}
+	code, err := newEnrollmentCode()
+	if err != nil {
+		slog.Error(errorRegister, "error", err)
+		return nil, status.Error(codes.Internal, errorInternal)
+	}
+	if err = sp.config.store.NewRegistration(
+		ctx, models.Registration{
+			Code:        code,
+			Cn:          request.CommonName,
+			CertRequest: data,
+			Created:     time.Now(),
+		},
+	); err != nil {
+		slog.Info(errorRegister, "error", err)
+		return nil, status.Error(codes.AlreadyExists, err.Error())
+	}
+	return &pb.RegisterResponse{PendingCode: proto.String(code)}, nil
+}
+
+// NewInvite creates single-use invite token valid for ttl.
+func (s *Server) NewInvite(ctx context.Context, ttl time.Duration) (models.Invite, error) {
+	token, err := randomCode(inviteTokenSize)
+	if err != nil {
+		return models.Invite{}, err
+	}
+	now := time.Now()
+	invite := models.Invite{
+		Token:   token,
+		Created: now,
+		Expires: now.Add(ttl),
+	}
+	err = s.withStorage(
+		func(store Storage) error {
+			return store.NewInvite(ctx, invite)
+		},
+	)
+	return invite, err
+}
+
+// Invites returns all invites.
+func (s *Server) Invites(ctx context.Context) ([]models.Invite, error) {
+	var invites []models.Invite
+	err := s.withStorage(
+		func(store Storage) error {
+			var err error
+			invites, err = store.Invites(ctx)
+			return err
+		},
+	)
+	return invites, err
+}
+
+// Registrations returns registrations waiting for admin approval.
+func (s *Server) Registrations(ctx context.Context) ([]models.Registration, error) {
+	var registrations []models.Registration
+	err := s.withStorage(
+		func(store Storage) error {
+			all, err := store.Registrations(ctx)
+			if err != nil {
+				return err
+			}
+			for _, r := range all {
+				if !registrationExpired(r) {
+					registrations = append(registrations, r)
+				}
+			}
+			return nil
+		},
+	)
+	return registrations, err
+}
+
+// ApproveRegistration approves registration of new user, user gets certificate on next
+// status request.
+func (s *Server) ApproveRegistration(ctx context.Context, code string) error {
+	return s.withStorage(
+		func(store Storage) error {
+			return store.ApproveRegistration(ctx, code)
+		},
+	)
+}
+
+// RejectRegistration deletes registration of new user.
+func (s *Server) RejectRegistration(ctx context.Context, code string) error {
+	return s.withStorage(
+		func(store Storage) error {
+			return store.DeleteRegistration(ctx, code)
+		},
+	)
+}
+
+func registrationExpired(r models.Registration) bool {
+	return time.Since(r.Created) > constants.RegistrationTTL
+}
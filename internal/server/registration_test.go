@@ -0,0 +1,191 @@
+package server
+
+import (
+	"context"
+	"crypto/x509"
+	"testing"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+	"github.com/sejo412/gophkeeper/pkg/certs"
+	pb "github.com/sejo412/gophkeeper/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// newTestRegistrationServer returns initialized Server with public server in registration mode.
+func newTestRegistrationServer(t *testing.T, mode models.RegistrationMode) (*Server, *GRPCPublic) {
+	t.Helper()
+	s := NewServer(Config{CacheDir: t.TempDir(), Registration: mode})
+	if err := s.Init(); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	store, err := s.storage()
+	if err != nil {
+		t.Fatalf("storage() error = %v", err)
+	}
+	t.Cleanup(
+		func() {
+			_ = store.Close()
+		},
+	)
+	s.config.SetStorage(store)
+	sp, err := NewGRPCPublic(*s.config)
+	if err != nil {
+		t.Fatalf("NewGRPCPublic() error = %v", err)
+	}
+	return s, sp
+}
+
+func testCertRequest(t *testing.T, cn string) []byte {
+	t.Helper()
+	key, err := certs.GenRsaKey(2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	csr := certs.NewCertRequest(cn, nil, nil, nil, false)
+	if err = csr.Sign(der); err != nil {
+		t.Fatal(err)
+	}
+	req, err := certs.RequestToBinary(*csr)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return req
+}
+
+func TestGRPCPublic_RegisterInvite(t *testing.T) {
+	ctx := context.Background()
+	s, sp := newTestRegistrationServer(t, models.RegistrationInvite)
+	invite, err := s.NewInvite(ctx, time.Hour)
+	if err != nil {
+		t.Fatalf("NewInvite() error = %v", err)
+	}
+	expired, err := s.NewInvite(ctx, -time.Hour)
+	if err != nil {
+		t.Fatalf("NewInvite() error = %v", err)
+	}
+	tests := []struct {
+		name   string
+		cn     string
+		invite string
+		want   codes.Code
+	}{
+		{name: "without invite", cn: "alice", invite: "", want: codes.PermissionDenied},
+		{name: "unknown invite", cn: "alice", invite: "unknown", want: codes.PermissionDenied},
+		{name: "expired invite", cn: "alice", invite: expired.Token, want: codes.PermissionDenied},
+		{name: "success", cn: "alice", invite: invite.Token, want: codes.OK},
+		{name: "used name keeps invite", cn: "alice", invite: "unknown", want: codes.AlreadyExists},
+		{name: "used invite", cn: "bob", invite: invite.Token, want: codes.PermissionDenied},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				resp, err := sp.Register(
+					ctx, &pb.RegisterRequest{
+						CertRequest: testCertRequest(t, tt.cn),
+						Invite:      proto.String(tt.invite),
+					},
+				)
+				if status.Code(err) != tt.want {
+					t.Fatalf("Register() error = %v, want %v", err, tt.want)
+				}
+				if tt.want == codes.OK && len(resp.GetClientCertificate()) == 0 {
+					t.Errorf("Register() got empty cert")
+				}
+			},
+		)
+	}
+	invites, err := s.Invites(ctx)
+	if err != nil || len(invites) != 2 || invites[0].Cn != "alice" {
+		t.Errorf("Invites() = %v, %v, want first used by alice", invites, err)
+	}
+}
+
+func TestGRPCPublic_RegisterApproval(t *testing.T) {
+	ctx := context.Background()
+	s, sp := newTestRegistrationServer(t, models.RegistrationApproval)
+	resp, err := sp.Register(ctx, &pb.RegisterRequest{CertRequest: testCertRequest(t, "alice")})
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	code := resp.GetPendingCode()
+	if code == "" || len(resp.GetClientCertificate()) != 0 {
+		t.Fatalf("Register() = %v, want pending code without certificate", resp)
+	}
+	if _, err = sp.Register(ctx, &pb.RegisterRequest{CertRequest: testCertRequest(t, "alice")}); err == nil {
+		t.Errorf("Register() of pending name error = nil, want error")
+	}
+	rejected, err := sp.Register(ctx, &pb.RegisterRequest{CertRequest: testCertRequest(t, "bob")})
+	if err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	registrations, err := s.Registrations(ctx)
+	if err != nil || len(registrations) != 2 {
+		t.Fatalf("Registrations() = %v, %v, want 2 registrations", registrations, err)
+	}
+	pending, err := sp.RegisterStatus(ctx, &pb.RegisterStatusRequest{Code: proto.String(code)})
+	if err != nil || pending.GetPendingCode() != code {
+		t.Fatalf("RegisterStatus() before approve = %v, %v, want pending", pending, err)
+	}
+	if err = s.ApproveRegistration(ctx, code); err != nil {
+		t.Fatalf("ApproveRegistration() error = %v", err)
+	}
+	if err = s.RejectRegistration(ctx, rejected.GetPendingCode()); err != nil {
+		t.Fatalf("RejectRegistration() error = %v", err)
+	}
+	approved, err := sp.RegisterStatus(ctx, &pb.RegisterStatusRequest{Code: proto.String(code)})
+	if err != nil || approved.GetPendingCode() != "" || len(approved.GetClientCertificate()) == 0 {
+		t.Fatalf("RegisterStatus() after approve = %v, %v, want certificate", approved, err)
+	}
+	if uid, _ := sp.config.store.FindUser(ctx, "alice"); uid < 0 {
+		t.Errorf("FindUser() after approve = %v, want registered user", uid)
+	}
+	for _, c := range []string{code, rejected.GetPendingCode()} {
+		if _, err = sp.RegisterStatus(ctx, &pb.RegisterStatusRequest{Code: proto.String(c)}); status.Code(err) !=
+			codes.NotFound {
+			t.Errorf("RegisterStatus(%q) error = %v, want NotFound", c, err)
+		}
+	}
+}
+
+func TestGRPCPublic_EnrollStatus(t *testing.T) {
+	ctx := context.Background()
+	_, sp := newTestRegistrationServer(t, models.RegistrationOpen)
+	if _, err := sp.Register(ctx, &pb.RegisterRequest{CertRequest: testCertRequest(t, "alice")}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	uid, err := sp.config.store.FindUser(ctx, "alice")
+	if err != nil || uid < 0 {
+		t.Fatalf("FindUser() = %v, %v, want registered user", uid, err)
+	}
+	resp, err := sp.Enroll(
+		ctx, &pb.EnrollRequest{User: proto.String("alice"), CertRequest: testCertRequest(t, "alice-phone")},
+	)
+	if err != nil {
+		t.Fatalf("Enroll() error = %v", err)
+	}
+	code := resp.GetCode()
+	pending, err := sp.EnrollStatus(ctx, &pb.EnrollStatusRequest{Code: proto.String(code)})
+	if err != nil || pending.GetApproved() {
+		t.Fatalf("EnrollStatus() before approve = %v, %v, want not approved", pending, err)
+	}
+	if err = sp.config.store.ApproveEnrollment(ctx, uid, code, models.Encrypted("vault key")); err != nil {
+		t.Fatalf("ApproveEnrollment() error = %v", err)
+	}
+	approved, err := sp.EnrollStatus(ctx, &pb.EnrollStatusRequest{Code: proto.String(code)})
+	if err != nil || !approved.GetApproved() || len(approved.GetClientCertificate()) == 0 {
+		t.Fatalf("EnrollStatus() after approve = %v, %v, want certificate", approved, err)
+	}
+	// certificate is issued once, so leaked code can't be used to get another one
+	if _, err = sp.EnrollStatus(ctx, &pb.EnrollStatusRequest{Code: proto.String(code)}); status.Code(err) !=
+		codes.NotFound {
+		t.Errorf("EnrollStatus() second time error = %v, want NotFound", err)
+	}
+}
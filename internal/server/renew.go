@@ -0,0 +1,44 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+	"github.com/sejo412/gophkeeper/pkg/certs"
+	pb "github.com/sejo412/gophkeeper/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const errorRenew = "error renewing certificate"
+
+// Renew issues new certificate for device authenticated by current still valid certificate.
+// Certificate request must have common name of current certificate, previous certificate
+// stays valid until it expires.
+func (s *GRPCPrivate) Renew(ctx context.Context, in *pb.RenewRequest) (*pb.RenewResponse, error) {
+	ctxUID, _ := ctx.Value(ctxUIDKey).(int)
+	uid := models.UserID(ctxUID)
+	cn, _ := ctx.Value(ctxCNKey).(string)
+	certRequest, err := certs.BinaryToRequest(in.GetCertRequest())
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if _, err = requestPublicKey(certRequest); err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	if certRequest.CommonName != cn {
+		return nil, status.Errorf(
+			codes.PermissionDenied, "certificate request for %q, current device is %q", certRequest.CommonName, cn,
+		)
+	}
+	cert, err := issueCertificate(ctx, s.config.store, s.config.signer, uid, certRequest)
+	if err != nil {
+		slog.Error(errorRenew, "error", err)
+		return nil, status.Error(codes.Internal, errorInternal)
+	}
+	return &pb.RenewResponse{
+		CaCertificate:     s.config.signer.CACert,
+		ClientCertificate: cert,
+	}, nil
+}
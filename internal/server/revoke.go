@@ -0,0 +1,131 @@
+package server
+
+import (
+	"context"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/constants"
+	"github.com/sejo412/gophkeeper/internal/models"
+	"github.com/sejo412/gophkeeper/pkg/certs"
+)
+
+// Revoke revokes client certificate by serial number or all certificates issued for device
+// by its CommonName. Revoked certificates are rejected by running server immediately.
+func (s *Server) Revoke(ctx context.Context, serial, cn string, reason models.RevocationReason) (
+	[]models.Revocation, error,
+) {
+	store, err := s.storage()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = store.Close()
+	}()
+	return revokeCertificates(ctx, store, serial, cn, reason)
+}
+
+// CRL returns DER encoded certificate revocation list signed by CA.
+func (s *Server) CRL(ctx context.Context) ([]byte, error) {
+	store, err := s.storage()
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = store.Close()
+	}()
+	revocations, err := store.Revocations(ctx)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]x509.RevocationListEntry, 0, len(revocations))
+	for _, r := range revocations {
+		serial, er := certs.ParseSerial(r.Serial)
+		if er != nil {
+			return nil, er
+		}
+		entries = append(
+			entries, x509.RevocationListEntry{
+				SerialNumber:   serial,
+				RevocationTime: r.Revoked,
+				ReasonCode:     int(r.Reason),
+			},
+		)
+	}
+	signer, err := certs.GetCASigner(
+		filepath.Join(s.config.CacheDir, constants.CertCAPublicFilename),
+		filepath.Join(s.config.CacheDir, constants.CertCAPrivateFilename),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("could not load CA signer: %w", err)
+	}
+	return certs.CreateCRL(signer, entries, big.NewInt(time.Now().Unix()), constants.CRLValidity)
+}
+
+// withStorage opens storage for admin command which works with database of stopped or running server.
+func (s *Server) withStorage(fn func(store Storage) error) error {
+	if err := s.checkNotEphemeral(); err != nil {
+		return err
+	}
+	store, err := s.storage()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		_ = store.Close()
+	}()
+	return fn(store)
+}
+
+// revokeCertificates revokes certificate by serial number or, if serial is empty,
+// all issued certificates of CommonName.
+func revokeCertificates(ctx context.Context, store Storage, serial, cn string, reason models.RevocationReason) (
+	[]models.Revocation, error,
+) {
+	revocations := make([]models.Revocation, 0)
+	now := time.Now()
+	switch {
+	case serial != "":
+		n, err := certs.ParseSerial(serial)
+		if err != nil {
+			return nil, err
+		}
+		serial = certs.SerialText(n)
+		issued, err := store.Certificates(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range issued {
+			if c.Serial == serial {
+				cn = c.Cn
+			}
+		}
+		revocations = append(
+			revocations, models.Revocation{Serial: serial, Cn: cn, Reason: reason, Revoked: now},
+		)
+	case cn != "":
+		issued, err := store.Certificates(ctx)
+		if err != nil {
+			return nil, err
+		}
+		for _, c := range issued {
+			if c.Cn == cn {
+				revocations = append(
+					revocations, models.Revocation{Serial: c.Serial, Cn: cn, Reason: reason, Revoked: now},
+				)
+			}
+		}
+	default:
+		return nil, errors.New("serial number or common name required")
+	}
+	for _, r := range revocations {
+		if err := store.Revoke(ctx, r); err != nil {
+			return nil, err
+		}
+	}
+	return revocations, nil
+}
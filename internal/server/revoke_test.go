@@ -0,0 +1,83 @@
+package server
+
+import (
+	"context"
+	"crypto/x509"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/constants"
+	"github.com/sejo412/gophkeeper/internal/models"
+	"github.com/sejo412/gophkeeper/pkg/certs"
+)
+
+func TestServer_Revoke(t *testing.T) {
+	ctx := context.Background()
+	s := NewServer(Config{CacheDir: t.TempDir()})
+	if err := s.Init(); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	store, err := s.storage()
+	if err != nil {
+		t.Fatalf("storage() error = %v", err)
+	}
+	for _, c := range []models.Certificate{
+		{Serial: "a1", Cn: "laptop", Issued: time.Now(), NotAfter: time.Now().Add(time.Hour)},
+		{Serial: "a2", Cn: "laptop", Issued: time.Now(), NotAfter: time.Now().Add(time.Hour)},
+		{Serial: "b1", Cn: "phone", Issued: time.Now(), NotAfter: time.Now().Add(time.Hour)},
+	} {
+		if err = store.AddCertificate(ctx, c); err != nil {
+			t.Fatal(err)
+		}
+	}
+	_ = store.Close()
+
+	if _, err = s.Revoke(ctx, "", "", models.RevocationUnspecified); err == nil {
+		t.Errorf("Revoke() without serial and cn error = nil, want error")
+	}
+	if _, err = s.Revoke(ctx, "zz", "", models.RevocationUnspecified); err == nil {
+		t.Errorf("Revoke() with invalid serial error = nil, want error")
+	}
+	revoked, err := s.Revoke(ctx, "", "laptop", models.RevocationKeyCompromise)
+	if err != nil || len(revoked) != 2 {
+		t.Fatalf("Revoke() by cn = %v, %v, want 2 certificates", revoked, err)
+	}
+	revoked, err = s.Revoke(ctx, "B1", "", models.RevocationSuperseded)
+	if err != nil || len(revoked) != 1 || revoked[0].Cn != "phone" {
+		t.Fatalf("Revoke() by serial = %v, %v, want certificate of phone", revoked, err)
+	}
+
+	der, err := s.CRL(ctx)
+	if err != nil {
+		t.Fatalf("CRL() error = %v", err)
+	}
+	crl, err := x509.ParseRevocationList(der)
+	if err != nil {
+		t.Fatalf("ParseRevocationList() error = %v", err)
+	}
+	signer, err := certs.GetCASigner(
+		filepath.Join(s.config.CacheDir, constants.CertCAPublicFilename),
+		filepath.Join(s.config.CacheDir, constants.CertCAPrivateFilename),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caCert, err := x509.ParseCertificate(signer.CACert)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = crl.CheckSignatureFrom(caCert); err != nil {
+		t.Errorf("CRL signature error = %v", err)
+	}
+	got := make(map[string]int)
+	for _, e := range crl.RevokedCertificateEntries {
+		got[certs.SerialText(e.SerialNumber)] = e.ReasonCode
+	}
+	want := map[string]int{"a1": 1, "a2": 1, "b1": 4}
+	for serial, reason := range want {
+		if got[serial] != reason {
+			t.Errorf("CRL entry %s reason = %d, want %d", serial, got[serial], reason)
+		}
+	}
+}
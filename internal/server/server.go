@@ -15,7 +15,8 @@ import (
 
 	"github.com/sejo412/gophkeeper/internal/constants"
 	"github.com/sejo412/gophkeeper/internal/helpers"
-	"github.com/sejo412/gophkeeper/internal/storage/sqlite"
+	"github.com/sejo412/gophkeeper/pkg/certs"
+	pb "github.com/sejo412/gophkeeper/proto"
 	"golang.org/x/sync/errgroup"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
@@ -36,7 +37,8 @@ func NewServer(opts Config) *Server {
 	}
 }
 
-// Init destroys all presents data and creates new data (Storage and certificates).
+// Init creates new data (Storage and certificates). Init refuses to overwrite existing data
+// with ErrInitialized unless Config.Force is set.
 func (s *Server) Init() error {
 	ctx := context.Background()
 	if s.config == nil {
@@ -50,10 +52,28 @@ func (s *Server) Init() error {
 			return fmt.Errorf("could not create cache dir: %w", er)
 		}
 	}
-	dbFile := filepath.Join(s.config.CacheDir, constants.DBFilename)
-	if err := createDatabase(ctx, dbFile); err != nil {
+	if s.running() {
+		return ErrRunning
+	}
+	if !s.config.Force {
+		if err := s.checkNotInitialized(); err != nil {
+			return err
+		}
+	}
+	store, err := s.newStorage()
+	if err != nil {
+		return err
+	}
+	err = createDatabase(ctx, store)
+	_ = store.Close()
+	if err != nil {
 		return fmt.Errorf("could not create database: %w", err)
 	}
+	return s.createCertificates(ctx)
+}
+
+// createCertificates creates CA and server certificate in cache dir.
+func (s *Server) createCertificates(ctx context.Context) error {
 	caCert := filepath.Join(s.config.CacheDir, constants.CertCAPublicFilename)
 	caKey := filepath.Join(s.config.CacheDir, constants.CertCAPrivateFilename)
 	if err := createCA(ctx, caCert, caKey); err != nil {
@@ -72,9 +92,17 @@ func (s *Server) Init() error {
 // Start starts main application.
 func (s *Server) Start() error {
 	// open storage
-	store, err := sqlite.New(filepath.Join(s.config.CacheDir, constants.DBFilename))
+	var store Storage
+	var err error
+	if s.config.Ephemeral {
+		var cleanup func()
+		store, cleanup, err = s.initEphemeral()
+		defer cleanup()
+	} else {
+		store, err = s.storage()
+	}
 	if err != nil {
-		return fmt.Errorf("could not open storage: %w", err)
+		return err
 	}
 	defer func() {
 		_ = store.Close()
@@ -86,7 +114,15 @@ func (s *Server) Start() error {
 	if err != nil {
 		return fmt.Errorf("could not create public server: %w", err)
 	}
-	publicGRPCServer := grpc.NewServer(grpcPublicServerOptions(s.grpcPublic)...)
+	var publicCreds credentials.TransportCredentials
+	if s.config.PublicTLS {
+		publicTLSCfg, er := publicTLSConfig(s.config.CacheDir)
+		if er != nil {
+			return fmt.Errorf("could not create public TLS configuration: %w", er)
+		}
+		publicCreds = credentials.NewTLS(publicTLSCfg)
+	}
+	publicGRPCServer := grpc.NewServer(grpcPublicServerOptions(s.grpcPublic, publicCreds)...)
 	registerGRPCPublicServer(publicGRPCServer, s.grpcPublic)
 	// for debug
 	reflection.Register(publicGRPCServer)
@@ -97,7 +133,10 @@ func (s *Server) Start() error {
 	}
 	creds := credentials.NewTLS(tlsCfg)
 
-	s.grpcPrivate = NewGRPCPrivate(*s.config)
+	s.grpcPrivate, err = NewGRPCPrivate(*s.config)
+	if err != nil {
+		return fmt.Errorf("could not create private server: %w", err)
+	}
 	privateGRPCServer := grpc.NewServer(grpcPrivateServerOptions(s.grpcPrivate, creds)...)
 	registerGRPCPrivateServer(privateGRPCServer, s.grpcPrivate)
 
@@ -110,16 +149,34 @@ func (s *Server) Start() error {
 		return fmt.Errorf("could not listen on port %d: %w", s.config.PrivatePort, err)
 	}
 
+	// admin service is optional, admin commands fall back to database
+	adminGRPCServer := grpc.NewServer(grpcAdminServerOptions()...)
+	pb.RegisterAdminServer(adminGRPCServer, NewGRPCAdmin(*s.config))
+	adminListener, err := listenAdmin(s.adminSocket())
+	if err != nil {
+		slog.Warn("admin service not started", "error", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go purgeTrash(ctx, store, s.config.TrashRetention, constants.TrashPurgeInterval)
+	go purgeVersions(ctx, store, s.config.VersionsMaxAge, constants.VersionsPurgeInterval)
+
 	idleConnsClosed := make(chan struct{})
 	sigs := make(chan os.Signal, 1)
 	signal.Notify(sigs, constants.GracefulSignals...)
 	go func() {
 		<-sigs
+		cancel()
 		fmt.Println()
 		slog.Info("shutting down public server...")
 		publicGRPCServer.GracefulStop()
 		slog.Info("shutting down private server...")
+		// watch streams are finished by server only
+		s.grpcPrivate.watchers.close()
 		privateGRPCServer.GracefulStop()
+		slog.Info("shutting down admin server...")
+		adminGRPCServer.GracefulStop()
 		close(idleConnsClosed)
 	}()
 
@@ -137,6 +194,15 @@ func (s *Server) Start() error {
 		},
 	)
 
+	if adminListener != nil {
+		errGrp.Go(
+			func() error {
+				slog.Info("starting admin server")
+				return adminGRPCServer.Serve(adminListener)
+			},
+		)
+	}
+
 	if err = errGrp.Wait(); err != nil {
 		return fmt.Errorf("could not start server: %w", err)
 	}
@@ -186,3 +252,66 @@ func tlsConfig(dir string) (*tls.Config, error) {
 		ClientAuth:   tls.RequireAndVerifyClientCert,
 	}, nil
 }
+
+// publicTLSConfig returns server-only TLS configuration for public server. Certificate chain
+// includes CA, so client without CA certificate can verify it by CA fingerprint.
+func publicTLSConfig(dir string) (*tls.Config, error) {
+	cfg, err := tlsConfig(dir)
+	if err != nil {
+		return nil, err
+	}
+	derCaCert, err := os.ReadFile(filepath.Join(dir, constants.CertCAPublicFilename))
+	if err != nil {
+		return nil, fmt.Errorf("could not load CA certificate: %w", err)
+	}
+	cfg.Certificates[0].Certificate = append(cfg.Certificates[0].Certificate, derCaCert)
+	cfg.ClientCAs = nil
+	cfg.ClientAuth = tls.NoClientCert
+	return cfg, nil
+}
+
+// CAFingerprint returns SHA-256 fingerprint of CA certificate, clients verify CA by it on registration.
+func (s *Server) CAFingerprint() (string, error) {
+	der, err := os.ReadFile(filepath.Join(s.config.CacheDir, constants.CertCAPublicFilename))
+	if err != nil {
+		return "", fmt.Errorf("could not read CA certificate: %w", err)
+	}
+	return certs.Fingerprint(der), nil
+}
+
+// listenAdmin listens on unix socket of admin service accessible by server owner only. Socket is
+// created in directory with mode 0700, so it is not accessible before its mode is changed.
+// Socket left by crashed server is replaced.
+func listenAdmin(socket string) (net.Listener, error) {
+	if conn, err := net.Dial("unix", socket); err == nil {
+		_ = conn.Close()
+		return nil, fmt.Errorf("admin socket %s is used by another server", socket)
+	}
+	dir := filepath.Dir(socket)
+	if err := os.MkdirAll(dir, 0700); err != nil {
+		return nil, fmt.Errorf("could not create admin socket directory: %w", err)
+	}
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return nil, fmt.Errorf("could not stat admin socket directory: %w", err)
+	}
+	if !info.IsDir() {
+		return nil, fmt.Errorf("admin socket directory %s is not a directory", dir)
+	}
+	// directory could be created by older server or by user with wider mode
+	if err = os.Chmod(dir, 0700); err != nil {
+		return nil, fmt.Errorf("could not protect admin socket directory: %w", err)
+	}
+	if err = os.Remove(socket); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("could not remove stale admin socket: %w", err)
+	}
+	listener, err := net.Listen("unix", socket)
+	if err != nil {
+		return nil, fmt.Errorf("could not listen on %s: %w", socket, err)
+	}
+	if err = os.Chmod(socket, 0600); err != nil {
+		_ = listener.Close()
+		return nil, fmt.Errorf("could not protect admin socket: %w", err)
+	}
+	return listener, nil
+}
@@ -2,6 +2,8 @@ package server
 
 import (
 	"crypto/tls"
+	"os"
+	"path/filepath"
 	"reflect"
 	"testing"
 	"time"
@@ -17,12 +19,22 @@ func TestServer_Init(t *testing.T) {
 		wantErr bool
 	}{
 		{
-			name: "success",
+			name: "already initialized",
 			fields: fields{
 				config: &Config{
 					CacheDir: testCacheDir,
 				},
 			},
+			wantErr: true,
+		},
+		{
+			name: "success force",
+			fields: fields{
+				config: &Config{
+					CacheDir: testCacheDir,
+					Force:    true,
+				},
+			},
 			wantErr: false,
 		},
 		{
@@ -150,3 +162,44 @@ func Test_tlsConfig(t *testing.T) {
 		})
 	}
 }
+
+func Test_listenAdmin(t *testing.T) {
+	tests := []struct {
+		name string
+		// mode of existing directory of socket, 0 if not exists
+		dirMode os.FileMode
+	}{
+		{name: "new directory"},
+		{name: "existing directory", dirMode: 0755},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := filepath.Join(t.TempDir(), "admin")
+			if tt.dirMode != 0 {
+				if err := os.Mkdir(dir, tt.dirMode); err != nil {
+					t.Fatal(err)
+				}
+			}
+			socket := filepath.Join(dir, "admin.sock")
+			listener, err := listenAdmin(socket)
+			if err != nil {
+				t.Fatalf("listenAdmin() error = %v", err)
+			}
+			defer func() {
+				_ = listener.Close()
+			}()
+			for path, want := range map[string]os.FileMode{dir: 0700, socket: 0600} {
+				info, er := os.Stat(path)
+				if er != nil {
+					t.Fatal(er)
+				}
+				if info.Mode().Perm() != want {
+					t.Errorf("listenAdmin() mode of %s = %v, want %v", filepath.Base(path), info.Mode().Perm(), want)
+				}
+			}
+			if _, err = listenAdmin(socket); err == nil {
+				t.Errorf("listenAdmin() of used socket error = nil, want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/sejo412/gophkeeper/internal/constants"
+	"github.com/sejo412/gophkeeper/internal/storage/postgres"
+	"github.com/sejo412/gophkeeper/internal/storage/sqlite"
+)
+
+// newStorage opens storage by DSN or SQLite database in cache dir, database file is created if not exists.
+func (s *Server) newStorage() (Storage, error) {
+	dsn := s.config.StorageDSN
+	switch {
+	case dsn == "":
+		store, err := sqlite.New(filepath.Join(s.config.CacheDir, constants.DBFilename))
+		if err != nil {
+			return nil, fmt.Errorf("could not open storage: %w", err)
+		}
+		return store, nil
+	case strings.HasPrefix(dsn, "postgres://"), strings.HasPrefix(dsn, "postgresql://"):
+		store, err := postgres.New(dsn)
+		if err != nil {
+			return nil, fmt.Errorf("could not open storage: %w", err)
+		}
+		return store, nil
+	default:
+		return nil, fmt.Errorf("unsupported storage DSN %q", redactDSN(dsn))
+	}
+}
+
+// openStorage opens existing storage without migrations.
+func (s *Server) openStorage() (Storage, error) {
+	if s.config.StorageDSN == "" {
+		if _, err := os.Stat(filepath.Join(s.config.CacheDir, constants.DBFilename)); err != nil {
+			return nil, fmt.Errorf("%w: %w", ErrNotInitialized, err)
+		}
+	}
+	return s.newStorage()
+}
+
+// storage opens database in cache dir, database schema must be migrated to version supported by server.
+func (s *Server) storage() (Storage, error) {
+	store, err := s.openStorage()
+	if err != nil {
+		return nil, err
+	}
+	current, latest, err := store.SchemaVersion(context.Background())
+	if err != nil {
+		_ = store.Close()
+		return nil, fmt.Errorf("could not get schema version: %w", err)
+	}
+	if current != latest {
+		_ = store.Close()
+		if current < latest {
+			return nil, fmt.Errorf("%w: database has version %d, server requires %d, run server migrate",
+				ErrSchemaVersion, current, latest)
+		}
+		return nil, fmt.Errorf("%w: database has version %d newer than supported %d", ErrSchemaVersion, current, latest)
+	}
+	return store, nil
+}
+
+// redactDSN hides password of DSN for messages.
+func redactDSN(dsn string) string {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return "invalid DSN"
+	}
+	return u.Redacted()
+}
@@ -0,0 +1,87 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+	"github.com/sejo412/gophkeeper/internal/protoconv"
+	pb "github.com/sejo412/gophkeeper/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+const (
+	errorTrash      = "error listing trash"
+	errorRestore    = "error restoring record from trash"
+	errorEmptyTrash = "error emptying trash"
+	errorPurgeTrash = "error purging trash"
+)
+
+// ListTrash returns deleted records of User with id and meta only, last deleted first.
+func (s *GRPCPrivate) ListTrash(ctx context.Context, _ *emptypb.Empty) (*pb.ListTrashResponse, error) {
+	ctxUID, _ := ctx.Value(ctxUIDKey).(int)
+	items, err := s.config.store.Trash(ctx, models.UserID(ctxUID))
+	if err != nil {
+		slog.Error(errorTrash, "error", err)
+		return nil, status.Error(codes.Internal, errorTrash)
+	}
+	resp := &pb.ListTrashResponse{Items: make([]*pb.TrashItem, 0, len(items))}
+	for _, item := range items {
+		resp.Items = append(
+			resp.Items, &pb.TrashItem{
+				Type:    protoconv.RecordTypeToProto(item.Type).Enum(),
+				Deleted: proto.Int64(item.Deleted.Unix()),
+				Item:    protoconv.RecordToProto(item.Type, item.Record),
+			},
+		)
+	}
+	return resp, nil
+}
+
+// RestoreTrash moves deleted models.Record back from trash of User.
+func (s *GRPCPrivate) RestoreTrash(ctx context.Context, in *pb.RestoreTrashRequest) (*emptypb.Empty, error) {
+	ctxUID, _ := ctx.Value(ctxUIDKey).(int)
+	uid := models.UserID(ctxUID)
+	if err := s.config.store.RestoreTrash(
+		ctx, uid, protoRecordTypeToModel(in.GetType()), models.ID(in.GetRecordNumber()),
+	); err != nil {
+		slog.Info(errorRestore, "error", err)
+		return nil, status.Error(codes.NotFound, errorRestore)
+	}
+	s.notify(ctx, uid)
+	return &emptypb.Empty{}, nil
+}
+
+// EmptyTrash permanently deletes all records in trash of User.
+func (s *GRPCPrivate) EmptyTrash(ctx context.Context, _ *emptypb.Empty) (*emptypb.Empty, error) {
+	ctxUID, _ := ctx.Value(ctxUIDKey).(int)
+	if err := s.config.store.EmptyTrash(ctx, models.UserID(ctxUID)); err != nil {
+		slog.Error(errorEmptyTrash, "error", err)
+		return nil, status.Error(codes.Internal, errorEmptyTrash)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+// purgeTrash permanently deletes records deleted more than retention ago on start and then every
+// interval until ctx is done. Zero retention keeps records until trash is emptied by user.
+func purgeTrash(ctx context.Context, store Storage, retention, interval time.Duration) {
+	if retention <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := store.PurgeTrash(ctx, time.Now().Add(-retention)); err != nil && ctx.Err() == nil {
+			slog.Error(errorPurgeTrash, "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,137 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+	"github.com/sejo412/gophkeeper/internal/storage/memory"
+	pb "github.com/sejo412/gophkeeper/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+func TestGRPCPrivate_Trash(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	if err := store.Init(ctx); err != nil {
+		t.Fatal(err)
+	}
+	uid, err := store.NewUser(ctx, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = store.Add(
+		ctx, uid, models.RecordPassword,
+		models.RecordEncrypted{Password: models.PasswordEncrypted{Meta: []byte("meta")}},
+	); err != nil {
+		t.Fatal(err)
+	}
+	if err = store.Delete(ctx, uid, models.RecordPassword, 1, 0); err != nil {
+		t.Fatal(err)
+	}
+	s := &GRPCPrivate{config: privateConfig{store: store}}
+	userCtx := context.WithValue(ctx, ctxUIDKey, int(uid))
+	otherCtx := context.WithValue(ctx, ctxUIDKey, 42)
+
+	resp, err := s.ListTrash(userCtx, &emptypb.Empty{})
+	if err != nil || len(resp.GetItems()) != 1 {
+		t.Fatalf("ListTrash() = %v, %v, want 1 item", resp, err)
+	}
+	item := resp.GetItems()[0]
+	if item.GetType() != pb.RecordType_PASSWORD || item.GetDeleted() == 0 ||
+		string(item.GetItem().GetPassword().GetMeta()) != "meta" {
+		t.Errorf("ListTrash() item = %v, want deleted password with meta", item)
+	}
+	if resp, _ = s.ListTrash(otherCtx, &emptypb.Empty{}); len(resp.GetItems()) != 0 {
+		t.Errorf("ListTrash() of other user = %v, want empty", resp)
+	}
+
+	req := &pb.RestoreTrashRequest{Type: pb.RecordType_PASSWORD.Enum(), RecordNumber: proto.Int64(1)}
+	if _, err = s.RestoreTrash(otherCtx, req); status.Code(err) != codes.NotFound {
+		t.Errorf("RestoreTrash() by other user error = %v, want %v", err, codes.NotFound)
+	}
+	if _, err = s.RestoreTrash(userCtx, req); err != nil {
+		t.Fatalf("RestoreTrash() error = %v", err)
+	}
+	if ok, _ := store.IsExist(ctx, uid, models.RecordPassword, 1); !ok {
+		t.Errorf("IsExist() after RestoreTrash() = false, want true")
+	}
+
+	if err = store.Delete(ctx, uid, models.RecordPassword, 1, 0); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = s.EmptyTrash(userCtx, &emptypb.Empty{}); err != nil {
+		t.Fatalf("EmptyTrash() error = %v", err)
+	}
+	if resp, _ = s.ListTrash(userCtx, &emptypb.Empty{}); len(resp.GetItems()) != 0 {
+		t.Errorf("ListTrash() after EmptyTrash() = %v, want empty", resp)
+	}
+	if _, err = s.RestoreTrash(userCtx, req); status.Code(err) != codes.NotFound {
+		t.Errorf("RestoreTrash() after EmptyTrash() error = %v, want %v", err, codes.NotFound)
+	}
+}
+
+// purgeStore records times passed to PurgeTrash and PurgeVersions.
+type purgeStore struct {
+	Storage
+	purged chan time.Time
+}
+
+func (p *purgeStore) PurgeTrash(ctx context.Context, before time.Time) error {
+	select {
+	case p.purged <- before:
+	case <-ctx.Done():
+	}
+	return nil
+}
+
+func (p *purgeStore) PurgeVersions(ctx context.Context, before time.Time) error {
+	return p.PurgeTrash(ctx, before)
+}
+
+func Test_purgeTrash(t *testing.T) {
+	tests := []struct {
+		name      string
+		retention time.Duration
+		wantPurge bool
+	}{
+		{name: "disabled", retention: 0, wantPurge: false},
+		{name: "enabled", retention: time.Hour, wantPurge: true},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				store := &purgeStore{purged: make(chan time.Time)}
+				ctx, cancel := context.WithCancel(context.Background())
+				done := make(chan struct{})
+				go func() {
+					purgeTrash(ctx, store, tt.retention, time.Millisecond)
+					close(done)
+				}()
+				defer cancel()
+				if !tt.wantPurge {
+					select {
+					case <-done:
+					case <-store.purged:
+						t.Errorf("PurgeTrash() called, want disabled purge")
+					case <-time.After(time.Second):
+						t.Errorf("purgeTrash() with disabled purge not returned")
+					}
+					return
+				}
+				for range 2 {
+					before := <-store.purged
+					if age := time.Since(before); age < tt.retention || age > tt.retention+time.Minute {
+						t.Errorf("PurgeTrash() before %v ago, want %v", age, tt.retention)
+					}
+				}
+				cancel()
+				<-done
+			},
+		)
+	}
+}
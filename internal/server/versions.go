@@ -0,0 +1,81 @@
+package server
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+	"github.com/sejo412/gophkeeper/internal/protoconv"
+	pb "github.com/sejo412/gophkeeper/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	errorVersions = "error listing versions"
+	errorVersion  = "error getting version"
+	errorPurge    = "error purging versions"
+)
+
+// ListVersions returns previous versions of record with id and meta only, newest first.
+func (s *GRPCPrivate) ListVersions(ctx context.Context, in *pb.ListVersionsRequest) (
+	*pb.ListVersionsResponse,
+	error,
+) {
+	ctxUID, _ := ctx.Value(ctxUIDKey).(int)
+	uid := models.UserID(ctxUID)
+	t := protoRecordTypeToModel(in.GetType())
+	versions, err := s.config.store.Versions(ctx, uid, t, models.ID(in.GetRecordNumber()))
+	if err != nil {
+		slog.Error(errorVersions, "error", err)
+		return nil, status.Error(codes.Internal, errorVersions)
+	}
+	resp := &pb.ListVersionsResponse{Versions: make([]*pb.Version, 0, len(versions))}
+	for _, version := range versions {
+		resp.Versions = append(resp.Versions, versionToProto(t, version))
+	}
+	return resp, nil
+}
+
+// GetVersion returns previous version of record by version number.
+func (s *GRPCPrivate) GetVersion(ctx context.Context, in *pb.GetVersionRequest) (*pb.Version, error) {
+	ctxUID, _ := ctx.Value(ctxUIDKey).(int)
+	uid := models.UserID(ctxUID)
+	t := protoRecordTypeToModel(in.GetType())
+	version, err := s.config.store.Version(ctx, uid, t, models.ID(in.GetRecordNumber()), in.GetVersion())
+	if err != nil {
+		slog.Info(errorVersion, "error", err)
+		return nil, status.Error(codes.NotFound, errorVersion)
+	}
+	return versionToProto(t, version), nil
+}
+
+// purgeVersions deletes versions created more than maxAge ago on start and then every interval
+// until ctx is done. Zero maxAge keeps versions of any age.
+func purgeVersions(ctx context.Context, store Storage, maxAge, interval time.Duration) {
+	if maxAge <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := store.PurgeVersions(ctx, time.Now().Add(-maxAge)); err != nil && ctx.Err() == nil {
+			slog.Error(errorPurge, "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func versionToProto(t models.RecordType, version models.Version) *pb.Version {
+	return &pb.Version{
+		Version: proto.Int64(version.Number),
+		Created: proto.Int64(version.Created.Unix()),
+		Item:    protoconv.RecordToProto(t, version.Record),
+	}
+}
@@ -0,0 +1,104 @@
+package server
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+	"github.com/sejo412/gophkeeper/internal/protoconv"
+	"github.com/sejo412/gophkeeper/internal/storage/memory"
+	pb "github.com/sejo412/gophkeeper/proto"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestGRPCPrivate_UpdateTrimsVersions(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	if err := store.Init(ctx); err != nil {
+		t.Fatal(err)
+	}
+	uid, err := store.NewUser(ctx, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for range 2 {
+		if err = store.Add(
+			ctx, uid, models.RecordText, models.RecordEncrypted{Text: models.TextEncrypted{Meta: []byte("meta")}},
+		); err != nil {
+			t.Fatal(err)
+		}
+	}
+	s := &GRPCPrivate{config: privateConfig{store: store, versionsKeep: 2}}
+	userCtx := context.WithValue(ctx, ctxUIDKey, int(uid))
+	// other record has versions over limit, they are kept until its own update
+	for range 3 {
+		if err = store.Update(
+			ctx, uid, models.RecordText, 2, models.RecordEncrypted{Text: models.TextEncrypted{Meta: []byte("other")}}, 0,
+		); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for range 4 {
+		if _, err = s.Update(
+			userCtx, &pb.UpdateRecordRequest{
+				Type:         pb.RecordType_TEXT.Enum(),
+				RecordNumber: proto.Int64(1),
+				Item: protoconv.RecordToProto(
+					models.RecordText, models.RecordEncrypted{Text: models.TextEncrypted{Meta: []byte("new")}},
+				),
+			},
+		); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+	}
+	if versions, _ := store.Versions(ctx, uid, models.RecordText, 1); len(versions) != 2 || versions[0].Number != 4 {
+		t.Errorf("Versions() of updated record = %v, want 2 newest", versions)
+	}
+	if versions, _ := store.Versions(ctx, uid, models.RecordText, 2); len(versions) != 3 {
+		t.Errorf("Versions() of other record = %v, want all kept", versions)
+	}
+}
+
+func Test_purgeVersions(t *testing.T) {
+	tests := []struct {
+		name      string
+		maxAge    time.Duration
+		wantPurge bool
+	}{
+		{name: "disabled", maxAge: 0, wantPurge: false},
+		{name: "enabled", maxAge: time.Hour, wantPurge: true},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				store := &purgeStore{purged: make(chan time.Time)}
+				ctx, cancel := context.WithCancel(context.Background())
+				done := make(chan struct{})
+				go func() {
+					purgeVersions(ctx, store, tt.maxAge, time.Millisecond)
+					close(done)
+				}()
+				defer cancel()
+				if !tt.wantPurge {
+					select {
+					case <-done:
+					case <-store.purged:
+						t.Errorf("PurgeVersions() called, want disabled purge")
+					case <-time.After(time.Second):
+						t.Errorf("purgeVersions() with disabled purge not returned")
+					}
+					return
+				}
+				for range 2 {
+					before := <-store.purged
+					if age := time.Since(before); age < tt.maxAge || age > tt.maxAge+time.Minute {
+						t.Errorf("PurgeVersions() before %v ago, want %v", age, tt.maxAge)
+					}
+				}
+				cancel()
+				<-done
+			},
+		)
+	}
+}
@@ -0,0 +1,207 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+	"github.com/sejo412/gophkeeper/internal/protoconv"
+	pb "github.com/sejo412/gophkeeper/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	errorWatch         = "error watching changes"
+	errorWatchOverflow = "too many changes not received, watch again"
+	errorWatchClosed   = "server is shutting down"
+	// watchBuffer is a count of changes buffered for every watcher.
+	watchBuffer = 64
+)
+
+var (
+	errWatchOverflow = errors.New(errorWatchOverflow)
+	errWatchClosed   = errors.New(errorWatchClosed)
+)
+
+// watcher receives changes of records of user, err is set before changes is closed.
+type watcher struct {
+	changes chan models.Change
+	err     error
+}
+
+// feed publishes changes of records of user to its watchers in order of revisions.
+type feed struct {
+	mu sync.Mutex
+	// revision is a revision of last published change.
+	revision int64
+	watchers map[*watcher]struct{}
+}
+
+// watchHub is an in-process pub/sub of changes of records, changes are read from Storage
+// after every change of records of watched user.
+type watchHub struct {
+	mu     sync.Mutex
+	feeds  map[models.UserID]*feed
+	closed bool
+}
+
+func newWatchHub() *watchHub {
+	return &watchHub{feeds: make(map[models.UserID]*feed)}
+}
+
+// subscribe adds watcher of changes of user. It returns revision of last change published
+// before subscription, watcher receives changes after it.
+func (h *watchHub) subscribe(ctx context.Context, store Storage, uid models.UserID) (*watcher, int64, error) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return nil, 0, errWatchClosed
+	}
+	f, ok := h.feeds[uid]
+	if !ok {
+		revision, err := store.LastRevision(ctx, uid)
+		if err != nil {
+			return nil, 0, err
+		}
+		f = &feed{revision: revision, watchers: make(map[*watcher]struct{})}
+		h.feeds[uid] = f
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	w := &watcher{changes: make(chan models.Change, watchBuffer)}
+	f.watchers[w] = struct{}{}
+	return w, f.revision, nil
+}
+
+// unsubscribe removes watcher of changes of user.
+func (h *watchHub) unsubscribe(uid models.UserID, w *watcher) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	f, ok := h.feeds[uid]
+	if !ok {
+		return
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	delete(f.watchers, w)
+	if len(f.watchers) == 0 {
+		delete(h.feeds, uid)
+	}
+}
+
+// publish sends changes of records of user made after last published change to its watchers.
+// Watcher not receiving changes is dropped.
+func (h *watchHub) publish(ctx context.Context, store Storage, uid models.UserID) error {
+	h.mu.Lock()
+	f, ok := h.feeds[uid]
+	h.mu.Unlock()
+	if !ok {
+		return nil
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	changes, err := store.Changes(ctx, uid, f.revision)
+	if err != nil {
+		return err
+	}
+	for _, change := range changes {
+		change.Record = models.RecordEncrypted{}
+		for w := range f.watchers {
+			select {
+			case w.changes <- change:
+			default:
+				w.err = errWatchOverflow
+				close(w.changes)
+				delete(f.watchers, w)
+			}
+		}
+		f.revision = change.Revision
+	}
+	return nil
+}
+
+// close drops all watchers, so their streams are finished before server stops.
+func (h *watchHub) close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for uid, f := range h.feeds {
+		f.mu.Lock()
+		for w := range f.watchers {
+			w.err = errWatchClosed
+			close(w.changes)
+		}
+		f.mu.Unlock()
+		delete(h.feeds, uid)
+	}
+}
+
+// Watch streams changes of records of User made after subscription, changes made after
+// since_revision are sent first.
+func (s *GRPCPrivate) Watch(in *pb.WatchRequest, stream pb.Private_WatchServer) error {
+	ctx := stream.Context()
+	ctxUID, _ := ctx.Value(ctxUIDKey).(int)
+	uid := models.UserID(ctxUID)
+	w, revision, err := s.watchers.subscribe(ctx, s.config.store, uid)
+	if errors.Is(err, errWatchClosed) {
+		return status.Error(codes.Unavailable, errorWatchClosed)
+	}
+	if err != nil {
+		slog.Error(errorWatch, "error", err)
+		return status.Error(codes.Internal, errorWatch)
+	}
+	defer s.watchers.unsubscribe(uid, w)
+	if since := in.GetSinceRevision(); since > 0 {
+		changes, er := s.config.store.Changes(ctx, uid, since)
+		if er != nil {
+			slog.Error(errorWatch, "error", er)
+			return status.Error(codes.Internal, errorWatch)
+		}
+		for _, change := range changes {
+			// later changes are received by watcher
+			if change.Revision > revision {
+				break
+			}
+			if err = stream.Send(watchEvent(change)); err != nil {
+				return err
+			}
+		}
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case change, ok := <-w.changes:
+			if !ok {
+				if errors.Is(w.err, errWatchOverflow) {
+					return status.Error(codes.ResourceExhausted, errorWatchOverflow)
+				}
+				return status.Error(codes.Unavailable, errorWatchClosed)
+			}
+			if err = stream.Send(watchEvent(change)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// watchEvent converts change to proto Change without item.
+func watchEvent(change models.Change) *pb.Change {
+	event := protoconv.ChangeToProto(change)
+	event.Item = nil
+	return event
+}
+
+// notify publishes changes of records of User to its watchers, changes are published even if
+// request is canceled after change.
+func (s *GRPCPrivate) notify(ctx context.Context, uid models.UserID) {
+	if s.watchers == nil {
+		return
+	}
+	if err := s.watchers.publish(context.WithoutCancel(ctx), s.config.store, uid); err != nil {
+		slog.Error(errorWatch, "error", err)
+	}
+}
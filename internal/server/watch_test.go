@@ -0,0 +1,182 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+	"github.com/sejo412/gophkeeper/internal/protoconv"
+	"github.com/sejo412/gophkeeper/internal/storage/memory"
+	pb "github.com/sejo412/gophkeeper/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// watchStream collects changes sent by Watch.
+type watchStream struct {
+	grpc.ServerStream
+	ctx     context.Context
+	changes chan *pb.Change
+}
+
+func (w *watchStream) Context() context.Context {
+	return w.ctx
+}
+
+func (w *watchStream) Send(change *pb.Change) error {
+	w.changes <- change
+	return nil
+}
+
+// watch starts Watch and waits for subscription, it returns stream and channel with result of Watch.
+func watch(ctx context.Context, t *testing.T, s *GRPCPrivate, since int64) (*watchStream, chan error) {
+	t.Helper()
+	stream := &watchStream{ctx: ctx, changes: make(chan *pb.Change, watchBuffer)}
+	done := make(chan error, 1)
+	watchers := func() int {
+		s.watchers.mu.Lock()
+		defer s.watchers.mu.Unlock()
+		count := 0
+		for _, f := range s.watchers.feeds {
+			f.mu.Lock()
+			count += len(f.watchers)
+			f.mu.Unlock()
+		}
+		return count
+	}
+	before := watchers()
+	go func() {
+		done <- s.Watch(&pb.WatchRequest{SinceRevision: proto.Int64(since)}, stream)
+	}()
+	for deadline := time.Now().Add(time.Second); watchers() == before; {
+		if time.Now().After(deadline) {
+			t.Fatalf("Watch() not subscribed")
+		}
+		time.Sleep(time.Millisecond)
+	}
+	return stream, done
+}
+
+func receive(t *testing.T, stream *watchStream) *pb.Change {
+	t.Helper()
+	select {
+	case change := <-stream.changes:
+		return change
+	case <-time.After(time.Second):
+		t.Fatalf("Watch() sent no change")
+		return nil
+	}
+}
+
+func TestGRPCPrivate_Watch(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	if err := store.Init(ctx); err != nil {
+		t.Fatal(err)
+	}
+	uid, err := store.NewUser(ctx, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	other, err := store.NewUser(ctx, "bob")
+	if err != nil {
+		t.Fatal(err)
+	}
+	text := models.RecordEncrypted{Text: models.TextEncrypted{Text: []byte("text"), Meta: []byte("meta")}}
+	if err = store.Add(ctx, uid, models.RecordText, text); err != nil {
+		t.Fatal(err)
+	}
+	s := &GRPCPrivate{config: privateConfig{store: store}, watchers: newWatchHub()}
+	userCtx := context.WithValue(ctx, ctxUIDKey, int(uid))
+	watchCtx, cancel := context.WithCancel(userCtx)
+	defer cancel()
+	stream, done := watch(watchCtx, t, s, 0)
+	otherStream, _ := watch(context.WithValue(watchCtx, ctxUIDKey, int(other)), t, s, 0)
+
+	create := &pb.AddRecordRequest{Type: pb.RecordType_TEXT.Enum(), Item: protoconv.RecordToProto(models.RecordText, text)}
+	if _, err = s.Create(userCtx, create); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	created := receive(t, stream)
+	if created.GetOp() != pb.ChangeOp_CHANGE_CREATE || created.GetType() != pb.RecordType_TEXT ||
+		created.GetRecordNumber() != 2 || created.GetRevision() != 2 || created.GetItem() != nil {
+		t.Errorf("Watch() sent %v, want creation of text 2 without item", created)
+	}
+	del := &pb.DeleteRecordRequest{Type: pb.RecordType_TEXT.Enum(), RecordNumber: proto.Int64(1)}
+	if _, err = s.Delete(userCtx, del); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if deleted := receive(t, stream); deleted.GetOp() != pb.ChangeOp_CHANGE_DELETE || deleted.GetRecordNumber() != 1 {
+		t.Errorf("Watch() sent %v, want deletion of text 1", deleted)
+	}
+	select {
+	case change := <-otherStream.changes:
+		t.Errorf("Watch() of other user sent %v, want nothing", change)
+	default:
+	}
+
+	// changes after since_revision are sent before new ones
+	resumed, _ := watch(watchCtx, t, s, 1)
+	if change := receive(t, resumed); change.GetRevision() != 2 || change.GetOp() != pb.ChangeOp_CHANGE_CREATE {
+		t.Errorf("Watch() since revision 1 sent %v, want change 2", change)
+	}
+	if change := receive(t, resumed); change.GetRevision() != 3 {
+		t.Errorf("Watch() since revision 1 sent %v, want change 3", change)
+	}
+
+	cancel()
+	if err = <-done; err != nil {
+		t.Errorf("Watch() after cancel error = %v", err)
+	}
+}
+
+func Test_watchHub(t *testing.T) {
+	ctx := context.Background()
+	store := memory.New()
+	if err := store.Init(ctx); err != nil {
+		t.Fatal(err)
+	}
+	uid, err := store.NewUser(ctx, "alice")
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := newWatchHub()
+	slow, revision, err := h.subscribe(ctx, store, uid)
+	if err != nil || revision != 0 {
+		t.Fatalf("subscribe() = %d, %v, want 0", revision, err)
+	}
+	for range watchBuffer + 1 {
+		if err = store.Add(ctx, uid, models.RecordText, models.RecordEncrypted{}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = h.publish(ctx, store, uid); err != nil {
+		t.Fatalf("publish() error = %v", err)
+	}
+	for range slow.changes {
+	}
+	if !errors.Is(slow.err, errWatchOverflow) {
+		t.Errorf("watcher error = %v, want %v", slow.err, errWatchOverflow)
+	}
+
+	w, revision, err := h.subscribe(ctx, store, uid)
+	if err != nil || revision != watchBuffer+1 {
+		t.Fatalf("subscribe() = %d, %v, want %d", revision, err, watchBuffer+1)
+	}
+	h.close()
+	if _, ok := <-w.changes; ok || !errors.Is(w.err, errWatchClosed) {
+		t.Errorf("watcher after close() = %v, want closed with %v", w.err, errWatchClosed)
+	}
+	if _, _, err = h.subscribe(ctx, store, uid); !errors.Is(err, errWatchClosed) {
+		t.Errorf("subscribe() after close() error = %v, want %v", err, errWatchClosed)
+	}
+	s := &GRPCPrivate{config: privateConfig{store: store}, watchers: h}
+	err = s.Watch(&pb.WatchRequest{}, &watchStream{ctx: context.WithValue(ctx, ctxUIDKey, int(uid))})
+	if status.Code(err) != codes.Unavailable {
+		t.Errorf("Watch() after close() error = %v, want %v", err, codes.Unavailable)
+	}
+}
@@ -0,0 +1,127 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+)
+
+// DisableUser disables or enables user, devices of disabled user are rejected by server.
+func (s *Storage) DisableUser(_ context.Context, uid models.UserID, disabled bool) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !disabled {
+		delete(s.disabled, uid)
+		return nil
+	}
+	if _, ok := s.disabled[uid]; !ok {
+		s.disabled[uid] = time.Now()
+	}
+	return nil
+}
+
+// IsUserDisabled returns true if user is disabled.
+func (s *Storage) IsUserDisabled(_ context.Context, uid models.UserID) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.disabled[uid]
+	return ok, nil
+}
+
+// DeleteUser deletes user with all its records, devices and enrollments. Issued certificates
+// are kept for revocation.
+func (s *Storage) DeleteUser(_ context.Context, uid models.UserID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.users[uid]; !ok {
+		return fmt.Errorf("user %d not found", uid)
+	}
+	for t, records := range s.records {
+		for id, r := range records {
+			if r.uid != uid {
+				continue
+			}
+			delete(records, id)
+			delete(s.versions[t], id)
+			if t == models.RecordBin {
+				delete(s.binChunks, id)
+			}
+		}
+	}
+	for key := range s.uploads {
+		if key.uid == uid {
+			delete(s.uploads, key)
+		}
+	}
+	for key, c := range s.changes {
+		if c.uid == uid {
+			delete(s.changes, key)
+		}
+	}
+	for id, device := range s.devices {
+		if device.UserID == uid {
+			delete(s.devices, id)
+		}
+	}
+	for code, enrollment := range s.enrollments {
+		if enrollment.UserID == uid {
+			delete(s.enrollments, code)
+		}
+	}
+	delete(s.disabled, uid)
+	delete(s.users, uid)
+	return nil
+}
+
+// Stats returns count and stored size of records by type for all users.
+func (s *Storage) Stats(_ context.Context) ([]models.UserStats, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	stats := make([]models.UserStats, 0, len(s.users))
+	index := make(map[models.UserID]int, len(s.users))
+	for i, id := range sortedKeys(s.users) {
+		stats = append(
+			stats, models.UserStats{
+				User: models.User{ID: id, Cn: s.users[id]}, Records: make(map[models.RecordType]models.RecordStats),
+			},
+		)
+		index[id] = i
+	}
+	for t, records := range s.records {
+		for id, r := range records {
+			i, ok := index[r.uid]
+			if !ok {
+				continue
+			}
+			rs := stats[i].Records[t]
+			rs.Count++
+			rs.Bytes += s.recordSize(t, id, r.data)
+			stats[i].Records[t] = rs
+		}
+	}
+	return stats, nil
+}
+
+// recordSize returns stored size of record with its chunks, s.mu must be locked.
+func (s *Storage) recordSize(t models.RecordType, id models.ID, rec models.RecordEncrypted) int64 {
+	var size int
+	switch t {
+	case models.RecordPassword:
+		p := rec.Password
+		size = len(p.Login) + len(p.Password) + len(p.Meta) + len(p.DataKey)
+	case models.RecordText:
+		size = len(rec.Text.Text) + len(rec.Text.Meta) + len(rec.Text.DataKey)
+	case models.RecordBin:
+		size = len(rec.Bin.Data) + len(rec.Bin.Meta) + len(rec.Bin.DataKey)
+		for _, chunk := range s.binChunks[id] {
+			size += len(chunk)
+		}
+	case models.RecordBank:
+		b := rec.Bank
+		size = len(b.Number) + len(b.Name) + len(b.Date) + len(b.Cvv) + len(b.Meta) + len(b.DataKey)
+	default:
+	}
+	return int64(size)
+}
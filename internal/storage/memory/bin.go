@@ -0,0 +1,145 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+)
+
+// uploadKey identifies upload, uploads of different users may have same id.
+type uploadKey struct {
+	uid models.UserID
+	id  string
+}
+
+// upload is a started upload with its received chunks.
+type upload struct {
+	models.Upload
+	chunks map[int64][]byte
+}
+
+// StartUpload begins new or resumes existing upload of chunked binary data
+// and returns count of already received chunks. Meta, UUID and data key of resumed upload are kept.
+func (s *Storage) StartUpload(_ context.Context, uid models.UserID, u models.Upload) (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.users[uid]; !ok {
+		return 0, fmt.Errorf("user id %d not exist", uid)
+	}
+	if u.RecordID != 0 {
+		if _, ok := s.liveRecord(uid, models.RecordBin, u.RecordID); !ok {
+			return 0, fmt.Errorf("%q with %d not found", models.RecordBin.String(), u.RecordID)
+		}
+	}
+	key := uploadKey{uid: uid, id: u.ID}
+	started, ok := s.uploads[key]
+	if !ok {
+		started = &upload{
+			Upload: models.Upload{
+				ID: u.ID, RecordID: u.RecordID, Meta: bytes.Clone(u.Meta), UUID: u.UUID, DataKey: bytes.Clone(u.DataKey),
+			},
+			chunks: make(map[int64][]byte),
+		}
+		s.uploads[key] = started
+	}
+	return int64(len(started.chunks)), nil
+}
+
+// UploadStatus returns started upload with count of received chunks, empty upload if not exists.
+func (s *Storage) UploadStatus(_ context.Context, uid models.UserID, uploadID string) (models.Upload, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	started, ok := s.uploads[uploadKey{uid: uid, id: uploadID}]
+	if !ok {
+		return models.Upload{ID: uploadID}, nil
+	}
+	result := started.Upload
+	result.Meta = bytes.Clone(started.Meta)
+	result.DataKey = bytes.Clone(started.DataKey)
+	result.Chunks = int64(len(started.chunks))
+	return result, nil
+}
+
+// UploadChunk saves chunk with sequence number seq to started upload.
+func (s *Storage) UploadChunk(_ context.Context, uid models.UserID, uploadID string, seq int64, chunk []byte) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	started, ok := s.uploads[uploadKey{uid: uid, id: uploadID}]
+	if !ok {
+		return fmt.Errorf("upload %q not found", uploadID)
+	}
+	if _, ok = started.chunks[seq]; ok {
+		return fmt.Errorf("failed save chunk %d of upload %q: chunk already saved", seq, uploadID)
+	}
+	started.chunks[seq] = bytes.Clone(chunk)
+	return nil
+}
+
+// CompleteUpload moves chunks of upload to new binary record or replaces data of existing one
+// like Update, replacing fails with models.ErrConflict if record has other revision than rev (if not 0).
+// Returns ID of record.
+func (s *Storage) CompleteUpload(
+	_ context.Context, uid models.UserID, uploadID string, rev int64,
+) (models.ID, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	key := uploadKey{uid: uid, id: uploadID}
+	started, ok := s.uploads[key]
+	if !ok {
+		return 0, fmt.Errorf("upload %q not found", uploadID)
+	}
+	bin := models.RecordEncrypted{
+		Bin: models.BinEncrypted{Data: []byte{}, Meta: started.Meta, UUID: started.UUID, DataKey: started.DataKey},
+	}
+	id := started.RecordID
+	if id == 0 {
+		id = s.addRecord(uid, models.RecordBin, bin)
+	} else {
+		r, exists := s.liveRecord(uid, models.RecordBin, id)
+		if !exists {
+			return 0, fmt.Errorf("%q with %d not found", models.RecordBin.String(), id)
+		}
+		if err := s.checkRevision(models.RecordBin, id, rev); err != nil {
+			return 0, err
+		}
+		s.addVersion(models.RecordBin, id, r.data)
+		s.records[models.RecordBin][id] = record{uid: uid, data: withID(cloneRecord(bin), id)}
+		s.saveChange(uid, models.RecordBin, id, models.ChangeUpdate)
+	}
+	s.binChunks[id] = started.chunks
+	delete(s.uploads, key)
+	return id, nil
+}
+
+// BinChunks returns count of chunks of binary record. Record saved by Add has one chunk.
+func (s *Storage) BinChunks(_ context.Context, uid models.UserID, id models.ID) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	r, ok := s.liveRecord(uid, models.RecordBin, id)
+	if !ok {
+		return 0, fmt.Errorf("%q with %d not found", models.RecordBin.String(), id)
+	}
+	count := int64(len(s.binChunks[id]))
+	if count == 0 && len(r.data.Bin.Data) > 0 {
+		return 1, nil
+	}
+	return count, nil
+}
+
+// BinChunk returns chunk with sequence number seq of binary record.
+func (s *Storage) BinChunk(_ context.Context, uid models.UserID, id models.ID, seq int64) ([]byte, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if r, ok := s.liveRecord(uid, models.RecordBin, id); ok {
+		if chunk, found := s.binChunks[id][seq]; found {
+			return bytes.Clone(chunk), nil
+		}
+		// data saved by Add is the only chunk
+		if seq == 0 && len(r.data.Bin.Data) > 0 {
+			return bytes.Clone(r.data.Bin.Data), nil
+		}
+	}
+	return nil, fmt.Errorf("chunk %d of %q with %d not found", seq, models.RecordBin.String(), id)
+}
@@ -0,0 +1,77 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+)
+
+// AddCertificate saves issued client certificate.
+func (s *Storage) AddCertificate(_ context.Context, cert models.Certificate) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.certificates[cert.Serial]; ok {
+		return fmt.Errorf("failed save certificate %s: already exists", cert.Serial)
+	}
+	cert.Issued = time.Unix(cert.Issued.Unix(), 0)
+	cert.NotAfter = time.Unix(cert.NotAfter.Unix(), 0)
+	cert.Revoked = false
+	s.certificates[cert.Serial] = cert
+	return nil
+}
+
+// Certificates returns all issued client certificates with their revocation state.
+func (s *Storage) Certificates(_ context.Context) ([]models.Certificate, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	result := make([]models.Certificate, 0, len(s.certificates))
+	for serial, cert := range s.certificates {
+		_, cert.Revoked = s.revocations[serial]
+		result = append(result, cert)
+	}
+	slices.SortFunc(
+		result, func(a, b models.Certificate) int {
+			return cmp.Or(a.Issued.Compare(b.Issued), cmp.Compare(a.Serial, b.Serial))
+		},
+	)
+	return result, nil
+}
+
+// Revoke saves revoked certificate, already revoked certificate keeps its first revocation.
+func (s *Storage) Revoke(_ context.Context, revocation models.Revocation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.revocations[revocation.Serial]; !ok {
+		revocation.Revoked = time.Unix(revocation.Revoked.Unix(), 0)
+		s.revocations[revocation.Serial] = revocation
+	}
+	return nil
+}
+
+// IsRevoked returns true if certificate with serial number is revoked.
+func (s *Storage) IsRevoked(_ context.Context, serial string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.revocations[serial]
+	return ok, nil
+}
+
+// Revocations returns all revoked certificates.
+func (s *Storage) Revocations(_ context.Context) ([]models.Revocation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	result := make([]models.Revocation, 0, len(s.revocations))
+	for _, revocation := range s.revocations {
+		result = append(result, revocation)
+	}
+	slices.SortFunc(
+		result, func(a, b models.Revocation) int {
+			return cmp.Or(a.Revoked.Compare(b.Revoked), cmp.Compare(a.Serial, b.Serial))
+		},
+	)
+	return result, nil
+}
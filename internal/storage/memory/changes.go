@@ -0,0 +1,99 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+)
+
+// changeKey identifies record of last change.
+type changeKey struct {
+	t  models.RecordType
+	id models.ID
+}
+
+// change is a last change of record, created is a revision of record creation.
+type change struct {
+	uid      models.UserID
+	revision int64
+	created  int64
+	deleted  bool
+}
+
+// Changes returns last changes of records of user with revision greater than since ordered by
+// revision. Created and updated records are returned with data, deleted records as tombstones.
+func (s *Storage) Changes(_ context.Context, uid models.UserID, since int64) ([]models.Change, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	changes := make([]models.Change, 0)
+	for key, c := range s.changes {
+		if c.uid != uid || c.revision <= since {
+			continue
+		}
+		result := models.Change{Revision: c.revision, Type: key.t, ID: key.id}
+		switch {
+		case c.deleted:
+			result.Op = models.ChangeDelete
+		case c.created > since:
+			result.Op = models.ChangeCreate
+		default:
+			result.Op = models.ChangeUpdate
+		}
+		if !c.deleted {
+			result.Record = cloneRecord(s.records[key.t][key.id].data)
+		}
+		changes = append(changes, result)
+	}
+	slices.SortFunc(
+		changes, func(a, b models.Change) int {
+			return cmp.Compare(a.Revision, b.Revision)
+		},
+	)
+	return changes, nil
+}
+
+// LastRevision returns revision of last change of records of user, zero if user has no changes.
+func (s *Storage) LastRevision(_ context.Context, uid models.UserID) (int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	var revision int64
+	for _, c := range s.changes {
+		if c.uid == uid {
+			revision = max(revision, c.revision)
+		}
+	}
+	return revision, nil
+}
+
+// saveChange saves change of record with next revision replacing previous change of record
+// and sets revision of record, s.mu must be locked.
+func (s *Storage) saveChange(uid models.UserID, t models.RecordType, id models.ID, op models.ChangeOp) {
+	s.lastRevision++
+	key := changeKey{t: t, id: id}
+	c := s.changes[key]
+	c.uid = uid
+	c.revision = s.lastRevision
+	if op == models.ChangeCreate {
+		c.created = s.lastRevision
+	}
+	c.deleted = op == models.ChangeDelete
+	s.changes[key] = c
+	r := s.records[t][id]
+	r.data.Password.Revision = s.lastRevision
+	r.data.Text.Revision = s.lastRevision
+	r.data.Bin.Revision = s.lastRevision
+	r.data.Bank.Revision = s.lastRevision
+	s.records[t][id] = r
+}
+
+// checkRevision returns models.ErrConflict if record has revision other than rev, zero rev skips
+// the check, s.mu must be locked.
+func (s *Storage) checkRevision(t models.RecordType, id models.ID, rev int64) error {
+	if revision := s.changes[changeKey{t: t, id: id}].revision; rev != 0 && revision != rev {
+		return fmt.Errorf("%q with id %d has revision %d, expected %d: %w", t.String(), id, revision, rev, models.ErrConflict)
+	}
+	return nil
+}
@@ -0,0 +1,17 @@
+package memory_test
+
+import (
+	"testing"
+
+	"github.com/sejo412/gophkeeper/internal/server"
+	"github.com/sejo412/gophkeeper/internal/storage/memory"
+	"github.com/sejo412/gophkeeper/internal/storage/storagetest"
+)
+
+func TestConformance(t *testing.T) {
+	storagetest.RunConformance(
+		t, func() server.Storage {
+			return memory.New()
+		},
+	)
+}
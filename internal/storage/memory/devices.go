@@ -0,0 +1,175 @@
+package memory
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+)
+
+// FindUser returns uid by user name, -1 if user not exists.
+func (s *Storage) FindUser(_ context.Context, name string) (models.UserID, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.userByName(name), nil
+}
+
+// Devices returns all devices of user.
+func (s *Storage) Devices(_ context.Context, uid models.UserID) ([]models.Device, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	devices := make([]models.Device, 0)
+	for _, id := range sortedKeys(s.devices) {
+		if device := s.devices[id]; device.UserID == uid {
+			devices = append(devices, device)
+		}
+	}
+	return devices, nil
+}
+
+// DeleteDevice deletes device of user, its certificate isn't accepted anymore.
+func (s *Storage) DeleteDevice(_ context.Context, uid models.UserID, id models.DeviceID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if device, ok := s.devices[id]; !ok || device.UserID != uid {
+		return fmt.Errorf("device %d not found", id)
+	}
+	delete(s.devices, id)
+	return nil
+}
+
+// NewEnrollment saves new device waiting for approval. Name of device must not be used
+// by other user, device or enrollment.
+func (s *Storage) NewEnrollment(_ context.Context, enrollment models.Enrollment) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.userByName(enrollment.Cn) != -1 || s.deviceByName(enrollment.Cn) != -1 {
+		return fmt.Errorf("name %q already used", enrollment.Cn)
+	}
+	if _, ok := s.enrollments[enrollment.Code]; ok {
+		return fmt.Errorf("failed create enrollment: code %q already used", enrollment.Code)
+	}
+	for _, e := range s.enrollments {
+		if e.Cn == enrollment.Cn {
+			return fmt.Errorf("failed create enrollment: name %q already used", enrollment.Cn)
+		}
+	}
+	s.enrollments[enrollment.Code] = models.Enrollment{
+		Code:        enrollment.Code,
+		UserID:      enrollment.UserID,
+		Cn:          enrollment.Cn,
+		CertRequest: bytes.Clone(enrollment.CertRequest),
+		Created:     time.Unix(enrollment.Created.Unix(), 0),
+	}
+	return nil
+}
+
+// Enrollment returns enrollment by code.
+func (s *Storage) Enrollment(_ context.Context, code string) (models.Enrollment, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	enrollment, ok := s.enrollments[code]
+	if !ok {
+		return models.Enrollment{}, fmt.Errorf("enrollment %q not found", code)
+	}
+	return enrollment, nil
+}
+
+// Enrollments returns not approved enrollments of user.
+func (s *Storage) Enrollments(_ context.Context, uid models.UserID) ([]models.Enrollment, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	enrollments := make([]models.Enrollment, 0)
+	for _, enrollment := range s.enrollments {
+		if enrollment.UserID == uid && !enrollment.Approved {
+			enrollments = append(enrollments, enrollment)
+		}
+	}
+	slices.SortFunc(
+		enrollments, func(a, b models.Enrollment) int {
+			return cmp.Or(a.Created.Compare(b.Created), cmp.Compare(a.Code, b.Code))
+		},
+	)
+	return enrollments, nil
+}
+
+// ApproveEnrollment saves vault key wrapped for enrolled device and adds device to user.
+// Legacy user without devices gets its first device, so it keeps access after approval.
+func (s *Storage) ApproveEnrollment(
+	_ context.Context, uid models.UserID, code string, vaultKey models.Encrypted,
+) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	enrollment, ok := s.enrollments[code]
+	if !ok || enrollment.UserID != uid || enrollment.Approved {
+		return fmt.Errorf("enrollment %q not found", code)
+	}
+	if s.deviceByName(enrollment.Cn) != -1 {
+		return fmt.Errorf("failed create device: name %q already used", enrollment.Cn)
+	}
+	now := time.Now()
+	if cn, exists := s.users[uid]; exists && !s.hasDevices(uid) {
+		s.addDevice(uid, cn, now)
+	}
+	enrollment.Approved = true
+	enrollment.VaultKey = bytes.Clone(vaultKey)
+	s.enrollments[code] = enrollment
+	s.addDevice(uid, enrollment.Cn, now)
+	return nil
+}
+
+// CompleteEnrollment deletes approved enrollment, returns error if enrollment is not approved
+// or already completed.
+func (s *Storage) CompleteEnrollment(_ context.Context, code string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if enrollment, ok := s.enrollments[code]; !ok || !enrollment.Approved {
+		return fmt.Errorf("approved enrollment %q not found", code)
+	}
+	delete(s.enrollments, code)
+	return nil
+}
+
+// PurgeEnrollments deletes enrollments created before given time.
+func (s *Storage) PurgeEnrollments(_ context.Context, before time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for code, enrollment := range s.enrollments {
+		if enrollment.Created.Unix() < before.Unix() {
+			delete(s.enrollments, code)
+		}
+	}
+	return nil
+}
+
+// addDevice adds device to user, s.mu must be locked.
+func (s *Storage) addDevice(uid models.UserID, cn string, created time.Time) {
+	s.lastDeviceID++
+	s.devices[s.lastDeviceID] = models.Device{
+		ID: s.lastDeviceID, UserID: uid, Cn: cn, Created: time.Unix(created.Unix(), 0),
+	}
+}
+
+// deviceByName returns id of device with name cn, -1 if not exists, s.mu must be locked.
+func (s *Storage) deviceByName(cn string) models.DeviceID {
+	for id, device := range s.devices {
+		if device.Cn == cn {
+			return id
+		}
+	}
+	return -1
+}
+
+// hasDevices returns true if user has devices, s.mu must be locked.
+func (s *Storage) hasDevices(uid models.UserID) bool {
+	for _, device := range s.devices {
+		if device.UserID == uid {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,373 @@
+// Package memory implements server.Storage in memory, data is lost when process exits.
+package memory
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+)
+
+// Storage implements server.Storage interface, it is safe for concurrent use.
+type Storage struct {
+	mu            sync.RWMutex
+	applied       []models.Migration
+	users         map[models.UserID]string
+	records       map[models.RecordType]map[models.ID]record
+	versions      map[models.RecordType]map[models.ID][]models.Version
+	binChunks     map[models.ID]map[int64][]byte
+	uploads       map[uploadKey]*upload
+	devices       map[models.DeviceID]models.Device
+	enrollments   map[string]models.Enrollment
+	certificates  map[string]models.Certificate
+	revocations   map[string]models.Revocation
+	invites       map[string]models.Invite
+	registrations map[string]models.Registration
+	disabled      map[models.UserID]time.Time
+	changes       map[changeKey]change
+	lastRevision  int64
+	lastUserID    models.UserID
+	lastRecordID  map[models.RecordType]models.ID
+	lastDeviceID  models.DeviceID
+}
+
+// record is a stored record with its owner, deleted record is kept in trash.
+type record struct {
+	uid     models.UserID
+	data    models.RecordEncrypted
+	deleted time.Time
+}
+
+// New constructs empty Storage.
+func New() *Storage {
+	s := &Storage{}
+	s.reset()
+	return s
+}
+
+// Init migrates storage to latest schema version.
+func (s *Storage) Init(ctx context.Context) error {
+	if _, err := s.Migrate(ctx); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Reset deletes all data and applied migrations.
+func (s *Storage) Reset(_ context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.reset()
+	return nil
+}
+
+// Close does nothing, data is kept until Storage is garbage collected.
+func (s *Storage) Close() error {
+	return nil
+}
+
+// ListAll returns id, meta for all records.
+func (s *Storage) ListAll(ctx context.Context, uid models.UserID) (models.RecordsEncrypted, error) {
+	result := models.RecordsEncrypted{}
+	for _, recType := range models.RecordTypes {
+		records, err := s.List(ctx, uid, recType)
+		if err != nil {
+			return models.RecordsEncrypted{}, fmt.Errorf("failed to list %q: %w", recType.String(), err)
+		}
+		switch recType {
+		case models.RecordPassword:
+			result.Password = records.Password
+		case models.RecordText:
+			result.Text = records.Text
+		case models.RecordBin:
+			result.Bin = records.Bin
+		case models.RecordBank:
+			result.Bank = records.Bank
+		default:
+		}
+	}
+	return result, nil
+}
+
+// List returns records id, meta by type ordered by id.
+func (s *Storage) List(_ context.Context, uid models.UserID, t models.RecordType) (models.RecordsEncrypted, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	records, ok := s.records[t]
+	if !ok {
+		return models.RecordsEncrypted{}, fmt.Errorf("invalid record type: %q", t.String())
+	}
+	result := models.RecordsEncrypted{
+		Password: []models.PasswordEncrypted{},
+		Text:     []models.TextEncrypted{},
+		Bin:      []models.BinEncrypted{},
+		Bank:     []models.BankEncrypted{},
+	}
+	for _, id := range sortedKeys(records) {
+		r := records[id]
+		if r.uid != uid || !r.deleted.IsZero() {
+			continue
+		}
+		switch t {
+		case models.RecordPassword:
+			p := r.data.Password
+			result.Password = append(
+				result.Password,
+				models.PasswordEncrypted{ID: id, Meta: p.Meta, UUID: p.UUID, DataKey: p.DataKey, Revision: p.Revision},
+			)
+		case models.RecordText:
+			v := r.data.Text
+			result.Text = append(
+				result.Text,
+				models.TextEncrypted{ID: id, Meta: v.Meta, UUID: v.UUID, DataKey: v.DataKey, Revision: v.Revision},
+			)
+		case models.RecordBin:
+			b := r.data.Bin
+			result.Bin = append(
+				result.Bin, models.BinEncrypted{ID: id, Meta: b.Meta, UUID: b.UUID, DataKey: b.DataKey, Revision: b.Revision},
+			)
+		case models.RecordBank:
+			b := r.data.Bank
+			result.Bank = append(
+				result.Bank,
+				models.BankEncrypted{ID: id, Meta: b.Meta, UUID: b.UUID, DataKey: b.DataKey, Revision: b.Revision},
+			)
+		default:
+		}
+	}
+	return result, nil
+}
+
+// Get returns object by id, userid and record type.
+func (s *Storage) Get(
+	_ context.Context, uid models.UserID, t models.RecordType,
+	id models.ID,
+) (models.RecordEncrypted, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	r, ok := s.liveRecord(uid, t, id)
+	if !ok {
+		return models.RecordEncrypted{}, fmt.Errorf("%q with %d not found", t.String(), id)
+	}
+	return cloneRecord(r.data), nil
+}
+
+// Delete moves object by id, userid and record type to trash of user.
+func (s *Storage) Delete(_ context.Context, uid models.UserID, t models.RecordType, id models.ID, rev int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	r, ok := s.liveRecord(uid, t, id)
+	if !ok {
+		return fmt.Errorf("nothing to delete")
+	}
+	if err := s.checkRevision(t, id, rev); err != nil {
+		return err
+	}
+	r.deleted = time.Unix(time.Now().Unix(), 0)
+	s.records[t][id] = r
+	s.saveChange(uid, t, id, models.ChangeDelete)
+	return nil
+}
+
+// Update updates object by id, userid and record type.
+func (s *Storage) Update(
+	_ context.Context, uid models.UserID, t models.RecordType, id models.ID,
+	rec models.RecordEncrypted, rev int64,
+) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.records[t]; !ok {
+		return errors.New("invalid record type")
+	}
+	r, ok := s.liveRecord(uid, t, id)
+	if !ok {
+		return fmt.Errorf("no records updated for userID %d", uid)
+	}
+	if err := s.checkRevision(t, id, rev); err != nil {
+		return err
+	}
+	if t == models.RecordBin && len(rec.Bin.Data) == 0 {
+		// data is not sent with meta of chunked bin, data and chunks are kept if encrypted by the same key
+		if rec.Bin.UUID != r.data.Bin.UUID || !bytes.Equal(rec.Bin.DataKey, r.data.Bin.DataKey) {
+			return fmt.Errorf("no records updated for userID %d", uid)
+		}
+		r.data.Bin.Meta = bytes.Clone(rec.Bin.Meta)
+		s.records[t][id] = r
+		s.saveChange(uid, t, id, models.ChangeUpdate)
+		return nil
+	}
+	s.addVersion(t, id, r.data)
+	s.records[t][id] = record{uid: uid, data: withID(cloneRecord(rec), id)}
+	s.saveChange(uid, t, id, models.ChangeUpdate)
+	if t == models.RecordBin {
+		delete(s.binChunks, id)
+	}
+	return nil
+}
+
+// Add adds encrypted record for user by record type.
+func (s *Storage) Add(
+	_ context.Context, uid models.UserID, t models.RecordType,
+	rec models.RecordEncrypted,
+) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.users[uid]; !ok {
+		return fmt.Errorf("user id %q not exist", uid)
+	}
+	if _, ok := s.records[t]; !ok {
+		return fmt.Errorf("invalid record type: %q", t)
+	}
+	s.addRecord(uid, t, rec)
+	return nil
+}
+
+// IsExist returns true if record exists, belongs to user and is not in trash.
+func (s *Storage) IsExist(_ context.Context, uid models.UserID, t models.RecordType, id models.ID) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if _, ok := s.records[t]; !ok {
+		return false, fmt.Errorf("invalid record type: %q", t.String())
+	}
+	_, ok := s.liveRecord(uid, t, id)
+	return ok, nil
+}
+
+// Users returns all registered users.
+func (s *Storage) Users(_ context.Context) ([]models.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	users := make([]models.User, 0, len(s.users))
+	for _, id := range sortedKeys(s.users) {
+		users = append(users, models.User{ID: id, Cn: s.users[id]})
+	}
+	return users, nil
+}
+
+// NewUser creates new user with its first device.
+func (s *Storage) NewUser(_ context.Context, cn string) (models.UserID, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.userByName(cn) != -1 || s.deviceByName(cn) != -1 {
+		return -1, fmt.Errorf("could not create user: name %q already used", cn)
+	}
+	s.lastUserID++
+	uid := s.lastUserID
+	s.users[uid] = cn
+	s.addDevice(uid, cn, time.Now())
+	return uid, nil
+}
+
+// IsUserExist returns true if user exists.
+func (s *Storage) IsUserExist(_ context.Context, user models.UserID) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	_, ok := s.users[user]
+	return ok, nil
+}
+
+// GetUserID returns uid by common name of device certificate. Legacy user without devices
+// is found by its own common name.
+func (s *Storage) GetUserID(_ context.Context, cn string) (models.UserID, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if id := s.deviceByName(cn); id != -1 {
+		return s.devices[id].UserID, nil
+	}
+	uid := s.userByName(cn)
+	if uid != -1 && s.hasDevices(uid) {
+		return -1, nil
+	}
+	return uid, nil
+}
+
+func (s *Storage) reset() {
+	s.applied = make([]models.Migration, 0)
+	s.users = make(map[models.UserID]string)
+	s.records = make(map[models.RecordType]map[models.ID]record, len(models.RecordTypes))
+	s.versions = make(map[models.RecordType]map[models.ID][]models.Version, len(models.RecordTypes))
+	s.lastRecordID = make(map[models.RecordType]models.ID, len(models.RecordTypes))
+	for _, t := range models.RecordTypes {
+		s.records[t] = make(map[models.ID]record)
+		s.versions[t] = make(map[models.ID][]models.Version)
+	}
+	s.binChunks = make(map[models.ID]map[int64][]byte)
+	s.uploads = make(map[uploadKey]*upload)
+	s.devices = make(map[models.DeviceID]models.Device)
+	s.enrollments = make(map[string]models.Enrollment)
+	s.certificates = make(map[string]models.Certificate)
+	s.revocations = make(map[string]models.Revocation)
+	s.invites = make(map[string]models.Invite)
+	s.registrations = make(map[string]models.Registration)
+	s.disabled = make(map[models.UserID]time.Time)
+	s.changes = make(map[changeKey]change)
+	s.lastRevision = 0
+	s.lastUserID = 0
+	s.lastDeviceID = 0
+}
+
+// addRecord saves record with next id of its type and returns id, s.mu must be locked.
+func (s *Storage) addRecord(uid models.UserID, t models.RecordType, rec models.RecordEncrypted) models.ID {
+	s.lastRecordID[t]++
+	id := s.lastRecordID[t]
+	s.records[t][id] = record{uid: uid, data: withID(cloneRecord(rec), id)}
+	s.saveChange(uid, t, id, models.ChangeCreate)
+	return id
+}
+
+// liveRecord returns record of user not moved to trash, s.mu must be locked.
+func (s *Storage) liveRecord(uid models.UserID, t models.RecordType, id models.ID) (record, bool) {
+	r, ok := s.records[t][id]
+	if !ok || r.uid != uid || !r.deleted.IsZero() {
+		return record{}, false
+	}
+	return r, true
+}
+
+// userByName returns id of user with name cn, -1 if not exists, s.mu must be locked.
+func (s *Storage) userByName(cn string) models.UserID {
+	for id, name := range s.users {
+		if name == cn {
+			return id
+		}
+	}
+	return -1
+}
+
+// sortedKeys returns sorted keys of map.
+func sortedKeys[K cmp.Ordered, V any](m map[K]V) []K {
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+// withID sets id of all record types.
+func withID(rec models.RecordEncrypted, id models.ID) models.RecordEncrypted {
+	rec.Password.ID = id
+	rec.Text.ID = id
+	rec.Bin.ID = id
+	rec.Bank.ID = id
+	return rec
+}
+
+// cloneRecord returns deep copy of record, stored data isn't changed by callers.
+func cloneRecord(rec models.RecordEncrypted) models.RecordEncrypted {
+	p, t, b, c := &rec.Password, &rec.Text, &rec.Bin, &rec.Bank
+	p.Login, p.Password, p.Meta, p.DataKey = bytes.Clone(p.Login), bytes.Clone(p.Password), bytes.Clone(p.Meta),
+		bytes.Clone(p.DataKey)
+	t.Text, t.Meta, t.DataKey = bytes.Clone(t.Text), bytes.Clone(t.Meta), bytes.Clone(t.DataKey)
+	b.Data, b.Meta, b.DataKey = bytes.Clone(b.Data), bytes.Clone(b.Meta), bytes.Clone(b.DataKey)
+	c.Number, c.Name, c.Date, c.Cvv = bytes.Clone(c.Number), bytes.Clone(c.Name), bytes.Clone(c.Date),
+		bytes.Clone(c.Cvv)
+	c.Meta, c.DataKey = bytes.Clone(c.Meta), bytes.Clone(c.DataKey)
+	return rec
+}
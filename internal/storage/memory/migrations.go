@@ -0,0 +1,71 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+)
+
+// migration is a schema change, memory storage has no schema, so migrations only keep
+// schema version equal to versions of other storages.
+type migration struct {
+	version     int
+	description string
+}
+
+// migrations are all schema changes, new schema change must be appended with next version.
+var migrations = []migration{
+	{version: 1, description: "initial schema"},
+	{version: 2, description: "record revisions"},
+	{version: 3, description: "record trash"},
+	{version: 4, description: "record changes"},
+	{version: 5, description: "record uuid and data key"},
+}
+
+// LatestSchemaVersion returns schema version supported by Storage.
+func LatestSchemaVersion() int {
+	return migrations[len(migrations)-1].version
+}
+
+// SchemaVersion returns current schema version of storage and latest supported version.
+func (s *Storage) SchemaVersion(_ context.Context) (int, int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.version(), LatestSchemaVersion(), nil
+}
+
+// Migrate applies not applied migrations and returns them.
+func (s *Storage) Migrate(_ context.Context) ([]models.Migration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	current, latest := s.version(), LatestSchemaVersion()
+	if current > latest {
+		return nil, fmt.Errorf("storage schema version %d is newer than supported %d", current, latest)
+	}
+	applied := make([]models.Migration, 0)
+	for _, m := range migrations {
+		if m.version <= current {
+			continue
+		}
+		applied = append(applied, models.Migration{Version: m.version, Description: m.description, Applied: time.Now()})
+	}
+	s.applied = append(s.applied, applied...)
+	return applied, nil
+}
+
+// Migrations returns applied migrations.
+func (s *Storage) Migrations(_ context.Context) ([]models.Migration, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return append(make([]models.Migration, 0, len(s.applied)), s.applied...), nil
+}
+
+// version returns version of last applied migration, s.mu must be locked.
+func (s *Storage) version() int {
+	if len(s.applied) == 0 {
+		return 0
+	}
+	return s.applied[len(s.applied)-1].Version
+}
@@ -0,0 +1,177 @@
+package memory
+
+import (
+	"bytes"
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+)
+
+// NewInvite saves new invite token.
+func (s *Storage) NewInvite(_ context.Context, invite models.Invite) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.invites[invite.Token]; ok {
+		return errors.New("failed create invite: token already exists")
+	}
+	s.invites[invite.Token] = models.Invite{
+		Token:   invite.Token,
+		Created: time.Unix(invite.Created.Unix(), 0),
+		Expires: time.Unix(invite.Expires.Unix(), 0),
+	}
+	return nil
+}
+
+// UseInvite marks not used and not expired invite as used by user with name cn.
+func (s *Storage) UseInvite(_ context.Context, token, cn string, now time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	invite, ok := s.invites[token]
+	if !ok || invite.Cn != "" || invite.Expires.Unix() <= now.Unix() {
+		return errors.New("invite not found, used or expired")
+	}
+	invite.Cn = cn
+	s.invites[token] = invite
+	return nil
+}
+
+// Invites returns all invites.
+func (s *Storage) Invites(_ context.Context) ([]models.Invite, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	invites := make([]models.Invite, 0, len(s.invites))
+	for _, invite := range s.invites {
+		invites = append(invites, invite)
+	}
+	slices.SortFunc(
+		invites, func(a, b models.Invite) int {
+			return cmp.Or(a.Created.Compare(b.Created), cmp.Compare(a.Token, b.Token))
+		},
+	)
+	return invites, nil
+}
+
+// NewRegistration saves registration of new user waiting for admin approval. Name of user
+// must not be used by other user, device or registration.
+func (s *Storage) NewRegistration(_ context.Context, registration models.Registration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.userByName(registration.Cn) != -1 || s.deviceByName(registration.Cn) != -1 {
+		return fmt.Errorf("name %q already used", registration.Cn)
+	}
+	if _, ok := s.registrations[registration.Code]; ok {
+		return fmt.Errorf("failed create registration: code %q already used", registration.Code)
+	}
+	for _, r := range s.registrations {
+		if r.Cn == registration.Cn {
+			return fmt.Errorf("failed create registration: name %q already used", registration.Cn)
+		}
+	}
+	s.registrations[registration.Code] = models.Registration{
+		Code:        registration.Code,
+		Cn:          registration.Cn,
+		CertRequest: bytes.Clone(registration.CertRequest),
+		Created:     time.Unix(registration.Created.Unix(), 0),
+	}
+	return nil
+}
+
+// Registration returns registration by code.
+func (s *Storage) Registration(_ context.Context, code string) (models.Registration, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	registration, ok := s.registrations[code]
+	if !ok {
+		return models.Registration{}, fmt.Errorf("registration %q not found", code)
+	}
+	return registration, nil
+}
+
+// Registrations returns registrations waiting for admin approval.
+func (s *Storage) Registrations(_ context.Context) ([]models.Registration, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	registrations := make([]models.Registration, 0)
+	for _, registration := range s.registrations {
+		if !registration.Approved {
+			registrations = append(registrations, registration)
+		}
+	}
+	slices.SortFunc(
+		registrations, func(a, b models.Registration) int {
+			return cmp.Or(a.Created.Compare(b.Created), cmp.Compare(a.Code, b.Code))
+		},
+	)
+	return registrations, nil
+}
+
+// ApproveRegistration approves registration waiting for admin approval.
+func (s *Storage) ApproveRegistration(_ context.Context, code string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	registration, ok := s.registrations[code]
+	if !ok || registration.Approved {
+		return fmt.Errorf("registration %q not found", code)
+	}
+	registration.Approved = true
+	s.registrations[code] = registration
+	return nil
+}
+
+// DeleteRegistration deletes rejected or completed registration.
+func (s *Storage) DeleteRegistration(_ context.Context, code string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.registrations[code]; !ok {
+		return fmt.Errorf("registration %q not found", code)
+	}
+	delete(s.registrations, code)
+	return nil
+}
+
+// CompleteRegistration spends approved registration, creates its user and saves certificate cert issued
+// for user at once.
+func (s *Storage) CompleteRegistration(_ context.Context, code string, cert models.Certificate) (
+	models.UserID, error,
+) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	registration, ok := s.registrations[code]
+	if !ok || !registration.Approved {
+		return -1, fmt.Errorf("registration %q not found", code)
+	}
+	if s.userByName(registration.Cn) != -1 || s.deviceByName(registration.Cn) != -1 {
+		return -1, fmt.Errorf("could not create user: name %q already used", registration.Cn)
+	}
+	if _, ok = s.certificates[cert.Serial]; ok {
+		return -1, fmt.Errorf("failed save certificate %s: already exists", cert.Serial)
+	}
+	delete(s.registrations, code)
+	s.lastUserID++
+	uid := s.lastUserID
+	s.users[uid] = registration.Cn
+	s.addDevice(uid, registration.Cn, time.Now())
+	cert.UserID = uid
+	cert.Issued = time.Unix(cert.Issued.Unix(), 0)
+	cert.NotAfter = time.Unix(cert.NotAfter.Unix(), 0)
+	cert.Revoked = false
+	s.certificates[cert.Serial] = cert
+	return uid, nil
+}
+
+// PurgeRegistrations deletes registrations created before time.
+func (s *Storage) PurgeRegistrations(_ context.Context, before time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for code, registration := range s.registrations {
+		if registration.Created.Unix() < before.Unix() {
+			delete(s.registrations, code)
+		}
+	}
+	return nil
+}
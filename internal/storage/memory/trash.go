@@ -0,0 +1,109 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+)
+
+// Trash returns deleted records of user with id, meta and keys only, last deleted first.
+func (s *Storage) Trash(_ context.Context, uid models.UserID) ([]models.TrashItem, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	items := make([]models.TrashItem, 0)
+	for _, t := range models.RecordTypes {
+		for _, id := range sortedKeys(s.records[t]) {
+			r := s.records[t][id]
+			if r.uid != uid || r.deleted.IsZero() {
+				continue
+			}
+			item := models.TrashItem{Type: t, Deleted: r.deleted}
+			switch t {
+			case models.RecordPassword:
+				p := r.data.Password
+				item.Record.Password = models.PasswordEncrypted{ID: id, Meta: p.Meta, UUID: p.UUID, DataKey: p.DataKey}
+			case models.RecordText:
+				v := r.data.Text
+				item.Record.Text = models.TextEncrypted{ID: id, Meta: v.Meta, UUID: v.UUID, DataKey: v.DataKey}
+			case models.RecordBin:
+				b := r.data.Bin
+				item.Record.Bin = models.BinEncrypted{ID: id, Meta: b.Meta, UUID: b.UUID, DataKey: b.DataKey}
+			case models.RecordBank:
+				b := r.data.Bank
+				item.Record.Bank = models.BankEncrypted{ID: id, Meta: b.Meta, UUID: b.UUID, DataKey: b.DataKey}
+			default:
+			}
+			item.Record = cloneRecord(item.Record)
+			items = append(items, item)
+		}
+	}
+	slices.SortStableFunc(
+		items, func(a, b models.TrashItem) int {
+			return cmp.Compare(b.Deleted.Unix(), a.Deleted.Unix())
+		},
+	)
+	return items, nil
+}
+
+// RestoreTrash moves deleted record back from trash of user.
+func (s *Storage) RestoreTrash(_ context.Context, uid models.UserID, t models.RecordType, id models.ID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.records[t]; !ok {
+		return fmt.Errorf("invalid record type: %q", t.String())
+	}
+	r, ok := s.records[t][id]
+	if !ok || r.uid != uid || r.deleted.IsZero() {
+		return fmt.Errorf("%q with %d not found in trash", t.String(), id)
+	}
+	r.deleted = time.Time{}
+	s.records[t][id] = r
+	// restored record appears again for clients
+	s.saveChange(uid, t, id, models.ChangeCreate)
+	return nil
+}
+
+// EmptyTrash permanently deletes all records in trash of user.
+func (s *Storage) EmptyTrash(_ context.Context, uid models.UserID) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.purgeTrash(
+		func(r record) bool {
+			return r.uid == uid
+		},
+	)
+	return nil
+}
+
+// PurgeTrash permanently deletes records of all users deleted before given time.
+func (s *Storage) PurgeTrash(_ context.Context, before time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.purgeTrash(
+		func(r record) bool {
+			return r.deleted.Unix() < before.Unix()
+		},
+	)
+	return nil
+}
+
+// purgeTrash permanently deletes records in trash matching filter with their chunks and versions,
+// s.mu must be locked.
+func (s *Storage) purgeTrash(match func(r record) bool) {
+	for t, records := range s.records {
+		for id, r := range records {
+			if r.deleted.IsZero() || !match(r) {
+				continue
+			}
+			delete(records, id)
+			delete(s.versions[t], id)
+			if t == models.RecordBin {
+				delete(s.binChunks, id)
+			}
+		}
+	}
+}
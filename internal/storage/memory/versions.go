@@ -0,0 +1,96 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+)
+
+// Versions returns previous versions of record with meta and keys only, newest first. Versions
+// of binary records are not kept.
+func (s *Storage) Versions(
+	_ context.Context, uid models.UserID, t models.RecordType, id models.ID,
+) ([]models.Version, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	versions := make([]models.Version, 0)
+	if r, ok := s.records[t][id]; !ok || r.uid != uid {
+		return versions, nil
+	}
+	for _, version := range slices.Backward(s.versions[t][id]) {
+		rec := cloneRecord(version.Record)
+		rec.Password = models.PasswordEncrypted{
+			ID: id, Meta: rec.Password.Meta, UUID: rec.Password.UUID, DataKey: rec.Password.DataKey,
+		}
+		rec.Text = models.TextEncrypted{ID: id, Meta: rec.Text.Meta, UUID: rec.Text.UUID, DataKey: rec.Text.DataKey}
+		rec.Bank = models.BankEncrypted{ID: id, Meta: rec.Bank.Meta, UUID: rec.Bank.UUID, DataKey: rec.Bank.DataKey}
+		versions = append(versions, models.Version{Number: version.Number, Created: version.Created, Record: rec})
+	}
+	return versions, nil
+}
+
+// Version returns previous version of record by version number.
+func (s *Storage) Version(
+	_ context.Context, uid models.UserID, t models.RecordType, id models.ID, number int64,
+) (models.Version, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if r, ok := s.records[t][id]; ok && r.uid == uid {
+		for _, version := range s.versions[t][id] {
+			if version.Number == number {
+				return models.Version{Number: number, Created: version.Created, Record: cloneRecord(version.Record)}, nil
+			}
+		}
+	}
+	return models.Version{}, fmt.Errorf("version %d of %q %d not found", number, t.String(), id)
+}
+
+// TrimVersions keeps only keep newest versions of record.
+func (s *Storage) TrimVersions(_ context.Context, t models.RecordType, id models.ID, keep int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if versions, ok := s.versions[t][id]; ok && len(versions) > keep {
+		s.versions[t][id] = versions[len(versions)-keep:]
+	}
+	return nil
+}
+
+// PurgeVersions deletes versions of all records created before given time.
+func (s *Storage) PurgeVersions(_ context.Context, before time.Time) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, records := range s.versions {
+		for id, versions := range records {
+			versions = slices.DeleteFunc(
+				versions, func(r models.Version) bool {
+					return r.Created.Unix() < before.Unix()
+				},
+			)
+			if len(versions) == 0 {
+				delete(records, id)
+				continue
+			}
+			records[id] = versions
+		}
+	}
+	return nil
+}
+
+// addVersion saves current version of record as its next version, s.mu must be locked.
+// Versions of binary records are not kept.
+func (s *Storage) addVersion(t models.RecordType, id models.ID, rec models.RecordEncrypted) {
+	if t == models.RecordBin {
+		return
+	}
+	versions := s.versions[t][id]
+	var number int64 = 1
+	if len(versions) > 0 {
+		number = versions[len(versions)-1].Number + 1
+	}
+	s.versions[t][id] = append(
+		versions, models.Version{Number: number, Created: time.Unix(time.Now().Unix(), 0), Record: cloneRecord(rec)},
+	)
+}
@@ -0,0 +1,146 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+)
+
+// recordSizes are SQL expressions of stored size of record by its table.
+var recordSizes = map[table]string{
+	tablePasswords: "COALESCE(LENGTH(login), 0) + COALESCE(LENGTH(password), 0) + COALESCE(LENGTH(meta), 0) + " +
+		"COALESCE(LENGTH(data_key), 0)",
+	tableTexts: "COALESCE(LENGTH(text), 0) + COALESCE(LENGTH(meta), 0) + COALESCE(LENGTH(data_key), 0)",
+	tableBins: "COALESCE(LENGTH(data), 0) + COALESCE(LENGTH(meta), 0) + COALESCE(LENGTH(data_key), 0) + " +
+		"(SELECT COALESCE(SUM(LENGTH(data)), 0) FROM " + tableBinChunksName + " WHERE bid = " + tableBinsName + ".id)",
+	tableBanks: "COALESCE(LENGTH(number), 0) + COALESCE(LENGTH(name), 0) + COALESCE(LENGTH(date), 0) + " +
+		"COALESCE(LENGTH(cvv), 0) + COALESCE(LENGTH(meta), 0) + COALESCE(LENGTH(data_key), 0)",
+}
+
+// DisableUser disables or enables user, devices of disabled user are rejected by server.
+func (s *Storage) DisableUser(ctx context.Context, uid models.UserID, disabled bool) error {
+	q := query{
+		query: queryWithTable("DELETE FROM %s WHERE uid = $1", tableDisabledUsers),
+		args:  []interface{}{uid},
+	}
+	if disabled {
+		q.query = queryWithTable("INSERT INTO %s(uid, disabled) VALUES ($1, $2) ON CONFLICT DO NOTHING", tableDisabledUsers)
+		q.args = append(q.args, time.Now().Unix())
+	}
+	if _, err := s.db.ExecContext(ctx, q.query, q.args...); err != nil {
+		return fmt.Errorf("failed disable user %d: %w", uid, err)
+	}
+	return nil
+}
+
+// IsUserDisabled returns true if user is disabled.
+func (s *Storage) IsUserDisabled(ctx context.Context, uid models.UserID) (bool, error) {
+	q := query{
+		query: queryWithTable("SELECT COUNT(*) FROM %s WHERE uid = $1", tableDisabledUsers),
+		args:  []interface{}{uid},
+	}
+	var count int
+	if err := s.db.QueryRowContext(ctx, q.query, q.args...).Scan(&count); err != nil {
+		return false, fmt.Errorf("failed check user disabled: %w", err)
+	}
+	return count > 0, nil
+}
+
+// DeleteUser deletes user with all its records, devices and enrollments. Issued certificates
+// are kept for revocation.
+func (s *Storage) DeleteUser(ctx context.Context, uid models.UserID) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	queries := []string{
+		fmt.Sprintf("DELETE FROM %s WHERE bid IN (SELECT id FROM %s WHERE uid = $1)", tableBinChunks, tableBins),
+		queryWithTable("DELETE FROM %s WHERE uid = $1", tableUploadChunks),
+	}
+	for _, t := range []table{
+		tableUploads, tablePasswords, tableTexts, tableBins, tableBanks, tableDevices, tableEnrollments,
+		tableDisabledUsers, tablePasswordVersions, tableTextVersions, tableBankVersions, tableChanges,
+	} {
+		queries = append(queries, queryWithTable("DELETE FROM %s WHERE uid = $1", t))
+	}
+	for _, q := range queries {
+		if _, err = tx.ExecContext(ctx, q, uid); err != nil {
+			return fmt.Errorf("failed delete user %d data: %w", uid, err)
+		}
+	}
+	res, err := tx.ExecContext(ctx, queryWithTable("DELETE FROM %s WHERE id = $1", tableUsers), uid)
+	if err != nil {
+		return fmt.Errorf("failed delete user %d: %w", uid, err)
+	}
+	rowsCount, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed get rows affected: %w", err)
+	}
+	if rowsCount == 0 {
+		return fmt.Errorf("user %d not found", uid)
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed delete user %d: %w", uid, err)
+	}
+	return nil
+}
+
+// Stats returns count and stored size of records by type for all users.
+func (s *Storage) Stats(ctx context.Context) ([]models.UserStats, error) {
+	users, err := s.Users(ctx)
+	if err != nil {
+		return nil, err
+	}
+	stats := make([]models.UserStats, len(users))
+	index := make(map[models.UserID]int, len(users))
+	for i, u := range users {
+		stats[i] = models.UserStats{User: u, Records: make(map[models.RecordType]models.RecordStats)}
+		index[u.ID] = i
+	}
+	for _, t := range []models.RecordType{
+		models.RecordPassword, models.RecordText, models.RecordBin, models.RecordBank,
+	} {
+		tbl := tables(t)
+		q := query{
+			table: tbl,
+			query: fmt.Sprintf(
+				"SELECT uid, COUNT(*), COALESCE(SUM(%s), 0)::BIGINT FROM %s GROUP BY uid", recordSizes[tbl], tbl.String(),
+			),
+		}
+		if err = s.recordStats(ctx, q, t, stats, index); err != nil {
+			return nil, err
+		}
+	}
+	return stats, nil
+}
+
+func (s *Storage) recordStats(
+	ctx context.Context, q query, t models.RecordType, stats []models.UserStats, index map[models.UserID]int,
+) error {
+	rows, err := s.db.QueryContext(ctx, q.query)
+	if err != nil {
+		return fmt.Errorf("failed query stats of %s: %w", q.table.String(), err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+	for rows.Next() {
+		var uid models.UserID
+		var rs models.RecordStats
+		if err = rows.Scan(&uid, &rs.Count, &rs.Bytes); err != nil {
+			return fmt.Errorf("failed scan stats of %s: %w", q.table.String(), err)
+		}
+		if i, ok := index[uid]; ok {
+			stats[i].Records[t] = rs
+		}
+	}
+	if err = rows.Err(); err != nil {
+		return fmt.Errorf("failed iterate stats of %s: %w", q.table.String(), err)
+	}
+	return nil
+}
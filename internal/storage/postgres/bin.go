@@ -0,0 +1,252 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+)
+
+// StartUpload begins new or resumes existing upload of chunked binary data
+// and returns count of already received chunks. Meta, UUID and data key of resumed upload are kept.
+func (s *Storage) StartUpload(ctx context.Context, uid models.UserID, upload models.Upload) (int64, error) {
+	if ok, err := s.IsUserExist(ctx, uid); err != nil || !ok {
+		return 0, fmt.Errorf("user id %d not exist or error: %w", uid, err)
+	}
+	if upload.RecordID != 0 {
+		ok, err := s.isOwner(ctx, uid, models.RecordBin, upload.RecordID)
+		if err != nil {
+			return 0, fmt.Errorf(
+				"failed check owner of %q with id %d: %w", models.RecordBin.String(), upload.RecordID, err,
+			)
+		}
+		if !ok {
+			return 0, fmt.Errorf("%q with %d not found", models.RecordBin.String(), upload.RecordID)
+		}
+	}
+	q := query{
+		query: queryWithTable(
+			"INSERT INTO %s(id, uid, bid, meta, uuid, data_key) VALUES ($1, $2, $3, $4, $5, $6) "+
+				"ON CONFLICT DO NOTHING",
+			tableUploads,
+		),
+		args: []any{upload.ID, uid, upload.RecordID, upload.Meta, upload.UUID, upload.DataKey},
+	}
+	if _, err := s.db.ExecContext(ctx, q.query, q.args...); err != nil {
+		return 0, fmt.Errorf("failed start upload %q: %w", upload.ID, err)
+	}
+	started, err := s.UploadStatus(ctx, uid, upload.ID)
+	return started.Chunks, err
+}
+
+// UploadStatus returns started upload with count of received chunks, empty upload if not exists.
+func (s *Storage) UploadStatus(ctx context.Context, uid models.UserID, uploadID string) (models.Upload, error) {
+	q := query{
+		query: queryWithTable(
+			"SELECT u.bid, u.meta, u.uuid, u.data_key, "+
+				"(SELECT COUNT(*) FROM upload_chunks WHERE uid = u.uid AND upload = u.id) "+
+				"FROM %s u WHERE u.uid = $1 AND u.id = $2",
+			tableUploads,
+		),
+		args: []any{uid, uploadID},
+	}
+	upload := models.Upload{ID: uploadID}
+	if err := s.db.QueryRowContext(ctx, q.query, q.args...).Scan(
+		&upload.RecordID, &upload.Meta, &upload.UUID, (*[]byte)(&upload.DataKey), &upload.Chunks,
+	); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return models.Upload{ID: uploadID}, nil
+		}
+		return models.Upload{}, fmt.Errorf("failed get upload %q: %w", uploadID, err)
+	}
+	return upload, nil
+}
+
+// UploadChunk saves chunk with sequence number seq to started upload.
+func (s *Storage) UploadChunk(ctx context.Context, uid models.UserID, uploadID string, seq int64, chunk []byte) error {
+	q := query{
+		query: queryWithTable(
+			"INSERT INTO %s(upload, uid, seq, data) SELECT id, uid, $1::BIGINT, $2::BYTEA FROM uploads "+
+				"WHERE uid = $3 AND id = $4",
+			tableUploadChunks,
+		),
+		args: []any{seq, chunk, uid, uploadID},
+	}
+	res, err := s.db.ExecContext(ctx, q.query, q.args...)
+	if err != nil {
+		return fmt.Errorf("failed save chunk %d of upload %q: %w", seq, uploadID, err)
+	}
+	rowsCount, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed get rows affected: %w", err)
+	}
+	if rowsCount == 0 {
+		return fmt.Errorf("upload %q not found", uploadID)
+	}
+	return nil
+}
+
+// CompleteUpload moves chunks of upload to new binary record or replaces data of existing one
+// like Update, replacing fails with models.ErrConflict if record has other revision than rev (if not 0).
+// Returns ID of record.
+func (s *Storage) CompleteUpload(
+	ctx context.Context, uid models.UserID, uploadID string, rev int64,
+) (models.ID, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	var id models.ID
+	var meta, dataKey []byte
+	var uuid string
+	op := models.ChangeUpdate
+	if err = tx.QueryRowContext(
+		ctx, queryWithTable("SELECT bid, meta, uuid, data_key FROM %s WHERE uid = $1 AND id = $2", tableUploads),
+		uid, uploadID,
+	).Scan(&id, &meta, &uuid, &dataKey); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, fmt.Errorf("upload %q not found", uploadID)
+		}
+		return 0, fmt.Errorf("failed get upload %q: %w", uploadID, err)
+	}
+	if id == 0 {
+		op = models.ChangeCreate
+		if err = tx.QueryRowContext(
+			ctx, queryWithTable(
+				"INSERT INTO %s(uid, data, meta, uuid, data_key) VALUES ($1, ''::BYTEA, $2, $3, $4) RETURNING id", tableBins,
+			),
+			uid, meta, uuid, dataKey,
+		).Scan(&id); err != nil {
+			return 0, fmt.Errorf("failed create %q: %w", models.RecordBin.String(), err)
+		}
+	} else {
+		if err = saveVersion(ctx, tx, uid, models.RecordBin, id); err != nil {
+			return 0, err
+		}
+		res, er := tx.ExecContext(
+			ctx, queryWithTable(
+				"UPDATE %s SET data = ''::BYTEA, meta = $1, uuid = $2, data_key = $3 WHERE id = $4 AND uid = $5 "+
+					"AND deleted IS NULL",
+				tableBins,
+			),
+			meta, uuid, dataKey, id, uid,
+		)
+		if er != nil {
+			return 0, fmt.Errorf("failed update %q with id %d: %w", models.RecordBin.String(), id, er)
+		}
+		if rowsCount, er := res.RowsAffected(); er != nil || rowsCount == 0 {
+			return 0, fmt.Errorf("%q with %d not found", models.RecordBin.String(), id)
+		}
+		if err = checkRevision(ctx, tx, models.RecordBin, id, rev); err != nil {
+			return 0, err
+		}
+		if err = deleteBinChunks(ctx, tx, id); err != nil {
+			return 0, err
+		}
+	}
+	queries := []query{
+		{
+			query: queryWithTable(
+				"INSERT INTO %s(bid, seq, data) SELECT $1::BIGINT, seq, data FROM upload_chunks WHERE uid = $2 AND upload = $3",
+				tableBinChunks,
+			),
+			args: []any{id, uid, uploadID},
+		},
+		{
+			query: queryWithTable("DELETE FROM %s WHERE uid = $1 AND upload = $2", tableUploadChunks),
+			args:  []any{uid, uploadID},
+		},
+		{
+			query: queryWithTable("DELETE FROM %s WHERE uid = $1 AND id = $2", tableUploads),
+			args:  []any{uid, uploadID},
+		},
+	}
+	for _, q := range queries {
+		if _, err = tx.ExecContext(ctx, q.query, q.args...); err != nil {
+			return 0, fmt.Errorf("failed complete upload %q: %w", uploadID, err)
+		}
+	}
+	if err = saveChange(ctx, tx, uid, models.RecordBin, id, op); err != nil {
+		return 0, err
+	}
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed commit upload %q: %w", uploadID, err)
+	}
+	return id, nil
+}
+
+// BinChunks returns count of chunks of binary record. Record saved by Add has one chunk.
+func (s *Storage) BinChunks(ctx context.Context, uid models.UserID, id models.ID) (int64, error) {
+	q := query{
+		query: queryWithTable(
+			"SELECT (SELECT COUNT(*) FROM bin_chunks WHERE bid = b.id), COALESCE(LENGTH(b.data), 0) > 0 "+
+				"FROM %s b WHERE b.id = $1 AND b.uid = $2 AND b.deleted IS NULL",
+			tableBins,
+		),
+		args: []any{id, uid},
+	}
+	var count int64
+	var hasData bool
+	if err := s.db.QueryRowContext(ctx, q.query, q.args...).Scan(&count, &hasData); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, fmt.Errorf("%q with %d not found", models.RecordBin.String(), id)
+		}
+		return 0, fmt.Errorf("failed count chunks of %d: %w", id, err)
+	}
+	if count == 0 && hasData {
+		return 1, nil
+	}
+	return count, nil
+}
+
+// BinChunk returns chunk with sequence number seq of binary record.
+func (s *Storage) BinChunk(ctx context.Context, uid models.UserID, id models.ID, seq int64) ([]byte, error) {
+	q := query{
+		query: queryWithTable(
+			"SELECT c.data FROM %s c JOIN bins b ON b.id = c.bid WHERE c.bid = $1 AND b.uid = $2 AND c.seq = $3 "+
+				"AND b.deleted IS NULL",
+			tableBinChunks,
+		),
+		args: []any{id, uid, seq},
+	}
+	var chunk []byte
+	err := s.db.QueryRowContext(ctx, q.query, q.args...).Scan(&chunk)
+	if err == nil {
+		return chunk, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("failed get chunk %d of %d: %w", seq, id, err)
+	}
+	if seq == 0 {
+		// data saved by Add is the only chunk
+		q = query{
+			query: queryWithTable("SELECT data FROM %s WHERE id = $1 AND uid = $2 AND deleted IS NULL AND LENGTH(data) > 0", tableBins),
+			args:  []any{id, uid},
+		}
+		err = s.db.QueryRowContext(ctx, q.query, q.args...).Scan(&chunk)
+		if err == nil {
+			return chunk, nil
+		}
+		if !errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("failed get data of %d: %w", id, err)
+		}
+	}
+	return nil, fmt.Errorf("chunk %d of %q with %d not found", seq, models.RecordBin.String(), id)
+}
+
+// deleteBinChunks deletes chunks of binary record in transaction of its update.
+func deleteBinChunks(ctx context.Context, tx *sql.Tx, id models.ID) error {
+	q := query{
+		query: queryWithTable("DELETE FROM %s WHERE bid = $1", tableBinChunks),
+		args:  []any{id},
+	}
+	if _, err := tx.ExecContext(ctx, q.query, q.args...); err != nil {
+		return fmt.Errorf("failed delete chunks of %d: %w", id, err)
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+)
+
+// AddCertificate saves issued client certificate.
+func (s *Storage) AddCertificate(ctx context.Context, cert models.Certificate) error {
+	q := addCertificateQuery(cert)
+	if _, err := s.db.ExecContext(ctx, q.query, q.args...); err != nil {
+		return fmt.Errorf("failed save certificate %s: %w", cert.Serial, err)
+	}
+	return nil
+}
+
+func addCertificateQuery(cert models.Certificate) query {
+	return query{
+		query: queryWithTable("INSERT INTO %s(serial, uid, cn, issued, not_after) VALUES ($1, $2, $3, $4, $5)",
+			tableCertificates),
+		args: []interface{}{cert.Serial, cert.UserID, cert.Cn, cert.Issued.Unix(), cert.NotAfter.Unix()},
+	}
+}
+
+// Certificates returns all issued client certificates with their revocation state.
+func (s *Storage) Certificates(ctx context.Context) ([]models.Certificate, error) {
+	q := query{
+		query: fmt.Sprintf(
+			"SELECT c.serial, c.uid, c.cn, c.issued, c.not_after, r.serial IS NOT NULL FROM %s c "+
+				"LEFT JOIN %s r ON r.serial = c.serial ORDER BY c.issued, c.serial",
+			tableCertificates, tableRevocations,
+		),
+	}
+	rows, err := s.db.QueryContext(ctx, q.query)
+	if err != nil {
+		return nil, fmt.Errorf("failed query certificates: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+	result := make([]models.Certificate, 0)
+	for rows.Next() {
+		var issued, notAfter int64
+		cert := models.Certificate{}
+		if err = rows.Scan(&cert.Serial, &cert.UserID, &cert.Cn, &issued, &notAfter, &cert.Revoked); err != nil {
+			return nil, fmt.Errorf("failed scan certificates: %w", err)
+		}
+		cert.Issued = time.Unix(issued, 0)
+		cert.NotAfter = time.Unix(notAfter, 0)
+		result = append(result, cert)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed iterate certificates: %w", err)
+	}
+	return result, nil
+}
+
+// Revoke saves revoked certificate, already revoked certificate keeps its first revocation.
+func (s *Storage) Revoke(ctx context.Context, revocation models.Revocation) error {
+	q := query{
+		query: queryWithTable("INSERT INTO %s(serial, cn, reason, revoked) VALUES ($1, $2, $3, $4) ON CONFLICT DO NOTHING",
+			tableRevocations),
+		args: []interface{}{revocation.Serial, revocation.Cn, revocation.Reason, revocation.Revoked.Unix()},
+	}
+	if _, err := s.db.ExecContext(ctx, q.query, q.args...); err != nil {
+		return fmt.Errorf("failed revoke certificate %s: %w", revocation.Serial, err)
+	}
+	return nil
+}
+
+// IsRevoked returns true if certificate with serial number is revoked.
+func (s *Storage) IsRevoked(ctx context.Context, serial string) (bool, error) {
+	q := query{
+		query: queryWithTable("SELECT COUNT(*) FROM %s WHERE serial = $1", tableRevocations),
+		args:  []interface{}{serial},
+	}
+	var count int
+	if err := s.db.QueryRowContext(ctx, q.query, q.args...).Scan(&count); err != nil {
+		return false, fmt.Errorf("failed check revocation: %w", err)
+	}
+	return count > 0, nil
+}
+
+// Revocations returns all revoked certificates.
+func (s *Storage) Revocations(ctx context.Context) ([]models.Revocation, error) {
+	q := query{
+		query: queryWithTable("SELECT serial, cn, reason, revoked FROM %s ORDER BY revoked", tableRevocations),
+	}
+	rows, err := s.db.QueryContext(ctx, q.query)
+	if err != nil {
+		return nil, fmt.Errorf("failed query revocations: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+	result := make([]models.Revocation, 0)
+	for rows.Next() {
+		var revoked int64
+		r := models.Revocation{}
+		if err = rows.Scan(&r.Serial, &r.Cn, &r.Reason, &revoked); err != nil {
+			return nil, fmt.Errorf("failed scan revocations: %w", err)
+		}
+		r.Revoked = time.Unix(revoked, 0)
+		result = append(result, r)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed iterate revocations: %w", err)
+	}
+	return result, nil
+}
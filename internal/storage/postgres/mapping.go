@@ -0,0 +1,171 @@
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+)
+
+var actions = map[models.RecordType]map[action]query{
+	models.RecordPassword: {
+		actionCreate: {
+			query: queryWithTable(
+				"INSERT INTO %s(uid, login, password, meta, uuid, data_key) VALUES ($1, $2, $3, $4, $5, $6)",
+				tablePasswords,
+			),
+		},
+		actionRead: {
+			query: queryWithTable(
+				"SELECT id, login, password, meta, uuid, data_key, revision FROM %s WHERE id = $1 AND uid = $2 AND deleted IS NULL", tablePasswords,
+			),
+		},
+		actionUpdate: {
+			query: queryWithTable(
+				"UPDATE %s SET login = $1, password = $2, meta = $3, uuid = $4, data_key = $5 WHERE id = $6 AND uid = $7 AND deleted IS NULL",
+				tablePasswords,
+			),
+		},
+		actionDelete: {
+			query: queryWithTable("UPDATE %s SET deleted = $1 WHERE id = $2 AND uid = $3 AND deleted IS NULL", tablePasswords),
+		},
+		actionList: {
+			query: queryWithTable("SELECT id, meta, uuid, data_key, revision FROM %s WHERE uid = $1 AND deleted IS NULL ORDER BY id", tablePasswords),
+		},
+		actionSaveVersion: {
+			query: fmt.Sprintf(
+				"INSERT INTO %s(rid, rev, uid, created, login, password, meta, uuid, data_key) "+
+					"SELECT id, (SELECT COALESCE(MAX(rev), 0) + 1 FROM %s WHERE rid = $1), uid, $2, login, password, meta, uuid, "+
+					"data_key FROM %s WHERE id = $1 AND uid = $3 AND deleted IS NULL",
+				tablePasswordVersions, tablePasswordVersions, tablePasswords,
+			),
+		},
+		actionVersions: {
+			query: queryWithTable(
+				"SELECT rev, created, meta, uuid, data_key FROM %s WHERE rid = $1 AND uid = $2 ORDER BY rev DESC", tablePasswordVersions,
+			),
+		},
+		actionVersion: {
+			query: queryWithTable(
+				"SELECT rid, login, password, meta, uuid, data_key, rev, created FROM %s WHERE rid = $1 AND uid = $2 AND rev = $3",
+				tablePasswordVersions,
+			),
+		},
+	},
+	models.RecordText: {
+		actionCreate: {
+			query: queryWithTable(
+				"INSERT INTO %s(uid, text, meta, uuid, data_key) VALUES ($1, $2, $3, $4, $5)", tableTexts,
+			),
+		},
+		actionRead: {
+			query: queryWithTable(
+				"SELECT id, text, meta, uuid, data_key, revision FROM %s WHERE id = $1 AND uid = $2 AND deleted IS NULL", tableTexts,
+			),
+		},
+		actionUpdate: {
+			query: queryWithTable(
+				"UPDATE %s SET text = $1, meta = $2, uuid = $3, data_key = $4 WHERE id = $5 AND uid = $6 AND deleted IS NULL", tableTexts,
+			),
+		},
+		actionDelete: {
+			query: queryWithTable("UPDATE %s SET deleted = $1 WHERE id = $2 AND uid = $3 AND deleted IS NULL", tableTexts),
+		},
+		actionList: {
+			query: queryWithTable("SELECT id, meta, uuid, data_key, revision FROM %s WHERE uid = $1 AND deleted IS NULL ORDER BY id", tableTexts),
+		},
+		actionSaveVersion: {
+			query: fmt.Sprintf(
+				"INSERT INTO %s(rid, rev, uid, created, text, meta, uuid, data_key) "+
+					"SELECT id, (SELECT COALESCE(MAX(rev), 0) + 1 FROM %s WHERE rid = $1), uid, $2, text, meta, uuid, "+
+					"data_key FROM %s WHERE id = $1 AND uid = $3 AND deleted IS NULL",
+				tableTextVersions, tableTextVersions, tableTexts,
+			),
+		},
+		actionVersions: {
+			query: queryWithTable(
+				"SELECT rev, created, meta, uuid, data_key FROM %s WHERE rid = $1 AND uid = $2 ORDER BY rev DESC", tableTextVersions,
+			),
+		},
+		actionVersion: {
+			query: queryWithTable(
+				"SELECT rid, text, meta, uuid, data_key, rev, created FROM %s WHERE rid = $1 AND uid = $2 AND rev = $3",
+				tableTextVersions,
+			),
+		},
+	},
+	models.RecordBin: {
+		actionCreate: {
+			query: queryWithTable(
+				"INSERT INTO %s(uid, data, meta, uuid, data_key) VALUES ($1, $2, $3, $4, $5)", tableBins,
+			),
+		},
+		actionRead: {
+			query: queryWithTable(
+				"SELECT id, data, meta, uuid, data_key, revision FROM %s WHERE id = $1 AND uid = $2 AND deleted IS NULL", tableBins,
+			),
+		},
+		actionUpdate: {
+			query: queryWithTable(
+				"UPDATE %s SET data = $1, meta = $2, uuid = $3, data_key = $4 WHERE id = $5 AND uid = $6 AND deleted IS NULL", tableBins,
+			),
+		},
+		actionUpdateMeta: {
+			query: queryWithTable(
+				"UPDATE %s SET meta = $1 WHERE id = $2 AND uid = $3 AND uuid = $4 AND data_key = $5 AND deleted IS NULL", tableBins,
+			),
+		},
+		actionDelete: {
+			query: queryWithTable("UPDATE %s SET deleted = $1 WHERE id = $2 AND uid = $3 AND deleted IS NULL", tableBins),
+		},
+		actionList: {
+			query: queryWithTable("SELECT id, meta, uuid, data_key, revision FROM %s WHERE uid = $1 AND deleted IS NULL ORDER BY id", tableBins),
+		},
+	},
+	models.RecordBank: {
+		actionCreate: {
+			query: queryWithTable(
+				"INSERT INTO %s(uid, number, name, date, cvv, meta, uuid, data_key) "+
+					"VALUES ($1, $2, $3, $4, $5, $6, $7, $8)",
+				tableBanks,
+			),
+		},
+		actionRead: {
+			query: queryWithTable(
+				"SELECT id, number, name, date, cvv, meta, uuid, data_key, revision FROM %s WHERE id = $1 AND uid = $2 AND deleted IS NULL",
+				tableBanks,
+			),
+		},
+		actionUpdate: {
+			query: queryWithTable(
+				"UPDATE %s SET number = $1, name = $2, date = $3, cvv = $4, meta = $5, uuid = $6, data_key = $7 "+
+					"WHERE id = $8 AND uid = $9 AND deleted IS NULL",
+				tableBanks,
+			),
+		},
+		actionDelete: {
+			query: queryWithTable("UPDATE %s SET deleted = $1 WHERE id = $2 AND uid = $3 AND deleted IS NULL", tableBanks),
+		},
+		actionList: {
+			query: queryWithTable("SELECT id, meta, uuid, data_key, revision FROM %s WHERE uid = $1 AND deleted IS NULL ORDER BY id", tableBanks),
+		},
+		actionSaveVersion: {
+			query: fmt.Sprintf(
+				"INSERT INTO %s(rid, rev, uid, created, number, name, date, cvv, meta, uuid, data_key) "+
+					"SELECT id, (SELECT COALESCE(MAX(rev), 0) + 1 FROM %s WHERE rid = $1), uid, $2, number, name, date, cvv, meta, uuid, "+
+					"data_key FROM %s WHERE id = $1 AND uid = $3 AND deleted IS NULL",
+				tableBankVersions, tableBankVersions, tableBanks,
+			),
+		},
+		actionVersions: {
+			query: queryWithTable(
+				"SELECT rev, created, meta, uuid, data_key FROM %s WHERE rid = $1 AND uid = $2 ORDER BY rev DESC", tableBankVersions,
+			),
+		},
+		actionVersion: {
+			query: queryWithTable(
+				"SELECT rid, number, name, date, cvv, meta, uuid, data_key, rev, created FROM %s WHERE rid = $1 AND uid = $2 AND rev = $3",
+				tableBankVersions,
+			),
+		},
+	},
+}
@@ -0,0 +1,424 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+)
+
+// migrationLock is a key of advisory lock which serializes migrations of several server instances.
+const migrationLock int64 = 0x676f70686b656570
+
+// migration is a schema change of database, migrations are applied in order of versions.
+type migration struct {
+	version     int
+	description string
+	queries     []query
+}
+
+// migrations are all schema changes, new schema change must be appended with next version.
+// Applied migration must never be changed. Versions match versions of SQLite storage.
+var migrations = []migration{
+	{
+		version:     1,
+		description: "initial schema",
+		queries: []query{
+			{
+				table: tableUsers,
+				query: queryWithTable(
+					"CREATE TABLE IF NOT EXISTS %s(id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY, "+
+						"cn TEXT UNIQUE NOT NULL)",
+					tableUsers,
+				),
+			},
+			{
+				table: tablePasswords,
+				query: queryWithTable(
+					"CREATE TABLE IF NOT EXISTS %s(id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY, "+
+						"uid BIGINT NOT NULL, login BYTEA, password BYTEA, meta BYTEA, uuid TEXT NOT NULL DEFAULT '', "+
+						"data_key BYTEA)",
+					tablePasswords,
+				),
+			},
+			{
+				table: tableTexts,
+				query: queryWithTable(
+					"CREATE TABLE IF NOT EXISTS %s(id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY, "+
+						"uid BIGINT NOT NULL, text BYTEA, meta BYTEA, uuid TEXT NOT NULL DEFAULT '', data_key BYTEA)",
+					tableTexts,
+				),
+			},
+			{
+				table: tableBins,
+				query: queryWithTable(
+					"CREATE TABLE IF NOT EXISTS %s(id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY, "+
+						"uid BIGINT NOT NULL, data BYTEA, meta BYTEA, uuid TEXT NOT NULL DEFAULT '', data_key BYTEA)",
+					tableBins,
+				),
+			},
+			{
+				table: tableBanks,
+				query: queryWithTable(
+					"CREATE TABLE IF NOT EXISTS %s(id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY, "+
+						"uid BIGINT NOT NULL, number BYTEA, name BYTEA, date BYTEA, cvv BYTEA, meta BYTEA, "+
+						"uuid TEXT NOT NULL DEFAULT '', data_key BYTEA)",
+					tableBanks,
+				),
+			},
+			{
+				table: tableBinChunks,
+				query: queryWithTable(
+					"CREATE TABLE IF NOT EXISTS %s(bid BIGINT NOT NULL, seq BIGINT NOT NULL, data BYTEA, "+
+						"PRIMARY KEY(bid, seq))",
+					tableBinChunks,
+				),
+			},
+			{
+				table: tableUploads,
+				query: queryWithTable(
+					"CREATE TABLE IF NOT EXISTS %s(id TEXT NOT NULL, uid BIGINT NOT NULL, bid BIGINT NOT NULL, "+
+						"meta BYTEA, uuid TEXT NOT NULL DEFAULT '', data_key BYTEA, PRIMARY KEY(uid, id))",
+					tableUploads,
+				),
+			},
+			{
+				table: tableUploadChunks,
+				query: queryWithTable(
+					"CREATE TABLE IF NOT EXISTS %s(upload TEXT NOT NULL, uid BIGINT NOT NULL, seq BIGINT NOT NULL, "+
+						"data BYTEA, PRIMARY KEY(uid, upload, seq))",
+					tableUploadChunks,
+				),
+			},
+			{
+				table: tableDevices,
+				query: queryWithTable(
+					"CREATE TABLE IF NOT EXISTS %s(id BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY, "+
+						"uid BIGINT NOT NULL, cn TEXT UNIQUE NOT NULL, created BIGINT NOT NULL)",
+					tableDevices,
+				),
+			},
+			{
+				table: tableEnrollments,
+				query: queryWithTable(
+					"CREATE TABLE IF NOT EXISTS %s(code TEXT PRIMARY KEY, uid BIGINT NOT NULL, cn TEXT UNIQUE NOT NULL, "+
+						"csr BYTEA NOT NULL, created BIGINT NOT NULL, approved BOOLEAN NOT NULL DEFAULT FALSE, "+
+						"vault_key BYTEA)",
+					tableEnrollments,
+				),
+			},
+			{
+				table: tableCertificates,
+				query: queryWithTable(
+					"CREATE TABLE IF NOT EXISTS %s(serial TEXT PRIMARY KEY, uid BIGINT NOT NULL, cn TEXT NOT NULL, "+
+						"issued BIGINT NOT NULL, not_after BIGINT NOT NULL)",
+					tableCertificates,
+				),
+			},
+			{
+				table: tableRevocations,
+				query: queryWithTable(
+					"CREATE TABLE IF NOT EXISTS %s(serial TEXT PRIMARY KEY, cn TEXT NOT NULL DEFAULT '', "+
+						"reason INTEGER NOT NULL, revoked BIGINT NOT NULL)",
+					tableRevocations,
+				),
+			},
+			{
+				table: tableInvites,
+				query: queryWithTable(
+					"CREATE TABLE IF NOT EXISTS %s(token TEXT PRIMARY KEY, created BIGINT NOT NULL, "+
+						"expires BIGINT NOT NULL, cn TEXT NOT NULL DEFAULT '')",
+					tableInvites,
+				),
+			},
+			{
+				table: tableRegistrations,
+				query: queryWithTable(
+					"CREATE TABLE IF NOT EXISTS %s(code TEXT PRIMARY KEY, cn TEXT UNIQUE NOT NULL, csr BYTEA NOT NULL, "+
+						"created BIGINT NOT NULL, approved BOOLEAN NOT NULL DEFAULT FALSE)",
+					tableRegistrations,
+				),
+			},
+			{
+				table: tableDisabledUsers,
+				query: queryWithTable(
+					"CREATE TABLE IF NOT EXISTS %s(uid BIGINT PRIMARY KEY, disabled BIGINT NOT NULL)",
+					tableDisabledUsers,
+				),
+			},
+		},
+	},
+	{
+		version:     2,
+		description: "record revisions",
+		queries: []query{
+			{
+				table: tablePasswordVersions,
+				query: queryWithTable(
+					"CREATE TABLE IF NOT EXISTS %s(rid BIGINT NOT NULL, rev BIGINT NOT NULL, uid BIGINT NOT NULL, "+
+						"created BIGINT NOT NULL, login BYTEA, password BYTEA, meta BYTEA, uuid TEXT NOT NULL DEFAULT '', "+
+						"data_key BYTEA, PRIMARY KEY(rid, rev))",
+					tablePasswordVersions,
+				),
+			},
+			{
+				table: tableTextVersions,
+				query: queryWithTable(
+					"CREATE TABLE IF NOT EXISTS %s(rid BIGINT NOT NULL, rev BIGINT NOT NULL, uid BIGINT NOT NULL, "+
+						"created BIGINT NOT NULL, text BYTEA, meta BYTEA, uuid TEXT NOT NULL DEFAULT '', data_key BYTEA, "+
+						"PRIMARY KEY(rid, rev))",
+					tableTextVersions,
+				),
+			},
+			{
+				table: tableBankVersions,
+				query: queryWithTable(
+					"CREATE TABLE IF NOT EXISTS %s(rid BIGINT NOT NULL, rev BIGINT NOT NULL, uid BIGINT NOT NULL, "+
+						"created BIGINT NOT NULL, number BYTEA, name BYTEA, date BYTEA, cvv BYTEA, meta BYTEA, "+
+						"uuid TEXT NOT NULL DEFAULT '', data_key BYTEA, PRIMARY KEY(rid, rev))",
+					tableBankVersions,
+				),
+			},
+		},
+	},
+	{
+		version:     3,
+		description: "record trash",
+		queries: []query{
+			{
+				table: tablePasswords,
+				query: queryWithTable("ALTER TABLE %s ADD COLUMN IF NOT EXISTS deleted BIGINT", tablePasswords),
+			},
+			{table: tableTexts, query: queryWithTable("ALTER TABLE %s ADD COLUMN IF NOT EXISTS deleted BIGINT", tableTexts)},
+			{table: tableBins, query: queryWithTable("ALTER TABLE %s ADD COLUMN IF NOT EXISTS deleted BIGINT", tableBins)},
+			{table: tableBanks, query: queryWithTable("ALTER TABLE %s ADD COLUMN IF NOT EXISTS deleted BIGINT", tableBanks)},
+		},
+	},
+	{
+		version:     4,
+		description: "record changes",
+		queries: append(
+			[]query{
+				{
+					table: tableChanges,
+					query: queryWithTable(
+						"CREATE TABLE IF NOT EXISTS %s(revision BIGINT GENERATED BY DEFAULT AS IDENTITY PRIMARY KEY, "+
+							"uid BIGINT NOT NULL, type INTEGER NOT NULL, rid BIGINT NOT NULL, created BIGINT NOT NULL, "+
+							"deleted BOOLEAN NOT NULL, UNIQUE(type, rid))",
+						tableChanges,
+					),
+				},
+			},
+			changesOfExisting()...,
+		),
+	},
+	{
+		version:     5,
+		description: "record uuid and data key",
+		queries:     recordColumns("uuid TEXT NOT NULL DEFAULT ''", "data_key BYTEA"),
+	},
+}
+
+// recordColumns returns queries which add columns with definitions to records and uploads of database
+// created before versioning without them, tables created by migration 1 already have them.
+func recordColumns(definitions ...string) []query {
+	queries := make([]query, 0)
+	for _, t := range []table{tablePasswords, tableTexts, tableBins, tableBanks, tableUploads} {
+		for _, definition := range definitions {
+			queries = append(
+				queries, query{
+					table: t,
+					query: fmt.Sprintf("ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s", t.String(), definition),
+				},
+			)
+		}
+	}
+	return queries
+}
+
+// changesOfExisting returns queries which add revision to records and save existing records
+// as their last changes.
+func changesOfExisting() []query {
+	queries := make([]query, 0)
+	for _, t := range models.RecordTypes {
+		queries = append(
+			queries,
+			query{
+				table: tables(t),
+				query: queryWithTable("ALTER TABLE %s ADD COLUMN IF NOT EXISTS revision BIGINT NOT NULL DEFAULT 0", tables(t)),
+			},
+			query{
+				table: tableChanges,
+				query: fmt.Sprintf(
+					"INSERT INTO %s(uid, type, rid, created, deleted) "+
+						"SELECT uid, $1::INTEGER, id, 0, deleted IS NOT NULL FROM %s ORDER BY id",
+					tableChanges, tables(t),
+				),
+				args: []any{int(t)},
+			},
+			query{
+				table: tables(t),
+				query: fmt.Sprintf(
+					"UPDATE %s SET revision = (SELECT revision FROM %s WHERE type = $1 AND rid = %s.id)",
+					tables(t), tableChanges, tables(t),
+				),
+				args: []any{int(t)},
+			},
+		)
+	}
+	return append(
+		queries, query{
+			table: tableChanges,
+			query: queryWithTable("UPDATE %s SET created = revision", tableChanges),
+		},
+	)
+}
+
+// LatestSchemaVersion returns schema version supported by Storage.
+func LatestSchemaVersion() int {
+	return migrations[len(migrations)-1].version
+}
+
+// SchemaVersion returns current schema version of database and latest supported version.
+func (s *Storage) SchemaVersion(ctx context.Context) (int, int, error) {
+	if err := s.createSchemaVersion(ctx); err != nil {
+		return 0, 0, err
+	}
+	q := query{
+		table: tableSchemaVersion,
+		query: queryWithTable("SELECT COALESCE(MAX(version), 0) FROM %s", tableSchemaVersion),
+	}
+	var version int
+	if err := s.db.QueryRowContext(ctx, q.query).Scan(&version); err != nil {
+		return 0, 0, fmt.Errorf("failed get schema version: %w", err)
+	}
+	return version, LatestSchemaVersion(), nil
+}
+
+// Migrate applies not applied migrations and returns them. Migrations of several server
+// instances are serialized, migration applied by other instance is skipped.
+func (s *Storage) Migrate(ctx context.Context) ([]models.Migration, error) {
+	current, latest, err := s.SchemaVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if current > latest {
+		return nil, fmt.Errorf("database schema version %d is newer than supported %d", current, latest)
+	}
+	applied := make([]models.Migration, 0)
+	for _, m := range migrations {
+		if m.version <= current {
+			continue
+		}
+		ok, er := s.migrate(ctx, m)
+		if er != nil {
+			return applied, er
+		}
+		if ok {
+			applied = append(applied, models.Migration{Version: m.version, Description: m.description, Applied: time.Now()})
+		}
+	}
+	return applied, nil
+}
+
+// Migrations returns applied migrations.
+func (s *Storage) Migrations(ctx context.Context) ([]models.Migration, error) {
+	if err := s.createSchemaVersion(ctx); err != nil {
+		return nil, err
+	}
+	q := query{
+		table: tableSchemaVersion,
+		query: queryWithTable("SELECT version, description, applied FROM %s ORDER BY version", tableSchemaVersion),
+	}
+	rows, err := s.db.QueryContext(ctx, q.query)
+	if err != nil {
+		return nil, fmt.Errorf("failed query migrations: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+	result := make([]models.Migration, 0)
+	for rows.Next() {
+		var applied int64
+		m := models.Migration{}
+		if err = rows.Scan(&m.Version, &m.Description, &applied); err != nil {
+			return nil, fmt.Errorf("failed scan migrations: %w", err)
+		}
+		m.Applied = time.Unix(applied, 0)
+		result = append(result, m)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed iterate migrations: %w", err)
+	}
+	return result, nil
+}
+
+// createSchemaVersion creates schema version table, concurrent creation by several server instances
+// is serialized.
+func (s *Storage) createSchemaVersion(ctx context.Context) error {
+	q := query{
+		table: tableSchemaVersion,
+		query: queryWithTable(
+			"CREATE TABLE IF NOT EXISTS %s(version INTEGER PRIMARY KEY, description TEXT NOT NULL, "+
+				"applied BIGINT NOT NULL)",
+			tableSchemaVersion,
+		),
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	if _, err = tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", migrationLock); err != nil {
+		return fmt.Errorf("failed lock migrations: %w", err)
+	}
+	if _, err = tx.ExecContext(ctx, q.query); err != nil {
+		return fmt.Errorf("failed create table %q: %w", q.table.String(), err)
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed create table %q: %w", q.table.String(), err)
+	}
+	return nil
+}
+
+// migrate applies migration with its version in one transaction, returns false if migration
+// was applied by other instance.
+func (s *Storage) migrate(ctx context.Context, m migration) (bool, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, fmt.Errorf("failed begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	if _, err = tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock($1)", migrationLock); err != nil {
+		return false, fmt.Errorf("failed lock migrations: %w", err)
+	}
+	var count int
+	if err = tx.QueryRowContext(
+		ctx, queryWithTable("SELECT COUNT(*) FROM %s WHERE version = $1", tableSchemaVersion), m.version,
+	).Scan(&count); err != nil {
+		return false, fmt.Errorf("failed check schema version %d: %w", m.version, err)
+	}
+	if count > 0 {
+		return false, nil
+	}
+	for _, q := range m.queries {
+		if _, err = tx.ExecContext(ctx, q.query, q.args...); err != nil {
+			return false, fmt.Errorf("failed migration %d on table %q: %w", m.version, q.table.String(), err)
+		}
+	}
+	if _, err = tx.ExecContext(
+		ctx, queryWithTable("INSERT INTO %s(version, description, applied) VALUES ($1, $2, $3)", tableSchemaVersion),
+		m.version, m.description, time.Now().Unix(),
+	); err != nil {
+		return false, fmt.Errorf("failed save schema version %d: %w", m.version, err)
+	}
+	if err = tx.Commit(); err != nil {
+		return false, fmt.Errorf("failed commit migration %d: %w", m.version, err)
+	}
+	return true, nil
+}
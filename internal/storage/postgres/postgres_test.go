@@ -0,0 +1,167 @@
+package postgres_test
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/server"
+	"github.com/sejo412/gophkeeper/internal/storage/postgres"
+	"github.com/sejo412/gophkeeper/internal/storage/storagetest"
+)
+
+// testDSNEnv is an environment variable with DSN of test database, its data is deleted by tests.
+const testDSNEnv = "GOPHKEEPER_TEST_POSTGRES_DSN"
+
+// testDSN is a DSN of test database, tests are skipped if empty.
+var testDSN string
+
+func TestMain(m *testing.M) {
+	testDSN = os.Getenv(testDSNEnv)
+	stop := func() {}
+	if testDSN == "" {
+		var err error
+		testDSN, stop, err = startPostgres()
+		if err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "local PostgreSQL not started, tests skipped: %v\n", err)
+		}
+	}
+	code := m.Run()
+	stop()
+	os.Exit(code)
+}
+
+// startPostgres starts temporary PostgreSQL instance by initdb and pg_ctl from PATH
+// or from /usr/lib/postgresql and returns its DSN and stop function.
+func startPostgres() (string, func(), error) {
+	noop := func() {}
+	if os.Geteuid() == 0 {
+		return "", noop, fmt.Errorf("PostgreSQL can't be run as root")
+	}
+	initdb, err := findBinary("initdb")
+	if err != nil {
+		return "", noop, err
+	}
+	pgCtl, err := findBinary("pg_ctl")
+	if err != nil {
+		return "", noop, err
+	}
+	dir, err := os.MkdirTemp(os.TempDir(), "postgres")
+	if err != nil {
+		return "", noop, err
+	}
+	cleanup := func() {
+		_ = os.RemoveAll(dir)
+	}
+	data := filepath.Join(dir, "data")
+	if out, er := exec.Command(
+		initdb, "-D", data, "-U", "postgres", "-A", "trust", "--no-sync",
+	).CombinedOutput(); er != nil {
+		cleanup()
+		return "", noop, fmt.Errorf("initdb: %w: %s", er, out)
+	}
+	port, err := freePort()
+	if err != nil {
+		cleanup()
+		return "", noop, err
+	}
+	if out, er := exec.Command(
+		pgCtl, "-D", data, "-l", filepath.Join(dir, "postgres.log"), "-w", "-o",
+		fmt.Sprintf("-p %d -k %s -c listen_addresses=127.0.0.1 -c fsync=off", port, dir), "start",
+	).CombinedOutput(); er != nil {
+		cleanup()
+		return "", noop, fmt.Errorf("pg_ctl start: %w: %s", er, out)
+	}
+	stop := func() {
+		_ = exec.Command(pgCtl, "-D", data, "-m", "immediate", "stop").Run()
+		cleanup()
+	}
+	return fmt.Sprintf("postgres://postgres@127.0.0.1:%d/postgres?sslmode=disable", port), stop, nil
+}
+
+func findBinary(name string) (string, error) {
+	if path, err := exec.LookPath(name); err == nil {
+		return path, nil
+	}
+	matches, _ := filepath.Glob(filepath.Join("/usr/lib/postgresql", "*", "bin", name))
+	if len(matches) == 0 {
+		return "", fmt.Errorf("%s not found", name)
+	}
+	return matches[len(matches)-1], nil
+}
+
+func freePort() (int, error) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		_ = l.Close()
+	}()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
+
+// newTestStorage returns Storage of test database without data.
+func newTestStorage() server.Storage {
+	store, err := postgres.New(testDSN)
+	if err != nil {
+		panic(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+	if err = store.Reset(ctx); err != nil {
+		panic(err)
+	}
+	return store
+}
+
+func TestConformance(t *testing.T) {
+	if testDSN == "" {
+		t.Skipf("PostgreSQL not available, set %s", testDSNEnv)
+	}
+	storagetest.RunConformance(t, newTestStorage)
+}
+
+func TestStorage_MigrateConcurrent(t *testing.T) {
+	if testDSN == "" {
+		t.Skipf("PostgreSQL not available, set %s", testDSNEnv)
+	}
+	ctx := context.Background()
+	_ = newTestStorage().Close()
+	errs := make(chan error, 4)
+	for range 4 {
+		go func() {
+			store, err := postgres.New(testDSN)
+			if err != nil {
+				errs <- err
+				return
+			}
+			defer func() {
+				_ = store.Close()
+			}()
+			_, err = store.Migrate(ctx)
+			errs <- err
+		}()
+	}
+	for range 4 {
+		if err := <-errs; err != nil {
+			t.Errorf("concurrent Migrate() error = %v", err)
+		}
+	}
+	store, err := postgres.New(testDSN)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer func() {
+		_ = store.Close()
+	}()
+	migrations, err := store.Migrations(ctx)
+	if err != nil || len(migrations) != postgres.LatestSchemaVersion() {
+		t.Errorf("Migrations() = %v, %v, want every migration applied once", migrations, err)
+	}
+}
@@ -0,0 +1,235 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+)
+
+// NewInvite saves new invite token.
+func (s *Storage) NewInvite(ctx context.Context, invite models.Invite) error {
+	q := query{
+		query: queryWithTable("INSERT INTO %s(token, created, expires) VALUES ($1, $2, $3)", tableInvites),
+		args:  []interface{}{invite.Token, invite.Created.Unix(), invite.Expires.Unix()},
+	}
+	if _, err := s.db.ExecContext(ctx, q.query, q.args...); err != nil {
+		return fmt.Errorf("failed create invite: %w", err)
+	}
+	return nil
+}
+
+// UseInvite marks not used and not expired invite as used by user with name cn.
+func (s *Storage) UseInvite(ctx context.Context, token, cn string, now time.Time) error {
+	q := query{
+		query: queryWithTable("UPDATE %s SET cn = $1 WHERE token = $2 AND cn = '' AND expires > $3", tableInvites),
+		args:  []interface{}{cn, token, now.Unix()},
+	}
+	res, err := s.db.ExecContext(ctx, q.query, q.args...)
+	if err != nil {
+		return fmt.Errorf("failed use invite: %w", err)
+	}
+	rowsCount, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed get rows affected: %w", err)
+	}
+	if rowsCount == 0 {
+		return errors.New("invite not found, used or expired")
+	}
+	return nil
+}
+
+// Invites returns all invites.
+func (s *Storage) Invites(ctx context.Context) ([]models.Invite, error) {
+	q := query{
+		query: queryWithTable("SELECT token, created, expires, cn FROM %s ORDER BY created", tableInvites),
+	}
+	rows, err := s.db.QueryContext(ctx, q.query)
+	if err != nil {
+		return nil, fmt.Errorf("failed query invites: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+	invites := make([]models.Invite, 0)
+	for rows.Next() {
+		var created, expires int64
+		invite := models.Invite{}
+		if err = rows.Scan(&invite.Token, &created, &expires, &invite.Cn); err != nil {
+			return nil, fmt.Errorf("failed scan invites: %w", err)
+		}
+		invite.Created = time.Unix(created, 0)
+		invite.Expires = time.Unix(expires, 0)
+		invites = append(invites, invite)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed iterate invites: %w", err)
+	}
+	return invites, nil
+}
+
+// NewRegistration saves registration of new user waiting for admin approval. Name of user
+// must not be used by other user, device or registration.
+func (s *Storage) NewRegistration(ctx context.Context, registration models.Registration) error {
+	q := query{
+		query: fmt.Sprintf(
+			"INSERT INTO %s(code, cn, csr, created) SELECT $1::TEXT, $2::TEXT, $3::BYTEA, $4::BIGINT "+
+				"WHERE NOT EXISTS (SELECT 1 FROM %s WHERE cn = $2) AND NOT EXISTS (SELECT 1 FROM %s WHERE cn = $2)",
+			tableRegistrations, tableUsers, tableDevices,
+		),
+		args: []interface{}{
+			registration.Code, registration.Cn, registration.CertRequest, registration.Created.Unix(),
+		},
+	}
+	res, err := s.db.ExecContext(ctx, q.query, q.args...)
+	if err != nil {
+		return fmt.Errorf("failed create registration: %w", err)
+	}
+	rowsCount, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed get rows affected: %w", err)
+	}
+	if rowsCount == 0 {
+		return fmt.Errorf("name %q already used", registration.Cn)
+	}
+	return nil
+}
+
+// Registration returns registration by code.
+func (s *Storage) Registration(ctx context.Context, code string) (models.Registration, error) {
+	q := query{
+		query: queryWithTable("SELECT code, cn, csr, created, approved FROM %s WHERE code = $1", tableRegistrations),
+		args:  []interface{}{code},
+	}
+	registration, err := scanRegistration(s.db.QueryRowContext(ctx, q.query, q.args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Registration{}, fmt.Errorf("registration %q not found", code)
+	}
+	if err != nil {
+		return models.Registration{}, fmt.Errorf("failed get registration: %w", err)
+	}
+	return registration, nil
+}
+
+// Registrations returns registrations waiting for admin approval.
+func (s *Storage) Registrations(ctx context.Context) ([]models.Registration, error) {
+	q := query{
+		query: queryWithTable(
+			"SELECT code, cn, csr, created, approved FROM %s WHERE NOT approved ORDER BY created", tableRegistrations,
+		),
+	}
+	rows, err := s.db.QueryContext(ctx, q.query)
+	if err != nil {
+		return nil, fmt.Errorf("failed query registrations: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+	registrations := make([]models.Registration, 0)
+	for rows.Next() {
+		registration, er := scanRegistration(rows)
+		if er != nil {
+			return nil, fmt.Errorf("failed scan registrations: %w", er)
+		}
+		registrations = append(registrations, registration)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed iterate registrations: %w", err)
+	}
+	return registrations, nil
+}
+
+// ApproveRegistration approves registration waiting for admin approval.
+func (s *Storage) ApproveRegistration(ctx context.Context, code string) error {
+	q := query{
+		query: queryWithTable("UPDATE %s SET approved = TRUE WHERE code = $1 AND NOT approved", tableRegistrations),
+		args:  []interface{}{code},
+	}
+	return s.execRegistration(ctx, q, code)
+}
+
+// DeleteRegistration deletes rejected or completed registration.
+func (s *Storage) DeleteRegistration(ctx context.Context, code string) error {
+	q := query{
+		query: queryWithTable("DELETE FROM %s WHERE code = $1", tableRegistrations),
+		args:  []interface{}{code},
+	}
+	return s.execRegistration(ctx, q, code)
+}
+
+// CompleteRegistration spends approved registration, creates its user and saves certificate cert issued
+// for user in one transaction.
+func (s *Storage) CompleteRegistration(ctx context.Context, code string, cert models.Certificate) (
+	models.UserID, error,
+) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return -1, fmt.Errorf("failed begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	var cn string
+	err = tx.QueryRowContext(
+		ctx, queryWithTable("DELETE FROM %s WHERE code = $1 AND approved RETURNING cn", tableRegistrations), code,
+	).Scan(&cn)
+	if errors.Is(err, sql.ErrNoRows) {
+		return -1, fmt.Errorf("registration %q not found", code)
+	}
+	if err != nil {
+		return -1, fmt.Errorf("failed delete registration %q: %w", code, err)
+	}
+	if cert.UserID, err = createUser(ctx, tx, cn); err != nil {
+		return -1, err
+	}
+	q := addCertificateQuery(cert)
+	if _, err = tx.ExecContext(ctx, q.query, q.args...); err != nil {
+		return -1, fmt.Errorf("failed save certificate %s: %w", cert.Serial, err)
+	}
+	if err = tx.Commit(); err != nil {
+		return -1, fmt.Errorf("failed complete registration %q: %w", code, err)
+	}
+	return cert.UserID, nil
+}
+
+// PurgeRegistrations deletes registrations created before time.
+func (s *Storage) PurgeRegistrations(ctx context.Context, before time.Time) error {
+	q := query{
+		query: queryWithTable("DELETE FROM %s WHERE created < $1", tableRegistrations),
+		args:  []interface{}{before.Unix()},
+	}
+	if _, err := s.db.ExecContext(ctx, q.query, q.args...); err != nil {
+		return fmt.Errorf("failed purge registrations: %w", err)
+	}
+	return nil
+}
+
+func (s *Storage) execRegistration(ctx context.Context, q query, code string) error {
+	res, err := s.db.ExecContext(ctx, q.query, q.args...)
+	if err != nil {
+		return fmt.Errorf("failed update registration %q: %w", code, err)
+	}
+	rowsCount, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed get rows affected: %w", err)
+	}
+	if rowsCount == 0 {
+		return fmt.Errorf("registration %q not found", code)
+	}
+	return nil
+}
+
+func scanRegistration(row scanner) (models.Registration, error) {
+	var created int64
+	registration := models.Registration{}
+	if err := row.Scan(
+		&registration.Code, &registration.Cn, &registration.CertRequest, &created, &registration.Approved,
+	); err != nil {
+		return models.Registration{}, err
+	}
+	registration.Created = time.Unix(created, 0)
+	return registration, nil
+}
@@ -0,0 +1,165 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+)
+
+// trashTypes are types of records moved to trash on delete.
+var trashTypes = []models.RecordType{
+	models.RecordPassword, models.RecordText, models.RecordBin, models.RecordBank,
+}
+
+// Trash returns deleted records of user with id, meta and keys only, last deleted first.
+func (s *Storage) Trash(ctx context.Context, uid models.UserID) ([]models.TrashItem, error) {
+	items := make([]models.TrashItem, 0)
+	for _, t := range trashTypes {
+		rows, err := s.db.QueryContext(
+			ctx, queryWithTable(
+				"SELECT id, meta, uuid, data_key, deleted FROM %s WHERE uid = $1 AND deleted IS NOT NULL", tables(t),
+			),
+			uid,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed query trash of %q: %w", t.String(), err)
+		}
+		for rows.Next() {
+			var id models.ID
+			var meta []byte
+			var uuid string
+			var dataKey []byte
+			var deleted int64
+			if err = rows.Scan(&id, &meta, &uuid, &dataKey, &deleted); err != nil {
+				_ = rows.Close()
+				return nil, fmt.Errorf("failed scan trash: %w", err)
+			}
+			items = append(items, trashItem(t, id, meta, uuid, dataKey, deleted))
+		}
+		err = rows.Err()
+		_ = rows.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed iterate trash: %w", err)
+		}
+	}
+	slices.SortStableFunc(
+		items, func(a, b models.TrashItem) int {
+			return b.Deleted.Compare(a.Deleted)
+		},
+	)
+	return items, nil
+}
+
+// RestoreTrash moves deleted record back from trash of user.
+func (s *Storage) RestoreTrash(ctx context.Context, uid models.UserID, t models.RecordType, id models.ID) error {
+	tableName := tables(t)
+	if tableName == tableUnknown {
+		return fmt.Errorf("invalid record type: %q", t.String())
+	}
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	res, err := tx.ExecContext(
+		ctx, queryWithTable("UPDATE %s SET deleted = NULL WHERE id = $1 AND uid = $2 AND deleted IS NOT NULL", tableName),
+		id, uid,
+	)
+	if err != nil {
+		return fmt.Errorf("failed restore %q with id %d: %w", t.String(), id, err)
+	}
+	rowsCount, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed get rows affected: %w", err)
+	}
+	if rowsCount == 0 {
+		return fmt.Errorf("%q with %d not found in trash", t.String(), id)
+	}
+	// restored record appears again for clients
+	if err = saveChange(ctx, tx, uid, t, id, models.ChangeCreate); err != nil {
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed restore %q with id %d: %w", t.String(), id, err)
+	}
+	return nil
+}
+
+// EmptyTrash permanently deletes all records in trash of user.
+func (s *Storage) EmptyTrash(ctx context.Context, uid models.UserID) error {
+	return s.purgeTrash(ctx, "uid = $1", uid)
+}
+
+// PurgeTrash permanently deletes records of all users deleted before given time.
+func (s *Storage) PurgeTrash(ctx context.Context, before time.Time) error {
+	return s.purgeTrash(ctx, "deleted < $1", before.Unix())
+}
+
+// purgeTrash permanently deletes records in trash matching condition with their chunks and versions
+// in one transaction.
+func (s *Storage) purgeTrash(ctx context.Context, condition string, arg any) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	for _, t := range trashTypes {
+		trashed := queryWithTable("SELECT id FROM %s WHERE deleted IS NOT NULL AND ", tables(t)) + condition
+		queries := make([]query, 0, 3)
+		if t == models.RecordBin {
+			queries = append(
+				queries, query{
+					table: tableBinChunks,
+					query: fmt.Sprintf("DELETE FROM %s WHERE bid IN (%s)", tableBinChunks, trashed),
+				},
+			)
+		}
+		if versions := versionTables(t); versions != tableUnknown {
+			queries = append(
+				queries, query{
+					table: versions,
+					query: fmt.Sprintf("DELETE FROM %s WHERE rid IN (%s)", versions, trashed),
+				},
+			)
+		}
+		queries = append(
+			queries, query{
+				table: tables(t),
+				query: queryWithTable("DELETE FROM %s WHERE deleted IS NOT NULL AND ", tables(t)) + condition,
+			},
+		)
+		for _, q := range queries {
+			if _, err = tx.ExecContext(ctx, q.query, arg); err != nil {
+				return fmt.Errorf("failed purge trash of %q: %w", q.table.String(), err)
+			}
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed commit purge of trash: %w", err)
+	}
+	return nil
+}
+
+// trashItem returns trash item of record type t with id, meta and keys only.
+func trashItem(t models.RecordType, id models.ID, meta []byte, uuid string, dataKey []byte, deleted int64) models.TrashItem {
+	item := models.TrashItem{Type: t, Deleted: time.Unix(deleted, 0)}
+	switch t {
+	case models.RecordPassword:
+		item.Record.Password = models.PasswordEncrypted{ID: id, Meta: meta, UUID: uuid, DataKey: dataKey}
+	case models.RecordText:
+		item.Record.Text = models.TextEncrypted{ID: id, Meta: meta, UUID: uuid, DataKey: dataKey}
+	case models.RecordBin:
+		item.Record.Bin = models.BinEncrypted{ID: id, Meta: meta, UUID: uuid, DataKey: dataKey}
+	case models.RecordBank:
+		item.Record.Bank = models.BankEncrypted{ID: id, Meta: meta, UUID: uuid, DataKey: dataKey}
+	default:
+	}
+	return item
+}
@@ -0,0 +1,121 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+)
+
+// Versions returns previous versions of record with meta and keys only, newest first. Versions
+// of binary records are not kept.
+func (s *Storage) Versions(
+	ctx context.Context, uid models.UserID, t models.RecordType, id models.ID,
+) ([]models.Version, error) {
+	versions := make([]models.Version, 0)
+	q, ok := actions[t][actionVersions]
+	if !ok {
+		return versions, nil
+	}
+	rows, err := s.db.QueryContext(ctx, q.query, id, uid)
+	if err != nil {
+		return nil, fmt.Errorf("failed query versions of %q %d: %w", t.String(), id, err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+	for rows.Next() {
+		var number, created int64
+		var meta []byte
+		var uuid string
+		var dataKey []byte
+		if err = rows.Scan(&number, &created, &meta, &uuid, &dataKey); err != nil {
+			return nil, fmt.Errorf("failed scan versions: %w", err)
+		}
+		version := models.Version{Number: number, Created: time.Unix(created, 0)}
+		switch t {
+		case models.RecordPassword:
+			version.Record.Password = models.PasswordEncrypted{ID: id, Meta: meta, UUID: uuid, DataKey: dataKey}
+		case models.RecordText:
+			version.Record.Text = models.TextEncrypted{ID: id, Meta: meta, UUID: uuid, DataKey: dataKey}
+		case models.RecordBank:
+			version.Record.Bank = models.BankEncrypted{ID: id, Meta: meta, UUID: uuid, DataKey: dataKey}
+		default:
+		}
+		versions = append(versions, version)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed iterate versions: %w", err)
+	}
+	return versions, nil
+}
+
+// Version returns previous version of record by version number.
+func (s *Storage) Version(
+	ctx context.Context, uid models.UserID, t models.RecordType, id models.ID, number int64,
+) (models.Version, error) {
+	q, ok := actions[t][actionVersion]
+	if !ok {
+		return models.Version{}, fmt.Errorf("versions of %q are not kept", t.String())
+	}
+	var created int64
+	version := models.Version{}
+	err := scanRecord(s.db.QueryRowContext(ctx, q.query, id, uid, number), t, &version.Record, &version.Number, &created)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Version{}, fmt.Errorf("version %d of %q %d not found", number, t.String(), id)
+	}
+	if err != nil {
+		return models.Version{}, fmt.Errorf("failed get version %d of %q %d: %w", number, t.String(), id, err)
+	}
+	version.Created = time.Unix(created, 0)
+	return version, nil
+}
+
+// TrimVersions keeps only keep newest versions of record, versions of binary records are not kept.
+func (s *Storage) TrimVersions(ctx context.Context, t models.RecordType, id models.ID, keep int) error {
+	versions := versionTables(t)
+	if versions == tableUnknown {
+		return nil
+	}
+	if _, err := s.db.ExecContext(
+		ctx, fmt.Sprintf(
+			"DELETE FROM %s WHERE rid = $1 AND rev <= (SELECT MAX(rev) FROM %s WHERE rid = $1) - $2",
+			versions, versions,
+		), id, keep,
+	); err != nil {
+		return fmt.Errorf("failed trim versions of %q %d: %w", t.String(), id, err)
+	}
+	return nil
+}
+
+// PurgeVersions deletes versions of all records created before given time.
+func (s *Storage) PurgeVersions(ctx context.Context, before time.Time) error {
+	for _, t := range []table{tablePasswordVersions, tableTextVersions, tableBankVersions} {
+		if _, err := s.db.ExecContext(
+			ctx, queryWithTable("DELETE FROM %s WHERE created < $1", t), before.Unix(),
+		); err != nil {
+			return fmt.Errorf("failed purge %q: %w", t.String(), err)
+		}
+	}
+	return nil
+}
+
+// saveVersion saves current version of record as its next version in transaction of its update.
+// Versions of types without actionSaveVersion (binary records) are not kept.
+func saveVersion(ctx context.Context, tx *sql.Tx, uid models.UserID, t models.RecordType, id models.ID) error {
+	q, ok := actions[t][actionSaveVersion]
+	if !ok {
+		return nil
+	}
+	// record is locked, so concurrent updates get different version numbers
+	if _, err := tx.ExecContext(ctx, actions[t][actionRead].query+" FOR UPDATE", id, uid); err != nil {
+		return fmt.Errorf("failed lock %q %d: %w", t.String(), id, err)
+	}
+	if _, err := tx.ExecContext(ctx, q.query, id, time.Now().Unix(), uid); err != nil {
+		return fmt.Errorf("failed save version of %q %d: %w", t.String(), id, err)
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+)
+
+func TestStorage_Admin(t *testing.T) {
+	ctx := context.Background()
+	s, err := New(filepath.Join(t.TempDir(), "admin.db"))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer func() {
+		_ = s.Close()
+	}()
+	if err = s.Init(ctx); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	uid, err := s.NewUser(ctx, testUser1.Cn)
+	if err != nil {
+		t.Fatalf("NewUser() error = %v", err)
+	}
+	other, err := s.NewUser(ctx, testUser2.Cn)
+	if err != nil {
+		t.Fatalf("NewUser() error = %v", err)
+	}
+	record := models.RecordEncrypted{
+		Text: models.TextEncrypted{Text: models.Encrypted("text"), Meta: models.Encrypted("meta"), UUID: "u1"},
+	}
+	for _, id := range []models.UserID{uid, uid, other} {
+		if err = s.Add(ctx, id, models.RecordText, record); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+
+	stats, err := s.Stats(ctx)
+	if err != nil || len(stats) != 2 {
+		t.Fatalf("Stats() = %v, %v, want 2 users", stats, err)
+	}
+	if got := stats[0].Records[models.RecordText]; got.Count != 2 || got.Bytes != 16 {
+		t.Errorf("Stats() texts of %s = %+v, want 2 records of 16 bytes", stats[0].Cn, got)
+	}
+	if got := stats[0].Records[models.RecordPassword]; got.Count != 0 {
+		t.Errorf("Stats() passwords of %s = %+v, want empty", stats[0].Cn, got)
+	}
+
+	if err = s.DisableUser(ctx, uid, true); err != nil {
+		t.Fatalf("DisableUser() error = %v", err)
+	}
+	if disabled, _ := s.IsUserDisabled(ctx, uid); !disabled {
+		t.Errorf("IsUserDisabled() = false, want true")
+	}
+	if disabled, _ := s.IsUserDisabled(ctx, other); disabled {
+		t.Errorf("IsUserDisabled() of other user = true, want false")
+	}
+	if err = s.DisableUser(ctx, uid, false); err != nil {
+		t.Fatalf("DisableUser() error = %v", err)
+	}
+	if disabled, _ := s.IsUserDisabled(ctx, uid); disabled {
+		t.Errorf("IsUserDisabled() after enable = true, want false")
+	}
+
+	if err = s.DeleteUser(ctx, uid); err != nil {
+		t.Fatalf("DeleteUser() error = %v", err)
+	}
+	if err = s.DeleteUser(ctx, uid); err == nil {
+		t.Errorf("DeleteUser() of deleted user error = nil, want error")
+	}
+	if id, _ := s.GetUserID(ctx, testUser1.Cn); id != -1 {
+		t.Errorf("GetUserID() of deleted user = %v, want -1", id)
+	}
+	stats, _ = s.Stats(ctx)
+	if len(stats) != 1 || stats[0].Records[models.RecordText].Count != 1 {
+		t.Errorf("Stats() after delete = %v, want one user with one record", stats)
+	}
+}
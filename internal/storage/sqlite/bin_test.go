@@ -0,0 +1,111 @@
+package sqlite
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+)
+
+func TestStorage_Upload(t *testing.T) {
+	ctx := context.Background()
+	s, err := New(filepath.Join(t.TempDir(), "bin.db"))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer func() {
+		_ = s.Close()
+	}()
+	if err = s.Init(ctx); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	uid, err := s.NewUser(ctx, testUser1.Cn)
+	if err != nil {
+		t.Fatalf("NewUser() error = %v", err)
+	}
+	chunks := [][]byte{[]byte("chunk0"), []byte("chunk1"), []byte("chunk2")}
+
+	upload := models.Upload{
+		ID:      "upload",
+		Meta:    models.Encrypted("meta"),
+		UUID:    "uuid",
+		DataKey: models.Encrypted("key"),
+	}
+	notExist := upload
+	notExist.RecordID = models.ID(42)
+	if _, err = s.StartUpload(ctx, uid, notExist); err == nil {
+		t.Errorf("StartUpload() for not existing record error = nil, want error")
+	}
+	got, err := s.StartUpload(ctx, uid, upload)
+	if err != nil || got != 0 {
+		t.Fatalf("StartUpload() = %v, %v, want 0, nil", got, err)
+	}
+	if err = s.UploadChunk(ctx, uid, "upload", 0, chunks[0]); err != nil {
+		t.Fatalf("UploadChunk() error = %v", err)
+	}
+	if err = s.UploadChunk(ctx, uid, "unknown", 0, chunks[0]); err == nil {
+		t.Errorf("UploadChunk() for unknown upload error = nil, want error")
+	}
+	// resume interrupted upload
+	resumed := upload
+	resumed.UUID = "other"
+	resumed.DataKey = models.Encrypted("other")
+	got, err = s.StartUpload(ctx, uid, resumed)
+	if err != nil || got != 1 {
+		t.Fatalf("StartUpload() resume = %v, %v, want 1, nil", got, err)
+	}
+	wantStatus := upload
+	wantStatus.Chunks = 1
+	if status, er := s.UploadStatus(ctx, uid, "upload"); er != nil || !reflect.DeepEqual(status, wantStatus) {
+		t.Errorf("UploadStatus() = %v, %v, want %v, nil", status, er, wantStatus)
+	}
+	for seq := got; seq < int64(len(chunks)); seq++ {
+		if err = s.UploadChunk(ctx, uid, "upload", seq, chunks[seq]); err != nil {
+			t.Fatalf("UploadChunk() error = %v", err)
+		}
+	}
+	id, err := s.CompleteUpload(ctx, uid, "upload", 0)
+	if err != nil {
+		t.Fatalf("CompleteUpload() error = %v", err)
+	}
+	if status, er := s.UploadStatus(ctx, uid, "upload"); er != nil || status.Chunks != 0 {
+		t.Errorf("UploadStatus() after complete = %v, %v, want 0 chunks, nil", status, er)
+	}
+	if got, err = s.BinChunks(ctx, uid, id); err != nil || got != int64(len(chunks)) {
+		t.Fatalf("BinChunks() = %v, %v, want %v, nil", got, err, len(chunks))
+	}
+	if record, er := s.Get(ctx, uid, models.RecordBin, id); er != nil || record.Bin.UUID != "uuid" ||
+		string(record.Bin.DataKey) != "key" {
+		t.Errorf("Get() uuid, data key = %v, %q, %v, want %v, %q, nil", record.Bin.UUID, record.Bin.DataKey, er, "uuid", "key")
+	}
+	for seq, want := range chunks {
+		chunk, er := s.BinChunk(ctx, uid, id, int64(seq))
+		if er != nil || !bytes.Equal(chunk, want) {
+			t.Errorf("BinChunk(%d) = %q, %v, want %q, nil", seq, chunk, er, want)
+		}
+	}
+	if _, err = s.BinChunk(ctx, models.UserID(42), id, 0); err == nil {
+		t.Errorf("BinChunk() of another user error = nil, want error")
+	}
+
+	// record created by Add is a single chunk
+	if err = s.Add(
+		ctx, uid, models.RecordBin,
+		models.RecordEncrypted{Bin: models.BinEncrypted{Data: models.Encrypted("data"), Meta: models.Encrypted("meta")}},
+	); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if got, err = s.BinChunks(ctx, uid, id+1); err != nil || got != 1 {
+		t.Errorf("BinChunks() legacy = %v, %v, want 1, nil", got, err)
+	}
+
+	if err = s.Delete(ctx, uid, models.RecordBin, id, 0); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if got, err = s.BinChunks(ctx, uid, id); err == nil && got != 0 {
+		t.Errorf("BinChunks() after delete = %v, want 0 or error", got)
+	}
+}
@@ -0,0 +1,67 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+)
+
+func TestStorage_Revoke(t *testing.T) {
+	ctx := context.Background()
+	s, err := New(filepath.Join(t.TempDir(), "certificates.db"))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer func() {
+		_ = s.Close()
+	}()
+	if err = s.Init(ctx); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	cert := models.Certificate{
+		Serial:   "1a2b",
+		UserID:   models.UserID(1),
+		Cn:       "laptop",
+		Issued:   time.Unix(1000, 0),
+		NotAfter: time.Unix(2000, 0),
+	}
+	if err = s.AddCertificate(ctx, cert); err != nil {
+		t.Fatalf("AddCertificate() error = %v", err)
+	}
+	if err = s.AddCertificate(ctx, cert); err == nil {
+		t.Errorf("AddCertificate() with same serial error = nil, want error")
+	}
+	certs, err := s.Certificates(ctx)
+	if err != nil || len(certs) != 1 || certs[0] != cert {
+		t.Errorf("Certificates() = %v, %v, want [%v]", certs, err, cert)
+	}
+
+	if revoked, _ := s.IsRevoked(ctx, cert.Serial); revoked {
+		t.Errorf("IsRevoked() before revoke = true, want false")
+	}
+	revocation := models.Revocation{
+		Serial:  cert.Serial,
+		Cn:      cert.Cn,
+		Reason:  models.RevocationKeyCompromise,
+		Revoked: time.Unix(1500, 0),
+	}
+	if err = s.Revoke(ctx, revocation); err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+	// repeated revocation keeps first one
+	again := revocation
+	again.Reason = models.RevocationSuperseded
+	if err = s.Revoke(ctx, again); err != nil {
+		t.Fatalf("Revoke() again error = %v", err)
+	}
+	if revoked, _ := s.IsRevoked(ctx, cert.Serial); !revoked {
+		t.Errorf("IsRevoked() after revoke = false, want true")
+	}
+	revocations, err := s.Revocations(ctx)
+	if err != nil || len(revocations) != 1 || revocations[0] != revocation {
+		t.Errorf("Revocations() = %v, %v, want [%v]", revocations, err, revocation)
+	}
+}
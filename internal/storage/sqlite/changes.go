@@ -0,0 +1,149 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+)
+
+// Changes returns last changes of records of user with revision greater than since ordered by
+// revision. Created and updated records are returned with data, deleted records as tombstones.
+func (s *Storage) Changes(ctx context.Context, uid models.UserID, since int64) ([]models.Change, error) {
+	// records are read in the same transaction as changes to get their state at revision of change
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	rows, err := tx.QueryContext(
+		ctx, queryWithTable(
+			"SELECT revision, type, rid, created, deleted FROM %s WHERE uid = ? AND revision > ? ORDER BY revision",
+			tableChanges,
+		),
+		uid, since,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed query changes: %w", err)
+	}
+	changes := make([]models.Change, 0)
+	for rows.Next() {
+		var change models.Change
+		var created int64
+		var deleted bool
+		if err = rows.Scan(&change.Revision, &change.Type, &change.ID, &created, &deleted); err != nil {
+			_ = rows.Close()
+			return nil, fmt.Errorf("failed scan changes: %w", err)
+		}
+		change.Op = changeOp(since, created, deleted)
+		changes = append(changes, change)
+	}
+	err = rows.Err()
+	_ = rows.Close()
+	if err != nil {
+		return nil, fmt.Errorf("failed iterate changes: %w", err)
+	}
+	for i, change := range changes {
+		if change.Op == models.ChangeDelete {
+			continue
+		}
+		rec := &changes[i].Record
+		if err = scanRecord(
+			tx.QueryRowContext(ctx, actions[change.Type][actionRead].query, change.ID, uid), change.Type, rec,
+			revisionOf(rec, change.Type),
+		); err != nil {
+			return nil, fmt.Errorf("failed get %q with id %d: %w", change.Type.String(), change.ID, err)
+		}
+	}
+	return changes, nil
+}
+
+// LastRevision returns revision of last change of records of user, zero if user has no changes.
+func (s *Storage) LastRevision(ctx context.Context, uid models.UserID) (int64, error) {
+	var revision int64
+	if err := s.db.QueryRowContext(
+		ctx, queryWithTable("SELECT COALESCE(MAX(revision), 0) FROM %s WHERE uid = ?", tableChanges), uid,
+	).Scan(&revision); err != nil {
+		return 0, fmt.Errorf("failed get last revision of user %d: %w", uid, err)
+	}
+	return revision, nil
+}
+
+// changeOp returns operation of last change of record for client which knows revision since.
+func changeOp(since, created int64, deleted bool) models.ChangeOp {
+	switch {
+	case deleted:
+		return models.ChangeDelete
+	case created > since:
+		return models.ChangeCreate
+	default:
+		return models.ChangeUpdate
+	}
+}
+
+// saveChange saves change of record with next revision replacing previous change of record
+// and sets revision of record. Revision of creation is kept by updates and deletion.
+func saveChange(
+	ctx context.Context, tx *sql.Tx, uid models.UserID, t models.RecordType, id models.ID, op models.ChangeOp,
+) error {
+	var created int64
+	err := tx.QueryRowContext(
+		ctx, queryWithTable("SELECT created FROM %s WHERE type = ? AND rid = ?", tableChanges), t, id,
+	).Scan(&created)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("failed get change of %q with id %d: %w", t.String(), id, err)
+	}
+	if _, err = tx.ExecContext(
+		ctx, queryWithTable("DELETE FROM %s WHERE type = ? AND rid = ?", tableChanges), t, id,
+	); err != nil {
+		return fmt.Errorf("failed delete change of %q with id %d: %w", t.String(), id, err)
+	}
+	var revision int64
+	if err = tx.QueryRowContext(
+		ctx, queryWithTable(
+			"INSERT INTO %s(uid, type, rid, created, deleted) VALUES (?, ?, ?, ?, ?) RETURNING revision", tableChanges,
+		),
+		uid, t, id, created, op == models.ChangeDelete,
+	).Scan(&revision); err != nil {
+		return fmt.Errorf("failed save change of %q with id %d: %w", t.String(), id, err)
+	}
+	queries := []query{
+		{query: queryWithTable("UPDATE %s SET revision = ? WHERE id = ?", tables(t)), args: []any{revision, id}},
+	}
+	if op == models.ChangeCreate {
+		queries = append(
+			queries, query{
+				query: queryWithTable("UPDATE %s SET created = revision WHERE revision = ?", tableChanges),
+				args:  []any{revision},
+			},
+		)
+	}
+	for _, q := range queries {
+		if _, err = tx.ExecContext(ctx, q.query, q.args...); err != nil {
+			return fmt.Errorf("failed set revision of %q with id %d: %w", t.String(), id, err)
+		}
+	}
+	return nil
+}
+
+// checkRevision returns models.ErrConflict if record has revision other than rev, zero rev skips
+// the check. Record must be changed by tx before the check, so its revision can't change concurrently.
+func checkRevision(ctx context.Context, tx *sql.Tx, t models.RecordType, id models.ID, rev int64) error {
+	if rev == 0 {
+		return nil
+	}
+	var revision int64
+	if err := tx.QueryRowContext(
+		ctx, queryWithTable("SELECT revision FROM %s WHERE id = ?", tables(t)), id,
+	).Scan(&revision); err != nil {
+		return fmt.Errorf("failed get revision of %q with id %d: %w", t.String(), id, err)
+	}
+	if revision != rev {
+		return fmt.Errorf("%q with id %d has revision %d, expected %d: %w", t.String(), id, revision, rev, models.ErrConflict)
+	}
+	return nil
+}
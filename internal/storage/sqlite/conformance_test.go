@@ -0,0 +1,26 @@
+package sqlite_test
+
+import (
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/sejo412/gophkeeper/internal/server"
+	"github.com/sejo412/gophkeeper/internal/storage/sqlite"
+	"github.com/sejo412/gophkeeper/internal/storage/storagetest"
+)
+
+func TestConformance(t *testing.T) {
+	dir := t.TempDir()
+	count := 0
+	storagetest.RunConformance(
+		t, func() server.Storage {
+			count++
+			store, err := sqlite.New(filepath.Join(dir, strconv.Itoa(count)+".db"))
+			if err != nil {
+				panic(err)
+			}
+			return store
+		},
+	)
+}
@@ -0,0 +1,255 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+)
+
+// FindUser returns uid by user name, -1 if user not exists.
+func (s *Storage) FindUser(ctx context.Context, name string) (models.UserID, error) {
+	q := query{
+		query: queryWithTable("SELECT id FROM %s WHERE cn = ?", tableUsers),
+		args:  []interface{}{name},
+	}
+	var uid int
+	err := s.db.QueryRowContext(ctx, q.query, q.args...).Scan(&uid)
+	if errors.Is(err, sql.ErrNoRows) {
+		return -1, nil
+	}
+	if err != nil {
+		return -1, fmt.Errorf("could not get user by name: %w", err)
+	}
+	return models.UserID(uid), nil
+}
+
+// Devices returns all devices of user.
+func (s *Storage) Devices(ctx context.Context, uid models.UserID) ([]models.Device, error) {
+	q := query{
+		query: queryWithTable("SELECT id, cn, created FROM %s WHERE uid = ? ORDER BY id", tableDevices),
+		args:  []interface{}{uid},
+	}
+	rows, err := s.db.QueryContext(ctx, q.query, q.args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed query devices: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+	devices := make([]models.Device, 0)
+	for rows.Next() {
+		var created int64
+		device := models.Device{UserID: uid}
+		if err = rows.Scan(&device.ID, &device.Cn, &created); err != nil {
+			return nil, fmt.Errorf("failed scan devices: %w", err)
+		}
+		device.Created = time.Unix(created, 0)
+		devices = append(devices, device)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed iterate devices: %w", err)
+	}
+	return devices, nil
+}
+
+// DeleteDevice deletes device of user, its certificate isn't accepted anymore.
+func (s *Storage) DeleteDevice(ctx context.Context, uid models.UserID, id models.DeviceID) error {
+	q := query{
+		query: queryWithTable("DELETE FROM %s WHERE id = ? AND uid = ?", tableDevices),
+		args:  []interface{}{id, uid},
+	}
+	res, err := s.db.ExecContext(ctx, q.query, q.args...)
+	if err != nil {
+		return fmt.Errorf("failed delete device %d: %w", id, err)
+	}
+	rowsCount, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed get rows affected: %w", err)
+	}
+	if rowsCount == 0 {
+		return fmt.Errorf("device %d not found", id)
+	}
+	return nil
+}
+
+// NewEnrollment saves new device waiting for approval. Name of device must not be used
+// by other user, device or enrollment.
+func (s *Storage) NewEnrollment(ctx context.Context, enrollment models.Enrollment) error {
+	q := query{
+		query: fmt.Sprintf(
+			"INSERT INTO %s(code, uid, cn, csr, created) SELECT ?, ?, ?, ?, ? "+
+				"WHERE NOT EXISTS (SELECT 1 FROM %s WHERE cn = ?) AND NOT EXISTS (SELECT 1 FROM %s WHERE cn = ?)",
+			tableEnrollments, tableUsers, tableDevices,
+		),
+		args: []interface{}{
+			enrollment.Code, enrollment.UserID, enrollment.Cn, enrollment.CertRequest, enrollment.Created.Unix(),
+			enrollment.Cn, enrollment.Cn,
+		},
+	}
+	res, err := s.db.ExecContext(ctx, q.query, q.args...)
+	if err != nil {
+		return fmt.Errorf("failed create enrollment: %w", err)
+	}
+	rowsCount, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed get rows affected: %w", err)
+	}
+	if rowsCount == 0 {
+		return fmt.Errorf("name %q already used", enrollment.Cn)
+	}
+	return nil
+}
+
+// Enrollment returns enrollment by code.
+func (s *Storage) Enrollment(ctx context.Context, code string) (models.Enrollment, error) {
+	q := query{
+		query: queryWithTable(
+			"SELECT code, uid, cn, csr, created, approved, vault_key FROM %s WHERE code = ?", tableEnrollments,
+		),
+		args: []interface{}{code},
+	}
+	enrollment, err := scanEnrollment(s.db.QueryRowContext(ctx, q.query, q.args...))
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.Enrollment{}, fmt.Errorf("enrollment %q not found", code)
+	}
+	if err != nil {
+		return models.Enrollment{}, fmt.Errorf("failed get enrollment: %w", err)
+	}
+	return enrollment, nil
+}
+
+// Enrollments returns not approved enrollments of user.
+func (s *Storage) Enrollments(ctx context.Context, uid models.UserID) ([]models.Enrollment, error) {
+	q := query{
+		query: queryWithTable(
+			"SELECT code, uid, cn, csr, created, approved, vault_key FROM %s WHERE uid = ? AND approved = 0 "+
+				"ORDER BY created",
+			tableEnrollments,
+		),
+		args: []interface{}{uid},
+	}
+	rows, err := s.db.QueryContext(ctx, q.query, q.args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed query enrollments: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+	enrollments := make([]models.Enrollment, 0)
+	for rows.Next() {
+		enrollment, er := scanEnrollment(rows)
+		if er != nil {
+			return nil, fmt.Errorf("failed scan enrollments: %w", er)
+		}
+		enrollments = append(enrollments, enrollment)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed iterate enrollments: %w", err)
+	}
+	return enrollments, nil
+}
+
+// ApproveEnrollment saves vault key wrapped for enrolled device and adds device to user.
+// Legacy user without devices gets its first device, so it keeps access after approval.
+func (s *Storage) ApproveEnrollment(
+	ctx context.Context, uid models.UserID, code string, vaultKey models.Encrypted,
+) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	now := time.Now().Unix()
+	if _, err = tx.ExecContext(
+		ctx, fmt.Sprintf(
+			"INSERT INTO %s(uid, cn, created) SELECT id, cn, ? FROM %s "+
+				"WHERE id = ? AND NOT EXISTS (SELECT 1 FROM %s WHERE uid = ?)",
+			tableDevices, tableUsers, tableDevices,
+		), now, uid, uid,
+	); err != nil {
+		return fmt.Errorf("failed create first device: %w", err)
+	}
+	res, err := tx.ExecContext(
+		ctx, queryWithTable(
+			"UPDATE %s SET approved = 1, vault_key = ? WHERE code = ? AND uid = ? AND approved = 0",
+			tableEnrollments,
+		), vaultKey, code, uid,
+	)
+	if err != nil {
+		return fmt.Errorf("failed approve enrollment: %w", err)
+	}
+	rowsCount, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed get rows affected: %w", err)
+	}
+	if rowsCount == 0 {
+		return fmt.Errorf("enrollment %q not found", code)
+	}
+	if _, err = tx.ExecContext(
+		ctx, fmt.Sprintf(
+			"INSERT INTO %s(uid, cn, created) SELECT uid, cn, ? FROM %s WHERE code = ?",
+			tableDevices, tableEnrollments,
+		), now, code,
+	); err != nil {
+		return fmt.Errorf("failed create device: %w", err)
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed commit enrollment %q: %w", code, err)
+	}
+	return nil
+}
+
+// CompleteEnrollment deletes approved enrollment, returns error if enrollment is not approved
+// or already completed.
+func (s *Storage) CompleteEnrollment(ctx context.Context, code string) error {
+	res, err := s.db.ExecContext(
+		ctx, queryWithTable("DELETE FROM %s WHERE code = ? AND approved = 1", tableEnrollments), code,
+	)
+	if err != nil {
+		return fmt.Errorf("failed complete enrollment: %w", err)
+	}
+	rowsCount, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed get rows affected: %w", err)
+	}
+	if rowsCount == 0 {
+		return fmt.Errorf("approved enrollment %q not found", code)
+	}
+	return nil
+}
+
+// PurgeEnrollments deletes enrollments created before given time.
+func (s *Storage) PurgeEnrollments(ctx context.Context, before time.Time) error {
+	q := query{
+		query: queryWithTable("DELETE FROM %s WHERE created < ?", tableEnrollments),
+		args:  []interface{}{before.Unix()},
+	}
+	if _, err := s.db.ExecContext(ctx, q.query, q.args...); err != nil {
+		return fmt.Errorf("failed purge enrollments: %w", err)
+	}
+	return nil
+}
+
+// scanner is implemented by sql.Row and sql.Rows.
+type scanner interface {
+	Scan(dest ...any) error
+}
+
+func scanEnrollment(row scanner) (models.Enrollment, error) {
+	var created int64
+	enrollment := models.Enrollment{}
+	if err := row.Scan(
+		&enrollment.Code, &enrollment.UserID, &enrollment.Cn, &enrollment.CertRequest, &created,
+		&enrollment.Approved, (*[]byte)(&enrollment.VaultKey),
+	); err != nil {
+		return models.Enrollment{}, err
+	}
+	enrollment.Created = time.Unix(created, 0)
+	return enrollment, nil
+}
@@ -0,0 +1,134 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+)
+
+func TestStorage_Devices(t *testing.T) {
+	ctx := context.Background()
+	s, err := New(filepath.Join(t.TempDir(), "devices.db"))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer func() {
+		_ = s.Close()
+	}()
+	if err = s.Init(ctx); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	uid, err := s.NewUser(ctx, testUser1.Cn)
+	if err != nil {
+		t.Fatalf("NewUser() error = %v", err)
+	}
+	devices, err := s.Devices(ctx, uid)
+	if err != nil || len(devices) != 1 || devices[0].Cn != testUser1.Cn {
+		t.Fatalf("Devices() = %v, %v, want first device %q", devices, err, testUser1.Cn)
+	}
+	if got, _ := s.FindUser(ctx, testUser1.Cn); got != uid {
+		t.Errorf("FindUser() = %v, want %v", got, uid)
+	}
+
+	enrollment := models.Enrollment{
+		Code:        "code",
+		UserID:      uid,
+		Cn:          "laptop",
+		CertRequest: []byte("csr"),
+		Created:     time.Now(),
+	}
+	if err = s.NewEnrollment(ctx, enrollment); err != nil {
+		t.Fatalf("NewEnrollment() error = %v", err)
+	}
+	taken := enrollment
+	taken.Code = "taken"
+	taken.Cn = testUser1.Cn
+	if err = s.NewEnrollment(ctx, taken); err == nil {
+		t.Errorf("NewEnrollment() with used name error = nil, want error")
+	}
+	if got, _ := s.GetUserID(ctx, "laptop"); got != -1 {
+		t.Errorf("GetUserID() of not approved device = %v, want -1", got)
+	}
+	pending, err := s.Enrollments(ctx, uid)
+	if err != nil || len(pending) != 1 || pending[0].Code != "code" {
+		t.Fatalf("Enrollments() = %v, %v, want one enrollment", pending, err)
+	}
+	if err = s.ApproveEnrollment(ctx, uid+1, "code", models.Encrypted("vault")); err == nil {
+		t.Errorf("ApproveEnrollment() by other user error = nil, want error")
+	}
+	if err = s.ApproveEnrollment(ctx, uid, "code", models.Encrypted("vault")); err != nil {
+		t.Fatalf("ApproveEnrollment() error = %v", err)
+	}
+	got, err := s.Enrollment(ctx, "code")
+	if err != nil || !got.Approved || string(got.VaultKey) != "vault" {
+		t.Errorf("Enrollment() = %v, %v, want approved with vault key", got, err)
+	}
+	if pending, _ = s.Enrollments(ctx, uid); len(pending) != 0 {
+		t.Errorf("Enrollments() after approve = %v, want empty", pending)
+	}
+	if id, _ := s.GetUserID(ctx, "laptop"); id != uid {
+		t.Errorf("GetUserID() of approved device = %v, want %v", id, uid)
+	}
+
+	devices, _ = s.Devices(ctx, uid)
+	if len(devices) != 2 {
+		t.Fatalf("Devices() = %v, want 2 devices", devices)
+	}
+	if err = s.DeleteDevice(ctx, uid+1, devices[1].ID); err == nil {
+		t.Errorf("DeleteDevice() of other user error = nil, want error")
+	}
+	if err = s.DeleteDevice(ctx, uid, devices[1].ID); err != nil {
+		t.Fatalf("DeleteDevice() error = %v", err)
+	}
+	if id, _ := s.GetUserID(ctx, "laptop"); id != -1 {
+		t.Errorf("GetUserID() of removed device = %v, want -1", id)
+	}
+
+	if err = s.PurgeEnrollments(ctx, time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("PurgeEnrollments() error = %v", err)
+	}
+	if _, err = s.Enrollment(ctx, "code"); err == nil {
+		t.Errorf("Enrollment() after purge error = nil, want error")
+	}
+}
+
+func TestStorage_ApproveEnrollmentLegacyUser(t *testing.T) {
+	ctx := context.Background()
+	s, err := New(filepath.Join(t.TempDir(), "legacy.db"))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer func() {
+		_ = s.Close()
+	}()
+	if err = s.Init(ctx); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	// user registered before devices has no device rows
+	if _, err = s.db.ExecContext(
+		ctx, fmt.Sprintf("INSERT INTO %s(id, cn) VALUES (7, 'legacy')", tableUsers),
+	); err != nil {
+		t.Fatal(err)
+	}
+	uid, err := s.GetUserID(ctx, "legacy")
+	if err != nil || uid != 7 {
+		t.Fatalf("GetUserID() of legacy user = %v, %v, want 7", uid, err)
+	}
+	if err = s.NewEnrollment(
+		ctx, models.Enrollment{Code: "code", UserID: uid, Cn: "phone", CertRequest: []byte("csr"), Created: time.Now()},
+	); err != nil {
+		t.Fatalf("NewEnrollment() error = %v", err)
+	}
+	if err = s.ApproveEnrollment(ctx, uid, "code", models.Encrypted("vault")); err != nil {
+		t.Fatalf("ApproveEnrollment() error = %v", err)
+	}
+	for _, cn := range []string{"legacy", "phone"} {
+		if got, _ := s.GetUserID(ctx, cn); got != uid {
+			t.Errorf("GetUserID(%q) = %v, want %v", cn, got, uid)
+		}
+	}
+}
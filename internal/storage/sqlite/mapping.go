@@ -1,77 +1,138 @@
 package sqlite
 
-import "github.com/sejo412/gophkeeper/internal/models"
+import (
+	"fmt"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+)
 
 var actions = map[models.RecordType]map[action]query{
 	models.RecordPassword: {
 		actionCreate: {
-			query: queryWithTable("INSERT INTO %s(uid, login, password, meta) VALUES (?, ?, ?, ?)", tablePasswords),
+			query: queryWithTable("INSERT INTO %s(uid, login, password, meta, uuid, data_key) VALUES (?, ?, ?, ?, ?, ?)", tablePasswords),
 		},
 		actionRead: {
-			query: queryWithTable("SELECT id, login, password, meta FROM %s WHERE id = ? AND uid = ?", tablePasswords),
+			query: queryWithTable("SELECT id, login, password, meta, uuid, data_key, revision FROM %s WHERE id = ? AND uid = ? AND deleted IS NULL", tablePasswords),
 		},
 		actionUpdate: {
-			query: queryWithTable("UPDATE %s SET login = ?, password = ?, meta = ? WHERE id = ? AND uid = ?", tablePasswords),
+			query: queryWithTable("UPDATE %s SET login = ?, password = ?, meta = ?, uuid = ?, data_key = ? WHERE id = ? AND uid = ? AND deleted IS NULL", tablePasswords),
 		},
 		actionDelete: {
-			query: queryWithTable("DELETE FROM %s WHERE id = ? AND uid = ?", tablePasswords),
+			query: queryWithTable("UPDATE %s SET deleted = ? WHERE id = ? AND uid = ? AND deleted IS NULL", tablePasswords),
 		},
 		actionList: {
-			query: queryWithTable("SELECT id, meta FROM %s WHERE uid = ?", tablePasswords),
+			query: queryWithTable("SELECT id, meta, uuid, data_key, revision FROM %s WHERE uid = ? AND deleted IS NULL", tablePasswords),
+		},
+		actionSaveVersion: {
+			query: fmt.Sprintf(
+				"INSERT INTO %s(rid, rev, uid, created, login, password, meta, uuid, data_key) "+
+					"SELECT id, (SELECT IFNULL(MAX(rev), 0) + 1 FROM %s WHERE rid = ?), uid, ?, login, password, meta, uuid, data_key "+
+					"FROM %s WHERE id = ? AND uid = ? AND deleted IS NULL",
+				tablePasswordVersions, tablePasswordVersions, tablePasswords,
+			),
+		},
+		actionVersions: {
+			query: queryWithTable(
+				"SELECT rev, created, meta, uuid, data_key FROM %s WHERE rid = ? AND uid = ? ORDER BY rev DESC", tablePasswordVersions,
+			),
+		},
+		actionVersion: {
+			query: queryWithTable(
+				"SELECT rid, login, password, meta, uuid, data_key, rev, created FROM %s WHERE rid = ? AND uid = ? AND rev = ?", tablePasswordVersions,
+			),
 		},
 	},
 	models.RecordText: {
 		actionCreate: {
-			query: queryWithTable("INSERT INTO %s(uid, text, meta) VALUES (?, ?, ?)", tableTexts),
+			query: queryWithTable("INSERT INTO %s(uid, text, meta, uuid, data_key) VALUES (?, ?, ?, ?, ?)", tableTexts),
 		},
 		actionRead: {
-			query: queryWithTable("SELECT id, text, meta FROM %s WHERE id = ? AND uid = ?", tableTexts),
+			query: queryWithTable("SELECT id, text, meta, uuid, data_key, revision FROM %s WHERE id = ? AND uid = ? AND deleted IS NULL", tableTexts),
 		},
 		actionUpdate: {
-			query: queryWithTable("UPDATE %s SET text = ?, meta = ? WHERE id = ? AND uid = ?", tableTexts),
+			query: queryWithTable("UPDATE %s SET text = ?, meta = ?, uuid = ?, data_key = ? WHERE id = ? AND uid = ? AND deleted IS NULL", tableTexts),
 		},
 		actionDelete: {
-			query: queryWithTable("DELETE FROM %s WHERE id = ? AND uid = ?", tableTexts),
+			query: queryWithTable("UPDATE %s SET deleted = ? WHERE id = ? AND uid = ? AND deleted IS NULL", tableTexts),
 		},
 		actionList: {
-			query: queryWithTable("SELECT id, meta FROM %s WHERE uid = ?", tableTexts),
+			query: queryWithTable("SELECT id, meta, uuid, data_key, revision FROM %s WHERE uid = ? AND deleted IS NULL", tableTexts),
+		},
+		actionSaveVersion: {
+			query: fmt.Sprintf(
+				"INSERT INTO %s(rid, rev, uid, created, text, meta, uuid, data_key) "+
+					"SELECT id, (SELECT IFNULL(MAX(rev), 0) + 1 FROM %s WHERE rid = ?), uid, ?, text, meta, uuid, data_key "+
+					"FROM %s WHERE id = ? AND uid = ? AND deleted IS NULL",
+				tableTextVersions, tableTextVersions, tableTexts,
+			),
+		},
+		actionVersions: {
+			query: queryWithTable(
+				"SELECT rev, created, meta, uuid, data_key FROM %s WHERE rid = ? AND uid = ? ORDER BY rev DESC", tableTextVersions,
+			),
+		},
+		actionVersion: {
+			query: queryWithTable(
+				"SELECT rid, text, meta, uuid, data_key, rev, created FROM %s WHERE rid = ? AND uid = ? AND rev = ?", tableTextVersions,
+			),
 		},
 	},
 	models.RecordBin: {
 		actionCreate: {
-			query: queryWithTable("INSERT INTO %s(uid, data, meta) VALUES (?, ?, ?)", tableBins),
+			query: queryWithTable("INSERT INTO %s(uid, data, meta, uuid, data_key) VALUES (?, ?, ?, ?, ?)", tableBins),
 		},
 		actionRead: {
-			query: queryWithTable("SELECT id, data, meta FROM %s WHERE id = ? AND uid = ?", tableBins),
+			query: queryWithTable("SELECT id, data, meta, uuid, data_key, revision FROM %s WHERE id = ? AND uid = ? AND deleted IS NULL", tableBins),
 		},
 		actionUpdate: {
-			query: queryWithTable("UPDATE %s SET data = ?, meta = ? WHERE id = ? AND uid = ?", tableBins),
+			query: queryWithTable("UPDATE %s SET data = ?, meta = ?, uuid = ?, data_key = ? WHERE id = ? AND uid = ? AND deleted IS NULL", tableBins),
+		},
+		actionUpdateMeta: {
+			query: queryWithTable("UPDATE %s SET meta = ? WHERE id = ? AND uid = ? AND uuid = ? AND data_key = ? AND deleted IS NULL", tableBins),
 		},
 		actionDelete: {
-			query: queryWithTable("DELETE FROM %s WHERE id = ? AND uid = ?", tableBins),
+			query: queryWithTable("UPDATE %s SET deleted = ? WHERE id = ? AND uid = ? AND deleted IS NULL", tableBins),
 		},
 		actionList: {
-			query: queryWithTable("SELECT id, meta FROM %s WHERE uid = ?", tableBins),
+			query: queryWithTable("SELECT id, meta, uuid, data_key, revision FROM %s WHERE uid = ? AND deleted IS NULL", tableBins),
 		},
 	},
 	models.RecordBank: {
 		actionCreate: {
-			query: queryWithTable("INSERT INTO %s(uid, number, name, date, cvv, meta) VALUES (?, ?, ?, ?, ?, ?)", tableBanks),
+			query: queryWithTable("INSERT INTO %s(uid, number, name, date, cvv, meta, uuid, data_key) VALUES (?, ?, ?, ?, ?, ?, ?, ?)", tableBanks),
 		},
 		actionRead: {
-			query: queryWithTable("SELECT id, number, name, date, cvv, meta FROM %s WHERE id = ? AND uid = ?", tableBanks),
+			query: queryWithTable("SELECT id, number, name, date, cvv, meta, uuid, data_key, revision FROM %s WHERE id = ? AND uid = ? AND deleted IS NULL", tableBanks),
 		},
 		actionUpdate: {
 			query: queryWithTable(
-				"UPDATE %s SET number = ?, name = ?, date = ?, cvv = ? meta = ? WHERE id = ? AND uid = ?",
+				"UPDATE %s SET number = ?, name = ?, date = ?, cvv = ?, meta = ?, uuid = ?, data_key = ? WHERE id = ? AND uid = ? AND deleted IS NULL",
 				tableBanks,
 			),
 		},
 		actionDelete: {
-			query: queryWithTable("DELETE FROM %s WHERE id = ? AND uid = ?", tableBanks),
+			query: queryWithTable("UPDATE %s SET deleted = ? WHERE id = ? AND uid = ? AND deleted IS NULL", tableBanks),
 		},
 		actionList: {
-			query: queryWithTable("SELECT id, meta FROM %s WHERE uid = ?", tableBanks),
+			query: queryWithTable("SELECT id, meta, uuid, data_key, revision FROM %s WHERE uid = ? AND deleted IS NULL", tableBanks),
+		},
+		actionSaveVersion: {
+			query: fmt.Sprintf(
+				"INSERT INTO %s(rid, rev, uid, created, number, name, date, cvv, meta, uuid, data_key) "+
+					"SELECT id, (SELECT IFNULL(MAX(rev), 0) + 1 FROM %s WHERE rid = ?), uid, ?, number, name, date, cvv, meta, uuid, data_key "+
+					"FROM %s WHERE id = ? AND uid = ? AND deleted IS NULL",
+				tableBankVersions, tableBankVersions, tableBanks,
+			),
+		},
+		actionVersions: {
+			query: queryWithTable(
+				"SELECT rev, created, meta, uuid, data_key FROM %s WHERE rid = ? AND uid = ? ORDER BY rev DESC", tableBankVersions,
+			),
+		},
+		actionVersion: {
+			query: queryWithTable(
+				"SELECT rid, number, name, date, cvv, meta, uuid, data_key, rev, created FROM %s WHERE rid = ? AND uid = ? AND rev = ?", tableBankVersions,
+			),
 		},
 	},
 }
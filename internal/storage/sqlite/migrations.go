@@ -0,0 +1,405 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+)
+
+// migration is a schema change of database, migrations are applied in order of versions.
+type migration struct {
+	version     int
+	description string
+	queries     []query
+	// columns are added after queries only to tables without them, SQLite has no ADD COLUMN IF NOT EXISTS.
+	columns []column
+}
+
+// column is a column added by migration only to table without such column.
+type column struct {
+	table      table
+	name       string
+	definition string
+}
+
+// migrations are all schema changes, new schema change must be appended with next version.
+// Applied migration must never be changed.
+var migrations = []migration{
+	{
+		version:     1,
+		description: "initial schema",
+		queries: []query{
+			{
+				table: tableUsers,
+				query: queryWithTable(
+					"CREATE TABLE IF NOT EXISTS %s(id INTEGER PRIMARY KEY, cn TEXT UNIQUE NOT NULL)",
+					tableUsers,
+				),
+			},
+			{
+				table: tablePasswords,
+				query: queryWithTable(
+					"CREATE TABLE IF NOT EXISTS %s(id INTEGER PRIMARY KEY, uid INTEGER NOT NULL, login BLOB, password BLOB, meta BLOB, uuid TEXT NOT NULL DEFAULT '', data_key BLOB)",
+					tablePasswords,
+				),
+			},
+			{
+				table: tableTexts,
+				query: queryWithTable(
+					"CREATE TABLE IF NOT EXISTS %s(id INTEGER PRIMARY KEY, uid INTEGER NOT NULL, text BLOB, meta BLOB, uuid TEXT NOT NULL DEFAULT '', data_key BLOB)",
+					tableTexts,
+				),
+			},
+			{
+				table: tableBins,
+				query: queryWithTable(
+					"CREATE TABLE IF NOT EXISTS %s(id INTEGER PRIMARY KEY, uid INTEGER NOT NULL, data BLOB, meta BLOB, uuid TEXT NOT NULL DEFAULT '', data_key BLOB)",
+					tableBins,
+				),
+			},
+			{
+				table: tableBanks,
+				query: queryWithTable(
+					"CREATE TABLE IF NOT EXISTS %s(id INTEGER PRIMARY KEY, uid INTEGER NOT NULL, number BLOB, "+
+						"name BLOB, date BLOB, cvv BLOB, meta BLOB, uuid TEXT NOT NULL DEFAULT '', data_key BLOB)",
+					tableBanks,
+				),
+			},
+			{
+				table: tableBinChunks,
+				query: queryWithTable(
+					"CREATE TABLE IF NOT EXISTS %s(bid INTEGER NOT NULL, seq INTEGER NOT NULL, data BLOB, "+
+						"PRIMARY KEY(bid, seq))",
+					tableBinChunks,
+				),
+			},
+			{
+				table: tableUploads,
+				query: queryWithTable(
+					"CREATE TABLE IF NOT EXISTS %s(id TEXT NOT NULL, uid INTEGER NOT NULL, bid INTEGER NOT NULL, "+
+						"meta BLOB, uuid TEXT NOT NULL DEFAULT '', data_key BLOB, PRIMARY KEY(uid, id))",
+					tableUploads,
+				),
+			},
+			{
+				table: tableUploadChunks,
+				query: queryWithTable(
+					"CREATE TABLE IF NOT EXISTS %s(upload TEXT NOT NULL, uid INTEGER NOT NULL, seq INTEGER NOT NULL, "+
+						"data BLOB, PRIMARY KEY(uid, upload, seq))",
+					tableUploadChunks,
+				),
+			},
+			{
+				table: tableDevices,
+				query: queryWithTable(
+					"CREATE TABLE IF NOT EXISTS %s(id INTEGER PRIMARY KEY, uid INTEGER NOT NULL, cn TEXT UNIQUE NOT NULL, "+
+						"created INTEGER NOT NULL)",
+					tableDevices,
+				),
+			},
+			{
+				table: tableEnrollments,
+				query: queryWithTable(
+					"CREATE TABLE IF NOT EXISTS %s(code TEXT PRIMARY KEY, uid INTEGER NOT NULL, cn TEXT UNIQUE NOT NULL, "+
+						"csr BLOB NOT NULL, created INTEGER NOT NULL, approved INTEGER NOT NULL DEFAULT 0, vault_key BLOB)",
+					tableEnrollments,
+				),
+			},
+			{
+				table: tableCertificates,
+				query: queryWithTable(
+					"CREATE TABLE IF NOT EXISTS %s(serial TEXT PRIMARY KEY, uid INTEGER NOT NULL, cn TEXT NOT NULL, "+
+						"issued INTEGER NOT NULL, not_after INTEGER NOT NULL)",
+					tableCertificates,
+				),
+			},
+			{
+				table: tableRevocations,
+				query: queryWithTable(
+					"CREATE TABLE IF NOT EXISTS %s(serial TEXT PRIMARY KEY, cn TEXT NOT NULL DEFAULT '', "+
+						"reason INTEGER NOT NULL, revoked INTEGER NOT NULL)",
+					tableRevocations,
+				),
+			},
+			{
+				table: tableInvites,
+				query: queryWithTable(
+					"CREATE TABLE IF NOT EXISTS %s(token TEXT PRIMARY KEY, created INTEGER NOT NULL, "+
+						"expires INTEGER NOT NULL, cn TEXT NOT NULL DEFAULT '')",
+					tableInvites,
+				),
+			},
+			{
+				table: tableRegistrations,
+				query: queryWithTable(
+					"CREATE TABLE IF NOT EXISTS %s(code TEXT PRIMARY KEY, cn TEXT UNIQUE NOT NULL, csr BLOB NOT NULL, "+
+						"created INTEGER NOT NULL, approved INTEGER NOT NULL DEFAULT 0)",
+					tableRegistrations,
+				),
+			},
+			{
+				table: tableDisabledUsers,
+				query: queryWithTable(
+					"CREATE TABLE IF NOT EXISTS %s(uid INTEGER PRIMARY KEY, disabled INTEGER NOT NULL)",
+					tableDisabledUsers,
+				),
+			},
+		},
+	},
+	{
+		version:     2,
+		description: "record revisions",
+		queries: []query{
+			{
+				table: tablePasswordVersions,
+				query: queryWithTable(
+					"CREATE TABLE IF NOT EXISTS %s(rid INTEGER NOT NULL, rev INTEGER NOT NULL, uid INTEGER NOT NULL, "+
+						"created INTEGER NOT NULL, login BLOB, password BLOB, meta BLOB, uuid TEXT NOT NULL DEFAULT '', "+
+						"data_key BLOB, PRIMARY KEY(rid, rev))",
+					tablePasswordVersions,
+				),
+			},
+			{
+				table: tableTextVersions,
+				query: queryWithTable(
+					"CREATE TABLE IF NOT EXISTS %s(rid INTEGER NOT NULL, rev INTEGER NOT NULL, uid INTEGER NOT NULL, "+
+						"created INTEGER NOT NULL, text BLOB, meta BLOB, uuid TEXT NOT NULL DEFAULT '', data_key BLOB, "+
+						"PRIMARY KEY(rid, rev))",
+					tableTextVersions,
+				),
+			},
+			{
+				table: tableBankVersions,
+				query: queryWithTable(
+					"CREATE TABLE IF NOT EXISTS %s(rid INTEGER NOT NULL, rev INTEGER NOT NULL, uid INTEGER NOT NULL, "+
+						"created INTEGER NOT NULL, number BLOB, name BLOB, date BLOB, cvv BLOB, meta BLOB, "+
+						"uuid TEXT NOT NULL DEFAULT '', data_key BLOB, PRIMARY KEY(rid, rev))",
+					tableBankVersions,
+				),
+			},
+		},
+	},
+	{
+		version:     3,
+		description: "record trash",
+		queries: []query{
+			{table: tablePasswords, query: queryWithTable("ALTER TABLE %s ADD COLUMN deleted INTEGER", tablePasswords)},
+			{table: tableTexts, query: queryWithTable("ALTER TABLE %s ADD COLUMN deleted INTEGER", tableTexts)},
+			{table: tableBins, query: queryWithTable("ALTER TABLE %s ADD COLUMN deleted INTEGER", tableBins)},
+			{table: tableBanks, query: queryWithTable("ALTER TABLE %s ADD COLUMN deleted INTEGER", tableBanks)},
+		},
+	},
+	{
+		version:     4,
+		description: "record changes",
+		queries: append(
+			[]query{
+				{
+					table: tableChanges,
+					query: queryWithTable(
+						"CREATE TABLE IF NOT EXISTS %s(revision INTEGER PRIMARY KEY AUTOINCREMENT, uid INTEGER NOT NULL, "+
+							"type INTEGER NOT NULL, rid INTEGER NOT NULL, created INTEGER NOT NULL, "+
+							"deleted INTEGER NOT NULL, UNIQUE(type, rid))",
+						tableChanges,
+					),
+				},
+			},
+			changesOfExisting()...,
+		),
+	},
+	{
+		version:     5,
+		description: "record uuid and data key",
+		columns:     append(recordColumns("uuid", "TEXT NOT NULL DEFAULT ''"), recordColumns("data_key", "BLOB")...),
+	},
+}
+
+// recordColumns returns column of records and uploads with name and definition.
+func recordColumns(name, definition string) []column {
+	columns := make([]column, 0)
+	for _, t := range []table{tablePasswords, tableTexts, tableBins, tableBanks, tableUploads} {
+		columns = append(columns, column{table: t, name: name, definition: definition})
+	}
+	return columns
+}
+
+// changesOfExisting returns queries which add revision to records and save existing records
+// as their last changes.
+func changesOfExisting() []query {
+	queries := make([]query, 0)
+	for _, t := range models.RecordTypes {
+		queries = append(
+			queries,
+			query{
+				table: tables(t),
+				query: queryWithTable("ALTER TABLE %s ADD COLUMN revision INTEGER NOT NULL DEFAULT 0", tables(t)),
+			},
+			query{
+				table: tableChanges,
+				query: fmt.Sprintf(
+					"INSERT INTO %s(uid, type, rid, created, deleted) SELECT uid, ?, id, 0, deleted IS NOT NULL FROM %s ORDER BY id",
+					tableChanges, tables(t),
+				),
+				args: []any{int(t)},
+			},
+			query{
+				table: tables(t),
+				query: fmt.Sprintf(
+					"UPDATE %s SET revision = (SELECT revision FROM %s WHERE type = ? AND rid = %s.id)",
+					tables(t), tableChanges, tables(t),
+				),
+				args: []any{int(t)},
+			},
+		)
+	}
+	return append(
+		queries, query{
+			table: tableChanges,
+			query: queryWithTable("UPDATE %s SET created = revision", tableChanges),
+		},
+	)
+}
+
+// LatestSchemaVersion returns schema version supported by Storage.
+func LatestSchemaVersion() int {
+	return migrations[len(migrations)-1].version
+}
+
+// SchemaVersion returns current schema version of database and latest supported version.
+// Database created before versioning has version 0.
+func (s *Storage) SchemaVersion(ctx context.Context) (int, int, error) {
+	if err := s.createSchemaVersion(ctx); err != nil {
+		return 0, 0, err
+	}
+	q := query{
+		table: tableSchemaVersion,
+		query: queryWithTable("SELECT IFNULL(MAX(version), 0) FROM %s", tableSchemaVersion),
+	}
+	var version int
+	if err := s.db.QueryRowContext(ctx, q.query).Scan(&version); err != nil {
+		return 0, 0, fmt.Errorf("failed get schema version: %w", err)
+	}
+	return version, LatestSchemaVersion(), nil
+}
+
+// Migrate applies not applied migrations and returns them.
+func (s *Storage) Migrate(ctx context.Context) ([]models.Migration, error) {
+	current, latest, err := s.SchemaVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if current > latest {
+		return nil, fmt.Errorf("database schema version %d is newer than supported %d", current, latest)
+	}
+	applied := make([]models.Migration, 0)
+	for _, m := range migrations {
+		if m.version <= current {
+			continue
+		}
+		if err = s.migrate(ctx, m); err != nil {
+			return applied, err
+		}
+		applied = append(applied, models.Migration{Version: m.version, Description: m.description, Applied: time.Now()})
+	}
+	return applied, nil
+}
+
+// Migrations returns applied migrations.
+func (s *Storage) Migrations(ctx context.Context) ([]models.Migration, error) {
+	if err := s.createSchemaVersion(ctx); err != nil {
+		return nil, err
+	}
+	q := query{
+		table: tableSchemaVersion,
+		query: queryWithTable("SELECT version, description, applied FROM %s ORDER BY version", tableSchemaVersion),
+	}
+	rows, err := s.db.QueryContext(ctx, q.query)
+	if err != nil {
+		return nil, fmt.Errorf("failed query migrations: %w", err)
+	}
+	defer func() {
+		_ = rows.Close()
+	}()
+	result := make([]models.Migration, 0)
+	for rows.Next() {
+		var applied int64
+		m := models.Migration{}
+		if err = rows.Scan(&m.Version, &m.Description, &applied); err != nil {
+			return nil, fmt.Errorf("failed scan migrations: %w", err)
+		}
+		m.Applied = time.Unix(applied, 0)
+		result = append(result, m)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed iterate migrations: %w", err)
+	}
+	return result, nil
+}
+
+func (s *Storage) createSchemaVersion(ctx context.Context) error {
+	q := query{
+		table: tableSchemaVersion,
+		query: queryWithTable(
+			"CREATE TABLE IF NOT EXISTS %s(version INTEGER PRIMARY KEY, description TEXT NOT NULL, "+
+				"applied INTEGER NOT NULL)",
+			tableSchemaVersion,
+		),
+	}
+	if _, err := s.db.ExecContext(ctx, q.query); err != nil {
+		return fmt.Errorf("failed create table %q: %w", q.table.String(), err)
+	}
+	return nil
+}
+
+// migrate applies migration with its version in one transaction.
+func (s *Storage) migrate(ctx context.Context, m migration) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	for _, q := range m.queries {
+		if _, err = tx.ExecContext(ctx, q.query, q.args...); err != nil {
+			return fmt.Errorf("failed migration %d on table %q: %w", m.version, q.table.String(), err)
+		}
+	}
+	for _, c := range m.columns {
+		if err = addColumn(ctx, tx, c); err != nil {
+			return fmt.Errorf("failed migration %d on table %q: %w", m.version, c.table.String(), err)
+		}
+	}
+	if _, err = tx.ExecContext(
+		ctx, queryWithTable("INSERT INTO %s(version, description, applied) VALUES (?, ?, ?)", tableSchemaVersion),
+		m.version, m.description, time.Now().Unix(),
+	); err != nil {
+		return fmt.Errorf("failed save schema version %d: %w", m.version, err)
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed commit migration %d: %w", m.version, err)
+	}
+	return nil
+}
+
+// addColumn adds column to table if table has no such column.
+func addColumn(ctx context.Context, tx *sql.Tx, c column) error {
+	var count int
+	if err := tx.QueryRowContext(
+		ctx, "SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?", c.table.String(), c.name,
+	).Scan(&count); err != nil {
+		return fmt.Errorf("failed get column %q: %w", c.name, err)
+	}
+	if count > 0 {
+		return nil
+	}
+	if _, err := tx.ExecContext(
+		ctx, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", c.table.String(), c.name, c.definition),
+	); err != nil {
+		return fmt.Errorf("failed add column %q: %w", c.name, err)
+	}
+	return nil
+}
@@ -0,0 +1,88 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+)
+
+func TestStorage_Migrate(t *testing.T) {
+	ctx := context.Background()
+	s, err := New(filepath.Join(t.TempDir(), "migrate.db"))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer func() {
+		_ = s.Close()
+	}()
+	// database created before versioning has tables without schema version
+	if _, err = s.db.ExecContext(
+		ctx, fmt.Sprintf("CREATE TABLE %s(id INTEGER PRIMARY KEY, cn TEXT UNIQUE NOT NULL)", tableUsers),
+	); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = s.db.ExecContext(ctx, fmt.Sprintf("INSERT INTO %s(id, cn) VALUES (7, 'legacy')", tableUsers)); err != nil {
+		t.Fatal(err)
+	}
+	// records were stored without uuid and data key
+	if _, err = s.db.ExecContext(
+		ctx, fmt.Sprintf(
+			"CREATE TABLE %s(id INTEGER PRIMARY KEY, uid INTEGER NOT NULL, login BLOB, password BLOB, meta BLOB)",
+			tablePasswords,
+		),
+	); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = s.db.ExecContext(
+		ctx, fmt.Sprintf("INSERT INTO %s(id, uid, login, password, meta) VALUES (3, 7, X'6C', X'70', X'6D')", tablePasswords),
+	); err != nil {
+		t.Fatal(err)
+	}
+	current, latest, err := s.SchemaVersion(ctx)
+	if err != nil || current != 0 || latest != LatestSchemaVersion() {
+		t.Fatalf("SchemaVersion() = %d, %d, %v, want 0, %d", current, latest, err, LatestSchemaVersion())
+	}
+	applied, err := s.Migrate(ctx)
+	if err != nil || len(applied) != len(migrations) {
+		t.Fatalf("Migrate() = %v, %v, want %d migrations", applied, err, len(migrations))
+	}
+	if id, _ := s.FindUser(ctx, "legacy"); id != 7 {
+		t.Errorf("FindUser() after migrate = %v, want 7", id)
+	}
+	record, err := s.Get(ctx, 7, models.RecordPassword, 3)
+	if err != nil || string(record.Password.Meta) != "m" || string(record.Password.Password) != "p" ||
+		record.Password.UUID != "" || record.Password.DataKey != nil {
+		t.Errorf("Get() legacy record after migrate = %+v, %v, want record without uuid and data key", record.Password, err)
+	}
+	if list, er := s.List(ctx, 7, models.RecordPassword); er != nil || len(list.Password) != 1 {
+		t.Errorf("List() after migrate = %+v, %v, want legacy record", list, er)
+	}
+	if applied, err = s.Migrate(ctx); err != nil || len(applied) != 0 {
+		t.Errorf("Migrate() of migrated database = %v, %v, want nothing applied", applied, err)
+	}
+	history, err := s.Migrations(ctx)
+	if err != nil || len(history) != len(migrations) || history[0].Version != 1 {
+		t.Errorf("Migrations() = %v, %v, want all migrations", history, err)
+	}
+
+	if _, err = s.db.ExecContext(
+		ctx, fmt.Sprintf("INSERT INTO %s(version, description, applied) VALUES (?, 'future', 0)", tableSchemaVersion),
+		LatestSchemaVersion()+1,
+	); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = s.Migrate(ctx); err == nil {
+		t.Errorf("Migrate() of newer database error = nil, want error")
+	}
+}
+
+func TestMigrations_Ordered(t *testing.T) {
+	for i, m := range migrations {
+		if m.version != i+1 {
+			t.Errorf("migration %q has version %d, want %d", m.description, m.version, i+1)
+		}
+	}
+}
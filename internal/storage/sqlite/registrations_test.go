@@ -0,0 +1,115 @@
+package sqlite
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+)
+
+func TestStorage_Invites(t *testing.T) {
+	ctx := context.Background()
+	s, err := New(filepath.Join(t.TempDir(), "invites.db"))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer func() {
+		_ = s.Close()
+	}()
+	if err = s.Init(ctx); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	now := time.Now()
+	for _, invite := range []models.Invite{
+		{Token: "valid", Created: now, Expires: now.Add(time.Hour)},
+		{Token: "expired", Created: now.Add(-2 * time.Hour), Expires: now.Add(-time.Hour)},
+	} {
+		if err = s.NewInvite(ctx, invite); err != nil {
+			t.Fatalf("NewInvite() error = %v", err)
+		}
+	}
+	if err = s.UseInvite(ctx, "expired", "alice", now); err == nil {
+		t.Errorf("UseInvite() expired error = nil, want error")
+	}
+	if err = s.UseInvite(ctx, "unknown", "alice", now); err == nil {
+		t.Errorf("UseInvite() unknown error = nil, want error")
+	}
+	if err = s.UseInvite(ctx, "valid", "alice", now); err != nil {
+		t.Fatalf("UseInvite() error = %v", err)
+	}
+	if err = s.UseInvite(ctx, "valid", "bob", now); err == nil {
+		t.Errorf("UseInvite() used error = nil, want error")
+	}
+	invites, err := s.Invites(ctx)
+	if err != nil || len(invites) != 2 {
+		t.Fatalf("Invites() = %v, %v, want 2 invites", invites, err)
+	}
+	if invites[0].Token != "expired" || invites[1].Token != "valid" || invites[1].Cn != "alice" {
+		t.Errorf("Invites() = %v, want used by alice", invites)
+	}
+}
+
+func TestStorage_Registrations(t *testing.T) {
+	ctx := context.Background()
+	s, err := New(filepath.Join(t.TempDir(), "registrations.db"))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer func() {
+		_ = s.Close()
+	}()
+	if err = s.Init(ctx); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	if _, err = s.NewUser(ctx, "taken"); err != nil {
+		t.Fatalf("NewUser() error = %v", err)
+	}
+	registration := models.Registration{Code: "code", Cn: "alice", CertRequest: []byte("csr"), Created: time.Now()}
+	if err = s.NewRegistration(ctx, registration); err != nil {
+		t.Fatalf("NewRegistration() error = %v", err)
+	}
+	for _, r := range []models.Registration{
+		{Code: "other", Cn: "alice", CertRequest: []byte("csr"), Created: time.Now()},
+		{Code: "other", Cn: "taken", CertRequest: []byte("csr"), Created: time.Now()},
+	} {
+		if err = s.NewRegistration(ctx, r); err == nil {
+			t.Errorf("NewRegistration(%q) error = nil, want error", r.Cn)
+		}
+	}
+	pending, err := s.Registrations(ctx)
+	if err != nil || len(pending) != 1 || pending[0].Cn != "alice" || pending[0].Approved {
+		t.Fatalf("Registrations() = %v, %v, want pending alice", pending, err)
+	}
+	if err = s.ApproveRegistration(ctx, "unknown"); err == nil {
+		t.Errorf("ApproveRegistration() unknown error = nil, want error")
+	}
+	if err = s.ApproveRegistration(ctx, "code"); err != nil {
+		t.Fatalf("ApproveRegistration() error = %v", err)
+	}
+	got, err := s.Registration(ctx, "code")
+	if err != nil || !got.Approved || string(got.CertRequest) != "csr" {
+		t.Errorf("Registration() = %v, %v, want approved", got, err)
+	}
+	if pending, _ = s.Registrations(ctx); len(pending) != 0 {
+		t.Errorf("Registrations() after approve = %v, want empty", pending)
+	}
+	if err = s.DeleteRegistration(ctx, "code"); err != nil {
+		t.Fatalf("DeleteRegistration() error = %v", err)
+	}
+	if _, err = s.Registration(ctx, "code"); err == nil {
+		t.Errorf("Registration() after delete error = nil, want error")
+	}
+
+	registration.Created = time.Now().Add(-time.Hour)
+	if err = s.NewRegistration(ctx, registration); err != nil {
+		t.Fatalf("NewRegistration() error = %v", err)
+	}
+	if err = s.PurgeRegistrations(ctx, time.Now()); err != nil {
+		t.Fatalf("PurgeRegistrations() error = %v", err)
+	}
+	if _, err = s.Registration(ctx, "code"); err == nil {
+		t.Errorf("Registration() after purge error = nil, want error")
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"database/sql"
 	"errors"
 	"fmt"
+	"time"
 
 	_ "github.com/mattn/go-sqlite3"
 	"github.com/sejo412/gophkeeper/internal/models"
@@ -27,49 +28,19 @@ func New(path string) (*Storage, error) {
 	return &Storage{db: db}, nil
 }
 
-// Init creates tables in database.
+// Init creates database or migrates it to latest schema version.
 func (s *Storage) Init(ctx context.Context) error {
-	queries := []query{
-		{
-			table: tableUsers,
-			query: queryWithTable(
-				"CREATE TABLE IF NOT EXISTS %s(id INTEGER PRIMARY KEY, cn TEXT UNIQUE NOT NULL)",
-				tableUsers,
-			),
-		},
-		{
-			table: tablePasswords,
-			query: queryWithTable(
-				"CREATE TABLE IF NOT EXISTS %s(id INTEGER PRIMARY KEY, uid INTEGER NOT NULL, login BLOB, password BLOB, meta BLOB)",
-				tablePasswords,
-			),
-		},
-		{
-			table: tableTexts,
-			query: queryWithTable(
-				"CREATE TABLE IF NOT EXISTS %s(id INTEGER PRIMARY KEY, uid INTEGER NOT NULL, text BLOB, meta BLOB)",
-				tableTexts,
-			),
-		},
-		{
-			table: tableBins,
-			query: queryWithTable(
-				"CREATE TABLE IF NOT EXISTS %s(id INTEGER PRIMARY KEY, uid INTEGER NOT NULL, data BLOB, meta BLOB)",
-				tableBins,
-			),
-		},
-		{
-			table: tableBanks,
-			query: queryWithTable(
-				"CREATE TABLE IF NOT EXISTS %s(id INTEGER PRIMARY KEY, uid INTEGER NOT NULL, number BLOB, "+
-					"name BLOB, date BLOB, cvv BLOB, meta BLOB)",
-				tableBanks,
-			),
-		},
-	}
-	for _, q := range queries {
-		if _, err := s.db.ExecContext(ctx, q.query); err != nil {
-			return fmt.Errorf("failed create table %q: %w", q.table.String(), err)
+	if _, err := s.Migrate(ctx); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Reset drops all tables of database.
+func (s *Storage) Reset(ctx context.Context) error {
+	for t := tableUsers; t <= tableSchemaVersion; t++ {
+		if _, err := s.db.ExecContext(ctx, queryWithTable("DROP TABLE IF EXISTS %s", t)); err != nil {
+			return fmt.Errorf("failed drop table %q: %w", t.String(), err)
 		}
 	}
 	return nil
@@ -130,36 +101,51 @@ func (s *Storage) List(ctx context.Context, uid models.UserID, t models.RecordTy
 	for rows.Next() {
 		var id models.ID
 		var meta []byte
-		if err = rows.Scan(&id, &meta); err != nil {
+		var uuid string
+		var dataKey []byte
+		var revision int64
+		if err = rows.Scan(&id, &meta, &uuid, &dataKey, &revision); err != nil {
 			return models.RecordsEncrypted{}, fmt.Errorf("failed scan %q: %w", t.String(), err)
 		}
 		switch t {
 		case models.RecordPassword:
 			result.Password = append(
 				result.Password, models.PasswordEncrypted{
-					ID:   id,
-					Meta: meta,
+					ID:       id,
+					Meta:     meta,
+					UUID:     uuid,
+					DataKey:  dataKey,
+					Revision: revision,
 				},
 			)
 		case models.RecordText:
 			result.Text = append(
 				result.Text, models.TextEncrypted{
-					ID:   id,
-					Meta: meta,
+					ID:       id,
+					Meta:     meta,
+					UUID:     uuid,
+					DataKey:  dataKey,
+					Revision: revision,
 				},
 			)
 		case models.RecordBin:
 			result.Bin = append(
 				result.Bin, models.BinEncrypted{
-					ID:   id,
-					Meta: meta,
+					ID:       id,
+					Meta:     meta,
+					UUID:     uuid,
+					DataKey:  dataKey,
+					Revision: revision,
 				},
 			)
 		case models.RecordBank:
 			result.Bank = append(
 				result.Bank, models.BankEncrypted{
-					ID:   id,
-					Meta: meta,
+					ID:       id,
+					Meta:     meta,
+					UUID:     uuid,
+					DataKey:  dataKey,
+					Revision: revision,
 				},
 			)
 		default:
@@ -183,20 +169,7 @@ func (s *Storage) Get(
 		Bin:      models.BinEncrypted{},
 		Bank:     models.BankEncrypted{},
 	}
-	row := s.db.QueryRowContext(ctx, actions[t][actionRead].query, args...)
-	var err error
-	switch t {
-	case models.RecordPassword:
-		err = row.Scan(&rec.Password.ID, &rec.Password.Login, &rec.Password.Password, &rec.Password.Meta)
-	case models.RecordText:
-		err = row.Scan(&rec.Text.ID, &rec.Text.Text, &rec.Text.Meta)
-	case models.RecordBin:
-		err = row.Scan(&rec.Bin.ID, &rec.Bin.Data, &rec.Bin.Meta)
-	case models.RecordBank:
-		err = row.Scan(&rec.Bank.ID, &rec.Bank.Number, &rec.Bank.Name, &rec.Bank.Date, &rec.Bank.Cvv, &rec.Bank.Meta)
-	default:
-		err = errors.New("unknown record")
-	}
+	err := scanRecord(s.db.QueryRowContext(ctx, actions[t][actionRead].query, args...), t, &rec, revisionOf(&rec, t))
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
 			return models.RecordEncrypted{}, fmt.Errorf("%q with %d not found", t.String(), id)
@@ -207,10 +180,19 @@ func (s *Storage) Get(
 	return rec, nil
 }
 
-// Delete deletes object by id, userid and record type.
-func (s *Storage) Delete(ctx context.Context, uid models.UserID, t models.RecordType, id models.ID) error {
-	args := []interface{}{id, uid}
-	res, err := s.db.ExecContext(ctx, actions[t][actionDelete].query, args...)
+// Delete moves object by id, userid and record type to trash of user.
+func (s *Storage) Delete(
+	ctx context.Context, uid models.UserID, t models.RecordType, id models.ID, rev int64,
+) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	args := []interface{}{time.Now().Unix(), id, uid}
+	res, err := tx.ExecContext(ctx, actions[t][actionDelete].query, args...)
 	if err != nil {
 		return fmt.Errorf("failed delete %q with id %d: %w", t.String(), id, err)
 	}
@@ -221,31 +203,60 @@ func (s *Storage) Delete(ctx context.Context, uid models.UserID, t models.Record
 	if rowsCount == 0 {
 		return fmt.Errorf("nothing to delete")
 	}
+	if err = checkRevision(ctx, tx, t, id, rev); err != nil {
+		return err
+	}
+	if err = saveChange(ctx, tx, uid, t, id, models.ChangeDelete); err != nil {
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed delete %q with id %d: %w", t.String(), id, err)
+	}
 	return nil
 }
 
 // Update updates object by id, userid and record type.
 func (s *Storage) Update(
 	ctx context.Context, uid models.UserID, t models.RecordType, id models.ID,
-	record models.RecordEncrypted,
+	record models.RecordEncrypted, rev int64,
 ) error {
 	var args []interface{}
+	update := actions[t][actionUpdate]
 	switch t {
 	case models.RecordPassword:
-		args = []interface{}{record.Password.Login, record.Password.Password, record.Password.Meta, id, uid}
+		args = []interface{}{
+			record.Password.Login, record.Password.Password, record.Password.Meta, record.Password.UUID,
+			record.Password.DataKey, id, uid,
+		}
 	case models.RecordText:
-		args = []interface{}{record.Text.Text, record.Text.Meta, id, uid}
+		args = []interface{}{record.Text.Text, record.Text.Meta, record.Text.UUID, record.Text.DataKey, id, uid}
 	case models.RecordBin:
-		args = []interface{}{record.Bin.Data, record.Bin.Meta, id, uid}
+		args = []interface{}{record.Bin.Data, record.Bin.Meta, record.Bin.UUID, record.Bin.DataKey, id, uid}
+		if len(record.Bin.Data) == 0 {
+			// data is not sent with meta of chunked bin, data and chunks are kept if encrypted by the same key
+			update = actions[t][actionUpdateMeta]
+			args = []interface{}{record.Bin.Meta, id, uid, record.Bin.UUID, record.Bin.DataKey}
+		}
 	case models.RecordBank:
 		args = []interface{}{
 			record.Bank.Number, record.Bank.Name, record.Bank.Date, record.Bank.Cvv, record.Bank.Meta,
-			id, uid,
+			record.Bank.UUID, record.Bank.DataKey, id, uid,
 		}
 	default:
 		return errors.New("invalid record type")
 	}
-	res, err := s.db.ExecContext(ctx, actions[t][actionUpdate].query, args...)
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	// revision is saved by first statement, so transaction takes write lock before reading record
+	if err = saveVersion(ctx, tx, uid, t, id); err != nil {
+		return err
+	}
+	res, err := tx.ExecContext(ctx, update.query, args...)
 	if err != nil {
 		return fmt.Errorf("failed update %q for userID %d: %w", t.String(), uid, err)
 	}
@@ -256,6 +267,20 @@ func (s *Storage) Update(
 	if rowCount == 0 {
 		return fmt.Errorf("no records updated for userID %d", uid)
 	}
+	if err = checkRevision(ctx, tx, t, id, rev); err != nil {
+		return err
+	}
+	if t == models.RecordBin && len(record.Bin.Data) > 0 {
+		if err = deleteBinChunks(ctx, tx, id); err != nil {
+			return err
+		}
+	}
+	if err = saveChange(ctx, tx, uid, t, id, models.ChangeUpdate); err != nil {
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed update %q for userID %d: %w", t.String(), uid, err)
+	}
 	return nil
 }
 
@@ -270,17 +295,37 @@ func (s *Storage) Add(
 	var args []interface{}
 	switch t {
 	case models.RecordPassword:
-		args = []interface{}{uid, record.Password.Login, record.Password.Password, record.Password.Meta}
+		args = []interface{}{
+			uid, record.Password.Login, record.Password.Password, record.Password.Meta, record.Password.UUID,
+			record.Password.DataKey,
+		}
 	case models.RecordText:
-		args = []interface{}{uid, record.Text.Text, record.Text.Meta}
+		args = []interface{}{uid, record.Text.Text, record.Text.Meta, record.Text.UUID, record.Text.DataKey}
 	case models.RecordBin:
-		args = []interface{}{uid, record.Bin.Data, record.Bin.Meta}
+		args = []interface{}{uid, record.Bin.Data, record.Bin.Meta, record.Bin.UUID, record.Bin.DataKey}
 	case models.RecordBank:
-		args = []interface{}{uid, record.Bank.Number, record.Bank.Name, record.Bank.Date, record.Bank.Cvv, record.Bank.Meta}
+		args = []interface{}{
+			uid, record.Bank.Number, record.Bank.Name, record.Bank.Date, record.Bank.Cvv, record.Bank.Meta,
+			record.Bank.UUID, record.Bank.DataKey,
+		}
 	default:
 		return fmt.Errorf("invalid record type: %q", t)
 	}
-	if _, err := s.db.ExecContext(ctx, actions[t][actionCreate].query, args...); err != nil {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed begin transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	var id models.ID
+	if err = tx.QueryRowContext(ctx, actions[t][actionCreate].query+" RETURNING id", args...).Scan(&id); err != nil {
+		return fmt.Errorf("failed create record %q for %d: %w", t.String(), uid, err)
+	}
+	if err = saveChange(ctx, tx, uid, t, id, models.ChangeCreate); err != nil {
+		return err
+	}
+	if err = tx.Commit(); err != nil {
 		return fmt.Errorf("failed create record %q for %d: %w", t.String(), uid, err)
 	}
 	return nil
@@ -317,7 +362,7 @@ func (s *Storage) isOwner(ctx context.Context, uid models.UserID, t models.Recor
 		return false, fmt.Errorf("invalid record type: %q", t.String())
 	}
 	q := query{
-		query: queryWithTable("SELECT uid FROM %s WHERE id = ?", tableName),
+		query: queryWithTable("SELECT uid FROM %s WHERE id = ? AND deleted IS NULL", tableName),
 		args:  []interface{}{id, uid},
 	}
 	var realUID int
@@ -367,18 +412,40 @@ func (s *Storage) Users(ctx context.Context) ([]models.User, error) {
 	return users, nil
 }
 
-// NewUser creates new user.
+// NewUser creates new user with its first device.
 func (s *Storage) NewUser(ctx context.Context, cn string) (models.UserID, error) {
-	q := query{
-		query: queryWithTable("INSERT INTO %s(cn) VALUES (?) RETURNING id", tableUsers),
-		args:  []interface{}{cn},
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return -1, fmt.Errorf("failed begin transaction: %w", err)
 	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+	uid, err := createUser(ctx, tx, cn)
+	if err != nil {
+		return -1, err
+	}
+	if err = tx.Commit(); err != nil {
+		return -1, fmt.Errorf("could not create user: %w", err)
+	}
+	return uid, nil
+}
+
+// createUser creates new user with its first device in transaction.
+func createUser(ctx context.Context, tx *sql.Tx, cn string) (models.UserID, error) {
 	var uid int
-	if err := s.db.QueryRowContext(ctx, q.query, q.args...).Scan(&uid); err != nil {
+	if err := tx.QueryRowContext(
+		ctx, queryWithTable("INSERT INTO %s(cn) VALUES (?) RETURNING id", tableUsers), cn,
+	).Scan(&uid); err != nil {
 		return -1, fmt.Errorf("could not create user: %w", err)
 	}
-	result := models.UserID(uid)
-	return result, nil
+	if _, err := tx.ExecContext(
+		ctx, queryWithTable("INSERT INTO %s(uid, cn, created) VALUES (?, ?, ?)", tableDevices),
+		uid, cn, time.Now().Unix(),
+	); err != nil {
+		return -1, fmt.Errorf("could not create device: %w", err)
+	}
+	return models.UserID(uid), nil
 }
 
 // IsUserExist returns true if user exists in database.
@@ -394,11 +461,16 @@ func (s *Storage) IsUserExist(ctx context.Context, user models.UserID) (bool, er
 	return count > 0, nil
 }
 
-// GetUserID returns uid by common name.
+// GetUserID returns uid by common name of device certificate. Legacy user without devices
+// is found by its own common name.
 func (s *Storage) GetUserID(ctx context.Context, cn string) (models.UserID, error) {
 	q := query{
-		query: queryWithTable("SELECT id FROM %s WHERE cn = ?", tableUsers),
-		args:  []interface{}{cn},
+		query: fmt.Sprintf(
+			"SELECT uid FROM %s WHERE cn = ? UNION ALL SELECT id FROM %s u WHERE cn = ? "+
+				"AND NOT EXISTS (SELECT 1 FROM %s d WHERE d.uid = u.id)",
+			tableDevices, tableUsers, tableDevices,
+		),
+		args: []interface{}{cn, cn},
 	}
 	var uid int
 	err := s.db.QueryRowContext(ctx, q.query, q.args...).Scan(&uid)
@@ -412,6 +484,32 @@ func (s *Storage) GetUserID(ctx context.Context, cn string) (models.UserID, erro
 	return result, nil
 }
 
+// scanRecord scans row of record of type t read by actionRead query into rec, extra columns of row
+// follow record columns.
+func scanRecord(row scanner, t models.RecordType, rec *models.RecordEncrypted, extra ...any) error {
+	var dest []any
+	// data key is NULL for legacy records, *[]byte scans NULL as nil
+	switch t {
+	case models.RecordPassword:
+		dest = []any{
+			&rec.Password.ID, &rec.Password.Login, &rec.Password.Password, &rec.Password.Meta, &rec.Password.UUID,
+			(*[]byte)(&rec.Password.DataKey),
+		}
+	case models.RecordText:
+		dest = []any{&rec.Text.ID, &rec.Text.Text, &rec.Text.Meta, &rec.Text.UUID, (*[]byte)(&rec.Text.DataKey)}
+	case models.RecordBin:
+		dest = []any{&rec.Bin.ID, &rec.Bin.Data, &rec.Bin.Meta, &rec.Bin.UUID, (*[]byte)(&rec.Bin.DataKey)}
+	case models.RecordBank:
+		dest = []any{
+			&rec.Bank.ID, &rec.Bank.Number, &rec.Bank.Name, &rec.Bank.Date, &rec.Bank.Cvv, &rec.Bank.Meta,
+			&rec.Bank.UUID, (*[]byte)(&rec.Bank.DataKey),
+		}
+	default:
+		return errors.New("unknown record")
+	}
+	return row.Scan(append(dest, extra...)...)
+}
+
 func queryWithTable(q string, t table) string {
 	return fmt.Sprintf(q, t.String())
 }
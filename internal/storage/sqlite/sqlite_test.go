@@ -513,8 +513,9 @@ func TestStorage_List(t *testing.T) {
 			want: models.RecordsEncrypted{
 				Password: []models.PasswordEncrypted{
 					{
-						ID:   testPasswordEncrypted1.ID,
-						Meta: testPasswordEncrypted1.Meta,
+						ID:       testPasswordEncrypted1.ID,
+						Meta:     testPasswordEncrypted1.Meta,
+						Revision: 1,
 					},
 				},
 				Text: []models.TextEncrypted{},
@@ -544,6 +545,9 @@ func TestStorage_List(t *testing.T) {
 }
 
 func TestStorage_Get(t *testing.T) {
+	// first record has first revision
+	found := testPasswordEncrypted1
+	found.Revision = 1
 	type fields struct {
 		db *sql.DB
 	}
@@ -572,7 +576,7 @@ func TestStorage_Get(t *testing.T) {
 				id:   models.ID(1),
 			},
 			want: models.RecordEncrypted{
-				Password: testPasswordEncrypted1,
+				Password: found,
 			},
 			wantErr: false,
 		},
@@ -676,7 +680,7 @@ func TestStorage_Update(t *testing.T) {
 				}
 				if err := s.Update(
 					tt.args.ctx, tt.args.user, tt.args.t, tt.args.id,
-					tt.args.record,
+					tt.args.record, 0,
 				); (err != nil) != tt.wantErr {
 					t.Errorf("Update() error = %v, wantErr %v", err, tt.wantErr)
 				}
@@ -734,7 +738,7 @@ func TestStorage_Delete(t *testing.T) {
 				s := &Storage{
 					db: tt.fields.db,
 				}
-				if err := s.Delete(tt.args.ctx, tt.args.user, tt.args.t, tt.args.id); (err != nil) != tt.wantErr {
+				if err := s.Delete(tt.args.ctx, tt.args.user, tt.args.t, tt.args.id, 0); (err != nil) != tt.wantErr {
 					t.Errorf("Delete() error = %v, wantErr %v", err, tt.wantErr)
 				}
 			},
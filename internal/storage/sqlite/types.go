@@ -13,15 +13,45 @@ const (
 	tableTexts
 	tableBins
 	tableBanks
+	tableBinChunks
+	tableUploads
+	tableUploadChunks
+	tableDevices
+	tableEnrollments
+	tableCertificates
+	tableRevocations
+	tableInvites
+	tableRegistrations
+	tableDisabledUsers
+	tablePasswordVersions
+	tableTextVersions
+	tableBankVersions
+	tableChanges
+	tableSchemaVersion
 )
 
 const (
-	tableUnknownName   string = "unknown"
-	tableUsersName     string = "users"
-	tablePasswordsName string = "passwords"
-	tableTextsName     string = "texts"
-	tableBinsName      string = "bins"
-	tableBanksName     string = "banks"
+	tableUnknownName          string = "unknown"
+	tableUsersName            string = "users"
+	tablePasswordsName        string = "passwords"
+	tableTextsName            string = "texts"
+	tableBinsName             string = "bins"
+	tableBanksName            string = "banks"
+	tableBinChunksName        string = "bin_chunks"
+	tableUploadsName          string = "uploads"
+	tableUploadChunksName     string = "upload_chunks"
+	tableDevicesName          string = "devices"
+	tableEnrollmentsName      string = "enrollments"
+	tableCertificatesName     string = "certificates"
+	tableRevocationsName      string = "revocations"
+	tableInvitesName          string = "invites"
+	tableRegistrationsName    string = "registrations"
+	tableDisabledUsersName    string = "disabled_users"
+	tablePasswordVersionsName string = "password_revisions"
+	tableTextVersionsName     string = "text_revisions"
+	tableBankVersionsName     string = "bank_revisions"
+	tableChangesName          string = "changes"
+	tableSchemaVersionName    string = "schema_version"
 )
 
 type action int
@@ -33,6 +63,12 @@ const (
 	actionUpdate
 	actionDelete
 	actionList
+	// actionSaveVersion saves current version of record in its history.
+	actionSaveVersion
+	actionVersions
+	actionVersion
+	// actionUpdateMeta updates only meta of binary record keeping its data and chunks.
+	actionUpdateMeta
 )
 
 type query struct {
@@ -56,6 +92,36 @@ func (t table) String() string {
 		return tableBinsName
 	case tableBanks:
 		return tableBanksName
+	case tableBinChunks:
+		return tableBinChunksName
+	case tableUploads:
+		return tableUploadsName
+	case tableUploadChunks:
+		return tableUploadChunksName
+	case tableDevices:
+		return tableDevicesName
+	case tableEnrollments:
+		return tableEnrollmentsName
+	case tableCertificates:
+		return tableCertificatesName
+	case tableRevocations:
+		return tableRevocationsName
+	case tableInvites:
+		return tableInvitesName
+	case tableRegistrations:
+		return tableRegistrationsName
+	case tableDisabledUsers:
+		return tableDisabledUsersName
+	case tablePasswordVersions:
+		return tablePasswordVersionsName
+	case tableTextVersions:
+		return tableTextVersionsName
+	case tableBankVersions:
+		return tableBankVersionsName
+	case tableChanges:
+		return tableChangesName
+	case tableSchemaVersion:
+		return tableSchemaVersionName
 	default:
 		return tableUnknownName
 	}
@@ -75,3 +141,34 @@ func tables(r models.RecordType) table {
 		return tableUnknown
 	}
 }
+
+// versionTables returns table of previous versions of record type, tableUnknown if versions of type are not kept.
+// Tables keep names given by migration "record revisions".
+func versionTables(r models.RecordType) table {
+	switch r {
+	case models.RecordPassword:
+		return tablePasswordVersions
+	case models.RecordText:
+		return tableTextVersions
+	case models.RecordBank:
+		return tableBankVersions
+	default:
+		return tableUnknown
+	}
+}
+
+// revisionOf returns pointer to revision of record of type t, nil for unknown type.
+func revisionOf(rec *models.RecordEncrypted, t models.RecordType) *int64 {
+	switch t {
+	case models.RecordPassword:
+		return &rec.Password.Revision
+	case models.RecordText:
+		return &rec.Text.Revision
+	case models.RecordBin:
+		return &rec.Bin.Revision
+	case models.RecordBank:
+		return &rec.Bank.Revision
+	default:
+		return nil
+	}
+}
@@ -0,0 +1,132 @@
+package storagetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+	"github.com/sejo412/gophkeeper/internal/server"
+)
+
+// testChanges checks that every change of record gets next revision, only last change of
+// record is returned relative to known revision and deleted records are kept as tombstones.
+func testChanges(t *testing.T, store server.Storage) {
+	ctx := context.Background()
+	owner := newUser(t, store, "alice")
+	other := newUser(t, store, "bob")
+	if changes, err := store.Changes(ctx, owner, 0); err != nil || len(changes) != 0 {
+		t.Fatalf("Changes() of new user = %v, %v, want empty", changes, err)
+	}
+	if last, err := store.LastRevision(ctx, owner); err != nil || last != 0 {
+		t.Fatalf("LastRevision() of new user = %d, %v, want 0", last, err)
+	}
+	for _, rt := range recordTypes {
+		if err := store.Add(ctx, owner, rt, newRecord(rt, "v0")); err != nil {
+			t.Fatalf("Add(%s) error = %v", rt, err)
+		}
+	}
+	if err := store.Add(ctx, other, models.RecordText, newRecord(models.RecordText, "other")); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	records, err := store.ListAll(ctx, owner)
+	if err != nil {
+		t.Fatalf("ListAll() error = %v", err)
+	}
+	created, err := store.Changes(ctx, owner, 0)
+	if err != nil || len(created) != len(recordTypes) {
+		t.Fatalf("Changes() = %v, %v, want %d changes", created, err, len(recordTypes))
+	}
+	for i, change := range created {
+		if change.Op != models.ChangeCreate || change.ID != recordID(records, change.Type) ||
+			recordValue(change.Record, change.Type) != "v0" {
+			t.Errorf("Changes() item = %+v, want created record with data", change)
+		}
+		if i > 0 && change.Revision <= created[i-1].Revision {
+			t.Errorf("Changes() revision %d after %d, want growing", change.Revision, created[i-1].Revision)
+		}
+		got, _ := store.Get(ctx, owner, change.Type, change.ID)
+		if revision := recordRevision(got, change.Type); revision != change.Revision {
+			t.Errorf("Get(%s) revision = %d, want %d", change.Type, revision, change.Revision)
+		}
+	}
+	since := created[len(created)-1].Revision
+	if last, er := store.LastRevision(ctx, owner); er != nil || last != since {
+		t.Errorf("LastRevision() = %d, %v, want %d", last, er, since)
+	}
+	if last, _ := store.LastRevision(ctx, other); last <= since {
+		t.Errorf("LastRevision() of other user = %d, want revision of its record", last)
+	}
+	if changes, _ := store.Changes(ctx, owner, since); len(changes) != 0 {
+		t.Errorf("Changes() since last revision = %v, want empty", changes)
+	}
+	if changes, _ := store.Changes(ctx, other, 0); len(changes) != 1 || recordValue(
+		changes[0].Record, models.RecordText,
+	) != "other" {
+		t.Errorf("Changes() of other user = %v, want own record only", changes)
+	}
+
+	text := recordID(records, models.RecordText)
+	password := recordID(records, models.RecordPassword)
+	for _, value := range []string{"v1", "v2"} {
+		if err = store.Update(ctx, owner, models.RecordText, text, newRecord(models.RecordText, value), 0); err != nil {
+			t.Fatalf("Update() error = %v", err)
+		}
+	}
+	if err = store.Delete(ctx, owner, models.RecordPassword, password, 0); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	changes, err := store.Changes(ctx, owner, since)
+	if err != nil || len(changes) != 2 {
+		t.Fatalf("Changes() since %d = %v, %v, want 2 changes", since, changes, err)
+	}
+	if changes[0].Op != models.ChangeUpdate || changes[0].ID != text ||
+		recordValue(changes[0].Record, models.RecordText) != "v2" {
+		t.Errorf("Changes() first = %+v, want last update of text", changes[0])
+	}
+	if changes[1].Op != models.ChangeDelete || changes[1].ID != password ||
+		changes[1].Revision <= changes[0].Revision {
+		t.Errorf("Changes() second = %+v, want tombstone of password", changes[1])
+	}
+	all, _ := store.Changes(ctx, owner, 0)
+	if len(all) != len(recordTypes) || all[len(all)-2].Op != models.ChangeCreate {
+		t.Errorf("Changes() from start = %v, want one change of every record, updated as created", all)
+	}
+
+	tombstone := changes[1]
+	if err = store.PurgeTrash(ctx, time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("PurgeTrash() error = %v", err)
+	}
+	if changes, _ = store.Changes(ctx, owner, since); len(changes) != 2 ||
+		changes[1].Op != models.ChangeDelete || changes[1].Revision != tombstone.Revision {
+		t.Errorf("Changes() after PurgeTrash() = %v, want tombstone %v kept", changes, tombstone)
+	}
+
+	bank := recordID(records, models.RecordBank)
+	if err = store.Delete(ctx, owner, models.RecordBank, bank, 0); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	since = tombstone.Revision
+	if err = store.RestoreTrash(ctx, owner, models.RecordBank, bank); err != nil {
+		t.Fatalf("RestoreTrash() error = %v", err)
+	}
+	changes, err = store.Changes(ctx, owner, since)
+	if err != nil || len(changes) != 1 || changes[0].Op != models.ChangeCreate || changes[0].ID != bank ||
+		recordValue(changes[0].Record, models.RecordBank) != "v0" {
+		t.Errorf("Changes() after RestoreTrash() = %v, %v, want bank created again", changes, err)
+	}
+}
+
+// recordRevision returns revision of record of type rt.
+func recordRevision(record models.RecordEncrypted, rt models.RecordType) int64 {
+	switch rt {
+	case models.RecordPassword:
+		return record.Password.Revision
+	case models.RecordText:
+		return record.Text.Revision
+	case models.RecordBin:
+		return record.Bin.Revision
+	default:
+		return record.Bank.Revision
+	}
+}
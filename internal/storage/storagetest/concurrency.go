@@ -0,0 +1,177 @@
+package storagetest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+	"github.com/sejo412/gophkeeper/internal/server"
+)
+
+const (
+	// workers is a count of goroutines used by concurrency tests.
+	workers = 8
+	// recordsPerWorker is a count of records added by every goroutine.
+	recordsPerWorker = 10
+)
+
+// testConcurrency checks that concurrent calls neither lose nor mix data of users.
+func testConcurrency(t *testing.T, store server.Storage) {
+	ctx := context.Background()
+	uids := make([]models.UserID, workers)
+	errs := make(chan error, workers*(recordsPerWorker+3))
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			uid, err := store.NewUser(ctx, fmt.Sprintf("user%d", w))
+			if err != nil {
+				errs <- fmt.Errorf("NewUser() error = %w", err)
+				return
+			}
+			uids[w] = uid
+			for i := range recordsPerWorker {
+				rt := recordTypes[i%len(recordTypes)]
+				if err = store.Add(ctx, uid, rt, newRecord(rt, fmt.Sprintf("user%d", w))); err != nil {
+					errs <- fmt.Errorf("Add() error = %w", err)
+				}
+				if _, err = store.ListAll(ctx, uid); err != nil {
+					errs <- fmt.Errorf("ListAll() error = %w", err)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Error(err)
+	}
+	if t.Failed() {
+		return
+	}
+	if users, err := store.Users(ctx); err != nil || len(users) != workers {
+		t.Errorf("Users() = %v, %v, want %d users", users, err, workers)
+	}
+	for w, uid := range uids {
+		all, err := store.ListAll(ctx, uid)
+		if err != nil {
+			t.Fatalf("ListAll() error = %v", err)
+		}
+		if count := len(all.Password) + len(all.Text) + len(all.Bin) + len(all.Bank); count != recordsPerWorker {
+			t.Errorf("ListAll() of user%d = %d records, want %d", w, count, recordsPerWorker)
+		}
+		for _, text := range all.Text {
+			got, er := store.Get(ctx, uid, models.RecordText, text.ID)
+			if want := fmt.Sprintf("user%d", w); er != nil || string(got.Text.Text) != want {
+				t.Errorf("Get() of user%d = %q, %v, want %q", w, got.Text.Text, er, want)
+			}
+		}
+	}
+}
+
+// testConcurrentUpload checks that chunks uploaded concurrently are all saved.
+func testConcurrentUpload(t *testing.T, store server.Storage) {
+	ctx := context.Background()
+	uid := newUser(t, store, "alice")
+	upload := models.Upload{ID: "upload", Meta: []byte("meta")}
+	if _, err := store.StartUpload(ctx, uid, upload); err != nil {
+		t.Fatalf("StartUpload() error = %v", err)
+	}
+	errs := make(chan error, workers)
+	var wg sync.WaitGroup
+	for seq := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := store.UploadChunk(ctx, uid, upload.ID, int64(seq), []byte{byte(seq)}); err != nil {
+				errs <- err
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("UploadChunk() error = %v", err)
+	}
+	id, err := store.CompleteUpload(ctx, uid, upload.ID, 0)
+	if err != nil {
+		t.Fatalf("CompleteUpload() error = %v", err)
+	}
+	if chunks, _ := store.BinChunks(ctx, uid, id); chunks != workers {
+		t.Fatalf("BinChunks() = %d, want %d", chunks, workers)
+	}
+	for seq := range workers {
+		if chunk, er := store.BinChunk(ctx, uid, id, int64(seq)); er != nil || len(chunk) != 1 || chunk[0] != byte(seq) {
+			t.Errorf("BinChunk(%d) = %v, %v, want [%d]", seq, chunk, er, seq)
+		}
+	}
+}
+
+// testConcurrentUpdate checks that concurrent updates of record save every previous version.
+func testConcurrentUpdate(t *testing.T, store server.Storage) {
+	ctx := context.Background()
+	uid := newUser(t, store, "alice")
+	if err := store.Add(ctx, uid, models.RecordText, newRecord(models.RecordText, "v0")); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	list, err := store.List(ctx, uid, models.RecordText)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	id := recordID(list, models.RecordText)
+	errs := make(chan error, workers)
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rec := newRecord(models.RecordText, fmt.Sprintf("v%d", w+1))
+			if er := store.Update(ctx, uid, models.RecordText, id, rec, 0); er != nil {
+				errs <- er
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for er := range errs {
+		t.Errorf("Update() error = %v", er)
+	}
+	versions, err := store.Versions(ctx, uid, models.RecordText, id)
+	if err != nil || len(versions) != workers {
+		t.Fatalf("Versions() = %d versions, %v, want %d", len(versions), err, workers)
+	}
+	for i, version := range versions {
+		if want := int64(workers - i); version.Number != want {
+			t.Errorf("Versions()[%d] = version %d, want %d", i, version.Number, want)
+		}
+	}
+}
+
+// testConcurrentInvite checks that single-use invite is used only once by concurrent registrations.
+func testConcurrentInvite(t *testing.T, store server.Storage) {
+	ctx := context.Background()
+	now := time.Now()
+	if err := store.NewInvite(ctx, models.Invite{Token: "token", Created: now, Expires: now.Add(time.Hour)}); err != nil {
+		t.Fatalf("NewInvite() error = %v", err)
+	}
+	var used atomic.Int32
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := store.UseInvite(ctx, "token", fmt.Sprintf("user%d", w), now); err == nil {
+				used.Add(1)
+			}
+		}()
+	}
+	wg.Wait()
+	if got := used.Load(); got != 1 {
+		t.Errorf("UseInvite() succeeded %d times, want 1", got)
+	}
+}
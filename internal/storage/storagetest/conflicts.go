@@ -0,0 +1,104 @@
+package storagetest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+	"github.com/sejo412/gophkeeper/internal/server"
+)
+
+// testConflicts checks that update and delete with expected revision fail with models.ErrConflict
+// for record changed since and keep it unchanged.
+func testConflicts(t *testing.T, store server.Storage) {
+	ctx := context.Background()
+	uid := newUser(t, store, "alice")
+	for _, rt := range recordTypes {
+		if err := store.Add(ctx, uid, rt, newRecord(rt, "v0")); err != nil {
+			t.Fatalf("Add(%s) error = %v", rt, err)
+		}
+	}
+	records, err := store.ListAll(ctx, uid)
+	if err != nil {
+		t.Fatalf("ListAll() error = %v", err)
+	}
+	for _, rt := range recordTypes {
+		id := recordID(records, rt)
+		read, er := store.Get(ctx, uid, rt, id)
+		if er != nil {
+			t.Fatalf("Get(%s) error = %v", rt, er)
+		}
+		stale := recordRevision(read, rt)
+		if er = store.Update(ctx, uid, rt, id, newRecord(rt, "v1"), stale); er != nil {
+			t.Fatalf("Update(%s) with current revision error = %v", rt, er)
+		}
+		versions, _ := store.Versions(ctx, uid, rt, id)
+		if er = store.Update(ctx, uid, rt, id, newRecord(rt, "v2"), stale); !errors.Is(er, models.ErrConflict) {
+			t.Errorf("Update(%s) with stale revision error = %v, want %v", rt, er, models.ErrConflict)
+		}
+		if er = store.Delete(ctx, uid, rt, id, stale); !errors.Is(er, models.ErrConflict) {
+			t.Errorf("Delete(%s) with stale revision error = %v, want %v", rt, er, models.ErrConflict)
+		}
+		got, _ := store.Get(ctx, uid, rt, id)
+		if recordValue(got, rt) != "v1" {
+			t.Errorf("Get(%s) after conflicts = %q, want v1", rt, recordValue(got, rt))
+		}
+		if got, _ := store.Versions(ctx, uid, rt, id); len(got) != len(versions) {
+			t.Errorf("Versions(%s) after conflicts = %d versions, want %d", rt, len(got), len(versions))
+		}
+		if er = store.Delete(ctx, uid, rt, id, recordRevision(got, rt)); er != nil {
+			t.Errorf("Delete(%s) with current revision error = %v", rt, er)
+		}
+	}
+	trash, err := store.Trash(ctx, uid)
+	if err != nil || len(trash) != len(recordTypes) {
+		t.Errorf("Trash() = %v, %v, want %d items", trash, err, len(recordTypes))
+	}
+}
+
+// testConcurrentConflicts checks that only one of concurrent updates with same expected revision
+// succeeds.
+func testConcurrentConflicts(t *testing.T, store server.Storage) {
+	ctx := context.Background()
+	uid := newUser(t, store, "alice")
+	if err := store.Add(ctx, uid, models.RecordText, newRecord(models.RecordText, "v0")); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	list, err := store.List(ctx, uid, models.RecordText)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	id := recordID(list, models.RecordText)
+	rev := list.Text[0].Revision
+	errs := make(chan error, workers)
+	var updated atomic.Int32
+	var wg sync.WaitGroup
+	for w := range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			rec := newRecord(models.RecordText, fmt.Sprintf("v%d", w+1))
+			switch er := store.Update(ctx, uid, models.RecordText, id, rec, rev); {
+			case er == nil:
+				updated.Add(1)
+			case !errors.Is(er, models.ErrConflict):
+				errs <- er
+			}
+		}()
+	}
+	wg.Wait()
+	close(errs)
+	for er := range errs {
+		t.Errorf("Update() error = %v", er)
+	}
+	if updated.Load() != 1 {
+		t.Errorf("Update() succeeded %d times, want once", updated.Load())
+	}
+	if versions, _ := store.Versions(ctx, uid, models.RecordText, id); len(versions) != 1 {
+		t.Errorf("Versions() = %d versions, want 1", len(versions))
+	}
+}
@@ -0,0 +1,223 @@
+package storagetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+	"github.com/sejo412/gophkeeper/internal/server"
+)
+
+// testIsolation checks that user can't read or change records, uploads and devices of other user.
+func testIsolation(t *testing.T, store server.Storage) {
+	ctx := context.Background()
+	owner := newUser(t, store, "alice")
+	other := newUser(t, store, "bob")
+	for _, rt := range recordTypes {
+		if err := store.Add(ctx, owner, rt, newRecord(rt, "secret")); err != nil {
+			t.Fatalf("Add(%s) error = %v", rt, err)
+		}
+	}
+	records, err := store.ListAll(ctx, owner)
+	if err != nil {
+		t.Fatalf("ListAll() error = %v", err)
+	}
+	if all, _ := store.ListAll(ctx, other); len(all.Password)+len(all.Text)+len(all.Bin)+len(all.Bank) != 0 {
+		t.Errorf("ListAll() of other user = %v, want empty", all)
+	}
+	for _, rt := range recordTypes {
+		id := recordID(records, rt)
+		if list, _ := store.List(ctx, other, rt); len(recordIDs(list, rt)) != 0 {
+			t.Errorf("List(%s) of other user = %v, want empty", rt, list)
+		}
+		if ok, _ := store.IsExist(ctx, other, rt, id); ok {
+			t.Errorf("IsExist(%s) of other user = true, want false", rt)
+		}
+		if _, err = store.Get(ctx, other, rt, id); err == nil {
+			t.Errorf("Get(%s) of other user error = nil, want error", rt)
+		}
+		if err = store.Update(ctx, other, rt, id, newRecord(rt, "stolen"), 0); err == nil {
+			t.Errorf("Update(%s) of other user error = nil, want error", rt)
+		}
+		if err = store.Delete(ctx, other, rt, id, 0); err == nil {
+			t.Errorf("Delete(%s) of other user error = nil, want error", rt)
+		}
+		got, er := store.Get(ctx, owner, rt, id)
+		if er != nil || recordValue(got, rt) != "secret" {
+			t.Errorf("Get(%s) of owner = %+v, %v, want not changed record", rt, got, er)
+		}
+	}
+
+	bin := recordID(records, models.RecordBin)
+	if _, err = store.BinChunks(ctx, other, bin); err == nil {
+		t.Errorf("BinChunks() of other user error = nil, want error")
+	}
+	if _, err = store.BinChunk(ctx, other, bin, 0); err == nil {
+		t.Errorf("BinChunk() of other user error = nil, want error")
+	}
+	replace := models.Upload{ID: "replace", RecordID: bin, Meta: []byte("meta")}
+	if _, err = store.StartUpload(ctx, other, replace); err == nil {
+		t.Errorf("StartUpload() replacing bin of other user error = nil, want error")
+	}
+	upload := models.Upload{ID: "upload", Meta: []byte("meta"), UUID: "b2"}
+	if _, err = store.StartUpload(ctx, owner, upload); err != nil {
+		t.Fatalf("StartUpload() error = %v", err)
+	}
+	if err = store.UploadChunk(ctx, other, upload.ID, 0, []byte("chunk")); err == nil {
+		t.Errorf("UploadChunk() to upload of other user error = nil, want error")
+	}
+	if status, _ := store.UploadStatus(ctx, other, upload.ID); status.Chunks != 0 || len(status.Meta) != 0 {
+		t.Errorf("UploadStatus() of other user = %+v, want empty", status)
+	}
+	if _, err = store.CompleteUpload(ctx, other, upload.ID, 0); err == nil {
+		t.Errorf("CompleteUpload() of other user error = nil, want error")
+	}
+	if _, err = store.StartUpload(ctx, other, upload); err != nil {
+		t.Fatalf("StartUpload() with same id by other user error = %v", err)
+	}
+	if err = store.UploadChunk(ctx, owner, upload.ID, 0, []byte("chunk")); err != nil {
+		t.Fatalf("UploadChunk() error = %v", err)
+	}
+	if status, _ := store.UploadStatus(ctx, other, upload.ID); status.Chunks != 0 {
+		t.Errorf("UploadStatus() of upload with same id = %d chunks, want 0", status.Chunks)
+	}
+
+	devices, err := store.Devices(ctx, owner)
+	if err != nil || len(devices) != 1 {
+		t.Fatalf("Devices() = %v, %v, want one device", devices, err)
+	}
+	if err = store.DeleteDevice(ctx, other, devices[0].ID); err == nil {
+		t.Errorf("DeleteDevice() of other user error = nil, want error")
+	}
+	enrollment := models.Enrollment{
+		Code: "code", UserID: owner, Cn: "laptop", CertRequest: []byte("csr"), Created: time.Now(),
+	}
+	if err = store.NewEnrollment(ctx, enrollment); err != nil {
+		t.Fatalf("NewEnrollment() error = %v", err)
+	}
+	if pending, _ := store.Enrollments(ctx, other); len(pending) != 0 {
+		t.Errorf("Enrollments() of other user = %v, want empty", pending)
+	}
+	if err = store.ApproveEnrollment(ctx, other, "code", models.Encrypted("vault")); err == nil {
+		t.Errorf("ApproveEnrollment() by other user error = nil, want error")
+	}
+	if id, _ := store.GetUserID(ctx, "laptop"); id != -1 {
+		t.Errorf("GetUserID() of not approved device = %v, want -1", id)
+	}
+	if id, _ := store.GetUserID(ctx, "bob"); id != other {
+		t.Errorf("GetUserID() of other user = %v, want %v", id, other)
+	}
+
+	if err = store.DisableUser(ctx, owner, true); err != nil {
+		t.Fatalf("DisableUser() error = %v", err)
+	}
+	if disabled, _ := store.IsUserDisabled(ctx, other); disabled {
+		t.Errorf("IsUserDisabled() of other user = true, want false")
+	}
+}
+
+// testNotFound checks that operations with not existing objects return error or empty result.
+func testNotFound(t *testing.T, store server.Storage) {
+	ctx := context.Background()
+	const unknown = 1000
+	uid := newUser(t, store, "alice")
+	if ok, _ := store.IsUserExist(ctx, unknown); ok {
+		t.Errorf("IsUserExist() of unknown user = true, want false")
+	}
+	if id, _ := store.GetUserID(ctx, "unknown"); id != -1 {
+		t.Errorf("GetUserID() of unknown device = %v, want -1", id)
+	}
+	for _, rt := range recordTypes {
+		if err := store.Add(ctx, unknown, rt, newRecord(rt, "data")); err == nil {
+			t.Errorf("Add(%s) for unknown user error = nil, want error", rt)
+		}
+		if ok, err := store.IsExist(ctx, uid, rt, unknown); err != nil || ok {
+			t.Errorf("IsExist(%s) of unknown record = %v, %v, want false", rt, ok, err)
+		}
+		if _, err := store.Get(ctx, uid, rt, unknown); err == nil {
+			t.Errorf("Get(%s) of unknown record error = nil, want error", rt)
+		}
+		if err := store.Update(ctx, uid, rt, unknown, newRecord(rt, "data"), 0); err == nil {
+			t.Errorf("Update(%s) of unknown record error = nil, want error", rt)
+		}
+		if err := store.Delete(ctx, uid, rt, unknown, 0); err == nil {
+			t.Errorf("Delete(%s) of unknown record error = nil, want error", rt)
+		}
+	}
+	if all, err := store.ListAll(ctx, unknown); err != nil || len(all.Password)+len(all.Bin) != 0 {
+		t.Errorf("ListAll() of unknown user = %v, %v, want empty", all, err)
+	}
+
+	if _, err := store.StartUpload(ctx, unknown, models.Upload{ID: "upload"}); err == nil {
+		t.Errorf("StartUpload() for unknown user error = nil, want error")
+	}
+	if _, err := store.StartUpload(ctx, uid, models.Upload{ID: "upload", RecordID: unknown}); err == nil {
+		t.Errorf("StartUpload() replacing unknown bin error = nil, want error")
+	}
+	if status, err := store.UploadStatus(ctx, uid, "unknown"); err != nil || status.Chunks != 0 {
+		t.Errorf("UploadStatus() of unknown upload = %+v, %v, want empty", status, err)
+	}
+	if err := store.UploadChunk(ctx, uid, "unknown", 0, []byte("chunk")); err == nil {
+		t.Errorf("UploadChunk() to unknown upload error = nil, want error")
+	}
+	if _, err := store.CompleteUpload(ctx, uid, "unknown", 0); err == nil {
+		t.Errorf("CompleteUpload() of unknown upload error = nil, want error")
+	}
+	if _, err := store.BinChunks(ctx, uid, unknown); err == nil {
+		t.Errorf("BinChunks() of unknown bin error = nil, want error")
+	}
+	if err := store.Add(ctx, uid, models.RecordBin, newRecord(models.RecordBin, "data")); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	bins, _ := store.List(ctx, uid, models.RecordBin)
+	if _, err := store.BinChunk(ctx, uid, bins.Bin[0].ID, 1); err == nil {
+		t.Errorf("BinChunk() out of range error = nil, want error")
+	}
+
+	if id, _ := store.FindUser(ctx, "unknown"); id != -1 {
+		t.Errorf("FindUser() of unknown user = %v, want -1", id)
+	}
+	if devices, err := store.Devices(ctx, unknown); err != nil || len(devices) != 0 {
+		t.Errorf("Devices() of unknown user = %v, %v, want empty", devices, err)
+	}
+	if err := store.DeleteDevice(ctx, uid, unknown); err == nil {
+		t.Errorf("DeleteDevice() of unknown device error = nil, want error")
+	}
+	if _, err := store.Enrollment(ctx, "unknown"); err == nil {
+		t.Errorf("Enrollment() of unknown code error = nil, want error")
+	}
+	if err := store.ApproveEnrollment(ctx, uid, "unknown", models.Encrypted("vault")); err == nil {
+		t.Errorf("ApproveEnrollment() of unknown code error = nil, want error")
+	}
+	if err := store.DeleteUser(ctx, unknown); err == nil {
+		t.Errorf("DeleteUser() of unknown user error = nil, want error")
+	}
+	if disabled, err := store.IsUserDisabled(ctx, unknown); err != nil || disabled {
+		t.Errorf("IsUserDisabled() of unknown user = %v, %v, want false", disabled, err)
+	}
+
+	if ok, err := store.IsRevoked(ctx, "unknown"); err != nil || ok {
+		t.Errorf("IsRevoked() of unknown serial = %v, %v, want false", ok, err)
+	}
+	now := time.Now()
+	if err := store.UseInvite(ctx, "unknown", "bob", now); err == nil {
+		t.Errorf("UseInvite() of unknown token error = nil, want error")
+	}
+	expired := models.Invite{Token: "expired", Created: now.Add(-2 * time.Hour), Expires: now.Add(-time.Hour)}
+	if err := store.NewInvite(ctx, expired); err != nil {
+		t.Fatalf("NewInvite() error = %v", err)
+	}
+	if err := store.UseInvite(ctx, "expired", "bob", now); err == nil {
+		t.Errorf("UseInvite() of expired token error = nil, want error")
+	}
+	if _, err := store.Registration(ctx, "unknown"); err == nil {
+		t.Errorf("Registration() of unknown code error = nil, want error")
+	}
+	if err := store.ApproveRegistration(ctx, "unknown"); err == nil {
+		t.Errorf("ApproveRegistration() of unknown code error = nil, want error")
+	}
+	if err := store.DeleteRegistration(ctx, "unknown"); err == nil {
+		t.Errorf("DeleteRegistration() of unknown code error = nil, want error")
+	}
+}
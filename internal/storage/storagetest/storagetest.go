@@ -0,0 +1,641 @@
+// Package storagetest provides conformance tests for implementations of server.Storage.
+package storagetest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+	"github.com/sejo412/gophkeeper/internal/server"
+)
+
+// recordTypes are all types of records.
+var recordTypes = []models.RecordType{
+	models.RecordPassword, models.RecordText, models.RecordBin, models.RecordBank,
+}
+
+// RunConformance runs conformance tests of Storage: every method, isolation of users,
+// errors for not existing objects and concurrent access. newStorage must return opened
+// empty Storage, every test gets its own Storage and closes it.
+func RunConformance(t *testing.T, newStorage func() server.Storage) {
+	t.Helper()
+	tests := []struct {
+		name string
+		fn   func(t *testing.T, store server.Storage)
+	}{
+		{name: "schema", fn: testSchema},
+		{name: "reset", fn: testReset},
+		{name: "users", fn: testUsers},
+		{name: "records", fn: testRecords},
+		{name: "list", fn: testList},
+		{name: "versions", fn: testVersions},
+		{name: "trash", fn: testTrash},
+		{name: "changes", fn: testChanges},
+		{name: "conflicts", fn: testConflicts},
+		{name: "uploads", fn: testUploads},
+		{name: "devices", fn: testDevices},
+		{name: "certificates", fn: testCertificates},
+		{name: "registrations", fn: testRegistrations},
+		{name: "admin", fn: testAdmin},
+		{name: "isolation", fn: testIsolation},
+		{name: "not found", fn: testNotFound},
+		{name: "concurrency", fn: testConcurrency},
+		{name: "concurrent upload", fn: testConcurrentUpload},
+		{name: "concurrent update", fn: testConcurrentUpdate},
+		{name: "concurrent conflicts", fn: testConcurrentConflicts},
+		{name: "concurrent invite", fn: testConcurrentInvite},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				tt.fn(t, open(t, newStorage))
+			},
+		)
+	}
+}
+
+// open returns initialized Storage closed on cleanup.
+func open(t *testing.T, newStorage func() server.Storage) server.Storage {
+	t.Helper()
+	store := newStorage()
+	t.Cleanup(
+		func() {
+			_ = store.Close()
+		},
+	)
+	if err := store.Init(context.Background()); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	return store
+}
+
+// newUser creates user and fails test on error.
+func newUser(t *testing.T, store server.Storage, cn string) models.UserID {
+	t.Helper()
+	uid, err := store.NewUser(context.Background(), cn)
+	if err != nil {
+		t.Fatalf("NewUser(%q) error = %v", cn, err)
+	}
+	return uid
+}
+
+// newRecord returns record of type rt with all fields set to value.
+func newRecord(rt models.RecordType, value string) models.RecordEncrypted {
+	data := []byte(value)
+	switch rt {
+	case models.RecordPassword:
+		return models.RecordEncrypted{
+			Password: models.PasswordEncrypted{Login: data, Password: data, Meta: data, UUID: value},
+		}
+	case models.RecordText:
+		return models.RecordEncrypted{Text: models.TextEncrypted{Text: data, Meta: data, UUID: value}}
+	case models.RecordBin:
+		return models.RecordEncrypted{Bin: models.BinEncrypted{Data: data, Meta: data, UUID: value}}
+	default:
+		return models.RecordEncrypted{
+			Bank: models.BankEncrypted{Number: data, Name: data, Date: data, Cvv: data, Meta: data, UUID: value},
+		}
+	}
+}
+
+// recordValue returns meta of record of type rt.
+func recordValue(record models.RecordEncrypted, rt models.RecordType) string {
+	switch rt {
+	case models.RecordPassword:
+		return string(record.Password.Meta)
+	case models.RecordText:
+		return string(record.Text.Meta)
+	case models.RecordBin:
+		return string(record.Bin.Meta)
+	default:
+		return string(record.Bank.Meta)
+	}
+}
+
+// recordIDs returns ids of listed records of type rt.
+func recordIDs(records models.RecordsEncrypted, rt models.RecordType) []models.ID {
+	ids := make([]models.ID, 0)
+	switch rt {
+	case models.RecordPassword:
+		for _, r := range records.Password {
+			ids = append(ids, r.ID)
+		}
+	case models.RecordText:
+		for _, r := range records.Text {
+			ids = append(ids, r.ID)
+		}
+	case models.RecordBin:
+		for _, r := range records.Bin {
+			ids = append(ids, r.ID)
+		}
+	default:
+		for _, r := range records.Bank {
+			ids = append(ids, r.ID)
+		}
+	}
+	return ids
+}
+
+// recordID returns id of first listed record of type rt, 0 if there are no records.
+func recordID(records models.RecordsEncrypted, rt models.RecordType) models.ID {
+	if ids := recordIDs(records, rt); len(ids) > 0 {
+		return ids[0]
+	}
+	return 0
+}
+
+func testSchema(t *testing.T, store server.Storage) {
+	ctx := context.Background()
+	current, latest, err := store.SchemaVersion(ctx)
+	if err != nil || current != latest || latest == 0 {
+		t.Fatalf("SchemaVersion() after Init = %d, %d, %v, want latest", current, latest, err)
+	}
+	if err = store.Init(ctx); err != nil {
+		t.Errorf("second Init() error = %v", err)
+	}
+	applied, err := store.Migrate(ctx)
+	if err != nil || len(applied) != 0 {
+		t.Errorf("Migrate() of migrated storage = %v, %v, want nothing applied", applied, err)
+	}
+	migrations, err := store.Migrations(ctx)
+	if err != nil || len(migrations) != latest {
+		t.Errorf("Migrations() = %v, %v, want %d migrations", migrations, err, latest)
+	}
+}
+
+func testReset(t *testing.T, store server.Storage) {
+	ctx := context.Background()
+	uid := newUser(t, store, "alice")
+	if err := store.Add(ctx, uid, models.RecordText, newRecord(models.RecordText, "text")); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if err := store.Reset(ctx); err != nil {
+		t.Fatalf("Reset() error = %v", err)
+	}
+	if current, _, err := store.SchemaVersion(ctx); err != nil || current != 0 {
+		t.Errorf("SchemaVersion() after Reset() = %d, %v, want 0", current, err)
+	}
+	if err := store.Reset(ctx); err != nil {
+		t.Errorf("Reset() of empty storage error = %v", err)
+	}
+	if err := store.Init(ctx); err != nil {
+		t.Fatalf("Init() after Reset() error = %v", err)
+	}
+	if users, err := store.Users(ctx); err != nil || len(users) != 0 {
+		t.Errorf("Users() after Reset() = %v, %v, want empty", users, err)
+	}
+}
+
+func testUsers(t *testing.T, store server.Storage) {
+	ctx := context.Background()
+	uid := newUser(t, store, "alice")
+	if _, err := store.NewUser(ctx, "alice"); err == nil {
+		t.Errorf("NewUser() with used name error = nil, want error")
+	}
+	other := newUser(t, store, "bob")
+	if uid == other {
+		t.Fatalf("NewUser() returned same id %v for different users", uid)
+	}
+	users, err := store.Users(ctx)
+	if err != nil || len(users) != 2 || users[0].Cn != "alice" || users[1].Cn != "bob" {
+		t.Errorf("Users() = %v, %v, want alice and bob", users, err)
+	}
+	if ok, _ := store.IsUserExist(ctx, uid); !ok {
+		t.Errorf("IsUserExist() = false, want true")
+	}
+	if id, _ := store.FindUser(ctx, "alice"); id != uid {
+		t.Errorf("FindUser() = %v, want %v", id, uid)
+	}
+	if id, _ := store.FindUser(ctx, "unknown"); id != -1 {
+		t.Errorf("FindUser() of unknown user = %v, want -1", id)
+	}
+	if id, _ := store.GetUserID(ctx, "alice"); id != uid {
+		t.Errorf("GetUserID() of first device = %v, want %v", id, uid)
+	}
+}
+
+func testRecords(t *testing.T, store server.Storage) {
+	ctx := context.Background()
+	uid := newUser(t, store, "alice")
+	records := map[models.RecordType]models.RecordEncrypted{
+		models.RecordPassword: {
+			Password: models.PasswordEncrypted{
+				Login: []byte("login"), Password: []byte("password"), Meta: []byte("meta"), UUID: "p1",
+				DataKey: []byte("key"),
+			},
+		},
+		models.RecordText: {Text: models.TextEncrypted{Text: []byte("text"), Meta: []byte("meta"), UUID: "t1"}},
+		models.RecordBin:  {Bin: models.BinEncrypted{Data: []byte("data"), Meta: []byte("meta"), UUID: "b1"}},
+		models.RecordBank: {
+			Bank: models.BankEncrypted{
+				Number: []byte("number"), Name: []byte("name"), Date: []byte("date"), Cvv: []byte("cvv"),
+				Meta: []byte("meta"), UUID: "c1",
+			},
+		},
+	}
+	for rt, record := range records {
+		if err := store.Add(ctx, uid, rt, record); err != nil {
+			t.Fatalf("Add(%s) error = %v", rt, err)
+		}
+	}
+	all, err := store.ListAll(ctx, uid)
+	if err != nil || len(all.Password) != 1 || len(all.Text) != 1 || len(all.Bin) != 1 || len(all.Bank) != 1 {
+		t.Fatalf("ListAll() = %v, %v, want one record of every type", all, err)
+	}
+	if string(all.Password[0].Meta) != "meta" || all.Password[0].UUID != "p1" ||
+		string(all.Password[0].DataKey) != "key" {
+		t.Errorf("ListAll() password = %+v, want meta, uuid and data key", all.Password[0])
+	}
+	if len(all.Text[0].DataKey) != 0 {
+		t.Errorf("ListAll() text data key = %v, want empty", all.Text[0].DataKey)
+	}
+
+	id := all.Text[0].ID
+	got, err := store.Get(ctx, uid, models.RecordText, id)
+	if err != nil || string(got.Text.Text) != "text" || got.Text.UUID != "t1" {
+		t.Errorf("Get() = %+v, %v, want text", got.Text, err)
+	}
+	bank, err := store.Get(ctx, uid, models.RecordBank, all.Bank[0].ID)
+	if err != nil || string(bank.Bank.Cvv) != "cvv" || string(bank.Bank.Date) != "date" {
+		t.Errorf("Get() = %+v, %v, want bank card", bank.Bank, err)
+	}
+	if ok, _ := store.IsExist(ctx, uid, models.RecordText, id); !ok {
+		t.Errorf("IsExist() = false, want true")
+	}
+	update := models.RecordEncrypted{Text: models.TextEncrypted{Text: []byte("new"), Meta: []byte("new meta")}}
+	if err = store.Update(ctx, uid, models.RecordText, id, update, 0); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if got, _ = store.Get(ctx, uid, models.RecordText, id); string(got.Text.Text) != "new" {
+		t.Errorf("Get() after Update() = %q, want %q", got.Text.Text, "new")
+	}
+	if err = store.Delete(ctx, uid, models.RecordText, id, 0); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if ok, _ := store.IsExist(ctx, uid, models.RecordText, id); ok {
+		t.Errorf("IsExist() after Delete() = true, want false")
+	}
+	if _, err = store.Get(ctx, uid, models.RecordText, id); err == nil {
+		t.Errorf("Get() after Delete() error = nil, want error")
+	}
+}
+
+func testList(t *testing.T, store server.Storage) {
+	ctx := context.Background()
+	uid := newUser(t, store, "alice")
+	for _, rt := range recordTypes {
+		if list, err := store.List(ctx, uid, rt); err != nil || len(recordIDs(list, rt)) != 0 {
+			t.Errorf("List(%s) of new user = %v, %v, want empty", rt, list, err)
+		}
+		for _, value := range []string{"first", "second", "third"} {
+			if err := store.Add(ctx, uid, rt, newRecord(rt, value)); err != nil {
+				t.Fatalf("Add(%s) error = %v", rt, err)
+			}
+		}
+	}
+	for _, rt := range recordTypes {
+		list, err := store.List(ctx, uid, rt)
+		if err != nil {
+			t.Fatalf("List(%s) error = %v", rt, err)
+		}
+		ids := recordIDs(list, rt)
+		if len(ids) != 3 {
+			t.Fatalf("List(%s) = %v, want 3 records", rt, ids)
+		}
+		for _, other := range recordTypes {
+			if other != rt && len(recordIDs(list, other)) != 0 {
+				t.Errorf("List(%s) returned records of type %s", rt, other)
+			}
+		}
+		for i, want := range []string{"first", "second", "third"} {
+			if i > 0 && ids[i] <= ids[i-1] {
+				t.Errorf("List(%s) ids = %v, want ascending", rt, ids)
+			}
+			got, er := store.Get(ctx, uid, rt, ids[i])
+			if er != nil || recordValue(got, rt) != want {
+				t.Errorf("Get(%s, %d) = %q, %v, want %q", rt, ids[i], recordValue(got, rt), er, want)
+			}
+		}
+	}
+}
+
+func testUploads(t *testing.T, store server.Storage) {
+	ctx := context.Background()
+	uid := newUser(t, store, "alice")
+	upload := models.Upload{ID: "upload", Meta: []byte("meta"), UUID: "b1", DataKey: []byte("key")}
+	if chunks, err := store.StartUpload(ctx, uid, upload); err != nil || chunks != 0 {
+		t.Fatalf("StartUpload() = %d, %v, want 0 chunks", chunks, err)
+	}
+	for seq, chunk := range []string{"first", "second"} {
+		if err := store.UploadChunk(ctx, uid, upload.ID, int64(seq), []byte(chunk)); err != nil {
+			t.Fatalf("UploadChunk(%d) error = %v", seq, err)
+		}
+	}
+	status, err := store.UploadStatus(ctx, uid, upload.ID)
+	if err != nil || status.Chunks != 2 || status.UUID != "b1" {
+		t.Errorf("UploadStatus() = %+v, %v, want 2 chunks", status, err)
+	}
+	if chunks, _ := store.StartUpload(ctx, uid, upload); chunks != 2 {
+		t.Errorf("StartUpload() of started upload = %d, want 2 chunks", chunks)
+	}
+	id, err := store.CompleteUpload(ctx, uid, upload.ID, 0)
+	if err != nil {
+		t.Fatalf("CompleteUpload() error = %v", err)
+	}
+	if chunks, _ := store.BinChunks(ctx, uid, id); chunks != 2 {
+		t.Errorf("BinChunks() = %d, want 2", chunks)
+	}
+	chunk, err := store.BinChunk(ctx, uid, id, 1)
+	if err != nil || string(chunk) != "second" {
+		t.Errorf("BinChunk() = %q, %v, want %q", chunk, err, "second")
+	}
+	if status, _ = store.UploadStatus(ctx, uid, upload.ID); status.Chunks != 0 {
+		t.Errorf("UploadStatus() after complete = %+v, want empty", status)
+	}
+
+	// meta of chunked bin is updated without data, chunks are kept only for the same data key
+	meta := models.RecordEncrypted{Bin: models.BinEncrypted{Meta: []byte("renamed"), UUID: "b1", DataKey: []byte("other")}}
+	if err = store.Update(ctx, uid, models.RecordBin, id, meta, 0); err == nil {
+		t.Errorf("Update() meta with other data key error = nil, want error")
+	}
+	meta.Bin.DataKey = upload.DataKey
+	if err = store.Update(ctx, uid, models.RecordBin, id, meta, 0); err != nil {
+		t.Fatalf("Update() meta error = %v", err)
+	}
+	if chunks, _ := store.BinChunks(ctx, uid, id); chunks != 2 {
+		t.Errorf("BinChunks() after meta update = %d, want 2", chunks)
+	}
+	if got, _ := store.Get(ctx, uid, models.RecordBin, id); string(got.Bin.Meta) != "renamed" {
+		t.Errorf("Get() meta after meta update = %q, want %q", got.Bin.Meta, "renamed")
+	}
+	if err = store.Update(ctx, uid, models.RecordBin, id, newRecord(models.RecordBin, "data"), 0); err != nil {
+		t.Fatalf("Update() data error = %v", err)
+	}
+	if chunks, _ := store.BinChunks(ctx, uid, id); chunks != 1 {
+		t.Errorf("BinChunks() after data update = %d, want 1", chunks)
+	}
+
+	// replacing data by upload checks revision like Update
+	replace := models.Upload{ID: "replace", RecordID: id, Meta: []byte("meta"), UUID: "b1", DataKey: []byte("key")}
+	if _, err = store.StartUpload(ctx, uid, replace); err != nil {
+		t.Fatalf("StartUpload() replace error = %v", err)
+	}
+	if err = store.UploadChunk(ctx, uid, replace.ID, 0, []byte("replaced")); err != nil {
+		t.Fatalf("UploadChunk() replace error = %v", err)
+	}
+	stored, _ := store.Get(ctx, uid, models.RecordBin, id)
+	rev := recordRevision(stored, models.RecordBin)
+	if _, err = store.CompleteUpload(ctx, uid, replace.ID, rev-1); !errors.Is(err, models.ErrConflict) {
+		t.Errorf("CompleteUpload() of changed record error = %v, want %v", err, models.ErrConflict)
+	}
+	if _, err = store.CompleteUpload(ctx, uid, replace.ID, rev); err != nil {
+		t.Fatalf("CompleteUpload() replace error = %v", err)
+	}
+	if chunk, _ := store.BinChunk(ctx, uid, id, 0); string(chunk) != "replaced" {
+		t.Errorf("BinChunk() after replace = %q, want %q", chunk, "replaced")
+	}
+	if stored, _ = store.Get(ctx, uid, models.RecordBin, id); recordRevision(stored, models.RecordBin) <= rev {
+		t.Errorf("revision after replace = %d, want greater than %d", recordRevision(stored, models.RecordBin), rev)
+	}
+
+	if err = store.Add(ctx, uid, models.RecordBin, models.RecordEncrypted{
+		Bin: models.BinEncrypted{Data: []byte("data"), Meta: []byte("meta")},
+	}); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	bins, _ := store.List(ctx, uid, models.RecordBin)
+	if len(bins.Bin) != 2 {
+		t.Fatalf("List() = %v, want 2 bins", bins.Bin)
+	}
+	added := bins.Bin[1].ID
+	if chunks, _ := store.BinChunks(ctx, uid, added); chunks != 1 {
+		t.Errorf("BinChunks() of added record = %d, want 1", chunks)
+	}
+	if chunk, _ = store.BinChunk(ctx, uid, added, 0); !bytes.Equal(chunk, []byte("data")) {
+		t.Errorf("BinChunk() of added record = %q, want %q", chunk, "data")
+	}
+}
+
+func testDevices(t *testing.T, store server.Storage) {
+	ctx := context.Background()
+	uid := newUser(t, store, "alice")
+	enrollment := models.Enrollment{
+		Code: "code", UserID: uid, Cn: "laptop", CertRequest: []byte("csr"), Created: time.Now(),
+	}
+	if err := store.NewEnrollment(ctx, enrollment); err != nil {
+		t.Fatalf("NewEnrollment() error = %v", err)
+	}
+	taken := enrollment
+	taken.Code = "taken"
+	taken.Cn = "alice"
+	if err := store.NewEnrollment(ctx, taken); err == nil {
+		t.Errorf("NewEnrollment() with used name error = nil, want error")
+	}
+	pending, err := store.Enrollments(ctx, uid)
+	if err != nil || len(pending) != 1 || pending[0].Cn != "laptop" {
+		t.Fatalf("Enrollments() = %v, %v, want laptop", pending, err)
+	}
+	if err = store.ApproveEnrollment(ctx, uid, "code", models.Encrypted("vault")); err != nil {
+		t.Fatalf("ApproveEnrollment() error = %v", err)
+	}
+	got, err := store.Enrollment(ctx, "code")
+	if err != nil || !got.Approved || string(got.VaultKey) != "vault" {
+		t.Errorf("Enrollment() = %+v, %v, want approved with vault key", got, err)
+	}
+	if id, _ := store.GetUserID(ctx, "laptop"); id != uid {
+		t.Errorf("GetUserID() of approved device = %v, want %v", id, uid)
+	}
+	devices, err := store.Devices(ctx, uid)
+	if err != nil || len(devices) != 2 {
+		t.Fatalf("Devices() = %v, %v, want 2 devices", devices, err)
+	}
+	if err = store.DeleteDevice(ctx, uid, devices[1].ID); err != nil {
+		t.Fatalf("DeleteDevice() error = %v", err)
+	}
+	if id, _ := store.GetUserID(ctx, "laptop"); id != -1 {
+		t.Errorf("GetUserID() of removed device = %v, want -1", id)
+	}
+	if err = store.CompleteEnrollment(ctx, "code"); err != nil {
+		t.Fatalf("CompleteEnrollment() error = %v", err)
+	}
+	if err = store.CompleteEnrollment(ctx, "code"); err == nil {
+		t.Errorf("CompleteEnrollment() twice error = nil, want error")
+	}
+	if _, err = store.Enrollment(ctx, "code"); err == nil {
+		t.Errorf("Enrollment() after complete error = nil, want error")
+	}
+	pendingCode := enrollment
+	pendingCode.Code, pendingCode.Cn = "pending", "phone"
+	if err = store.NewEnrollment(ctx, pendingCode); err != nil {
+		t.Fatalf("NewEnrollment() error = %v", err)
+	}
+	if err = store.CompleteEnrollment(ctx, "pending"); err == nil {
+		t.Errorf("CompleteEnrollment() of not approved error = nil, want error")
+	}
+	if err = store.PurgeEnrollments(ctx, time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("PurgeEnrollments() error = %v", err)
+	}
+	if _, err = store.Enrollment(ctx, "pending"); err == nil {
+		t.Errorf("Enrollment() after purge error = nil, want error")
+	}
+}
+
+func testCertificates(t *testing.T, store server.Storage) {
+	ctx := context.Background()
+	uid := newUser(t, store, "alice")
+	now := time.Now()
+	for _, serial := range []string{"a1", "a2"} {
+		if err := store.AddCertificate(
+			ctx, models.Certificate{Serial: serial, UserID: uid, Cn: "alice", Issued: now, NotAfter: now.Add(time.Hour)},
+		); err != nil {
+			t.Fatalf("AddCertificate() error = %v", err)
+		}
+	}
+	revocation := models.Revocation{Serial: "a1", Cn: "alice", Reason: models.RevocationKeyCompromise, Revoked: now}
+	if err := store.Revoke(ctx, revocation); err != nil {
+		t.Fatalf("Revoke() error = %v", err)
+	}
+	again := revocation
+	again.Reason = models.RevocationSuperseded
+	if err := store.Revoke(ctx, again); err != nil {
+		t.Errorf("Revoke() of revoked certificate error = %v", err)
+	}
+	if ok, _ := store.IsRevoked(ctx, "a1"); !ok {
+		t.Errorf("IsRevoked() = false, want true")
+	}
+	if ok, _ := store.IsRevoked(ctx, "a2"); ok {
+		t.Errorf("IsRevoked() of valid certificate = true, want false")
+	}
+	revocations, err := store.Revocations(ctx)
+	if err != nil || len(revocations) != 1 || revocations[0].Reason != models.RevocationKeyCompromise {
+		t.Errorf("Revocations() = %v, %v, want first revocation only", revocations, err)
+	}
+	certificates, err := store.Certificates(ctx)
+	if err != nil || len(certificates) != 2 || !certificates[0].Revoked || certificates[1].Revoked {
+		t.Errorf("Certificates() = %v, %v, want first revoked", certificates, err)
+	}
+}
+
+func testRegistrations(t *testing.T, store server.Storage) {
+	ctx := context.Background()
+	now := time.Now()
+	if err := store.NewInvite(ctx, models.Invite{Token: "token", Created: now, Expires: now.Add(time.Hour)}); err != nil {
+		t.Fatalf("NewInvite() error = %v", err)
+	}
+	if err := store.UseInvite(ctx, "token", "alice", now); err != nil {
+		t.Fatalf("UseInvite() error = %v", err)
+	}
+	if err := store.UseInvite(ctx, "token", "bob", now); err == nil {
+		t.Errorf("UseInvite() of used invite error = nil, want error")
+	}
+	invites, err := store.Invites(ctx)
+	if err != nil || len(invites) != 1 || invites[0].Cn != "alice" {
+		t.Errorf("Invites() = %v, %v, want invite used by alice", invites, err)
+	}
+
+	registration := models.Registration{Code: "code", Cn: "bob", CertRequest: []byte("csr"), Created: now}
+	if err = store.NewRegistration(ctx, registration); err != nil {
+		t.Fatalf("NewRegistration() error = %v", err)
+	}
+	if err = store.NewRegistration(ctx, registration); err == nil {
+		t.Errorf("NewRegistration() with used name error = nil, want error")
+	}
+	if pending, _ := store.Registrations(ctx); len(pending) != 1 {
+		t.Errorf("Registrations() = %v, want one registration", pending)
+	}
+	if err = store.ApproveRegistration(ctx, "code"); err != nil {
+		t.Fatalf("ApproveRegistration() error = %v", err)
+	}
+	if got, _ := store.Registration(ctx, "code"); !got.Approved {
+		t.Errorf("Registration() approved = false, want true")
+	}
+	if pending, _ := store.Registrations(ctx); len(pending) != 0 {
+		t.Errorf("Registrations() after approve = %v, want empty", pending)
+	}
+	owner := newUser(t, store, "owner")
+	used := models.Certificate{Serial: "c1", UserID: owner, Cn: "owner", Issued: now, NotAfter: now.Add(time.Hour)}
+	if err = store.AddCertificate(ctx, used); err != nil {
+		t.Fatalf("AddCertificate() error = %v", err)
+	}
+	if _, err = store.CompleteRegistration(ctx, "code", used); err == nil {
+		t.Errorf("CompleteRegistration() with used serial error = nil, want error")
+	}
+	if got, _ := store.Registration(ctx, "code"); !got.Approved {
+		t.Errorf("Registration() after failed completion = %v, want approved registration", got)
+	}
+	if uid, _ := store.GetUserID(ctx, "bob"); uid >= 0 {
+		t.Errorf("GetUserID() after failed completion = %v, want -1", uid)
+	}
+	issued := models.Certificate{Serial: "c2", Cn: "bob", Issued: now, NotAfter: now.Add(time.Hour)}
+	uid, err := store.CompleteRegistration(ctx, "code", issued)
+	if err != nil {
+		t.Fatalf("CompleteRegistration() error = %v", err)
+	}
+	if got, _ := store.GetUserID(ctx, "bob"); got != uid {
+		t.Errorf("GetUserID() = %v, want %v", got, uid)
+	}
+	certificates, err := store.Certificates(ctx)
+	if err != nil || len(certificates) != 2 || certificates[1].Serial != "c2" || certificates[1].UserID != uid {
+		t.Errorf("Certificates() = %v, %v, want certificate of registered user", certificates, err)
+	}
+	if _, err = store.CompleteRegistration(ctx, "code", issued); err == nil {
+		t.Errorf("CompleteRegistration() of spent registration error = nil, want error")
+	}
+
+	rejected := models.Registration{Code: "rejected", Cn: "carol", CertRequest: []byte("csr"), Created: now}
+	if err = store.NewRegistration(ctx, rejected); err != nil {
+		t.Fatalf("NewRegistration() error = %v", err)
+	}
+	if _, err = store.CompleteRegistration(ctx, "rejected", issued); err == nil {
+		t.Errorf("CompleteRegistration() of not approved registration error = nil, want error")
+	}
+	if err = store.DeleteRegistration(ctx, "rejected"); err != nil {
+		t.Fatalf("DeleteRegistration() error = %v", err)
+	}
+	if err = store.PurgeRegistrations(ctx, now.Add(time.Hour)); err != nil {
+		t.Errorf("PurgeRegistrations() error = %v", err)
+	}
+}
+
+func testAdmin(t *testing.T, store server.Storage) {
+	ctx := context.Background()
+	uid := newUser(t, store, "alice")
+	other := newUser(t, store, "bob")
+	record := models.RecordEncrypted{Text: models.TextEncrypted{Text: []byte("text"), Meta: []byte("meta")}}
+	for _, id := range []models.UserID{uid, uid, other} {
+		if err := store.Add(ctx, id, models.RecordText, record); err != nil {
+			t.Fatalf("Add() error = %v", err)
+		}
+	}
+	stats, err := store.Stats(ctx)
+	if err != nil || len(stats) != 2 {
+		t.Fatalf("Stats() = %v, %v, want 2 users", stats, err)
+	}
+	if got := stats[0].Records[models.RecordText]; got.Count != 2 || got.Bytes != 16 {
+		t.Errorf("Stats() texts = %+v, want 2 records of 16 bytes", got)
+	}
+	if err = store.DisableUser(ctx, uid, true); err != nil {
+		t.Fatalf("DisableUser() error = %v", err)
+	}
+	if disabled, _ := store.IsUserDisabled(ctx, uid); !disabled {
+		t.Errorf("IsUserDisabled() = false, want true")
+	}
+	if err = store.DisableUser(ctx, uid, false); err != nil {
+		t.Fatalf("DisableUser() error = %v", err)
+	}
+	if disabled, _ := store.IsUserDisabled(ctx, uid); disabled {
+		t.Errorf("IsUserDisabled() after enable = true, want false")
+	}
+	if err = store.DeleteUser(ctx, uid); err != nil {
+		t.Fatalf("DeleteUser() error = %v", err)
+	}
+	if id, _ := store.FindUser(ctx, "alice"); id != -1 {
+		t.Errorf("FindUser() of deleted user = %v, want -1", id)
+	}
+	if records, _ := store.List(ctx, other, models.RecordText); len(records.Text) != 1 {
+		t.Errorf("List() of other user after delete = %v, want one record", records.Text)
+	}
+}
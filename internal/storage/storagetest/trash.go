@@ -0,0 +1,133 @@
+package storagetest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+	"github.com/sejo412/gophkeeper/internal/server"
+)
+
+// testTrash checks that delete moves record to trash of its owner, restore returns it back
+// and empty or purge of trash delete it permanently with its chunks.
+func testTrash(t *testing.T, store server.Storage) {
+	ctx := context.Background()
+	owner := newUser(t, store, "alice")
+	other := newUser(t, store, "bob")
+	for _, rt := range recordTypes {
+		if err := store.Add(ctx, owner, rt, newRecord(rt, "v0")); err != nil {
+			t.Fatalf("Add(%s) error = %v", rt, err)
+		}
+	}
+	records, err := store.ListAll(ctx, owner)
+	if err != nil {
+		t.Fatalf("ListAll() error = %v", err)
+	}
+	for _, rt := range recordTypes {
+		id := recordID(records, rt)
+		if err = store.Delete(ctx, owner, rt, id, 0); err != nil {
+			t.Fatalf("Delete(%s) error = %v", rt, err)
+		}
+		if list, _ := store.List(ctx, owner, rt); len(recordIDs(list, rt)) != 0 {
+			t.Errorf("List(%s) after Delete() = %v, want empty", rt, list)
+		}
+		if _, er := store.Get(ctx, owner, rt, id); er == nil {
+			t.Errorf("Get(%s) of record in trash error = nil, want error", rt)
+		}
+		if ok, _ := store.IsExist(ctx, owner, rt, id); ok {
+			t.Errorf("IsExist(%s) of record in trash = true, want false", rt)
+		}
+		if er := store.Update(ctx, owner, rt, id, newRecord(rt, "v1"), 0); er == nil {
+			t.Errorf("Update(%s) of record in trash error = nil, want error", rt)
+		}
+		if er := store.Delete(ctx, owner, rt, id, 0); er == nil {
+			t.Errorf("Delete(%s) of record in trash error = nil, want error", rt)
+		}
+	}
+
+	trash, err := store.Trash(ctx, owner)
+	if err != nil || len(trash) != len(recordTypes) {
+		t.Fatalf("Trash() = %v, %v, want %d items", trash, err, len(recordTypes))
+	}
+	for _, item := range trash {
+		if recordValue(item.Record, item.Type) != "v0" || item.Deleted.IsZero() {
+			t.Errorf("Trash() item = %+v, want meta and deletion time", item)
+		}
+	}
+	if list, _ := store.Trash(ctx, other); len(list) != 0 {
+		t.Errorf("Trash() of other user = %v, want empty", list)
+	}
+
+	text := recordID(records, models.RecordText)
+	if err = store.RestoreTrash(ctx, other, models.RecordText, text); err == nil {
+		t.Errorf("RestoreTrash() by other user error = nil, want error")
+	}
+	if err = store.RestoreTrash(ctx, owner, models.RecordText, text); err != nil {
+		t.Fatalf("RestoreTrash() error = %v", err)
+	}
+	if got, er := store.Get(ctx, owner, models.RecordText, text); er != nil || string(got.Text.Text) != "v0" {
+		t.Errorf("Get() after RestoreTrash() = %+v, %v, want restored record", got, er)
+	}
+	if err = store.RestoreTrash(ctx, owner, models.RecordText, text); err == nil {
+		t.Errorf("RestoreTrash() of restored record error = nil, want error")
+	}
+
+	if err = store.PurgeTrash(ctx, time.Now().Add(-time.Hour)); err != nil {
+		t.Fatalf("PurgeTrash() error = %v", err)
+	}
+	if list, _ := store.Trash(ctx, owner); len(list) != len(recordTypes)-1 {
+		t.Errorf("Trash() after PurgeTrash() of old items = %v, want %d items", list, len(recordTypes)-1)
+	}
+	if err = store.PurgeTrash(ctx, time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("PurgeTrash() error = %v", err)
+	}
+	if list, _ := store.Trash(ctx, owner); len(list) != 0 {
+		t.Errorf("Trash() after PurgeTrash() = %v, want empty", list)
+	}
+	password := recordID(records, models.RecordPassword)
+	if err = store.RestoreTrash(ctx, owner, models.RecordPassword, password); err == nil {
+		t.Errorf("RestoreTrash() of purged record error = nil, want error")
+	}
+	if got, _ := store.Get(ctx, owner, models.RecordText, text); string(got.Text.Text) != "v0" {
+		t.Errorf("Get() of restored record after PurgeTrash() = %+v, want kept", got)
+	}
+
+	upload := models.Upload{ID: "upload", Meta: []byte("meta")}
+	if _, err = store.StartUpload(ctx, owner, upload); err != nil {
+		t.Fatalf("StartUpload() error = %v", err)
+	}
+	if err = store.UploadChunk(ctx, owner, upload.ID, 0, []byte("chunk")); err != nil {
+		t.Fatalf("UploadChunk() error = %v", err)
+	}
+	bin, err := store.CompleteUpload(ctx, owner, upload.ID, 0)
+	if err != nil {
+		t.Fatalf("CompleteUpload() error = %v", err)
+	}
+	for _, item := range []struct {
+		rt models.RecordType
+		id models.ID
+	}{{rt: models.RecordText, id: text}, {rt: models.RecordBin, id: bin}} {
+		if err = store.Delete(ctx, owner, item.rt, item.id, 0); err != nil {
+			t.Fatalf("Delete(%s) error = %v", item.rt, err)
+		}
+	}
+	if _, err = store.BinChunk(ctx, owner, bin, 0); err == nil {
+		t.Errorf("BinChunk() of record in trash error = nil, want error")
+	}
+	if err = store.EmptyTrash(ctx, other); err != nil {
+		t.Fatalf("EmptyTrash() of other user error = %v", err)
+	}
+	if list, _ := store.Trash(ctx, owner); len(list) != 2 {
+		t.Errorf("Trash() after EmptyTrash() of other user = %v, want 2 items", list)
+	}
+	if err = store.EmptyTrash(ctx, owner); err != nil {
+		t.Fatalf("EmptyTrash() error = %v", err)
+	}
+	if list, _ := store.Trash(ctx, owner); len(list) != 0 {
+		t.Errorf("Trash() after EmptyTrash() = %v, want empty", list)
+	}
+	if err = store.RestoreTrash(ctx, owner, models.RecordBin, bin); err == nil {
+		t.Errorf("RestoreTrash() of emptied record error = nil, want error")
+	}
+}
@@ -0,0 +1,129 @@
+package storagetest
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/sejo412/gophkeeper/internal/models"
+	"github.com/sejo412/gophkeeper/internal/server"
+)
+
+// testVersions checks that update saves previous version of record and purge keeps limits.
+func testVersions(t *testing.T, store server.Storage) {
+	ctx := context.Background()
+	owner := newUser(t, store, "alice")
+	other := newUser(t, store, "bob")
+	for _, rt := range recordTypes {
+		if err := store.Add(ctx, owner, rt, newRecord(rt, "v0")); err != nil {
+			t.Fatalf("Add(%s) error = %v", rt, err)
+		}
+	}
+	records, err := store.ListAll(ctx, owner)
+	if err != nil {
+		t.Fatalf("ListAll() error = %v", err)
+	}
+	for _, rt := range recordTypes {
+		id := recordID(records, rt)
+		for i := 1; i <= 3; i++ {
+			if err = store.Update(ctx, owner, rt, id, newRecord(rt, fmt.Sprintf("v%d", i)), 0); err != nil {
+				t.Fatalf("Update(%s) error = %v", rt, err)
+			}
+		}
+		versions, er := store.Versions(ctx, owner, rt, id)
+		if er != nil {
+			t.Fatalf("Versions(%s) error = %v", rt, er)
+		}
+		if rt == models.RecordBin {
+			if len(versions) != 0 {
+				t.Errorf("Versions(%s) = %v, want empty", rt, versions)
+			}
+			continue
+		}
+		if len(versions) != 3 {
+			t.Fatalf("Versions(%s) = %d versions, want 3", rt, len(versions))
+		}
+		for i, version := range versions {
+			want := fmt.Sprintf("v%d", 2-i)
+			if version.Number != int64(3-i) || recordValue(version.Record, rt) != want || version.Created.IsZero() {
+				t.Errorf("Versions(%s)[%d] = %+v, want version %d with %q", rt, i, version, 3-i, want)
+			}
+		}
+		version, er := store.Version(ctx, owner, rt, id, 1)
+		if er != nil || version.Number != 1 || recordValue(version.Record, rt) != "v0" {
+			t.Errorf("Version(%s, 1) = %+v, %v, want first version", rt, version, er)
+		}
+		if rt == models.RecordText && (string(version.Record.Text.Text) != "v0" || version.Record.Text.UUID != "v0") {
+			t.Errorf("Version(%s, 1) = %+v, want all fields of first version", rt, version.Record.Text)
+		}
+		if got, _ := store.Get(ctx, owner, rt, id); recordValue(got, rt) != "v3" {
+			t.Errorf("Get(%s) = %+v, want last version", rt, got)
+		}
+		if _, er = store.Version(ctx, owner, rt, id, 4); er == nil {
+			t.Errorf("Version(%s, 4) error = nil, want error", rt)
+		}
+		if list, _ := store.Versions(ctx, other, rt, id); len(list) != 0 {
+			t.Errorf("Versions(%s) of other user = %v, want empty", rt, list)
+		}
+		if _, er = store.Version(ctx, other, rt, id, 1); er == nil {
+			t.Errorf("Version(%s, 1) of other user error = nil, want error", rt)
+		}
+	}
+
+	text := recordID(records, models.RecordText)
+	if err = store.TrimVersions(ctx, models.RecordText, text, 2); err != nil {
+		t.Fatalf("TrimVersions() error = %v", err)
+	}
+	if versions, _ := store.Versions(ctx, owner, models.RecordText, text); len(versions) != 2 ||
+		versions[1].Number != 2 {
+		t.Errorf("Versions() after TrimVersions(2) = %v, want versions 3 and 2", versions)
+	}
+	bank := recordID(records, models.RecordBank)
+	if versions, _ := store.Versions(ctx, owner, models.RecordBank, bank); len(versions) != 3 {
+		t.Errorf("Versions() of other record after TrimVersions() = %v, want all kept", versions)
+	}
+	if err = store.TrimVersions(ctx, models.RecordBin, recordID(records, models.RecordBin), 2); err != nil {
+		t.Errorf("TrimVersions() of bin error = %v, want nil", err)
+	}
+	if err = store.Update(ctx, owner, models.RecordText, text, newRecord(models.RecordText, "v4"), 0); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if versions, _ := store.Versions(ctx, owner, models.RecordText, text); len(versions) != 3 ||
+		versions[0].Number != 4 {
+		t.Errorf("Versions() after purge and Update() = %v, want version 4 first", versions)
+	}
+	if err = store.PurgeVersions(ctx, time.Now().Add(-time.Hour)); err != nil {
+		t.Fatalf("PurgeVersions() error = %v", err)
+	}
+	if versions, _ := store.Versions(ctx, owner, models.RecordText, text); len(versions) != 3 {
+		t.Errorf("Versions() after PurgeVersions() of older = %v, want kept", versions)
+	}
+	if err = store.PurgeVersions(ctx, time.Now().Add(time.Hour)); err != nil {
+		t.Fatalf("PurgeVersions() error = %v", err)
+	}
+	for _, rt := range []models.RecordType{models.RecordText, models.RecordBank} {
+		if versions, _ := store.Versions(ctx, owner, rt, recordID(records, rt)); len(versions) != 0 {
+			t.Errorf("Versions(%s) after PurgeVersions() = %v, want empty", rt, versions)
+		}
+	}
+
+	password := recordID(records, models.RecordPassword)
+	if err = store.Update(
+		ctx, owner, models.RecordPassword, password, newRecord(models.RecordPassword, "v4"), 0,
+	); err != nil {
+		t.Fatalf("Update() error = %v", err)
+	}
+	if err = store.Delete(ctx, owner, models.RecordPassword, password, 0); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if versions, _ := store.Versions(ctx, owner, models.RecordPassword, password); len(versions) != 1 {
+		t.Errorf("Versions() of record in trash = %v, want kept", versions)
+	}
+	if err = store.EmptyTrash(ctx, owner); err != nil {
+		t.Fatalf("EmptyTrash() error = %v", err)
+	}
+	if _, err = store.Version(ctx, owner, models.RecordPassword, password, 1); err == nil {
+		t.Errorf("Version() of purged record error = nil, want error")
+	}
+}
@@ -213,7 +213,8 @@ func GenRsaCert(request CertRequest, signer CASigner) (certOut, keyOut []byte, e
 	}
 	if request.IsCA {
 		template.NotAfter = time.Now().AddDate(caYears, 0, 0)
-		template.KeyUsage = x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign | x509.KeyUsageKeyEncipherment
+		template.KeyUsage = x509.KeyUsageDigitalSignature | x509.KeyUsageCertSign | x509.KeyUsageKeyEncipherment |
+			x509.KeyUsageCRLSign
 	} else {
 		template.NotAfter = time.Now().AddDate(nonCaYears, 0, 0)
 		template.KeyUsage = x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment | x509.KeyUsageContentCommitment
@@ -0,0 +1,50 @@
+package certs
+
+import (
+	"crypto/rand"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+)
+
+// SerialText returns hex representation of certificate serial number.
+func SerialText(serial *big.Int) string {
+	return serial.Text(16)
+}
+
+// ParseSerial parses serial number returned by SerialText.
+func ParseSerial(serial string) (*big.Int, error) {
+	n, ok := new(big.Int).SetString(serial, 16)
+	if !ok {
+		return nil, fmt.Errorf("invalid serial number %q", serial)
+	}
+	return n, nil
+}
+
+// CreateCRL returns DER encoded certificate revocation list signed by CA and valid for validity.
+// CA certificate must have CRL sign key usage.
+func CreateCRL(signer CASigner, revoked []x509.RevocationListEntry, number *big.Int, validity time.Duration) (
+	[]byte, error,
+) {
+	if signer.CAKey == nil {
+		return nil, errors.New("CA key not loaded")
+	}
+	caCert, err := x509.ParseCertificate(signer.CACert)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse CA certificate: %w", err)
+	}
+	now := time.Now()
+	template := &x509.RevocationList{
+		RevokedCertificateEntries: revoked,
+		Number:                    number,
+		ThisUpdate:                now,
+		NextUpdate:                now.Add(validity),
+	}
+	crl, err := x509.CreateRevocationList(rand.Reader, template, caCert, signer.CAKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create CRL: %w", err)
+	}
+	return crl, nil
+}
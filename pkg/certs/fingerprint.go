@@ -0,0 +1,24 @@
+package certs
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"strings"
+)
+
+// Fingerprint returns SHA-256 fingerprint of DER encoded certificate as colon separated hex.
+func Fingerprint(der []byte) string {
+	sum := sha256.Sum256(der)
+	parts := make([]string, len(sum))
+	for i, b := range sum {
+		parts[i] = fmt.Sprintf("%02X", b)
+	}
+	return strings.Join(parts, ":")
+}
+
+// FingerprintMatch returns true if DER encoded certificate has fingerprint given
+// in any case with or without colons.
+func FingerprintMatch(der []byte, fingerprint string) bool {
+	normalize := strings.NewReplacer(":", "", " ", "")
+	return strings.EqualFold(normalize.Replace(Fingerprint(der)), normalize.Replace(fingerprint))
+}
@@ -1,6 +1,7 @@
 package crypt
 
 import (
+	"bytes"
 	"crypto/aes"
 	"crypto/cipher"
 	"crypto/rand"
@@ -10,60 +11,113 @@ import (
 	"fmt"
 )
 
-// EncryptWithPublicKey encrypts data with public RSA and AES.
+// EncryptWithPublicKey encrypts data with public RSA and AES. Result starts with Envelope.
 func EncryptWithPublicKey(pubKey *rsa.PublicKey, data []byte) ([]byte, error) {
-	aesKey := make([]byte, 32)
-	if _, err := rand.Read(aesKey); err != nil {
-		return nil, fmt.Errorf("failed to generate AES key: %w", err)
+	return EncryptWithAssociatedData(pubKey, data, nil)
+}
+
+// EncryptWithAssociatedData encrypts data like EncryptWithPublicKey and authenticates Envelope
+// and associatedData, which must be passed to DecryptWithAssociatedData.
+func EncryptWithAssociatedData(pubKey *rsa.PublicKey, data, associatedData []byte) ([]byte, error) {
+	aesKey, err := newDataKey()
+	if err != nil {
+		return nil, err
 	}
-	encryptedKey, err := rsa.EncryptOAEP(
-		sha256.New(),
-		rand.Reader,
-		pubKey,
-		aesKey,
-		nil,
-	)
+	envelope, err := newEnvelope(pubKey, CipherSuiteAES256GCM, aesKey)
 	if err != nil {
-		return nil, fmt.Errorf("failed to encrypt AES key: %w", err)
+		return nil, err
+	}
+	header, err := envelope.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode envelope: %w", err)
+	}
+	additionalData, err := envelope.additionalData(associatedData)
+	if err != nil {
+		return nil, err
 	}
 
-	encryptedData, err := encryptWithAES(aesKey, data)
+	encryptedData, err := encryptWithAES(aesKey, data, additionalData)
 	if err != nil {
 		return nil, fmt.Errorf("failed to encrypt data: %w", err)
 	}
 
-	res := make([]byte, len(encryptedKey)+len(encryptedData))
-	copy(res[:len(encryptedKey)], encryptedKey)
-	copy(res[len(encryptedKey):], encryptedData)
-
-	return res, nil
+	return append(header, encryptedData...), nil
 }
 
 // DecryptWithPrivateKey decrypts data with RSA and AES.
+// Legacy data without Envelope (encrypted key || AES-GCM data) is accepted too.
 func DecryptWithPrivateKey(privKey *rsa.PrivateKey, data []byte) ([]byte, error) {
+	return DecryptWithAssociatedData(privKey, data, nil)
+}
+
+// DecryptWithAssociatedData decrypts data encrypted by EncryptWithAssociatedData,
+// fails if associatedData differs.
+func DecryptWithAssociatedData(privKey *rsa.PrivateKey, data, associatedData []byte) ([]byte, error) {
+	if !hasEnvelope(data) {
+		return decryptLegacy(privKey, data, associatedData)
+	}
+	r := bytes.NewReader(data)
+	envelope, err := readEnvelope(r)
+	if err != nil {
+		// legacy encrypted key may start with magic by chance
+		if res, er := decryptLegacy(privKey, data, associatedData); er == nil {
+			return res, nil
+		}
+		return nil, fmt.Errorf("failed to read envelope: %w", err)
+	}
+	aesKey, err := envelope.unwrapKey(privKey, CipherSuiteAES256GCM)
+	if err != nil {
+		return nil, err
+	}
+	additionalData, err := envelope.additionalData(associatedData)
+	if err != nil {
+		return nil, err
+	}
+	return decryptWithAES(aesKey, data[len(data)-r.Len():], additionalData)
+}
+
+// decryptLegacy decrypts data without Envelope where encrypted key size is privKey.Size().
+func decryptLegacy(privKey *rsa.PrivateKey, data, associatedData []byte) ([]byte, error) {
 	keySize := privKey.Size()
 	if len(data) < keySize {
 		return nil, errors.New("ciphertext too short")
 	}
-	encryptedKey := data[:keySize]
-	data = data[keySize:]
+	aesKey, err := unwrapKey(privKey, data[:keySize])
+	if err != nil {
+		return nil, err
+	}
+	return decryptWithAES(aesKey, data[keySize:], associatedData)
+}
 
-	aesKey, err := rsa.DecryptOAEP(
-		sha256.New(),
-		rand.Reader,
-		privKey,
-		encryptedKey,
-		nil,
-	)
+// newDataKey generates random AES-256 key.
+func newDataKey() ([]byte, error) {
+	aesKey := make([]byte, 32)
+	if _, err := rand.Read(aesKey); err != nil {
+		return nil, fmt.Errorf("failed to generate AES key: %w", err)
+	}
+	return aesKey, nil
+}
+
+// wrapKey encrypts AES key with RSA-OAEP.
+func wrapKey(pubKey *rsa.PublicKey, aesKey []byte) ([]byte, error) {
+	encryptedKey, err := rsa.EncryptOAEP(sha256.New(), rand.Reader, pubKey, aesKey, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to decrypt AES key: %w", err)
+		return nil, fmt.Errorf("failed to encrypt AES key: %w", err)
 	}
+	return encryptedKey, nil
+}
 
-	return decryptWithAES(aesKey, data)
+// unwrapKey decrypts AES key with RSA-OAEP.
+func unwrapKey(privKey *rsa.PrivateKey, encryptedKey []byte) ([]byte, error) {
+	aesKey, err := rsa.DecryptOAEP(sha256.New(), rand.Reader, privKey, encryptedKey, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt AES key: %w", err)
+	}
+	return aesKey, nil
 }
 
 // encryptWithAES encrypts data with AES-GCM.
-func encryptWithAES(key, data []byte) ([]byte, error) {
+func encryptWithAES(key, data, associatedData []byte) ([]byte, error) {
 	block, err := aes.NewCipher(key)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create AES cipher: %w", err)
@@ -79,11 +133,11 @@ func encryptWithAES(key, data []byte) ([]byte, error) {
 		return nil, fmt.Errorf("failed to generate nonce: %w", err)
 	}
 
-	return gcm.Seal(nonce, nonce, data, nil), nil
+	return gcm.Seal(nonce, nonce, data, associatedData), nil
 }
 
 // decryptWithAES decrypts data with AES-GCM.
-func decryptWithAES(key, ciphertext []byte) ([]byte, error) {
+func decryptWithAES(key, ciphertext, associatedData []byte) ([]byte, error) {
 	block, err := aes.NewCipher(key)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create AES cipher: %w", err)
@@ -100,5 +154,5 @@ func decryptWithAES(key, ciphertext []byte) ([]byte, error) {
 	}
 
 	nonce, ciphertext := ciphertext[:nonceSize], ciphertext[nonceSize:]
-	return gcm.Open(nil, nonce, ciphertext, nil)
+	return gcm.Open(nil, nonce, ciphertext, associatedData)
 }
@@ -0,0 +1,81 @@
+package crypt
+
+import (
+	"bytes"
+	"crypto/rsa"
+	"fmt"
+)
+
+// NewDataKey generates random data key for EncryptWithDataKey.
+func NewDataKey() ([]byte, error) {
+	return newDataKey()
+}
+
+// WrapKey encrypts data key with public RSA. Result is Envelope without data.
+func WrapKey(pubKey *rsa.PublicKey, dataKey []byte) ([]byte, error) {
+	envelope, err := newEnvelope(pubKey, CipherSuiteAES256GCM, dataKey)
+	if err != nil {
+		return nil, err
+	}
+	res, err := envelope.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode envelope: %w", err)
+	}
+	return res, nil
+}
+
+// UnwrapKey decrypts data key wrapped by WrapKey.
+func UnwrapKey(privKey *rsa.PrivateKey, wrappedKey []byte) ([]byte, error) {
+	r := bytes.NewReader(wrappedKey)
+	envelope, err := readEnvelope(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read envelope: %w", err)
+	}
+	if r.Len() != 0 {
+		return nil, fmt.Errorf("unexpected data after wrapped key")
+	}
+	return envelope.unwrapKey(privKey, CipherSuiteAES256GCM)
+}
+
+// EncryptWithDataKey encrypts data with AES-GCM and data key, authenticates Envelope and associatedData.
+// Result starts with Envelope without wrapped key.
+func EncryptWithDataKey(dataKey, data, associatedData []byte) ([]byte, error) {
+	envelope := Envelope{
+		Version:     EnvelopeVersion,
+		KeyWrap:     KeyWrapDataKey,
+		CipherSuite: CipherSuiteAES256GCM,
+	}
+	header, err := envelope.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode envelope: %w", err)
+	}
+	additionalData, err := envelope.additionalData(associatedData)
+	if err != nil {
+		return nil, err
+	}
+	encryptedData, err := encryptWithAES(dataKey, data, additionalData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt data: %w", err)
+	}
+	return append(header, encryptedData...), nil
+}
+
+// DecryptWithDataKey decrypts data encrypted by EncryptWithDataKey.
+func DecryptWithDataKey(dataKey, data, associatedData []byte) ([]byte, error) {
+	r := bytes.NewReader(data)
+	envelope, err := readEnvelope(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read envelope: %w", err)
+	}
+	if envelope.KeyWrap != KeyWrapDataKey {
+		return nil, fmt.Errorf("unexpected key wrap algorithm %d", envelope.KeyWrap)
+	}
+	if envelope.CipherSuite != CipherSuiteAES256GCM {
+		return nil, fmt.Errorf("unsupported cipher suite %d", envelope.CipherSuite)
+	}
+	additionalData, err := envelope.additionalData(associatedData)
+	if err != nil {
+		return nil, err
+	}
+	return decryptWithAES(dataKey, data[len(data)-r.Len():], additionalData)
+}
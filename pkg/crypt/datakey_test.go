@@ -0,0 +1,114 @@
+package crypt
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestWrapKey(t *testing.T) {
+	key := testKey(t)
+	dataKey, err := NewDataKey()
+	if err != nil || len(dataKey) != 32 {
+		t.Fatalf("NewDataKey() = %x, %v, want 32 bytes", dataKey, err)
+	}
+	wrapped, err := WrapKey(&key.PublicKey, dataKey)
+	if err != nil {
+		t.Fatalf("WrapKey() error = %v", err)
+	}
+	if got, er := UnwrapKey(key, wrapped); er != nil || !bytes.Equal(got, dataKey) {
+		t.Errorf("UnwrapKey() = %x, %v, want %x", got, er, dataKey)
+	}
+	tests := []struct {
+		name    string
+		key     func() []byte
+		wantErr string
+	}{
+		{name: "data after key", key: func() []byte { return append(bytes.Clone(wrapped), 0) }, wantErr: "unexpected data"},
+		{name: "truncated", key: func() []byte { return wrapped[:len(wrapped)-1] }, wantErr: "failed to read envelope"},
+		{name: "no envelope", key: func() []byte { return dataKey }, wantErr: "failed to read envelope"},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				if _, er := UnwrapKey(key, tt.key()); er == nil || !strings.Contains(er.Error(), tt.wantErr) {
+					t.Errorf("UnwrapKey() error = %v, want %q", er, tt.wantErr)
+				}
+			},
+		)
+	}
+	if _, err = UnwrapKey(testKey(t), wrapped); err == nil || !strings.Contains(err.Error(), "another key") {
+		t.Errorf("UnwrapKey() with other key error = %v, want another key", err)
+	}
+}
+
+func TestEncryptWithDataKey(t *testing.T) {
+	dataKey, err := NewDataKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	otherKey, err := NewDataKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := []byte("secret")
+	encrypted, err := EncryptWithDataKey(dataKey, data, []byte("text/text/uuid"))
+	if err != nil {
+		t.Fatalf("EncryptWithDataKey() error = %v", err)
+	}
+	if bytes.Contains(encrypted, dataKey) {
+		t.Errorf("EncryptWithDataKey() result contains data key")
+	}
+	rsaEncrypted, err := EncryptWithPublicKey(&testKey(t).PublicKey, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name           string
+		key            []byte
+		data           []byte
+		associatedData []byte
+		wantErr        string
+	}{
+		{name: "valid", key: dataKey, data: encrypted, associatedData: []byte("text/text/uuid")},
+		{
+			name: "other associated data", key: dataKey, data: encrypted, associatedData: []byte("text/meta/uuid"),
+			wantErr: "authentication failed",
+		},
+		{
+			name: "other key", key: otherKey, data: encrypted, associatedData: []byte("text/text/uuid"),
+			wantErr: "authentication failed",
+		},
+		{
+			name: "envelope with key ID", key: dataKey, associatedData: []byte("text/text/uuid"),
+			data: reencode(
+				t, encrypted, func(e *Envelope) {
+					e.KeyID = []byte("key")
+				},
+			),
+			wantErr: "authentication failed",
+		},
+		{
+			name: "downgraded envelope", key: dataKey, data: reencode(t, encrypted, downgrade),
+			associatedData: []byte("text/text/uuid"), wantErr: "authentication failed",
+		},
+		{name: "wrapped by RSA", key: dataKey, data: rsaEncrypted, wantErr: "unexpected key wrap"},
+		{name: "no envelope", key: dataKey, data: []byte("legacy"), wantErr: "failed to read envelope"},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				got, er := DecryptWithDataKey(tt.key, tt.data, tt.associatedData)
+				if tt.wantErr != "" {
+					if er == nil || !strings.Contains(er.Error(), tt.wantErr) {
+						t.Errorf("DecryptWithDataKey() error = %v, want %q", er, tt.wantErr)
+					}
+					return
+				}
+				if er != nil || !bytes.Equal(got, data) {
+					t.Errorf("DecryptWithDataKey() = %q, %v, want %q", got, er, data)
+				}
+			},
+		)
+	}
+}
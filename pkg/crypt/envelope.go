@@ -0,0 +1,178 @@
+package crypt
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// EnvelopeVersion is a current version of ciphertext envelope. Since version 2 encoded envelope
+// is authenticated together with associated data of ciphertext.
+const EnvelopeVersion byte = 2
+
+// envelopeVersionLegacy is a version of envelope which isn't authenticated by ciphertext.
+const envelopeVersionLegacy byte = 1
+
+// keyIDSize is a size of key ID in envelope.
+const keyIDSize = 8
+
+// envelopeMagic starts every ciphertext with envelope.
+var envelopeMagic = []byte("GKE\x00")
+
+// KeyWrap is an algorithm which encrypts data key.
+type KeyWrap byte
+
+const (
+	KeyWrapUnknown KeyWrap = iota
+	// KeyWrapRSAOAEPSHA256 is RSA-OAEP with SHA-256.
+	KeyWrapRSAOAEPSHA256
+	// KeyWrapDataKey means data key isn't in envelope, it's wrapped once for many ciphertexts, see WrapKey.
+	KeyWrapDataKey
+	// KeyWrapArgon2id means data key is derived from password by Argon2id, envelope keeps its parameters.
+	KeyWrapArgon2id
+)
+
+// CipherSuite is an algorithm which encrypts data with data key.
+type CipherSuite byte
+
+const (
+	CipherSuiteUnknown CipherSuite = iota
+	// CipherSuiteAES256GCM is AES-256-GCM with random nonce before ciphertext.
+	CipherSuiteAES256GCM
+	// CipherSuiteAES256GCMStream is AES-256-GCM STREAM construction with segments of StreamSegmentSize.
+	CipherSuiteAES256GCMStream
+)
+
+// Envelope is a self-describing header of ciphertext.
+type Envelope struct {
+	// Version is a version of envelope format.
+	Version byte
+	// KeyWrap is an algorithm of WrappedKey.
+	KeyWrap KeyWrap
+	// KeyID identifies key used for KeyWrap, see KeyID.
+	KeyID []byte
+	// CipherSuite is an algorithm of data encrypted after envelope.
+	CipherSuite CipherSuite
+	// WrappedKey is an encrypted data key.
+	WrappedKey []byte
+}
+
+// KeyID returns identifier of RSA public key stored in envelope.
+func KeyID(pubKey *rsa.PublicKey) []byte {
+	sum := sha256.Sum256(x509.MarshalPKCS1PublicKey(pubKey))
+	return sum[:keyIDSize]
+}
+
+// MarshalBinary encodes envelope as magic || version || key wrap || key ID length || key ID ||
+// cipher suite || wrapped key length (uint16) || wrapped key.
+func (e Envelope) MarshalBinary() ([]byte, error) {
+	if len(e.KeyID) > 0xff {
+		return nil, errors.New("key ID too long")
+	}
+	if len(e.WrappedKey) > 0xffff {
+		return nil, errors.New("wrapped key too long")
+	}
+	res := make([]byte, 0, len(envelopeMagic)+6+len(e.KeyID)+len(e.WrappedKey))
+	res = append(res, envelopeMagic...)
+	res = append(res, e.Version, byte(e.KeyWrap), byte(len(e.KeyID)))
+	res = append(res, e.KeyID...)
+	res = append(res, byte(e.CipherSuite))
+	res = binary.BigEndian.AppendUint16(res, uint16(len(e.WrappedKey)))
+	res = append(res, e.WrappedKey...)
+	return res, nil
+}
+
+// readEnvelope reads envelope written by Envelope.MarshalBinary.
+func readEnvelope(r io.Reader) (Envelope, error) {
+	magic := make([]byte, len(envelopeMagic))
+	if _, err := io.ReadFull(r, magic); err != nil {
+		return Envelope{}, fmt.Errorf("failed to read magic: %w", err)
+	}
+	if !bytes.Equal(magic, envelopeMagic) {
+		return Envelope{}, errors.New("bad envelope magic")
+	}
+	var head [3]byte
+	if _, err := io.ReadFull(r, head[:]); err != nil {
+		return Envelope{}, fmt.Errorf("failed to read envelope: %w", err)
+	}
+	e := Envelope{
+		Version: head[0],
+		KeyWrap: KeyWrap(head[1]),
+		KeyID:   make([]byte, head[2]),
+	}
+	if e.Version < envelopeVersionLegacy || e.Version > EnvelopeVersion {
+		return Envelope{}, fmt.Errorf("unsupported envelope version %d", e.Version)
+	}
+	if _, err := io.ReadFull(r, e.KeyID); err != nil {
+		return Envelope{}, fmt.Errorf("failed to read key ID: %w", err)
+	}
+	var tail [3]byte
+	if _, err := io.ReadFull(r, tail[:]); err != nil {
+		return Envelope{}, fmt.Errorf("failed to read envelope: %w", err)
+	}
+	e.CipherSuite = CipherSuite(tail[0])
+	e.WrappedKey = make([]byte, binary.BigEndian.Uint16(tail[1:]))
+	if _, err := io.ReadFull(r, e.WrappedKey); err != nil {
+		return Envelope{}, fmt.Errorf("failed to read wrapped key: %w", err)
+	}
+	return e, nil
+}
+
+// additionalData returns associated data of ciphertext after envelope: encoded envelope || associatedData.
+// Legacy envelope isn't authenticated.
+func (e Envelope) additionalData(associatedData []byte) ([]byte, error) {
+	if e.Version == envelopeVersionLegacy {
+		return associatedData, nil
+	}
+	header, err := e.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode envelope: %w", err)
+	}
+	return append(header, associatedData...), nil
+}
+
+// hasEnvelope reports whether data starts with envelope magic.
+func hasEnvelope(data []byte) bool {
+	return bytes.HasPrefix(data, envelopeMagic)
+}
+
+// peekEnvelope reports whether stream starts with envelope magic.
+func peekEnvelope(r *bufio.Reader) bool {
+	magic, err := r.Peek(len(envelopeMagic))
+	return err == nil && hasEnvelope(magic)
+}
+
+// newEnvelope wraps data key with public RSA.
+func newEnvelope(pubKey *rsa.PublicKey, suite CipherSuite, dataKey []byte) (Envelope, error) {
+	wrappedKey, err := wrapKey(pubKey, dataKey)
+	if err != nil {
+		return Envelope{}, err
+	}
+	return Envelope{
+		Version:     EnvelopeVersion,
+		KeyWrap:     KeyWrapRSAOAEPSHA256,
+		KeyID:       KeyID(pubKey),
+		CipherSuite: suite,
+		WrappedKey:  wrappedKey,
+	}, nil
+}
+
+// unwrapKey returns data key of envelope encrypted for private RSA.
+func (e Envelope) unwrapKey(privKey *rsa.PrivateKey, suite CipherSuite) ([]byte, error) {
+	if e.KeyWrap != KeyWrapRSAOAEPSHA256 {
+		return nil, fmt.Errorf("unsupported key wrap algorithm %d", e.KeyWrap)
+	}
+	if e.CipherSuite != suite {
+		return nil, fmt.Errorf("unexpected cipher suite %d, want %d", e.CipherSuite, suite)
+	}
+	if !bytes.Equal(e.KeyID, KeyID(&privKey.PublicKey)) {
+		return nil, errors.New("data encrypted with another key")
+	}
+	return unwrapKey(privKey, e.WrappedKey)
+}
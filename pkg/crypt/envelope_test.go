@@ -0,0 +1,269 @@
+package crypt
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+// encryptLegacy encrypts data in format without Envelope: encrypted key || AES-GCM data.
+func encryptLegacy(t *testing.T, key, wrappedKey, data, associatedData []byte) []byte {
+	t.Helper()
+	encrypted, err := encryptWithAES(key, data, associatedData)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return append(bytes.Clone(wrappedKey), encrypted...)
+}
+
+// reencode replaces envelope of encrypted data by envelope modified with fn.
+func reencode(t *testing.T, encrypted []byte, fn func(e *Envelope)) []byte {
+	t.Helper()
+	r := bytes.NewReader(encrypted)
+	envelope, err := readEnvelope(r)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fn(&envelope)
+	header, err := envelope.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return append(header, encrypted[len(encrypted)-r.Len():]...)
+}
+
+// downgrade sets legacy version of envelope.
+func downgrade(e *Envelope) {
+	e.Version = envelopeVersionLegacy
+}
+
+func TestEncryptWithAssociatedData(t *testing.T) {
+	key := testKey(t)
+	other := testKey(t)
+	data := []byte("secret")
+	encrypted, err := EncryptWithAssociatedData(&key.PublicKey, data, []byte("password/login/uuid"))
+	if err != nil {
+		t.Fatalf("EncryptWithAssociatedData() error = %v", err)
+	}
+	if !hasEnvelope(encrypted) {
+		t.Errorf("EncryptWithAssociatedData() result has no envelope")
+	}
+	// legacy envelope of version 1 isn't authenticated
+	header, err := Envelope{
+		Version:     envelopeVersionLegacy,
+		KeyWrap:     KeyWrapRSAOAEPSHA256,
+		KeyID:       KeyID(&key.PublicKey),
+		CipherSuite: CipherSuiteAES256GCM,
+	}.MarshalBinary()
+	if err != nil {
+		t.Fatal(err)
+	}
+	aesKey, err := newDataKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wrapped, err := wrapKey(&key.PublicKey, aesKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	legacy := reencode(
+		t, append(header, encryptLegacy(t, aesKey, nil, data, []byte("password/login/uuid"))...),
+		func(e *Envelope) {
+			e.WrappedKey = wrapped
+		},
+	)
+	tests := []struct {
+		name           string
+		encrypted      []byte
+		associatedData []byte
+		wantErr        string
+	}{
+		{name: "same associated data", encrypted: encrypted, associatedData: []byte("password/login/uuid")},
+		{
+			name: "other associated data", encrypted: encrypted, associatedData: []byte("password/meta/uuid"),
+			wantErr: "authentication failed",
+		},
+		{name: "no associated data", encrypted: encrypted, associatedData: nil, wantErr: "authentication failed"},
+		{
+			name: "downgraded envelope", encrypted: reencode(t, encrypted, downgrade),
+			associatedData: []byte("password/login/uuid"), wantErr: "authentication failed",
+		},
+		{name: "legacy envelope", encrypted: legacy, associatedData: []byte("password/login/uuid")},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				got, err := DecryptWithAssociatedData(key, tt.encrypted, tt.associatedData)
+				if tt.wantErr != "" {
+					if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+						t.Errorf("DecryptWithAssociatedData() error = %v, want %q", err, tt.wantErr)
+					}
+					return
+				}
+				if err != nil || !bytes.Equal(got, data) {
+					t.Errorf("DecryptWithAssociatedData() = %q, %v, want %q", got, err, data)
+				}
+			},
+		)
+	}
+	if _, err = DecryptWithAssociatedData(other, encrypted, []byte("password/login/uuid")); err == nil ||
+		!strings.Contains(err.Error(), "another key") {
+		t.Errorf("DecryptWithAssociatedData() with other key error = %v, want another key", err)
+	}
+}
+
+func TestDecryptWithAssociatedData_Legacy(t *testing.T) {
+	key := testKey(t)
+	aesKey, err := newDataKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	wrapped, err := wrapKey(&key.PublicKey, aesKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data := []byte("legacy secret")
+	legacy := encryptLegacy(t, aesKey, wrapped, data, nil)
+	if hasEnvelope(legacy) {
+		t.Skip("legacy ciphertext starts with envelope magic by chance")
+	}
+	if got, er := DecryptWithPrivateKey(key, legacy); er != nil || !bytes.Equal(got, data) {
+		t.Errorf("DecryptWithPrivateKey() of legacy = %q, %v, want %q", got, er, data)
+	}
+	bound := encryptLegacy(t, aesKey, wrapped, data, []byte("ad"))
+	if got, er := DecryptWithAssociatedData(key, bound, []byte("ad")); er != nil || !bytes.Equal(got, data) {
+		t.Errorf("DecryptWithAssociatedData() of legacy = %q, %v, want %q", got, er, data)
+	}
+
+	// encrypted key starting with magic is parsed as envelope first, then decrypted as legacy
+	magic := bytes.Clone(legacy)
+	copy(magic, envelopeMagic)
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr string
+	}{
+		{
+			name:    "magic and bad version",
+			data:    append(append(bytes.Clone(magic[:4]), 0x7f), magic[5:]...),
+			wantErr: "unsupported envelope version",
+		},
+		{name: "magic and truncated envelope", data: magic[:5], wantErr: "failed to read envelope"},
+		{name: "too short", data: []byte("short"), wantErr: "too short"},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				if _, er := DecryptWithPrivateKey(key, tt.data); er == nil || !strings.Contains(er.Error(), tt.wantErr) {
+					t.Errorf("DecryptWithPrivateKey() error = %v, want %q", er, tt.wantErr)
+				}
+			},
+		)
+	}
+}
+
+func TestEnvelope_MarshalBinary(t *testing.T) {
+	key := testKey(t)
+	envelope, err := newEnvelope(&key.PublicKey, CipherSuiteAES256GCM, []byte("0123456789abcdef0123456789abcdef"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	encoded, err := envelope.MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary() error = %v", err)
+	}
+	r := bytes.NewReader(append(bytes.Clone(encoded), "data"...))
+	got, err := readEnvelope(r)
+	if err != nil {
+		t.Fatalf("readEnvelope() error = %v", err)
+	}
+	if got.Version != EnvelopeVersion || got.KeyWrap != KeyWrapRSAOAEPSHA256 || got.CipherSuite != CipherSuiteAES256GCM ||
+		!bytes.Equal(got.KeyID, KeyID(&key.PublicKey)) || !bytes.Equal(got.WrappedKey, envelope.WrappedKey) {
+		t.Errorf("readEnvelope() = %+v, want %+v", got, envelope)
+	}
+	if r.Len() != len("data") {
+		t.Errorf("readEnvelope() left %d bytes, want data after envelope", r.Len())
+	}
+	if _, err = (Envelope{KeyID: make([]byte, 0x100)}).MarshalBinary(); err == nil {
+		t.Errorf("MarshalBinary() with long key ID error = nil, want error")
+	}
+
+	tests := []struct {
+		name    string
+		data    []byte
+		wantErr string
+	}{
+		{name: "bad magic", data: append([]byte("GKX\x00"), encoded[4:]...), wantErr: "bad envelope magic"},
+		{
+			name:    "unsupported version",
+			data:    append(append(bytes.Clone(encoded[:4]), EnvelopeVersion+1), encoded[5:]...),
+			wantErr: "unsupported envelope version",
+		},
+		{name: "truncated key ID", data: encoded[:10], wantErr: "failed to read key ID"},
+		{name: "truncated wrapped key", data: encoded[:len(encoded)-1], wantErr: "failed to read wrapped key"},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				if _, er := readEnvelope(bytes.NewReader(tt.data)); er == nil || !strings.Contains(er.Error(), tt.wantErr) {
+					t.Errorf("readEnvelope() error = %v, want %q", er, tt.wantErr)
+				}
+			},
+		)
+	}
+}
+
+func TestEnvelope_unwrapKey(t *testing.T) {
+	key := testKey(t)
+	other := testKey(t)
+	dataKey := []byte("0123456789abcdef0123456789abcdef")
+	envelope, err := newEnvelope(&key.PublicKey, CipherSuiteAES256GCM, dataKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		envelope func() Envelope
+		suite    CipherSuite
+		wantErr  string
+	}{
+		{name: "valid", suite: CipherSuiteAES256GCM},
+		{name: "other suite", suite: CipherSuiteAES256GCMStream, wantErr: "unexpected cipher suite"},
+		{
+			name: "other key wrap", suite: CipherSuiteAES256GCM, wantErr: "unsupported key wrap",
+			envelope: func() Envelope {
+				e := envelope
+				e.KeyWrap = KeyWrapDataKey
+				return e
+			},
+		},
+		{
+			name: "other key ID", suite: CipherSuiteAES256GCM, wantErr: "another key",
+			envelope: func() Envelope {
+				e := envelope
+				e.KeyID = KeyID(&other.PublicKey)
+				return e
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				e := envelope
+				if tt.envelope != nil {
+					e = tt.envelope()
+				}
+				got, er := e.unwrapKey(key, tt.suite)
+				if tt.wantErr != "" {
+					if er == nil || !strings.Contains(er.Error(), tt.wantErr) {
+						t.Errorf("unwrapKey() error = %v, want %q", er, tt.wantErr)
+					}
+					return
+				}
+				if er != nil || !bytes.Equal(got, dataKey) {
+					t.Errorf("unwrapKey() = %x, %v, want %x", got, er, dataKey)
+				}
+			},
+		)
+	}
+}
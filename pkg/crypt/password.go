@@ -0,0 +1,117 @@
+package crypt
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// argon2idParamsSize is a size of encoded Argon2idParams.
+const argon2idParamsSize = 16 + 4 + 4 + 1
+
+// ErrInvalidPassword is returned when data can't be decrypted with password.
+var ErrInvalidPassword = errors.New("invalid password")
+
+// Argon2idParams are parameters of Argon2id key derivation.
+type Argon2idParams struct {
+	// Salt is a random salt, 16 bytes.
+	Salt []byte
+	// Time is a number of passes over memory.
+	Time uint32
+	// Memory is a size of memory in KiB.
+	Memory uint32
+	// Threads is a number of lanes.
+	Threads uint8
+}
+
+// DefaultArgon2idParams returns recommended by RFC 9106 parameters with new random salt.
+func DefaultArgon2idParams() (Argon2idParams, error) {
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return Argon2idParams{}, fmt.Errorf("failed to generate salt: %w", err)
+	}
+	return Argon2idParams{
+		Salt:    salt,
+		Time:    3,
+		Memory:  64 * 1024,
+		Threads: 4,
+	}, nil
+}
+
+func (p Argon2idParams) key(password []byte) []byte {
+	return argon2.IDKey(password, p.Salt, p.Time, p.Memory, p.Threads, 32)
+}
+
+func (p Argon2idParams) marshal() []byte {
+	res := make([]byte, 0, argon2idParamsSize)
+	res = append(res, p.Salt...)
+	res = binary.BigEndian.AppendUint32(res, p.Time)
+	res = binary.BigEndian.AppendUint32(res, p.Memory)
+	return append(res, p.Threads)
+}
+
+func unmarshalArgon2idParams(data []byte) (Argon2idParams, error) {
+	if len(data) != argon2idParamsSize {
+		return Argon2idParams{}, errors.New("invalid Argon2id parameters")
+	}
+	return Argon2idParams{
+		Salt:    data[:16],
+		Time:    binary.BigEndian.Uint32(data[16:20]),
+		Memory:  binary.BigEndian.Uint32(data[20:24]),
+		Threads: data[24],
+	}, nil
+}
+
+// EncryptWithPassword encrypts data with AES-GCM and key derived from password by Argon2id.
+// Parameters of Argon2id are stored in Envelope before data.
+func EncryptWithPassword(password, data []byte, params Argon2idParams) ([]byte, error) {
+	header, err := Envelope{
+		Version:     EnvelopeVersion,
+		KeyWrap:     KeyWrapArgon2id,
+		CipherSuite: CipherSuiteAES256GCM,
+		WrappedKey:  params.marshal(),
+	}.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode envelope: %w", err)
+	}
+	encryptedData, err := encryptWithAES(params.key(password), data, header)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encrypt data: %w", err)
+	}
+	return append(header, encryptedData...), nil
+}
+
+// DecryptWithPassword decrypts data encrypted by EncryptWithPassword.
+func DecryptWithPassword(password, data []byte) ([]byte, error) {
+	r := bytes.NewReader(data)
+	envelope, err := readEnvelope(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read envelope: %w", err)
+	}
+	if envelope.KeyWrap != KeyWrapArgon2id || envelope.CipherSuite != CipherSuiteAES256GCM {
+		return nil, errors.New("data is not encrypted with password")
+	}
+	params, err := unmarshalArgon2idParams(envelope.WrappedKey)
+	if err != nil {
+		return nil, err
+	}
+	header := data[:len(data)-r.Len()]
+	res, err := decryptWithAES(params.key(password), data[len(header):], header)
+	if err != nil {
+		return nil, ErrInvalidPassword
+	}
+	return res, nil
+}
+
+// IsPasswordEncrypted reports whether data is encrypted by EncryptWithPassword.
+func IsPasswordEncrypted(data []byte) bool {
+	if !hasEnvelope(data) {
+		return false
+	}
+	envelope, err := readEnvelope(bytes.NewReader(data))
+	return err == nil && envelope.KeyWrap == KeyWrapArgon2id
+}
@@ -0,0 +1,100 @@
+package crypt
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// testArgon2idParams returns fast parameters for tests.
+func testArgon2idParams(t *testing.T) Argon2idParams {
+	t.Helper()
+	params, err := DefaultArgon2idParams()
+	if err != nil {
+		t.Fatal(err)
+	}
+	params.Time, params.Memory, params.Threads = 1, 1024, 1
+	return params
+}
+
+func TestDefaultArgon2idParams(t *testing.T) {
+	params, err := DefaultArgon2idParams()
+	if err != nil {
+		t.Fatalf("DefaultArgon2idParams() error = %v", err)
+	}
+	if len(params.Salt) != 16 || params.Time == 0 || params.Memory == 0 || params.Threads == 0 {
+		t.Errorf("DefaultArgon2idParams() = %+v, want 16 bytes salt and non-zero costs", params)
+	}
+	other, _ := DefaultArgon2idParams()
+	if bytes.Equal(params.Salt, other.Salt) {
+		t.Errorf("DefaultArgon2idParams() returned same salt twice")
+	}
+	got, err := unmarshalArgon2idParams(params.marshal())
+	if err != nil || !bytes.Equal(got.Salt, params.Salt) || got.Time != params.Time || got.Memory != params.Memory ||
+		got.Threads != params.Threads {
+		t.Errorf("unmarshalArgon2idParams() = %+v, %v, want %+v", got, err, params)
+	}
+	if _, err = unmarshalArgon2idParams(params.marshal()[1:]); err == nil {
+		t.Errorf("unmarshalArgon2idParams() of short data error = nil, want error")
+	}
+}
+
+func TestEncryptWithPassword(t *testing.T) {
+	password := []byte("master password")
+	data := []byte("private key")
+	encrypted, err := EncryptWithPassword(password, data, testArgon2idParams(t))
+	if err != nil {
+		t.Fatalf("EncryptWithPassword() error = %v", err)
+	}
+	if !IsPasswordEncrypted(encrypted) {
+		t.Errorf("IsPasswordEncrypted() = false, want true")
+	}
+	// parameters are authenticated, so changed cost can't be used to weaken key derivation
+	tampered := bytes.Clone(encrypted)
+	// last byte of Time after header of envelope without key ID and salt
+	tampered[len(envelopeMagic)+6+16+3]++
+	rsaEncrypted, err := EncryptWithPublicKey(&testKey(t).PublicKey, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name     string
+		password []byte
+		data     []byte
+		wantErr  error
+		wantText string
+	}{
+		{name: "valid", password: password, data: encrypted},
+		{name: "wrong password", password: []byte("other"), data: encrypted, wantErr: ErrInvalidPassword},
+		{name: "tampered parameters", password: password, data: tampered, wantErr: ErrInvalidPassword},
+		{name: "encrypted with key", password: password, data: rsaEncrypted, wantText: "not encrypted with password"},
+		{name: "no envelope", password: password, data: []byte("plain"), wantText: "failed to read envelope"},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				got, er := DecryptWithPassword(tt.password, tt.data)
+				switch {
+				case tt.wantErr != nil:
+					if !errors.Is(er, tt.wantErr) {
+						t.Errorf("DecryptWithPassword() error = %v, want %v", er, tt.wantErr)
+					}
+				case tt.wantText != "":
+					if er == nil || !strings.Contains(er.Error(), tt.wantText) {
+						t.Errorf("DecryptWithPassword() error = %v, want %q", er, tt.wantText)
+					}
+				default:
+					if er != nil || !bytes.Equal(got, data) {
+						t.Errorf("DecryptWithPassword() = %q, %v, want %q", got, er, data)
+					}
+				}
+			},
+		)
+	}
+	for _, notEncrypted := range [][]byte{rsaEncrypted, []byte("plain"), envelopeMagic} {
+		if IsPasswordEncrypted(notEncrypted) {
+			t.Errorf("IsPasswordEncrypted(%q) = true, want false", notEncrypted)
+		}
+	}
+}
@@ -0,0 +1,278 @@
+package crypt
+
+import (
+	"bufio"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+)
+
+const (
+	// StreamSegmentSize is a size of plain data sealed in one segment of stream.
+	StreamSegmentSize = 64 * 1024
+	// streamNoncePrefixSize is a size of random nonce prefix of stream.
+	streamNoncePrefixSize = 7
+	// streamTagSize is a size of GCM tag of each segment.
+	streamTagSize = 16
+)
+
+// ErrStreamTruncated is returned when encrypted stream ends without final segment.
+var ErrStreamTruncated = errors.New("encrypted stream truncated")
+
+// streamCipher seals and opens stream segments using STREAM construction:
+// nonce of segment is prefix || counter || last flag, every segment authenticates additionalData.
+type streamCipher struct {
+	aead           cipher.AEAD
+	nonce          []byte
+	additionalData []byte
+	counter        uint32
+}
+
+func newStreamCipher(key, prefix, additionalData []byte) (*streamCipher, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create AES cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create GCM: %w", err)
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	copy(nonce, prefix)
+	return &streamCipher{
+		aead:           gcm,
+		nonce:          nonce,
+		additionalData: additionalData,
+	}, nil
+}
+
+// next returns nonce of next segment.
+func (s *streamCipher) next(last bool) ([]byte, error) {
+	if s.counter == math.MaxUint32 {
+		return nil, errors.New("too many segments in stream")
+	}
+	binary.BigEndian.PutUint32(s.nonce[streamNoncePrefixSize:], s.counter)
+	s.nonce[len(s.nonce)-1] = 0
+	if last {
+		s.nonce[len(s.nonce)-1] = 1
+	}
+	s.counter++
+	return s.nonce, nil
+}
+
+func (s *streamCipher) seal(dst, segment []byte, last bool) ([]byte, error) {
+	nonce, err := s.next(last)
+	if err != nil {
+		return nil, err
+	}
+	return s.aead.Seal(dst, nonce, segment, s.additionalData), nil
+}
+
+func (s *streamCipher) open(dst, segment []byte, last bool) ([]byte, error) {
+	nonce, err := s.next(last)
+	if err != nil {
+		return nil, err
+	}
+	return s.aead.Open(dst, nonce, segment, s.additionalData)
+}
+
+// encryptWriter encrypts data written to it by segments.
+type encryptWriter struct {
+	w      io.Writer
+	cipher *streamCipher
+	buf    []byte
+	out    []byte
+	err    error
+}
+
+// NewEncryptWriter returns io.WriteCloser which encrypts data with AES-GCM by segments
+// of StreamSegmentSize and writes it to w. AES key is encrypted with public RSA
+// and written in Envelope before data, every segment authenticates Envelope. Close must be called to write final segment.
+func NewEncryptWriter(w io.Writer, pubKey *rsa.PublicKey) (io.WriteCloser, error) {
+	aesKey, err := newDataKey()
+	if err != nil {
+		return nil, err
+	}
+	envelope, err := newEnvelope(pubKey, CipherSuiteAES256GCMStream, aesKey)
+	if err != nil {
+		return nil, err
+	}
+	header, err := envelope.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode envelope: %w", err)
+	}
+	additionalData, err := envelope.additionalData(nil)
+	if err != nil {
+		return nil, err
+	}
+	prefix := make([]byte, streamNoncePrefixSize)
+	if _, err = rand.Read(prefix); err != nil {
+		return nil, fmt.Errorf("failed to generate nonce prefix: %w", err)
+	}
+	sc, err := newStreamCipher(aesKey, prefix, additionalData)
+	if err != nil {
+		return nil, err
+	}
+	if _, err = w.Write(append(header, prefix...)); err != nil {
+		return nil, fmt.Errorf("failed to write stream header: %w", err)
+	}
+	return &encryptWriter{
+		w:      w,
+		cipher: sc,
+		buf:    make([]byte, 0, StreamSegmentSize),
+		out:    make([]byte, 0, StreamSegmentSize+streamTagSize),
+	}, nil
+}
+
+// Write encrypts p. Full segment is flushed only when next data arrives,
+// because the last segment must be sealed with final flag by Close.
+func (e *encryptWriter) Write(p []byte) (int, error) {
+	if e.err != nil {
+		return 0, e.err
+	}
+	n := 0
+	for len(p) > 0 {
+		if len(e.buf) == StreamSegmentSize {
+			if err := e.flush(false); err != nil {
+				return n, err
+			}
+		}
+		m := copy(e.buf[len(e.buf):StreamSegmentSize], p)
+		e.buf = e.buf[:len(e.buf)+m]
+		p = p[m:]
+		n += m
+	}
+	return n, nil
+}
+
+// Close writes final segment. It doesn't close underlying writer.
+func (e *encryptWriter) Close() error {
+	if e.err != nil {
+		return e.err
+	}
+	if err := e.flush(true); err != nil {
+		return err
+	}
+	e.err = errors.New("write to closed encrypt writer")
+	return nil
+}
+
+func (e *encryptWriter) flush(last bool) error {
+	out, err := e.cipher.seal(e.out[:0], e.buf, last)
+	if err != nil {
+		e.err = err
+		return err
+	}
+	if _, err = e.w.Write(out); err != nil {
+		e.err = fmt.Errorf("failed to write segment: %w", err)
+		return e.err
+	}
+	e.buf = e.buf[:0]
+	return nil
+}
+
+// decryptReader decrypts stream written by encryptWriter.
+type decryptReader struct {
+	r      *bufio.Reader
+	cipher *streamCipher
+	in     []byte
+	plain  []byte
+	buf    []byte
+	done   bool
+	err    error
+}
+
+// NewDecryptReader returns io.Reader which decrypts data encrypted by NewEncryptWriter
+// and read from r. Truncated, reordered or modified segments cause error.
+func NewDecryptReader(r io.Reader, privKey *rsa.PrivateKey) (io.Reader, error) {
+	br := bufio.NewReader(r)
+	var aesKey, additionalData []byte
+	if peekEnvelope(br) {
+		envelope, err := readEnvelope(br)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read envelope: %w", err)
+		}
+		if aesKey, err = envelope.unwrapKey(privKey, CipherSuiteAES256GCMStream); err != nil {
+			return nil, err
+		}
+		if additionalData, err = envelope.additionalData(nil); err != nil {
+			return nil, err
+		}
+	} else {
+		// legacy stream without envelope starts with encrypted key
+		encryptedKey := make([]byte, privKey.Size())
+		if _, err := io.ReadFull(br, encryptedKey); err != nil {
+			return nil, fmt.Errorf("failed to read encrypted key: %w", err)
+		}
+		var err error
+		if aesKey, err = unwrapKey(privKey, encryptedKey); err != nil {
+			return nil, err
+		}
+	}
+	prefix := make([]byte, streamNoncePrefixSize)
+	if _, err := io.ReadFull(br, prefix); err != nil {
+		return nil, fmt.Errorf("failed to read nonce prefix: %w", err)
+	}
+	sc, err := newStreamCipher(aesKey, prefix, additionalData)
+	if err != nil {
+		return nil, err
+	}
+	return &decryptReader{
+		r:      br,
+		cipher: sc,
+		in:     make([]byte, StreamSegmentSize+streamTagSize),
+		plain:  make([]byte, 0, StreamSegmentSize),
+	}, nil
+}
+
+func (d *decryptReader) Read(p []byte) (int, error) {
+	for len(d.buf) == 0 {
+		if d.err != nil {
+			return 0, d.err
+		}
+		if d.done {
+			return 0, io.EOF
+		}
+		d.err = d.next()
+	}
+	n := copy(p, d.buf)
+	d.buf = d.buf[n:]
+	return n, nil
+}
+
+// next reads and decrypts next segment. Segment is final if it is short
+// or nothing follows it.
+func (d *decryptReader) next() error {
+	n, err := io.ReadFull(d.r, d.in)
+	last := false
+	switch {
+	case errors.Is(err, io.EOF):
+		return ErrStreamTruncated
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		last = true
+	case err != nil:
+		return fmt.Errorf("failed to read segment: %w", err)
+	default:
+		if _, er := d.r.Peek(1); errors.Is(er, io.EOF) {
+			last = true
+		} else if er != nil {
+			return fmt.Errorf("failed to read segment: %w", er)
+		}
+	}
+	buf, err := d.cipher.open(d.plain[:0], d.in[:n], last)
+	if err != nil {
+		if last {
+			return fmt.Errorf("failed to decrypt final segment: %w", ErrStreamTruncated)
+		}
+		return fmt.Errorf("failed to decrypt segment: %w", err)
+	}
+	d.buf = buf
+	d.done = last
+	return nil
+}
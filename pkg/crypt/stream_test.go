@@ -0,0 +1,214 @@
+package crypt
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"io"
+	"testing"
+)
+
+// testKey generates RSA key for tests.
+func testKey(t *testing.T) *rsa.PrivateKey {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+// testData returns random data of size.
+func testData(t *testing.T, size int) []byte {
+	t.Helper()
+	data := make([]byte, size)
+	if _, err := rand.Read(data); err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+// encryptStream encrypts data by NewEncryptWriter with writes of random sizes.
+func encryptStream(t *testing.T, pubKey *rsa.PublicKey, data []byte) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := NewEncryptWriter(&buf, pubKey)
+	if err != nil {
+		t.Fatalf("NewEncryptWriter() error = %v", err)
+	}
+	for rest := data; len(rest) > 0; {
+		n := min(len(rest), 1+len(rest)%(StreamSegmentSize+7))
+		if _, err = w.Write(rest[:n]); err != nil {
+			t.Fatalf("Write() error = %v", err)
+		}
+		rest = rest[n:]
+	}
+	if err = w.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+	return buf.Bytes()
+}
+
+// decryptStream reads all data from NewDecryptReader.
+func decryptStream(privKey *rsa.PrivateKey, encrypted []byte) ([]byte, error) {
+	r, err := NewDecryptReader(bytes.NewReader(encrypted), privKey)
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(r)
+}
+
+// streamHeaderSize returns size of envelope and nonce prefix before segments.
+func streamHeaderSize(privKey *rsa.PrivateKey) int {
+	return len(envelopeMagic) + 6 + keyIDSize + privKey.Size() + streamNoncePrefixSize
+}
+
+func TestStream_RoundTrip(t *testing.T) {
+	key := testKey(t)
+	tests := []struct {
+		name string
+		size int
+	}{
+		{name: "empty", size: 0},
+		{name: "one byte", size: 1},
+		{name: "one segment", size: StreamSegmentSize},
+		{name: "segment and byte", size: StreamSegmentSize + 1},
+		{name: "several megabytes", size: 5*1024*1024 + 123},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				data := testData(t, tt.size)
+				encrypted := encryptStream(t, &key.PublicKey, data)
+				segments := tt.size/StreamSegmentSize + 1
+				if tt.size > 0 && tt.size%StreamSegmentSize == 0 {
+					segments--
+				}
+				if want := streamHeaderSize(key) + tt.size + segments*streamTagSize; len(encrypted) != want {
+					t.Errorf("encrypted size = %d, want %d", len(encrypted), want)
+				}
+				got, err := decryptStream(key, encrypted)
+				if err != nil {
+					t.Fatalf("decrypt error = %v", err)
+				}
+				if !bytes.Equal(got, data) {
+					t.Errorf("decrypted data differs from encrypted")
+				}
+			},
+		)
+	}
+}
+
+func TestStream_Tampered(t *testing.T) {
+	key := testKey(t)
+	data := testData(t, 3*StreamSegmentSize+100)
+	encrypted := encryptStream(t, &key.PublicKey, data)
+	header := streamHeaderSize(key)
+	segment := StreamSegmentSize + streamTagSize
+	// segmentAt returns i-th encrypted segment
+	segmentAt := func(i int) []byte {
+		return encrypted[header+i*segment : min(header+(i+1)*segment, len(encrypted))]
+	}
+	join := func(parts ...[]byte) []byte {
+		return bytes.Join(parts, nil)
+	}
+	flipped := bytes.Clone(encrypted)
+	flipped[header+segment-1] ^= 1
+
+	tests := []struct {
+		name      string
+		encrypted []byte
+		wantErr   error
+	}{
+		{
+			name:      "dropped last segment",
+			encrypted: encrypted[:header+3*segment],
+			wantErr:   ErrStreamTruncated,
+		},
+		{
+			name:      "dropped all segments",
+			encrypted: encrypted[:header],
+			wantErr:   ErrStreamTruncated,
+		},
+		{
+			name:      "truncated last segment",
+			encrypted: encrypted[:len(encrypted)-1],
+			wantErr:   ErrStreamTruncated,
+		},
+		{
+			name:      "swapped segments",
+			encrypted: join(encrypted[:header], segmentAt(1), segmentAt(0), segmentAt(2), segmentAt(3)),
+		},
+		{
+			name:      "dropped middle segment",
+			encrypted: join(encrypted[:header], segmentAt(0), segmentAt(2), segmentAt(3)),
+		},
+		{
+			name:      "flipped tag",
+			encrypted: flipped,
+		},
+		{
+			name:      "downgraded envelope",
+			encrypted: reencode(t, encrypted, downgrade),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(
+			tt.name, func(t *testing.T) {
+				_, err := decryptStream(key, tt.encrypted)
+				if err == nil {
+					t.Fatalf("decrypt error = nil, want error")
+				}
+				if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+					t.Errorf("decrypt error = %v, want %v", err, tt.wantErr)
+				}
+			},
+		)
+	}
+}
+
+func TestStream_Legacy(t *testing.T) {
+	key := testKey(t)
+	data := testData(t, StreamSegmentSize+10)
+	aesKey, err := newDataKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	// legacy stream is encrypted key || nonce prefix || segments
+	encrypted, err := wrapKey(&key.PublicKey, aesKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prefix := testData(t, streamNoncePrefixSize)
+	encrypted = append(encrypted, prefix...)
+	sc, err := newStreamCipher(aesKey, prefix, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if encrypted, err = sc.seal(encrypted, data[:StreamSegmentSize], false); err != nil {
+		t.Fatal(err)
+	}
+	if encrypted, err = sc.seal(encrypted, data[StreamSegmentSize:], true); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := decryptStream(key, encrypted)
+	if err != nil {
+		t.Fatalf("decrypt legacy error = %v", err)
+	}
+	if !bytes.Equal(got, data) {
+		t.Errorf("decrypted legacy data differs from encrypted")
+	}
+	if _, err = decryptStream(key, encrypted[:len(encrypted)-StreamSegmentSize/2]); !errors.Is(err, ErrStreamTruncated) {
+		t.Errorf("decrypt truncated legacy error = %v, want %v", err, ErrStreamTruncated)
+	}
+}
+
+func TestStream_WrongKey(t *testing.T) {
+	key := testKey(t)
+	encrypted := encryptStream(t, &key.PublicKey, testData(t, 10))
+	if _, err := decryptStream(testKey(t), encrypted); err == nil {
+		t.Errorf("decrypt with other key error = nil, want error")
+	}
+}
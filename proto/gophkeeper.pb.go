@@ -77,9 +77,64 @@ func (RecordType) EnumDescriptor() ([]byte, []int) {
 	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{0}
 }
 
+type ChangeOp int32
+
+const (
+	ChangeOp_CHANGE_UNKNOWN ChangeOp = 0
+	ChangeOp_CHANGE_CREATE  ChangeOp = 1
+	ChangeOp_CHANGE_UPDATE  ChangeOp = 2
+	// CHANGE_DELETE is a tombstone of record moved to trash or deleted permanently.
+	ChangeOp_CHANGE_DELETE ChangeOp = 3
+)
+
+// Enum value maps for ChangeOp.
+var (
+	ChangeOp_name = map[int32]string{
+		0: "CHANGE_UNKNOWN",
+		1: "CHANGE_CREATE",
+		2: "CHANGE_UPDATE",
+		3: "CHANGE_DELETE",
+	}
+	ChangeOp_value = map[string]int32{
+		"CHANGE_UNKNOWN": 0,
+		"CHANGE_CREATE":  1,
+		"CHANGE_UPDATE":  2,
+		"CHANGE_DELETE":  3,
+	}
+)
+
+func (x ChangeOp) Enum() *ChangeOp {
+	p := new(ChangeOp)
+	*p = x
+	return p
+}
+
+func (x ChangeOp) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ChangeOp) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_gophkeeper_proto_enumTypes[1].Descriptor()
+}
+
+func (ChangeOp) Type() protoreflect.EnumType {
+	return &file_proto_gophkeeper_proto_enumTypes[1]
+}
+
+func (x ChangeOp) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ChangeOp.Descriptor instead.
+func (ChangeOp) EnumDescriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{1}
+}
+
 type RegisterRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	CertRequest   []byte                 `protobuf:"bytes,1,opt,name=cert_request,json=certRequest" json:"cert_request,omitempty"`
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	CertRequest []byte                 `protobuf:"bytes,1,opt,name=cert_request,json=certRequest" json:"cert_request,omitempty"`
+	// invite token, required if server registers users by invites.
+	Invite        *string `protobuf:"bytes,2,opt,name=invite" json:"invite,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -121,12 +176,21 @@ func (x *RegisterRequest) GetCertRequest() []byte {
 	return nil
 }
 
+func (x *RegisterRequest) GetInvite() string {
+	if x != nil && x.Invite != nil {
+		return *x.Invite
+	}
+	return ""
+}
+
 type RegisterResponse struct {
 	state             protoimpl.MessageState `protogen:"open.v1"`
 	CaCertificate     []byte                 `protobuf:"bytes,2,opt,name=ca_certificate,json=caCertificate" json:"ca_certificate,omitempty"`
 	ClientCertificate []byte                 `protobuf:"bytes,3,opt,name=client_certificate,json=clientCertificate" json:"client_certificate,omitempty"`
-	unknownFields     protoimpl.UnknownFields
-	sizeCache         protoimpl.SizeCache
+	// pending_code is set instead of certificates while registration waits for admin approval.
+	PendingCode   *string `protobuf:"bytes,4,opt,name=pending_code,json=pendingCode" json:"pending_code,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *RegisterResponse) Reset() {
@@ -173,27 +237,34 @@ func (x *RegisterResponse) GetClientCertificate() []byte {
 	return nil
 }
 
-type ListRequest struct {
+func (x *RegisterResponse) GetPendingCode() string {
+	if x != nil && x.PendingCode != nil {
+		return *x.PendingCode
+	}
+	return ""
+}
+
+type RegisterStatusRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Type          *RecordType            `protobuf:"varint,1,opt,name=type,enum=gophkeeper.RecordType" json:"type,omitempty"`
+	Code          *string                `protobuf:"bytes,1,opt,name=code" json:"code,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListRequest) Reset() {
-	*x = ListRequest{}
+func (x *RegisterStatusRequest) Reset() {
+	*x = RegisterStatusRequest{}
 	mi := &file_proto_gophkeeper_proto_msgTypes[2]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListRequest) String() string {
+func (x *RegisterStatusRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListRequest) ProtoMessage() {}
+func (*RegisterStatusRequest) ProtoMessage() {}
 
-func (x *ListRequest) ProtoReflect() protoreflect.Message {
+func (x *RegisterStatusRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_gophkeeper_proto_msgTypes[2]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -205,39 +276,41 @@ func (x *ListRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListRequest.ProtoReflect.Descriptor instead.
-func (*ListRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use RegisterStatusRequest.ProtoReflect.Descriptor instead.
+func (*RegisterStatusRequest) Descriptor() ([]byte, []int) {
 	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{2}
 }
 
-func (x *ListRequest) GetType() RecordType {
-	if x != nil && x.Type != nil {
-		return *x.Type
+func (x *RegisterStatusRequest) GetCode() string {
+	if x != nil && x.Code != nil {
+		return *x.Code
 	}
-	return RecordType_UNKNOWN
+	return ""
 }
 
-type ListResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Records       []byte                 `protobuf:"bytes,1,opt,name=records" json:"records,omitempty"`
+type EnrollRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// user is a CommonName of user which device is enrolled to.
+	User          *string `protobuf:"bytes,1,opt,name=user" json:"user,omitempty"`
+	CertRequest   []byte  `protobuf:"bytes,2,opt,name=cert_request,json=certRequest" json:"cert_request,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ListResponse) Reset() {
-	*x = ListResponse{}
+func (x *EnrollRequest) Reset() {
+	*x = EnrollRequest{}
 	mi := &file_proto_gophkeeper_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ListResponse) String() string {
+func (x *EnrollRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ListResponse) ProtoMessage() {}
+func (*EnrollRequest) ProtoMessage() {}
 
-func (x *ListResponse) ProtoReflect() protoreflect.Message {
+func (x *EnrollRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_gophkeeper_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -249,40 +322,47 @@ func (x *ListResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ListResponse.ProtoReflect.Descriptor instead.
-func (*ListResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use EnrollRequest.ProtoReflect.Descriptor instead.
+func (*EnrollRequest) Descriptor() ([]byte, []int) {
 	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *ListResponse) GetRecords() []byte {
+func (x *EnrollRequest) GetUser() string {
+	if x != nil && x.User != nil {
+		return *x.User
+	}
+	return ""
+}
+
+func (x *EnrollRequest) GetCertRequest() []byte {
 	if x != nil {
-		return x.Records
+		return x.CertRequest
 	}
 	return nil
 }
 
-type AddRecordRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Type          *RecordType            `protobuf:"varint,1,opt,name=type,enum=gophkeeper.RecordType" json:"type,omitempty"`
-	Record        []byte                 `protobuf:"bytes,2,opt,name=record" json:"record,omitempty"`
+type EnrollResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// code must be entered on existing device to approve enrollment.
+	Code          *string `protobuf:"bytes,1,opt,name=code" json:"code,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *AddRecordRequest) Reset() {
-	*x = AddRecordRequest{}
+func (x *EnrollResponse) Reset() {
+	*x = EnrollResponse{}
 	mi := &file_proto_gophkeeper_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *AddRecordRequest) String() string {
+func (x *EnrollResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*AddRecordRequest) ProtoMessage() {}
+func (*EnrollResponse) ProtoMessage() {}
 
-func (x *AddRecordRequest) ProtoReflect() protoreflect.Message {
+func (x *EnrollResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_gophkeeper_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -294,47 +374,39 @@ func (x *AddRecordRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use AddRecordRequest.ProtoReflect.Descriptor instead.
-func (*AddRecordRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use EnrollResponse.ProtoReflect.Descriptor instead.
+func (*EnrollResponse) Descriptor() ([]byte, []int) {
 	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *AddRecordRequest) GetType() RecordType {
-	if x != nil && x.Type != nil {
-		return *x.Type
-	}
-	return RecordType_UNKNOWN
-}
-
-func (x *AddRecordRequest) GetRecord() []byte {
-	if x != nil {
-		return x.Record
+func (x *EnrollResponse) GetCode() string {
+	if x != nil && x.Code != nil {
+		return *x.Code
 	}
-	return nil
+	return ""
 }
 
-type GetRecordRequest struct {
+type EnrollStatusRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Type          *RecordType            `protobuf:"varint,1,opt,name=type,enum=gophkeeper.RecordType" json:"type,omitempty"`
-	RecordNumber  *int64                 `protobuf:"varint,2,opt,name=record_number,json=recordNumber" json:"record_number,omitempty"`
+	Code          *string                `protobuf:"bytes,1,opt,name=code" json:"code,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetRecordRequest) Reset() {
-	*x = GetRecordRequest{}
+func (x *EnrollStatusRequest) Reset() {
+	*x = EnrollStatusRequest{}
 	mi := &file_proto_gophkeeper_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetRecordRequest) String() string {
+func (x *EnrollStatusRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetRecordRequest) ProtoMessage() {}
+func (*EnrollStatusRequest) ProtoMessage() {}
 
-func (x *GetRecordRequest) ProtoReflect() protoreflect.Message {
+func (x *EnrollStatusRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_gophkeeper_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -346,48 +418,43 @@ func (x *GetRecordRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetRecordRequest.ProtoReflect.Descriptor instead.
-func (*GetRecordRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use EnrollStatusRequest.ProtoReflect.Descriptor instead.
+func (*EnrollStatusRequest) Descriptor() ([]byte, []int) {
 	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{5}
 }
 
-func (x *GetRecordRequest) GetType() RecordType {
-	if x != nil && x.Type != nil {
-		return *x.Type
-	}
-	return RecordType_UNKNOWN
-}
-
-func (x *GetRecordRequest) GetRecordNumber() int64 {
-	if x != nil && x.RecordNumber != nil {
-		return *x.RecordNumber
+func (x *EnrollStatusRequest) GetCode() string {
+	if x != nil && x.Code != nil {
+		return *x.Code
 	}
-	return 0
+	return ""
 }
 
-type GetRecordResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Type          *RecordType            `protobuf:"varint,1,opt,name=type,enum=gophkeeper.RecordType" json:"type,omitempty"`
-	Record        []byte                 `protobuf:"bytes,2,opt,name=record" json:"record,omitempty"`
-	Error         *string                `protobuf:"bytes,3,opt,name=error" json:"error,omitempty"`
+type EnrollStatusResponse struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	Approved          *bool                  `protobuf:"varint,1,opt,name=approved" json:"approved,omitempty"`
+	CaCertificate     []byte                 `protobuf:"bytes,2,opt,name=ca_certificate,json=caCertificate" json:"ca_certificate,omitempty"`
+	ClientCertificate []byte                 `protobuf:"bytes,3,opt,name=client_certificate,json=clientCertificate" json:"client_certificate,omitempty"`
+	// vault_key is a vault private key encrypted for public key of enrolled device.
+	VaultKey      []byte `protobuf:"bytes,4,opt,name=vault_key,json=vaultKey" json:"vault_key,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetRecordResponse) Reset() {
-	*x = GetRecordResponse{}
+func (x *EnrollStatusResponse) Reset() {
+	*x = EnrollStatusResponse{}
 	mi := &file_proto_gophkeeper_proto_msgTypes[6]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetRecordResponse) String() string {
+func (x *EnrollStatusResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetRecordResponse) ProtoMessage() {}
+func (*EnrollStatusResponse) ProtoMessage() {}
 
-func (x *GetRecordResponse) ProtoReflect() protoreflect.Message {
+func (x *EnrollStatusResponse) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_gophkeeper_proto_msgTypes[6]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -399,55 +466,65 @@ func (x *GetRecordResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetRecordResponse.ProtoReflect.Descriptor instead.
-func (*GetRecordResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use EnrollStatusResponse.ProtoReflect.Descriptor instead.
+func (*EnrollStatusResponse) Descriptor() ([]byte, []int) {
 	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *GetRecordResponse) GetType() RecordType {
-	if x != nil && x.Type != nil {
-		return *x.Type
+func (x *EnrollStatusResponse) GetApproved() bool {
+	if x != nil && x.Approved != nil {
+		return *x.Approved
 	}
-	return RecordType_UNKNOWN
+	return false
 }
 
-func (x *GetRecordResponse) GetRecord() []byte {
+func (x *EnrollStatusResponse) GetCaCertificate() []byte {
 	if x != nil {
-		return x.Record
+		return x.CaCertificate
 	}
 	return nil
 }
 
-func (x *GetRecordResponse) GetError() string {
-	if x != nil && x.Error != nil {
-		return *x.Error
+func (x *EnrollStatusResponse) GetClientCertificate() []byte {
+	if x != nil {
+		return x.ClientCertificate
 	}
-	return ""
+	return nil
 }
 
-type UpdateRecordRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Type          *RecordType            `protobuf:"varint,1,opt,name=type,enum=gophkeeper.RecordType" json:"type,omitempty"`
-	RecordNumber  *int64                 `protobuf:"varint,2,opt,name=record_number,json=recordNumber" json:"record_number,omitempty"`
-	Record        []byte                 `protobuf:"bytes,3,opt,name=record" json:"record,omitempty"`
+func (x *EnrollStatusResponse) GetVaultKey() []byte {
+	if x != nil {
+		return x.VaultKey
+	}
+	return nil
+}
+
+type Device struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Id    *int64                 `protobuf:"varint,1,opt,name=id" json:"id,omitempty"`
+	Name  *string                `protobuf:"bytes,2,opt,name=name" json:"name,omitempty"`
+	// created is a unix time of device registration.
+	Created *int64 `protobuf:"varint,3,opt,name=created" json:"created,omitempty"`
+	// current is true for device which made request.
+	Current       *bool `protobuf:"varint,4,opt,name=current" json:"current,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *UpdateRecordRequest) Reset() {
-	*x = UpdateRecordRequest{}
+func (x *Device) Reset() {
+	*x = Device{}
 	mi := &file_proto_gophkeeper_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *UpdateRecordRequest) String() string {
+func (x *Device) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*UpdateRecordRequest) ProtoMessage() {}
+func (*Device) ProtoMessage() {}
 
-func (x *UpdateRecordRequest) ProtoReflect() protoreflect.Message {
+func (x *Device) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_gophkeeper_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -459,54 +536,64 @@ func (x *UpdateRecordRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use UpdateRecordRequest.ProtoReflect.Descriptor instead.
-func (*UpdateRecordRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use Device.ProtoReflect.Descriptor instead.
+func (*Device) Descriptor() ([]byte, []int) {
 	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *UpdateRecordRequest) GetType() RecordType {
-	if x != nil && x.Type != nil {
-		return *x.Type
+func (x *Device) GetId() int64 {
+	if x != nil && x.Id != nil {
+		return *x.Id
 	}
-	return RecordType_UNKNOWN
+	return 0
 }
 
-func (x *UpdateRecordRequest) GetRecordNumber() int64 {
-	if x != nil && x.RecordNumber != nil {
-		return *x.RecordNumber
+func (x *Device) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *Device) GetCreated() int64 {
+	if x != nil && x.Created != nil {
+		return *x.Created
 	}
 	return 0
 }
 
-func (x *UpdateRecordRequest) GetRecord() []byte {
-	if x != nil {
-		return x.Record
+func (x *Device) GetCurrent() bool {
+	if x != nil && x.Current != nil {
+		return *x.Current
 	}
-	return nil
+	return false
 }
 
-type DeleteRecordRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Type          *RecordType            `protobuf:"varint,1,opt,name=type,enum=gophkeeper.RecordType" json:"type,omitempty"`
-	RecordNumber  *int64                 `protobuf:"varint,2,opt,name=record_number,json=recordNumber" json:"record_number,omitempty"`
+type PendingDevice struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Code  *string                `protobuf:"bytes,1,opt,name=code" json:"code,omitempty"`
+	Name  *string                `protobuf:"bytes,2,opt,name=name" json:"name,omitempty"`
+	// public_key is a PKIX public key of device.
+	PublicKey     []byte `protobuf:"bytes,3,opt,name=public_key,json=publicKey" json:"public_key,omitempty"`
+	Created       *int64 `protobuf:"varint,4,opt,name=created" json:"created,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteRecordRequest) Reset() {
-	*x = DeleteRecordRequest{}
+func (x *PendingDevice) Reset() {
+	*x = PendingDevice{}
 	mi := &file_proto_gophkeeper_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteRecordRequest) String() string {
+func (x *PendingDevice) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteRecordRequest) ProtoMessage() {}
+func (*PendingDevice) ProtoMessage() {}
 
-func (x *DeleteRecordRequest) ProtoReflect() protoreflect.Message {
+func (x *PendingDevice) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_gophkeeper_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -518,143 +605,3221 @@ func (x *DeleteRecordRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteRecordRequest.ProtoReflect.Descriptor instead.
-func (*DeleteRecordRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use PendingDevice.ProtoReflect.Descriptor instead.
+func (*PendingDevice) Descriptor() ([]byte, []int) {
 	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{8}
 }
 
-func (x *DeleteRecordRequest) GetType() RecordType {
-	if x != nil && x.Type != nil {
-		return *x.Type
+func (x *PendingDevice) GetCode() string {
+	if x != nil && x.Code != nil {
+		return *x.Code
 	}
-	return RecordType_UNKNOWN
+	return ""
 }
 
-func (x *DeleteRecordRequest) GetRecordNumber() int64 {
-	if x != nil && x.RecordNumber != nil {
-		return *x.RecordNumber
+func (x *PendingDevice) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
 	}
-	return 0
+	return ""
 }
 
-var File_proto_gophkeeper_proto protoreflect.FileDescriptor
-
-const file_proto_gophkeeper_proto_rawDesc = "" +
-	"\n" +
-	"\x16proto/gophkeeper.proto\x12\n" +
-	"gophkeeper\x1a\x1bgoogle/protobuf/empty.proto\"4\n" +
-	"\x0fRegisterRequest\x12!\n" +
-	"\fcert_request\x18\x01 \x01(\fR\vcertRequest\"n\n" +
-	"\x10RegisterResponse\x12%\n" +
-	"\x0eca_certificate\x18\x02 \x01(\fR\rcaCertificate\x12-\n" +
-	"\x12client_certificate\x18\x03 \x01(\fR\x11clientCertificateJ\x04\b\x01\x10\x02\"9\n" +
-	"\vListRequest\x12*\n" +
-	"\x04type\x18\x01 \x01(\x0e2\x16.gophkeeper.RecordTypeR\x04type\"(\n" +
-	"\fListResponse\x12\x18\n" +
-	"\arecords\x18\x01 \x01(\fR\arecords\"V\n" +
-	"\x10AddRecordRequest\x12*\n" +
-	"\x04type\x18\x01 \x01(\x0e2\x16.gophkeeper.RecordTypeR\x04type\x12\x16\n" +
-	"\x06record\x18\x02 \x01(\fR\x06record\"c\n" +
-	"\x10GetRecordRequest\x12*\n" +
-	"\x04type\x18\x01 \x01(\x0e2\x16.gophkeeper.RecordTypeR\x04type\x12#\n" +
-	"\rrecord_number\x18\x02 \x01(\x03R\frecordNumber\"m\n" +
-	"\x11GetRecordResponse\x12*\n" +
-	"\x04type\x18\x01 \x01(\x0e2\x16.gophkeeper.RecordTypeR\x04type\x12\x16\n" +
-	"\x06record\x18\x02 \x01(\fR\x06record\x12\x14\n" +
-	"\x05error\x18\x03 \x01(\tR\x05error\"~\n" +
-	"\x13UpdateRecordRequest\x12*\n" +
-	"\x04type\x18\x01 \x01(\x0e2\x16.gophkeeper.RecordTypeR\x04type\x12#\n" +
-	"\rrecord_number\x18\x02 \x01(\x03R\frecordNumber\x12\x16\n" +
-	"\x06record\x18\x03 \x01(\fR\x06record\"f\n" +
-	"\x13DeleteRecordRequest\x12*\n" +
-	"\x04type\x18\x01 \x01(\x0e2\x16.gophkeeper.RecordTypeR\x04type\x12#\n" +
-	"\rrecord_number\x18\x02 \x01(\x03R\frecordNumber*D\n" +
-	"\n" +
-	"RecordType\x12\v\n" +
-	"\aUNKNOWN\x10\x00\x12\f\n" +
-	"\bPASSWORD\x10\x01\x12\b\n" +
-	"\x04TEXT\x10\x02\x12\a\n" +
-	"\x03BIN\x10\x03\x12\b\n" +
-	"\x04BANK\x10\x042O\n" +
-	"\x06Public\x12E\n" +
-	"\bRegister\x12\x1b.gophkeeper.RegisterRequest\x1a\x1c.gophkeeper.RegisterResponse2\x8c\x03\n" +
-	"\aPrivate\x12;\n" +
-	"\aListAll\x12\x16.google.protobuf.Empty\x1a\x18.gophkeeper.ListResponse\x129\n" +
-	"\x04List\x12\x17.gophkeeper.ListRequest\x1a\x18.gophkeeper.ListResponse\x12>\n" +
-	"\x06Create\x12\x1c.gophkeeper.AddRecordRequest\x1a\x16.google.protobuf.Empty\x12C\n" +
-	"\x04Read\x12\x1c.gophkeeper.GetRecordRequest\x1a\x1d.gophkeeper.GetRecordResponse\x12A\n" +
-	"\x06Update\x12\x1f.gophkeeper.UpdateRecordRequest\x1a\x16.google.protobuf.Empty\x12A\n" +
-	"\x06Delete\x12\x1f.gophkeeper.DeleteRecordRequest\x1a\x16.google.protobuf.EmptyB\x12Z\x10gophkeeper/protob\beditionsp\xe8\a"
+func (x *PendingDevice) GetPublicKey() []byte {
+	if x != nil {
+		return x.PublicKey
+	}
+	return nil
+}
 
-var (
-	file_proto_gophkeeper_proto_rawDescOnce sync.Once
-	file_proto_gophkeeper_proto_rawDescData []byte
-)
+func (x *PendingDevice) GetCreated() int64 {
+	if x != nil && x.Created != nil {
+		return *x.Created
+	}
+	return 0
+}
 
-func file_proto_gophkeeper_proto_rawDescGZIP() []byte {
-	file_proto_gophkeeper_proto_rawDescOnce.Do(func() {
-		file_proto_gophkeeper_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_gophkeeper_proto_rawDesc), len(file_proto_gophkeeper_proto_rawDesc)))
-	})
-	return file_proto_gophkeeper_proto_rawDescData
+type ListDevicesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Devices       []*Device              `protobuf:"bytes,1,rep,name=devices" json:"devices,omitempty"`
+	Pending       []*PendingDevice       `protobuf:"bytes,2,rep,name=pending" json:"pending,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-var file_proto_gophkeeper_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_proto_gophkeeper_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
-var file_proto_gophkeeper_proto_goTypes = []any{
-	(RecordType)(0),             // 0: gophkeeper.RecordType
-	(*RegisterRequest)(nil),     // 1: gophkeeper.RegisterRequest
-	(*RegisterResponse)(nil),    // 2: gophkeeper.RegisterResponse
-	(*ListRequest)(nil),         // 3: gophkeeper.ListRequest
-	(*ListResponse)(nil),        // 4: gophkeeper.ListResponse
-	(*AddRecordRequest)(nil),    // 5: gophkeeper.AddRecordRequest
-	(*GetRecordRequest)(nil),    // 6: gophkeeper.GetRecordRequest
-	(*GetRecordResponse)(nil),   // 7: gophkeeper.GetRecordResponse
-	(*UpdateRecordRequest)(nil), // 8: gophkeeper.UpdateRecordRequest
-	(*DeleteRecordRequest)(nil), // 9: gophkeeper.DeleteRecordRequest
-	(*emptypb.Empty)(nil),       // 10: google.protobuf.Empty
+func (x *ListDevicesResponse) Reset() {
+	*x = ListDevicesResponse{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
 }
-var file_proto_gophkeeper_proto_depIdxs = []int32{
-	0,  // 0: gophkeeper.ListRequest.type:type_name -> gophkeeper.RecordType
-	0,  // 1: gophkeeper.AddRecordRequest.type:type_name -> gophkeeper.RecordType
-	0,  // 2: gophkeeper.GetRecordRequest.type:type_name -> gophkeeper.RecordType
-	0,  // 3: gophkeeper.GetRecordResponse.type:type_name -> gophkeeper.RecordType
-	0,  // 4: gophkeeper.UpdateRecordRequest.type:type_name -> gophkeeper.RecordType
-	0,  // 5: gophkeeper.DeleteRecordRequest.type:type_name -> gophkeeper.RecordType
-	1,  // 6: gophkeeper.Public.Register:input_type -> gophkeeper.RegisterRequest
-	10, // 7: gophkeeper.Private.ListAll:input_type -> google.protobuf.Empty
-	3,  // 8: gophkeeper.Private.List:input_type -> gophkeeper.ListRequest
-	5,  // 9: gophkeeper.Private.Create:input_type -> gophkeeper.AddRecordRequest
-	6,  // 10: gophkeeper.Private.Read:input_type -> gophkeeper.GetRecordRequest
-	8,  // 11: gophkeeper.Private.Update:input_type -> gophkeeper.UpdateRecordRequest
-	9,  // 12: gophkeeper.Private.Delete:input_type -> gophkeeper.DeleteRecordRequest
-	2,  // 13: gophkeeper.Public.Register:output_type -> gophkeeper.RegisterResponse
-	4,  // 14: gophkeeper.Private.ListAll:output_type -> gophkeeper.ListResponse
-	4,  // 15: gophkeeper.Private.List:output_type -> gophkeeper.ListResponse
-	10, // 16: gophkeeper.Private.Create:output_type -> google.protobuf.Empty
-	7,  // 17: gophkeeper.Private.Read:output_type -> gophkeeper.GetRecordResponse
-	10, // 18: gophkeeper.Private.Update:output_type -> google.protobuf.Empty
-	10, // 19: gophkeeper.Private.Delete:output_type -> google.protobuf.Empty
-	13, // [13:20] is the sub-list for method output_type
-	6,  // [6:13] is the sub-list for method input_type
-	6,  // [6:6] is the sub-list for extension type_name
-	6,  // [6:6] is the sub-list for extension extendee
-	0,  // [0:6] is the sub-list for field type_name
+
+func (x *ListDevicesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
 }
 
-func init() { file_proto_gophkeeper_proto_init() }
-func file_proto_gophkeeper_proto_init() {
-	if File_proto_gophkeeper_proto != nil {
-		return
+func (*ListDevicesResponse) ProtoMessage() {}
+
+func (x *ListDevicesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListDevicesResponse.ProtoReflect.Descriptor instead.
+func (*ListDevicesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *ListDevicesResponse) GetDevices() []*Device {
+	if x != nil {
+		return x.Devices
+	}
+	return nil
+}
+
+func (x *ListDevicesResponse) GetPending() []*PendingDevice {
+	if x != nil {
+		return x.Pending
+	}
+	return nil
+}
+
+type ApproveDeviceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Code          *string                `protobuf:"bytes,1,opt,name=code" json:"code,omitempty"`
+	VaultKey      []byte                 `protobuf:"bytes,2,opt,name=vault_key,json=vaultKey" json:"vault_key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ApproveDeviceRequest) Reset() {
+	*x = ApproveDeviceRequest{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ApproveDeviceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ApproveDeviceRequest) ProtoMessage() {}
+
+func (x *ApproveDeviceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ApproveDeviceRequest.ProtoReflect.Descriptor instead.
+func (*ApproveDeviceRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ApproveDeviceRequest) GetCode() string {
+	if x != nil && x.Code != nil {
+		return *x.Code
+	}
+	return ""
+}
+
+func (x *ApproveDeviceRequest) GetVaultKey() []byte {
+	if x != nil {
+		return x.VaultKey
+	}
+	return nil
+}
+
+type RemoveDeviceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            *int64                 `protobuf:"varint,1,opt,name=id" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RemoveDeviceRequest) Reset() {
+	*x = RemoveDeviceRequest{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RemoveDeviceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RemoveDeviceRequest) ProtoMessage() {}
+
+func (x *RemoveDeviceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RemoveDeviceRequest.ProtoReflect.Descriptor instead.
+func (*RemoveDeviceRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *RemoveDeviceRequest) GetId() int64 {
+	if x != nil && x.Id != nil {
+		return *x.Id
+	}
+	return 0
+}
+
+type PasswordRecord struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	Id       *int64                 `protobuf:"varint,1,opt,name=id" json:"id,omitempty"`
+	Login    []byte                 `protobuf:"bytes,2,opt,name=login" json:"login,omitempty"`
+	Password []byte                 `protobuf:"bytes,3,opt,name=password" json:"password,omitempty"`
+	Meta     []byte                 `protobuf:"bytes,4,opt,name=meta" json:"meta,omitempty"`
+	// uuid is a client generated record identity bound to encrypted fields.
+	Uuid *string `protobuf:"bytes,5,opt,name=uuid" json:"uuid,omitempty"`
+	// data_key is a record data key wrapped with client public key, empty for legacy records
+	// with fields encrypted separately.
+	DataKey []byte `protobuf:"bytes,6,opt,name=data_key,json=dataKey" json:"data_key,omitempty"`
+	// revision is a server revision of last change of record, set by server.
+	Revision      *int64 `protobuf:"varint,7,opt,name=revision" json:"revision,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PasswordRecord) Reset() {
+	*x = PasswordRecord{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PasswordRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PasswordRecord) ProtoMessage() {}
+
+func (x *PasswordRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PasswordRecord.ProtoReflect.Descriptor instead.
+func (*PasswordRecord) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *PasswordRecord) GetId() int64 {
+	if x != nil && x.Id != nil {
+		return *x.Id
+	}
+	return 0
+}
+
+func (x *PasswordRecord) GetLogin() []byte {
+	if x != nil {
+		return x.Login
+	}
+	return nil
+}
+
+func (x *PasswordRecord) GetPassword() []byte {
+	if x != nil {
+		return x.Password
+	}
+	return nil
+}
+
+func (x *PasswordRecord) GetMeta() []byte {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+func (x *PasswordRecord) GetUuid() string {
+	if x != nil && x.Uuid != nil {
+		return *x.Uuid
+	}
+	return ""
+}
+
+func (x *PasswordRecord) GetDataKey() []byte {
+	if x != nil {
+		return x.DataKey
+	}
+	return nil
+}
+
+func (x *PasswordRecord) GetRevision() int64 {
+	if x != nil && x.Revision != nil {
+		return *x.Revision
+	}
+	return 0
+}
+
+type TextRecord struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            *int64                 `protobuf:"varint,1,opt,name=id" json:"id,omitempty"`
+	Text          []byte                 `protobuf:"bytes,2,opt,name=text" json:"text,omitempty"`
+	Meta          []byte                 `protobuf:"bytes,3,opt,name=meta" json:"meta,omitempty"`
+	Uuid          *string                `protobuf:"bytes,4,opt,name=uuid" json:"uuid,omitempty"`
+	DataKey       []byte                 `protobuf:"bytes,5,opt,name=data_key,json=dataKey" json:"data_key,omitempty"`
+	Revision      *int64                 `protobuf:"varint,6,opt,name=revision" json:"revision,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TextRecord) Reset() {
+	*x = TextRecord{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TextRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TextRecord) ProtoMessage() {}
+
+func (x *TextRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TextRecord.ProtoReflect.Descriptor instead.
+func (*TextRecord) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *TextRecord) GetId() int64 {
+	if x != nil && x.Id != nil {
+		return *x.Id
+	}
+	return 0
+}
+
+func (x *TextRecord) GetText() []byte {
+	if x != nil {
+		return x.Text
+	}
+	return nil
+}
+
+func (x *TextRecord) GetMeta() []byte {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+func (x *TextRecord) GetUuid() string {
+	if x != nil && x.Uuid != nil {
+		return *x.Uuid
+	}
+	return ""
+}
+
+func (x *TextRecord) GetDataKey() []byte {
+	if x != nil {
+		return x.DataKey
+	}
+	return nil
+}
+
+func (x *TextRecord) GetRevision() int64 {
+	if x != nil && x.Revision != nil {
+		return *x.Revision
+	}
+	return 0
+}
+
+type BinRecord struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            *int64                 `protobuf:"varint,1,opt,name=id" json:"id,omitempty"`
+	Data          []byte                 `protobuf:"bytes,2,opt,name=data" json:"data,omitempty"`
+	Meta          []byte                 `protobuf:"bytes,3,opt,name=meta" json:"meta,omitempty"`
+	Uuid          *string                `protobuf:"bytes,4,opt,name=uuid" json:"uuid,omitempty"`
+	DataKey       []byte                 `protobuf:"bytes,5,opt,name=data_key,json=dataKey" json:"data_key,omitempty"`
+	Revision      *int64                 `protobuf:"varint,6,opt,name=revision" json:"revision,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BinRecord) Reset() {
+	*x = BinRecord{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BinRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BinRecord) ProtoMessage() {}
+
+func (x *BinRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BinRecord.ProtoReflect.Descriptor instead.
+func (*BinRecord) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *BinRecord) GetId() int64 {
+	if x != nil && x.Id != nil {
+		return *x.Id
+	}
+	return 0
+}
+
+func (x *BinRecord) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *BinRecord) GetMeta() []byte {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+func (x *BinRecord) GetUuid() string {
+	if x != nil && x.Uuid != nil {
+		return *x.Uuid
+	}
+	return ""
+}
+
+func (x *BinRecord) GetDataKey() []byte {
+	if x != nil {
+		return x.DataKey
+	}
+	return nil
+}
+
+func (x *BinRecord) GetRevision() int64 {
+	if x != nil && x.Revision != nil {
+		return *x.Revision
+	}
+	return 0
+}
+
+type BankRecord struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            *int64                 `protobuf:"varint,1,opt,name=id" json:"id,omitempty"`
+	Number        []byte                 `protobuf:"bytes,2,opt,name=number" json:"number,omitempty"`
+	Name          []byte                 `protobuf:"bytes,3,opt,name=name" json:"name,omitempty"`
+	Date          []byte                 `protobuf:"bytes,4,opt,name=date" json:"date,omitempty"`
+	Cvv           []byte                 `protobuf:"bytes,5,opt,name=cvv" json:"cvv,omitempty"`
+	Meta          []byte                 `protobuf:"bytes,6,opt,name=meta" json:"meta,omitempty"`
+	Uuid          *string                `protobuf:"bytes,7,opt,name=uuid" json:"uuid,omitempty"`
+	DataKey       []byte                 `protobuf:"bytes,8,opt,name=data_key,json=dataKey" json:"data_key,omitempty"`
+	Revision      *int64                 `protobuf:"varint,9,opt,name=revision" json:"revision,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BankRecord) Reset() {
+	*x = BankRecord{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BankRecord) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BankRecord) ProtoMessage() {}
+
+func (x *BankRecord) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BankRecord.ProtoReflect.Descriptor instead.
+func (*BankRecord) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *BankRecord) GetId() int64 {
+	if x != nil && x.Id != nil {
+		return *x.Id
+	}
+	return 0
+}
+
+func (x *BankRecord) GetNumber() []byte {
+	if x != nil {
+		return x.Number
+	}
+	return nil
+}
+
+func (x *BankRecord) GetName() []byte {
+	if x != nil {
+		return x.Name
+	}
+	return nil
+}
+
+func (x *BankRecord) GetDate() []byte {
+	if x != nil {
+		return x.Date
+	}
+	return nil
+}
+
+func (x *BankRecord) GetCvv() []byte {
+	if x != nil {
+		return x.Cvv
+	}
+	return nil
+}
+
+func (x *BankRecord) GetMeta() []byte {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+func (x *BankRecord) GetUuid() string {
+	if x != nil && x.Uuid != nil {
+		return *x.Uuid
+	}
+	return ""
+}
+
+func (x *BankRecord) GetDataKey() []byte {
+	if x != nil {
+		return x.DataKey
+	}
+	return nil
+}
+
+func (x *BankRecord) GetRevision() int64 {
+	if x != nil && x.Revision != nil {
+		return *x.Revision
+	}
+	return 0
+}
+
+// Record is an encrypted record of one RecordType.
+type Record struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Record:
+	//
+	//	*Record_Password
+	//	*Record_Text
+	//	*Record_Bin
+	//	*Record_Bank
+	Record        isRecord_Record `protobuf_oneof:"record"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Record) Reset() {
+	*x = Record{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Record) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Record) ProtoMessage() {}
+
+func (x *Record) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Record.ProtoReflect.Descriptor instead.
+func (*Record) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *Record) GetRecord() isRecord_Record {
+	if x != nil {
+		return x.Record
+	}
+	return nil
+}
+
+func (x *Record) GetPassword() *PasswordRecord {
+	if x != nil {
+		if x, ok := x.Record.(*Record_Password); ok {
+			return x.Password
+		}
+	}
+	return nil
+}
+
+func (x *Record) GetText() *TextRecord {
+	if x != nil {
+		if x, ok := x.Record.(*Record_Text); ok {
+			return x.Text
+		}
+	}
+	return nil
+}
+
+func (x *Record) GetBin() *BinRecord {
+	if x != nil {
+		if x, ok := x.Record.(*Record_Bin); ok {
+			return x.Bin
+		}
+	}
+	return nil
+}
+
+func (x *Record) GetBank() *BankRecord {
+	if x != nil {
+		if x, ok := x.Record.(*Record_Bank); ok {
+			return x.Bank
+		}
+	}
+	return nil
+}
+
+type isRecord_Record interface {
+	isRecord_Record()
+}
+
+type Record_Password struct {
+	Password *PasswordRecord `protobuf:"bytes,1,opt,name=password,oneof"`
+}
+
+type Record_Text struct {
+	Text *TextRecord `protobuf:"bytes,2,opt,name=text,oneof"`
+}
+
+type Record_Bin struct {
+	Bin *BinRecord `protobuf:"bytes,3,opt,name=bin,oneof"`
+}
+
+type Record_Bank struct {
+	Bank *BankRecord `protobuf:"bytes,4,opt,name=bank,oneof"`
+}
+
+func (*Record_Password) isRecord_Record() {}
+
+func (*Record_Text) isRecord_Record() {}
+
+func (*Record_Bin) isRecord_Record() {}
+
+func (*Record_Bank) isRecord_Record() {}
+
+type ListRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          *RecordType            `protobuf:"varint,1,opt,name=type,enum=gophkeeper.RecordType" json:"type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListRequest) Reset() {
+	*x = ListRequest{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListRequest) ProtoMessage() {}
+
+func (x *ListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListRequest.ProtoReflect.Descriptor instead.
+func (*ListRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *ListRequest) GetType() RecordType {
+	if x != nil && x.Type != nil {
+		return *x.Type
+	}
+	return RecordType_UNKNOWN
+}
+
+type ListResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Deprecated: JSON encoded records, use items.
+	//
+	// Deprecated: Marked as deprecated in proto/gophkeeper.proto.
+	Records []byte `protobuf:"bytes,1,opt,name=records" json:"records,omitempty"`
+	// items contains only id and meta of records.
+	Items         []*Record `protobuf:"bytes,2,rep,name=items" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListResponse) Reset() {
+	*x = ListResponse{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListResponse) ProtoMessage() {}
+
+func (x *ListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListResponse.ProtoReflect.Descriptor instead.
+func (*ListResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{18}
+}
+
+// Deprecated: Marked as deprecated in proto/gophkeeper.proto.
+func (x *ListResponse) GetRecords() []byte {
+	if x != nil {
+		return x.Records
+	}
+	return nil
+}
+
+func (x *ListResponse) GetItems() []*Record {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type AddRecordRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Type  *RecordType            `protobuf:"varint,1,opt,name=type,enum=gophkeeper.RecordType" json:"type,omitempty"`
+	// Deprecated: JSON encoded record, use item.
+	//
+	// Deprecated: Marked as deprecated in proto/gophkeeper.proto.
+	Record        []byte  `protobuf:"bytes,2,opt,name=record" json:"record,omitempty"`
+	Item          *Record `protobuf:"bytes,3,opt,name=item" json:"item,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AddRecordRequest) Reset() {
+	*x = AddRecordRequest{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AddRecordRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AddRecordRequest) ProtoMessage() {}
+
+func (x *AddRecordRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AddRecordRequest.ProtoReflect.Descriptor instead.
+func (*AddRecordRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *AddRecordRequest) GetType() RecordType {
+	if x != nil && x.Type != nil {
+		return *x.Type
+	}
+	return RecordType_UNKNOWN
+}
+
+// Deprecated: Marked as deprecated in proto/gophkeeper.proto.
+func (x *AddRecordRequest) GetRecord() []byte {
+	if x != nil {
+		return x.Record
+	}
+	return nil
+}
+
+func (x *AddRecordRequest) GetItem() *Record {
+	if x != nil {
+		return x.Item
+	}
+	return nil
+}
+
+type GetRecordRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          *RecordType            `protobuf:"varint,1,opt,name=type,enum=gophkeeper.RecordType" json:"type,omitempty"`
+	RecordNumber  *int64                 `protobuf:"varint,2,opt,name=record_number,json=recordNumber" json:"record_number,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRecordRequest) Reset() {
+	*x = GetRecordRequest{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRecordRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRecordRequest) ProtoMessage() {}
+
+func (x *GetRecordRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRecordRequest.ProtoReflect.Descriptor instead.
+func (*GetRecordRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *GetRecordRequest) GetType() RecordType {
+	if x != nil && x.Type != nil {
+		return *x.Type
+	}
+	return RecordType_UNKNOWN
+}
+
+func (x *GetRecordRequest) GetRecordNumber() int64 {
+	if x != nil && x.RecordNumber != nil {
+		return *x.RecordNumber
+	}
+	return 0
+}
+
+type GetRecordResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Type  *RecordType            `protobuf:"varint,1,opt,name=type,enum=gophkeeper.RecordType" json:"type,omitempty"`
+	// Deprecated: JSON encoded record, use item.
+	//
+	// Deprecated: Marked as deprecated in proto/gophkeeper.proto.
+	Record        []byte  `protobuf:"bytes,2,opt,name=record" json:"record,omitempty"`
+	Error         *string `protobuf:"bytes,3,opt,name=error" json:"error,omitempty"`
+	Item          *Record `protobuf:"bytes,4,opt,name=item" json:"item,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRecordResponse) Reset() {
+	*x = GetRecordResponse{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRecordResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRecordResponse) ProtoMessage() {}
+
+func (x *GetRecordResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRecordResponse.ProtoReflect.Descriptor instead.
+func (*GetRecordResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *GetRecordResponse) GetType() RecordType {
+	if x != nil && x.Type != nil {
+		return *x.Type
+	}
+	return RecordType_UNKNOWN
+}
+
+// Deprecated: Marked as deprecated in proto/gophkeeper.proto.
+func (x *GetRecordResponse) GetRecord() []byte {
+	if x != nil {
+		return x.Record
+	}
+	return nil
+}
+
+func (x *GetRecordResponse) GetError() string {
+	if x != nil && x.Error != nil {
+		return *x.Error
+	}
+	return ""
+}
+
+func (x *GetRecordResponse) GetItem() *Record {
+	if x != nil {
+		return x.Item
+	}
+	return nil
+}
+
+type UpdateRecordRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Type         *RecordType            `protobuf:"varint,1,opt,name=type,enum=gophkeeper.RecordType" json:"type,omitempty"`
+	RecordNumber *int64                 `protobuf:"varint,2,opt,name=record_number,json=recordNumber" json:"record_number,omitempty"`
+	// Deprecated: JSON encoded record, use item.
+	//
+	// Deprecated: Marked as deprecated in proto/gophkeeper.proto.
+	Record []byte  `protobuf:"bytes,3,opt,name=record" json:"record,omitempty"`
+	Item   *Record `protobuf:"bytes,4,opt,name=item" json:"item,omitempty"`
+	// Revision of record read by client, update fails with ABORTED if record was changed since.
+	// Zero or unset revision replaces any revision.
+	ExpectedRevision *int64 `protobuf:"varint,5,opt,name=expected_revision,json=expectedRevision" json:"expected_revision,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *UpdateRecordRequest) Reset() {
+	*x = UpdateRecordRequest{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateRecordRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateRecordRequest) ProtoMessage() {}
+
+func (x *UpdateRecordRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateRecordRequest.ProtoReflect.Descriptor instead.
+func (*UpdateRecordRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *UpdateRecordRequest) GetType() RecordType {
+	if x != nil && x.Type != nil {
+		return *x.Type
+	}
+	return RecordType_UNKNOWN
+}
+
+func (x *UpdateRecordRequest) GetRecordNumber() int64 {
+	if x != nil && x.RecordNumber != nil {
+		return *x.RecordNumber
+	}
+	return 0
+}
+
+// Deprecated: Marked as deprecated in proto/gophkeeper.proto.
+func (x *UpdateRecordRequest) GetRecord() []byte {
+	if x != nil {
+		return x.Record
+	}
+	return nil
+}
+
+func (x *UpdateRecordRequest) GetItem() *Record {
+	if x != nil {
+		return x.Item
+	}
+	return nil
+}
+
+func (x *UpdateRecordRequest) GetExpectedRevision() int64 {
+	if x != nil && x.ExpectedRevision != nil {
+		return *x.ExpectedRevision
+	}
+	return 0
+}
+
+type DeleteRecordRequest struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Type         *RecordType            `protobuf:"varint,1,opt,name=type,enum=gophkeeper.RecordType" json:"type,omitempty"`
+	RecordNumber *int64                 `protobuf:"varint,2,opt,name=record_number,json=recordNumber" json:"record_number,omitempty"`
+	// Revision of record read by client, delete fails with ABORTED if record was changed since.
+	// Zero or unset revision deletes any revision.
+	ExpectedRevision *int64 `protobuf:"varint,3,opt,name=expected_revision,json=expectedRevision" json:"expected_revision,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *DeleteRecordRequest) Reset() {
+	*x = DeleteRecordRequest{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteRecordRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteRecordRequest) ProtoMessage() {}
+
+func (x *DeleteRecordRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteRecordRequest.ProtoReflect.Descriptor instead.
+func (*DeleteRecordRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *DeleteRecordRequest) GetType() RecordType {
+	if x != nil && x.Type != nil {
+		return *x.Type
+	}
+	return RecordType_UNKNOWN
+}
+
+func (x *DeleteRecordRequest) GetRecordNumber() int64 {
+	if x != nil && x.RecordNumber != nil {
+		return *x.RecordNumber
+	}
+	return 0
+}
+
+func (x *DeleteRecordRequest) GetExpectedRevision() int64 {
+	if x != nil && x.ExpectedRevision != nil {
+		return *x.ExpectedRevision
+	}
+	return 0
+}
+
+type ListVersionsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          *RecordType            `protobuf:"varint,1,opt,name=type,enum=gophkeeper.RecordType" json:"type,omitempty"`
+	RecordNumber  *int64                 `protobuf:"varint,2,opt,name=record_number,json=recordNumber" json:"record_number,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListVersionsRequest) Reset() {
+	*x = ListVersionsRequest{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[24]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListVersionsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListVersionsRequest) ProtoMessage() {}
+
+func (x *ListVersionsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[24]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListVersionsRequest.ProtoReflect.Descriptor instead.
+func (*ListVersionsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{24}
+}
+
+func (x *ListVersionsRequest) GetType() RecordType {
+	if x != nil && x.Type != nil {
+		return *x.Type
+	}
+	return RecordType_UNKNOWN
+}
+
+func (x *ListVersionsRequest) GetRecordNumber() int64 {
+	if x != nil && x.RecordNumber != nil {
+		return *x.RecordNumber
+	}
+	return 0
+}
+
+// Version is a previous version of record saved on its update.
+type Version struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// version is a number of version, versions of record are numbered from 1.
+	Version *int64 `protobuf:"varint,1,opt,name=version" json:"version,omitempty"`
+	// created is a unix time when version was replaced.
+	Created       *int64  `protobuf:"varint,2,opt,name=created" json:"created,omitempty"`
+	Item          *Record `protobuf:"bytes,3,opt,name=item" json:"item,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Version) Reset() {
+	*x = Version{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Version) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Version) ProtoMessage() {}
+
+func (x *Version) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Version.ProtoReflect.Descriptor instead.
+func (*Version) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *Version) GetVersion() int64 {
+	if x != nil && x.Version != nil {
+		return *x.Version
+	}
+	return 0
+}
+
+func (x *Version) GetCreated() int64 {
+	if x != nil && x.Created != nil {
+		return *x.Created
+	}
+	return 0
+}
+
+func (x *Version) GetItem() *Record {
+	if x != nil {
+		return x.Item
+	}
+	return nil
+}
+
+type ListVersionsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// versions contain only id, meta and keys of record, newest first.
+	Versions      []*Version `protobuf:"bytes,1,rep,name=versions" json:"versions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListVersionsResponse) Reset() {
+	*x = ListVersionsResponse{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[26]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListVersionsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListVersionsResponse) ProtoMessage() {}
+
+func (x *ListVersionsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[26]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListVersionsResponse.ProtoReflect.Descriptor instead.
+func (*ListVersionsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{26}
+}
+
+func (x *ListVersionsResponse) GetVersions() []*Version {
+	if x != nil {
+		return x.Versions
+	}
+	return nil
+}
+
+type GetVersionRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          *RecordType            `protobuf:"varint,1,opt,name=type,enum=gophkeeper.RecordType" json:"type,omitempty"`
+	RecordNumber  *int64                 `protobuf:"varint,2,opt,name=record_number,json=recordNumber" json:"record_number,omitempty"`
+	Version       *int64                 `protobuf:"varint,3,opt,name=version" json:"version,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetVersionRequest) Reset() {
+	*x = GetVersionRequest{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetVersionRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetVersionRequest) ProtoMessage() {}
+
+func (x *GetVersionRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[27]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetVersionRequest.ProtoReflect.Descriptor instead.
+func (*GetVersionRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *GetVersionRequest) GetType() RecordType {
+	if x != nil && x.Type != nil {
+		return *x.Type
+	}
+	return RecordType_UNKNOWN
+}
+
+func (x *GetVersionRequest) GetRecordNumber() int64 {
+	if x != nil && x.RecordNumber != nil {
+		return *x.RecordNumber
+	}
+	return 0
+}
+
+func (x *GetVersionRequest) GetVersion() int64 {
+	if x != nil && x.Version != nil {
+		return *x.Version
+	}
+	return 0
+}
+
+// TrashItem is a deleted record kept in trash until restore or purge.
+type TrashItem struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Type  *RecordType            `protobuf:"varint,1,opt,name=type,enum=gophkeeper.RecordType" json:"type,omitempty"`
+	// deleted is a unix time when record was deleted.
+	Deleted       *int64  `protobuf:"varint,2,opt,name=deleted" json:"deleted,omitempty"`
+	Item          *Record `protobuf:"bytes,3,opt,name=item" json:"item,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TrashItem) Reset() {
+	*x = TrashItem{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[28]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TrashItem) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TrashItem) ProtoMessage() {}
+
+func (x *TrashItem) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[28]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TrashItem.ProtoReflect.Descriptor instead.
+func (*TrashItem) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{28}
+}
+
+func (x *TrashItem) GetType() RecordType {
+	if x != nil && x.Type != nil {
+		return *x.Type
+	}
+	return RecordType_UNKNOWN
+}
+
+func (x *TrashItem) GetDeleted() int64 {
+	if x != nil && x.Deleted != nil {
+		return *x.Deleted
+	}
+	return 0
+}
+
+func (x *TrashItem) GetItem() *Record {
+	if x != nil {
+		return x.Item
+	}
+	return nil
+}
+
+type ListTrashResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// items contain only id, meta and keys of records, last deleted first.
+	Items         []*TrashItem `protobuf:"bytes,1,rep,name=items" json:"items,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ListTrashResponse) Reset() {
+	*x = ListTrashResponse{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[29]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListTrashResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListTrashResponse) ProtoMessage() {}
+
+func (x *ListTrashResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[29]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ListTrashResponse.ProtoReflect.Descriptor instead.
+func (*ListTrashResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{29}
+}
+
+func (x *ListTrashResponse) GetItems() []*TrashItem {
+	if x != nil {
+		return x.Items
+	}
+	return nil
+}
+
+type RestoreTrashRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          *RecordType            `protobuf:"varint,1,opt,name=type,enum=gophkeeper.RecordType" json:"type,omitempty"`
+	RecordNumber  *int64                 `protobuf:"varint,2,opt,name=record_number,json=recordNumber" json:"record_number,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RestoreTrashRequest) Reset() {
+	*x = RestoreTrashRequest{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RestoreTrashRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RestoreTrashRequest) ProtoMessage() {}
+
+func (x *RestoreTrashRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RestoreTrashRequest.ProtoReflect.Descriptor instead.
+func (*RestoreTrashRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *RestoreTrashRequest) GetType() RecordType {
+	if x != nil && x.Type != nil {
+		return *x.Type
+	}
+	return RecordType_UNKNOWN
+}
+
+func (x *RestoreTrashRequest) GetRecordNumber() int64 {
+	if x != nil && x.RecordNumber != nil {
+		return *x.RecordNumber
+	}
+	return 0
+}
+
+type ChangesRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// since_revision is a last revision known by client, 0 returns all records.
+	SinceRevision *int64 `protobuf:"varint,1,opt,name=since_revision,json=sinceRevision" json:"since_revision,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangesRequest) Reset() {
+	*x = ChangesRequest{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangesRequest) ProtoMessage() {}
+
+func (x *ChangesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangesRequest.ProtoReflect.Descriptor instead.
+func (*ChangesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *ChangesRequest) GetSinceRevision() int64 {
+	if x != nil && x.SinceRevision != nil {
+		return *x.SinceRevision
+	}
+	return 0
+}
+
+// Change is a last change of record.
+type Change struct {
+	state        protoimpl.MessageState `protogen:"open.v1"`
+	Revision     *int64                 `protobuf:"varint,1,opt,name=revision" json:"revision,omitempty"`
+	Op           *ChangeOp              `protobuf:"varint,2,opt,name=op,enum=gophkeeper.ChangeOp" json:"op,omitempty"`
+	Type         *RecordType            `protobuf:"varint,3,opt,name=type,enum=gophkeeper.RecordType" json:"type,omitempty"`
+	RecordNumber *int64                 `protobuf:"varint,4,opt,name=record_number,json=recordNumber" json:"record_number,omitempty"`
+	// item is a full record, absent for CHANGE_DELETE.
+	Item          *Record `protobuf:"bytes,5,opt,name=item" json:"item,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Change) Reset() {
+	*x = Change{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Change) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Change) ProtoMessage() {}
+
+func (x *Change) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Change.ProtoReflect.Descriptor instead.
+func (*Change) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *Change) GetRevision() int64 {
+	if x != nil && x.Revision != nil {
+		return *x.Revision
+	}
+	return 0
+}
+
+func (x *Change) GetOp() ChangeOp {
+	if x != nil && x.Op != nil {
+		return *x.Op
+	}
+	return ChangeOp_CHANGE_UNKNOWN
+}
+
+func (x *Change) GetType() RecordType {
+	if x != nil && x.Type != nil {
+		return *x.Type
+	}
+	return RecordType_UNKNOWN
+}
+
+func (x *Change) GetRecordNumber() int64 {
+	if x != nil && x.RecordNumber != nil {
+		return *x.RecordNumber
+	}
+	return 0
+}
+
+func (x *Change) GetItem() *Record {
+	if x != nil {
+		return x.Item
+	}
+	return nil
+}
+
+type ChangesResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// changes are ordered by revision, only last change of every record is returned.
+	Changes       []*Change `protobuf:"bytes,1,rep,name=changes" json:"changes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChangesResponse) Reset() {
+	*x = ChangesResponse{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChangesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChangesResponse) ProtoMessage() {}
+
+func (x *ChangesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChangesResponse.ProtoReflect.Descriptor instead.
+func (*ChangesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *ChangesResponse) GetChanges() []*Change {
+	if x != nil {
+		return x.Changes
+	}
+	return nil
+}
+
+type WatchRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Changes after since_revision are sent before new changes, zero sends new changes only.
+	SinceRevision *int64 `protobuf:"varint,1,opt,name=since_revision,json=sinceRevision" json:"since_revision,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchRequest) Reset() {
+	*x = WatchRequest{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchRequest) ProtoMessage() {}
+
+func (x *WatchRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchRequest.ProtoReflect.Descriptor instead.
+func (*WatchRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *WatchRequest) GetSinceRevision() int64 {
+	if x != nil && x.SinceRevision != nil {
+		return *x.SinceRevision
+	}
+	return 0
+}
+
+type UploadBinHeader struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// upload_id identifies upload, stream with same upload_id resumes interrupted upload.
+	UploadId *string `protobuf:"bytes,1,opt,name=upload_id,json=uploadId" json:"upload_id,omitempty"`
+	// record_number of existing binary record to replace its data, 0 for new record.
+	RecordNumber *int64 `protobuf:"varint,2,opt,name=record_number,json=recordNumber" json:"record_number,omitempty"`
+	Meta         []byte `protobuf:"bytes,3,opt,name=meta" json:"meta,omitempty"`
+	// first_chunk is a sequence number of first chunk in stream.
+	FirstChunk *int64 `protobuf:"varint,4,opt,name=first_chunk,json=firstChunk" json:"first_chunk,omitempty"`
+	// uuid and data_key of binary record, ignored when upload resumes.
+	Uuid    *string `protobuf:"bytes,5,opt,name=uuid" json:"uuid,omitempty"`
+	DataKey []byte  `protobuf:"bytes,6,opt,name=data_key,json=dataKey" json:"data_key,omitempty"`
+	// Revision of replaced record read by client, upload fails with ABORTED if record was changed since.
+	// Zero or unset revision replaces any revision.
+	ExpectedRevision *int64 `protobuf:"varint,7,opt,name=expected_revision,json=expectedRevision" json:"expected_revision,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *UploadBinHeader) Reset() {
+	*x = UploadBinHeader{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadBinHeader) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadBinHeader) ProtoMessage() {}
+
+func (x *UploadBinHeader) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadBinHeader.ProtoReflect.Descriptor instead.
+func (*UploadBinHeader) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *UploadBinHeader) GetUploadId() string {
+	if x != nil && x.UploadId != nil {
+		return *x.UploadId
+	}
+	return ""
+}
+
+func (x *UploadBinHeader) GetRecordNumber() int64 {
+	if x != nil && x.RecordNumber != nil {
+		return *x.RecordNumber
+	}
+	return 0
+}
+
+func (x *UploadBinHeader) GetMeta() []byte {
+	if x != nil {
+		return x.Meta
+	}
+	return nil
+}
+
+func (x *UploadBinHeader) GetFirstChunk() int64 {
+	if x != nil && x.FirstChunk != nil {
+		return *x.FirstChunk
+	}
+	return 0
+}
+
+func (x *UploadBinHeader) GetUuid() string {
+	if x != nil && x.Uuid != nil {
+		return *x.Uuid
+	}
+	return ""
+}
+
+func (x *UploadBinHeader) GetDataKey() []byte {
+	if x != nil {
+		return x.DataKey
+	}
+	return nil
+}
+
+func (x *UploadBinHeader) GetExpectedRevision() int64 {
+	if x != nil && x.ExpectedRevision != nil {
+		return *x.ExpectedRevision
+	}
+	return 0
+}
+
+type UploadBinRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Payload:
+	//
+	//	*UploadBinRequest_Header
+	//	*UploadBinRequest_Chunk
+	Payload       isUploadBinRequest_Payload `protobuf_oneof:"payload"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadBinRequest) Reset() {
+	*x = UploadBinRequest{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadBinRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadBinRequest) ProtoMessage() {}
+
+func (x *UploadBinRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadBinRequest.ProtoReflect.Descriptor instead.
+func (*UploadBinRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *UploadBinRequest) GetPayload() isUploadBinRequest_Payload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *UploadBinRequest) GetHeader() *UploadBinHeader {
+	if x != nil {
+		if x, ok := x.Payload.(*UploadBinRequest_Header); ok {
+			return x.Header
+		}
+	}
+	return nil
+}
+
+func (x *UploadBinRequest) GetChunk() []byte {
+	if x != nil {
+		if x, ok := x.Payload.(*UploadBinRequest_Chunk); ok {
+			return x.Chunk
+		}
+	}
+	return nil
+}
+
+type isUploadBinRequest_Payload interface {
+	isUploadBinRequest_Payload()
+}
+
+type UploadBinRequest_Header struct {
+	// header must be first message of stream.
+	Header *UploadBinHeader `protobuf:"bytes,1,opt,name=header,oneof"`
+}
+
+type UploadBinRequest_Chunk struct {
+	Chunk []byte `protobuf:"bytes,2,opt,name=chunk,oneof"`
+}
+
+func (*UploadBinRequest_Header) isUploadBinRequest_Payload() {}
+
+func (*UploadBinRequest_Chunk) isUploadBinRequest_Payload() {}
+
+type UploadBinResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RecordNumber  *int64                 `protobuf:"varint,1,opt,name=record_number,json=recordNumber" json:"record_number,omitempty"`
+	Chunks        *int64                 `protobuf:"varint,2,opt,name=chunks" json:"chunks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadBinResponse) Reset() {
+	*x = UploadBinResponse{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadBinResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadBinResponse) ProtoMessage() {}
+
+func (x *UploadBinResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadBinResponse.ProtoReflect.Descriptor instead.
+func (*UploadBinResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *UploadBinResponse) GetRecordNumber() int64 {
+	if x != nil && x.RecordNumber != nil {
+		return *x.RecordNumber
+	}
+	return 0
+}
+
+func (x *UploadBinResponse) GetChunks() int64 {
+	if x != nil && x.Chunks != nil {
+		return *x.Chunks
+	}
+	return 0
+}
+
+type UploadBinStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UploadId      *string                `protobuf:"bytes,1,opt,name=upload_id,json=uploadId" json:"upload_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadBinStatusRequest) Reset() {
+	*x = UploadBinStatusRequest{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadBinStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadBinStatusRequest) ProtoMessage() {}
+
+func (x *UploadBinStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadBinStatusRequest.ProtoReflect.Descriptor instead.
+func (*UploadBinStatusRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *UploadBinStatusRequest) GetUploadId() string {
+	if x != nil && x.UploadId != nil {
+		return *x.UploadId
+	}
+	return ""
+}
+
+type UploadBinStatusResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// chunks is a count of chunks already received by server.
+	Chunks *int64 `protobuf:"varint,1,opt,name=chunks" json:"chunks,omitempty"`
+	// uuid and data_key of binary record given by started upload, empty if upload not exists.
+	Uuid          *string `protobuf:"bytes,2,opt,name=uuid" json:"uuid,omitempty"`
+	DataKey       []byte  `protobuf:"bytes,3,opt,name=data_key,json=dataKey" json:"data_key,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UploadBinStatusResponse) Reset() {
+	*x = UploadBinStatusResponse{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UploadBinStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UploadBinStatusResponse) ProtoMessage() {}
+
+func (x *UploadBinStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UploadBinStatusResponse.ProtoReflect.Descriptor instead.
+func (*UploadBinStatusResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *UploadBinStatusResponse) GetChunks() int64 {
+	if x != nil && x.Chunks != nil {
+		return *x.Chunks
+	}
+	return 0
+}
+
+func (x *UploadBinStatusResponse) GetUuid() string {
+	if x != nil && x.Uuid != nil {
+		return *x.Uuid
+	}
+	return ""
+}
+
+func (x *UploadBinStatusResponse) GetDataKey() []byte {
+	if x != nil {
+		return x.DataKey
+	}
+	return nil
+}
+
+type DownloadBinRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	RecordNumber  *int64                 `protobuf:"varint,1,opt,name=record_number,json=recordNumber" json:"record_number,omitempty"`
+	FirstChunk    *int64                 `protobuf:"varint,2,opt,name=first_chunk,json=firstChunk" json:"first_chunk,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DownloadBinRequest) Reset() {
+	*x = DownloadBinRequest{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DownloadBinRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadBinRequest) ProtoMessage() {}
+
+func (x *DownloadBinRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadBinRequest.ProtoReflect.Descriptor instead.
+func (*DownloadBinRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *DownloadBinRequest) GetRecordNumber() int64 {
+	if x != nil && x.RecordNumber != nil {
+		return *x.RecordNumber
+	}
+	return 0
+}
+
+func (x *DownloadBinRequest) GetFirstChunk() int64 {
+	if x != nil && x.FirstChunk != nil {
+		return *x.FirstChunk
+	}
+	return 0
+}
+
+type DownloadBinResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Seq   *int64                 `protobuf:"varint,1,opt,name=seq" json:"seq,omitempty"`
+	Chunk []byte                 `protobuf:"bytes,2,opt,name=chunk" json:"chunk,omitempty"`
+	// chunks is a total count of chunks of record.
+	Chunks        *int64 `protobuf:"varint,3,opt,name=chunks" json:"chunks,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DownloadBinResponse) Reset() {
+	*x = DownloadBinResponse{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DownloadBinResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DownloadBinResponse) ProtoMessage() {}
+
+func (x *DownloadBinResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DownloadBinResponse.ProtoReflect.Descriptor instead.
+func (*DownloadBinResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *DownloadBinResponse) GetSeq() int64 {
+	if x != nil && x.Seq != nil {
+		return *x.Seq
+	}
+	return 0
+}
+
+func (x *DownloadBinResponse) GetChunk() []byte {
+	if x != nil {
+		return x.Chunk
+	}
+	return nil
+}
+
+func (x *DownloadBinResponse) GetChunks() int64 {
+	if x != nil && x.Chunks != nil {
+		return *x.Chunks
+	}
+	return 0
+}
+
+type RenewRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// cert_request of current device, common name must match current certificate.
+	CertRequest   []byte `protobuf:"bytes,1,opt,name=cert_request,json=certRequest" json:"cert_request,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RenewRequest) Reset() {
+	*x = RenewRequest{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenewRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenewRequest) ProtoMessage() {}
+
+func (x *RenewRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenewRequest.ProtoReflect.Descriptor instead.
+func (*RenewRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *RenewRequest) GetCertRequest() []byte {
+	if x != nil {
+		return x.CertRequest
+	}
+	return nil
+}
+
+type RenewResponse struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	CaCertificate     []byte                 `protobuf:"bytes,1,opt,name=ca_certificate,json=caCertificate" json:"ca_certificate,omitempty"`
+	ClientCertificate []byte                 `protobuf:"bytes,2,opt,name=client_certificate,json=clientCertificate" json:"client_certificate,omitempty"`
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *RenewResponse) Reset() {
+	*x = RenewResponse{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RenewResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RenewResponse) ProtoMessage() {}
+
+func (x *RenewResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RenewResponse.ProtoReflect.Descriptor instead.
+func (*RenewResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *RenewResponse) GetCaCertificate() []byte {
+	if x != nil {
+		return x.CaCertificate
+	}
+	return nil
+}
+
+func (x *RenewResponse) GetClientCertificate() []byte {
+	if x != nil {
+		return x.ClientCertificate
+	}
+	return nil
+}
+
+type AdminUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          *string                `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AdminUserRequest) Reset() {
+	*x = AdminUserRequest{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdminUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminUserRequest) ProtoMessage() {}
+
+func (x *AdminUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminUserRequest.ProtoReflect.Descriptor instead.
+func (*AdminUserRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *AdminUserRequest) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+type AdminDisableUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          *string                `protobuf:"bytes,1,opt,name=name" json:"name,omitempty"`
+	Disabled      *bool                  `protobuf:"varint,2,opt,name=disabled" json:"disabled,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AdminDisableUserRequest) Reset() {
+	*x = AdminDisableUserRequest{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdminDisableUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminDisableUserRequest) ProtoMessage() {}
+
+func (x *AdminDisableUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminDisableUserRequest.ProtoReflect.Descriptor instead.
+func (*AdminDisableUserRequest) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *AdminDisableUserRequest) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *AdminDisableUserRequest) GetDisabled() bool {
+	if x != nil && x.Disabled != nil {
+		return *x.Disabled
+	}
+	return false
+}
+
+type AdminCertificate struct {
+	state  protoimpl.MessageState `protogen:"open.v1"`
+	Serial *string                `protobuf:"bytes,1,opt,name=serial" json:"serial,omitempty"`
+	UserId *int64                 `protobuf:"varint,2,opt,name=user_id,json=userId" json:"user_id,omitempty"`
+	Name   *string                `protobuf:"bytes,3,opt,name=name" json:"name,omitempty"`
+	// issued and not_after are unix times.
+	Issued        *int64 `protobuf:"varint,4,opt,name=issued" json:"issued,omitempty"`
+	NotAfter      *int64 `protobuf:"varint,5,opt,name=not_after,json=notAfter" json:"not_after,omitempty"`
+	Revoked       *bool  `protobuf:"varint,6,opt,name=revoked" json:"revoked,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AdminCertificate) Reset() {
+	*x = AdminCertificate{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdminCertificate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminCertificate) ProtoMessage() {}
+
+func (x *AdminCertificate) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminCertificate.ProtoReflect.Descriptor instead.
+func (*AdminCertificate) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *AdminCertificate) GetSerial() string {
+	if x != nil && x.Serial != nil {
+		return *x.Serial
+	}
+	return ""
+}
+
+func (x *AdminCertificate) GetUserId() int64 {
+	if x != nil && x.UserId != nil {
+		return *x.UserId
+	}
+	return 0
+}
+
+func (x *AdminCertificate) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *AdminCertificate) GetIssued() int64 {
+	if x != nil && x.Issued != nil {
+		return *x.Issued
+	}
+	return 0
+}
+
+func (x *AdminCertificate) GetNotAfter() int64 {
+	if x != nil && x.NotAfter != nil {
+		return *x.NotAfter
+	}
+	return 0
+}
+
+func (x *AdminCertificate) GetRevoked() bool {
+	if x != nil && x.Revoked != nil {
+		return *x.Revoked
+	}
+	return false
+}
+
+type AdminUser struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            *int64                 `protobuf:"varint,1,opt,name=id" json:"id,omitempty"`
+	Name          *string                `protobuf:"bytes,2,opt,name=name" json:"name,omitempty"`
+	Disabled      *bool                  `protobuf:"varint,3,opt,name=disabled" json:"disabled,omitempty"`
+	Devices       []*Device              `protobuf:"bytes,4,rep,name=devices" json:"devices,omitempty"`
+	Certificates  []*AdminCertificate    `protobuf:"bytes,5,rep,name=certificates" json:"certificates,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AdminUser) Reset() {
+	*x = AdminUser{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdminUser) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminUser) ProtoMessage() {}
+
+func (x *AdminUser) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminUser.ProtoReflect.Descriptor instead.
+func (*AdminUser) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *AdminUser) GetId() int64 {
+	if x != nil && x.Id != nil {
+		return *x.Id
+	}
+	return 0
+}
+
+func (x *AdminUser) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *AdminUser) GetDisabled() bool {
+	if x != nil && x.Disabled != nil {
+		return *x.Disabled
+	}
+	return false
+}
+
+func (x *AdminUser) GetDevices() []*Device {
+	if x != nil {
+		return x.Devices
+	}
+	return nil
+}
+
+func (x *AdminUser) GetCertificates() []*AdminCertificate {
+	if x != nil {
+		return x.Certificates
+	}
+	return nil
+}
+
+type AdminListUsersResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Users         []*AdminUser           `protobuf:"bytes,1,rep,name=users" json:"users,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AdminListUsersResponse) Reset() {
+	*x = AdminListUsersResponse{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdminListUsersResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminListUsersResponse) ProtoMessage() {}
+
+func (x *AdminListUsersResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminListUsersResponse.ProtoReflect.Descriptor instead.
+func (*AdminListUsersResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *AdminListUsersResponse) GetUsers() []*AdminUser {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+type AdminRecordStats struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Type          *RecordType            `protobuf:"varint,1,opt,name=type,enum=gophkeeper.RecordType" json:"type,omitempty"`
+	Count         *int64                 `protobuf:"varint,2,opt,name=count" json:"count,omitempty"`
+	Bytes         *int64                 `protobuf:"varint,3,opt,name=bytes" json:"bytes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AdminRecordStats) Reset() {
+	*x = AdminRecordStats{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdminRecordStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminRecordStats) ProtoMessage() {}
+
+func (x *AdminRecordStats) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminRecordStats.ProtoReflect.Descriptor instead.
+func (*AdminRecordStats) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *AdminRecordStats) GetType() RecordType {
+	if x != nil && x.Type != nil {
+		return *x.Type
+	}
+	return RecordType_UNKNOWN
+}
+
+func (x *AdminRecordStats) GetCount() int64 {
+	if x != nil && x.Count != nil {
+		return *x.Count
+	}
+	return 0
+}
+
+func (x *AdminRecordStats) GetBytes() int64 {
+	if x != nil && x.Bytes != nil {
+		return *x.Bytes
+	}
+	return 0
+}
+
+type AdminUserStats struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            *int64                 `protobuf:"varint,1,opt,name=id" json:"id,omitempty"`
+	Name          *string                `protobuf:"bytes,2,opt,name=name" json:"name,omitempty"`
+	Records       []*AdminRecordStats    `protobuf:"bytes,3,rep,name=records" json:"records,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AdminUserStats) Reset() {
+	*x = AdminUserStats{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdminUserStats) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminUserStats) ProtoMessage() {}
+
+func (x *AdminUserStats) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminUserStats.ProtoReflect.Descriptor instead.
+func (*AdminUserStats) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *AdminUserStats) GetId() int64 {
+	if x != nil && x.Id != nil {
+		return *x.Id
+	}
+	return 0
+}
+
+func (x *AdminUserStats) GetName() string {
+	if x != nil && x.Name != nil {
+		return *x.Name
+	}
+	return ""
+}
+
+func (x *AdminUserStats) GetRecords() []*AdminRecordStats {
+	if x != nil {
+		return x.Records
+	}
+	return nil
+}
+
+type AdminStatsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Users         []*AdminUserStats      `protobuf:"bytes,1,rep,name=users" json:"users,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AdminStatsResponse) Reset() {
+	*x = AdminStatsResponse{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdminStatsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminStatsResponse) ProtoMessage() {}
+
+func (x *AdminStatsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminStatsResponse.ProtoReflect.Descriptor instead.
+func (*AdminStatsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *AdminStatsResponse) GetUsers() []*AdminUserStats {
+	if x != nil {
+		return x.Users
+	}
+	return nil
+}
+
+type AdminListCertificatesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Certificates  []*AdminCertificate    `protobuf:"bytes,1,rep,name=certificates" json:"certificates,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AdminListCertificatesResponse) Reset() {
+	*x = AdminListCertificatesResponse{}
+	mi := &file_proto_gophkeeper_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdminListCertificatesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminListCertificatesResponse) ProtoMessage() {}
+
+func (x *AdminListCertificatesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_gophkeeper_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminListCertificatesResponse.ProtoReflect.Descriptor instead.
+func (*AdminListCertificatesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_gophkeeper_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *AdminListCertificatesResponse) GetCertificates() []*AdminCertificate {
+	if x != nil {
+		return x.Certificates
+	}
+	return nil
+}
+
+var File_proto_gophkeeper_proto protoreflect.FileDescriptor
+
+const file_proto_gophkeeper_proto_rawDesc = "" +
+	"\n" +
+	"\x16proto/gophkeeper.proto\x12\n" +
+	"gophkeeper\x1a\x1bgoogle/protobuf/empty.proto\"L\n" +
+	"\x0fRegisterRequest\x12!\n" +
+	"\fcert_request\x18\x01 \x01(\fR\vcertRequest\x12\x16\n" +
+	"\x06invite\x18\x02 \x01(\tR\x06invite\"\x91\x01\n" +
+	"\x10RegisterResponse\x12%\n" +
+	"\x0eca_certificate\x18\x02 \x01(\fR\rcaCertificate\x12-\n" +
+	"\x12client_certificate\x18\x03 \x01(\fR\x11clientCertificate\x12!\n" +
+	"\fpending_code\x18\x04 \x01(\tR\vpendingCodeJ\x04\b\x01\x10\x02\"+\n" +
+	"\x15RegisterStatusRequest\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\"F\n" +
+	"\rEnrollRequest\x12\x12\n" +
+	"\x04user\x18\x01 \x01(\tR\x04user\x12!\n" +
+	"\fcert_request\x18\x02 \x01(\fR\vcertRequest\"$\n" +
+	"\x0eEnrollResponse\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\")\n" +
+	"\x13EnrollStatusRequest\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\"\xa5\x01\n" +
+	"\x14EnrollStatusResponse\x12\x1a\n" +
+	"\bapproved\x18\x01 \x01(\bR\bapproved\x12%\n" +
+	"\x0eca_certificate\x18\x02 \x01(\fR\rcaCertificate\x12-\n" +
+	"\x12client_certificate\x18\x03 \x01(\fR\x11clientCertificate\x12\x1b\n" +
+	"\tvault_key\x18\x04 \x01(\fR\bvaultKey\"`\n" +
+	"\x06Device\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x18\n" +
+	"\acreated\x18\x03 \x01(\x03R\acreated\x12\x18\n" +
+	"\acurrent\x18\x04 \x01(\bR\acurrent\"p\n" +
+	"\rPendingDevice\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1d\n" +
+	"\n" +
+	"public_key\x18\x03 \x01(\fR\tpublicKey\x12\x18\n" +
+	"\acreated\x18\x04 \x01(\x03R\acreated\"x\n" +
+	"\x13ListDevicesResponse\x12,\n" +
+	"\adevices\x18\x01 \x03(\v2\x12.gophkeeper.DeviceR\adevices\x123\n" +
+	"\apending\x18\x02 \x03(\v2\x19.gophkeeper.PendingDeviceR\apending\"G\n" +
+	"\x14ApproveDeviceRequest\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x1b\n" +
+	"\tvault_key\x18\x02 \x01(\fR\bvaultKey\"%\n" +
+	"\x13RemoveDeviceRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\"\xb1\x01\n" +
+	"\x0ePasswordRecord\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x14\n" +
+	"\x05login\x18\x02 \x01(\fR\x05login\x12\x1a\n" +
+	"\bpassword\x18\x03 \x01(\fR\bpassword\x12\x12\n" +
+	"\x04meta\x18\x04 \x01(\fR\x04meta\x12\x12\n" +
+	"\x04uuid\x18\x05 \x01(\tR\x04uuid\x12\x19\n" +
+	"\bdata_key\x18\x06 \x01(\fR\adataKey\x12\x1a\n" +
+	"\brevision\x18\a \x01(\x03R\brevision\"\x8f\x01\n" +
+	"\n" +
+	"TextRecord\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04text\x18\x02 \x01(\fR\x04text\x12\x12\n" +
+	"\x04meta\x18\x03 \x01(\fR\x04meta\x12\x12\n" +
+	"\x04uuid\x18\x04 \x01(\tR\x04uuid\x12\x19\n" +
+	"\bdata_key\x18\x05 \x01(\fR\adataKey\x12\x1a\n" +
+	"\brevision\x18\x06 \x01(\x03R\brevision\"\x8e\x01\n" +
+	"\tBinRecord\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04data\x18\x02 \x01(\fR\x04data\x12\x12\n" +
+	"\x04meta\x18\x03 \x01(\fR\x04meta\x12\x12\n" +
+	"\x04uuid\x18\x04 \x01(\tR\x04uuid\x12\x19\n" +
+	"\bdata_key\x18\x05 \x01(\fR\adataKey\x12\x1a\n" +
+	"\brevision\x18\x06 \x01(\x03R\brevision\"\xcd\x01\n" +
+	"\n" +
+	"BankRecord\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x16\n" +
+	"\x06number\x18\x02 \x01(\fR\x06number\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\fR\x04name\x12\x12\n" +
+	"\x04date\x18\x04 \x01(\fR\x04date\x12\x10\n" +
+	"\x03cvv\x18\x05 \x01(\fR\x03cvv\x12\x12\n" +
+	"\x04meta\x18\x06 \x01(\fR\x04meta\x12\x12\n" +
+	"\x04uuid\x18\a \x01(\tR\x04uuid\x12\x19\n" +
+	"\bdata_key\x18\b \x01(\fR\adataKey\x12\x1a\n" +
+	"\brevision\x18\t \x01(\x03R\brevision\"\xd3\x01\n" +
+	"\x06Record\x128\n" +
+	"\bpassword\x18\x01 \x01(\v2\x1a.gophkeeper.PasswordRecordH\x00R\bpassword\x12,\n" +
+	"\x04text\x18\x02 \x01(\v2\x16.gophkeeper.TextRecordH\x00R\x04text\x12)\n" +
+	"\x03bin\x18\x03 \x01(\v2\x15.gophkeeper.BinRecordH\x00R\x03bin\x12,\n" +
+	"\x04bank\x18\x04 \x01(\v2\x16.gophkeeper.BankRecordH\x00R\x04bankB\b\n" +
+	"\x06record\"9\n" +
+	"\vListRequest\x12*\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x16.gophkeeper.RecordTypeR\x04type\"V\n" +
+	"\fListResponse\x12\x1c\n" +
+	"\arecords\x18\x01 \x01(\fB\x02\x18\x01R\arecords\x12(\n" +
+	"\x05items\x18\x02 \x03(\v2\x12.gophkeeper.RecordR\x05items\"\x82\x01\n" +
+	"\x10AddRecordRequest\x12*\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x16.gophkeeper.RecordTypeR\x04type\x12\x1a\n" +
+	"\x06record\x18\x02 \x01(\fB\x02\x18\x01R\x06record\x12&\n" +
+	"\x04item\x18\x03 \x01(\v2\x12.gophkeeper.RecordR\x04item\"c\n" +
+	"\x10GetRecordRequest\x12*\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x16.gophkeeper.RecordTypeR\x04type\x12#\n" +
+	"\rrecord_number\x18\x02 \x01(\x03R\frecordNumber\"\x99\x01\n" +
+	"\x11GetRecordResponse\x12*\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x16.gophkeeper.RecordTypeR\x04type\x12\x1a\n" +
+	"\x06record\x18\x02 \x01(\fB\x02\x18\x01R\x06record\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\x12&\n" +
+	"\x04item\x18\x04 \x01(\v2\x12.gophkeeper.RecordR\x04item\"\xd7\x01\n" +
+	"\x13UpdateRecordRequest\x12*\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x16.gophkeeper.RecordTypeR\x04type\x12#\n" +
+	"\rrecord_number\x18\x02 \x01(\x03R\frecordNumber\x12\x1a\n" +
+	"\x06record\x18\x03 \x01(\fB\x02\x18\x01R\x06record\x12&\n" +
+	"\x04item\x18\x04 \x01(\v2\x12.gophkeeper.RecordR\x04item\x12+\n" +
+	"\x11expected_revision\x18\x05 \x01(\x03R\x10expectedRevision\"\x93\x01\n" +
+	"\x13DeleteRecordRequest\x12*\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x16.gophkeeper.RecordTypeR\x04type\x12#\n" +
+	"\rrecord_number\x18\x02 \x01(\x03R\frecordNumber\x12+\n" +
+	"\x11expected_revision\x18\x03 \x01(\x03R\x10expectedRevision\"f\n" +
+	"\x13ListVersionsRequest\x12*\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x16.gophkeeper.RecordTypeR\x04type\x12#\n" +
+	"\rrecord_number\x18\x02 \x01(\x03R\frecordNumber\"e\n" +
+	"\aVersion\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\x03R\aversion\x12\x18\n" +
+	"\acreated\x18\x02 \x01(\x03R\acreated\x12&\n" +
+	"\x04item\x18\x03 \x01(\v2\x12.gophkeeper.RecordR\x04item\"G\n" +
+	"\x14ListVersionsResponse\x12/\n" +
+	"\bversions\x18\x01 \x03(\v2\x13.gophkeeper.VersionR\bversions\"~\n" +
+	"\x11GetVersionRequest\x12*\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x16.gophkeeper.RecordTypeR\x04type\x12#\n" +
+	"\rrecord_number\x18\x02 \x01(\x03R\frecordNumber\x12\x18\n" +
+	"\aversion\x18\x03 \x01(\x03R\aversion\"y\n" +
+	"\tTrashItem\x12*\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x16.gophkeeper.RecordTypeR\x04type\x12\x18\n" +
+	"\adeleted\x18\x02 \x01(\x03R\adeleted\x12&\n" +
+	"\x04item\x18\x03 \x01(\v2\x12.gophkeeper.RecordR\x04item\"@\n" +
+	"\x11ListTrashResponse\x12+\n" +
+	"\x05items\x18\x01 \x03(\v2\x15.gophkeeper.TrashItemR\x05items\"f\n" +
+	"\x13RestoreTrashRequest\x12*\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x16.gophkeeper.RecordTypeR\x04type\x12#\n" +
+	"\rrecord_number\x18\x02 \x01(\x03R\frecordNumber\"7\n" +
+	"\x0eChangesRequest\x12%\n" +
+	"\x0esince_revision\x18\x01 \x01(\x03R\rsinceRevision\"\xc3\x01\n" +
+	"\x06Change\x12\x1a\n" +
+	"\brevision\x18\x01 \x01(\x03R\brevision\x12$\n" +
+	"\x02op\x18\x02 \x01(\x0e2\x14.gophkeeper.ChangeOpR\x02op\x12*\n" +
+	"\x04type\x18\x03 \x01(\x0e2\x16.gophkeeper.RecordTypeR\x04type\x12#\n" +
+	"\rrecord_number\x18\x04 \x01(\x03R\frecordNumber\x12&\n" +
+	"\x04item\x18\x05 \x01(\v2\x12.gophkeeper.RecordR\x04item\"?\n" +
+	"\x0fChangesResponse\x12,\n" +
+	"\achanges\x18\x01 \x03(\v2\x12.gophkeeper.ChangeR\achanges\"5\n" +
+	"\fWatchRequest\x12%\n" +
+	"\x0esince_revision\x18\x01 \x01(\x03R\rsinceRevision\"\xe4\x01\n" +
+	"\x0fUploadBinHeader\x12\x1b\n" +
+	"\tupload_id\x18\x01 \x01(\tR\buploadId\x12#\n" +
+	"\rrecord_number\x18\x02 \x01(\x03R\frecordNumber\x12\x12\n" +
+	"\x04meta\x18\x03 \x01(\fR\x04meta\x12\x1f\n" +
+	"\vfirst_chunk\x18\x04 \x01(\x03R\n" +
+	"firstChunk\x12\x12\n" +
+	"\x04uuid\x18\x05 \x01(\tR\x04uuid\x12\x19\n" +
+	"\bdata_key\x18\x06 \x01(\fR\adataKey\x12+\n" +
+	"\x11expected_revision\x18\a \x01(\x03R\x10expectedRevision\"l\n" +
+	"\x10UploadBinRequest\x125\n" +
+	"\x06header\x18\x01 \x01(\v2\x1b.gophkeeper.UploadBinHeaderH\x00R\x06header\x12\x16\n" +
+	"\x05chunk\x18\x02 \x01(\fH\x00R\x05chunkB\t\n" +
+	"\apayload\"P\n" +
+	"\x11UploadBinResponse\x12#\n" +
+	"\rrecord_number\x18\x01 \x01(\x03R\frecordNumber\x12\x16\n" +
+	"\x06chunks\x18\x02 \x01(\x03R\x06chunks\"5\n" +
+	"\x16UploadBinStatusRequest\x12\x1b\n" +
+	"\tupload_id\x18\x01 \x01(\tR\buploadId\"`\n" +
+	"\x17UploadBinStatusResponse\x12\x16\n" +
+	"\x06chunks\x18\x01 \x01(\x03R\x06chunks\x12\x12\n" +
+	"\x04uuid\x18\x02 \x01(\tR\x04uuid\x12\x19\n" +
+	"\bdata_key\x18\x03 \x01(\fR\adataKey\"Z\n" +
+	"\x12DownloadBinRequest\x12#\n" +
+	"\rrecord_number\x18\x01 \x01(\x03R\frecordNumber\x12\x1f\n" +
+	"\vfirst_chunk\x18\x02 \x01(\x03R\n" +
+	"firstChunk\"U\n" +
+	"\x13DownloadBinResponse\x12\x10\n" +
+	"\x03seq\x18\x01 \x01(\x03R\x03seq\x12\x14\n" +
+	"\x05chunk\x18\x02 \x01(\fR\x05chunk\x12\x16\n" +
+	"\x06chunks\x18\x03 \x01(\x03R\x06chunks\"1\n" +
+	"\fRenewRequest\x12!\n" +
+	"\fcert_request\x18\x01 \x01(\fR\vcertRequest\"e\n" +
+	"\rRenewResponse\x12%\n" +
+	"\x0eca_certificate\x18\x01 \x01(\fR\rcaCertificate\x12-\n" +
+	"\x12client_certificate\x18\x02 \x01(\fR\x11clientCertificate\"&\n" +
+	"\x10AdminUserRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\"I\n" +
+	"\x17AdminDisableUserRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1a\n" +
+	"\bdisabled\x18\x02 \x01(\bR\bdisabled\"\xa6\x01\n" +
+	"\x10AdminCertificate\x12\x16\n" +
+	"\x06serial\x18\x01 \x01(\tR\x06serial\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\x03R\x06userId\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x16\n" +
+	"\x06issued\x18\x04 \x01(\x03R\x06issued\x12\x1b\n" +
+	"\tnot_after\x18\x05 \x01(\x03R\bnotAfter\x12\x18\n" +
+	"\arevoked\x18\x06 \x01(\bR\arevoked\"\xbb\x01\n" +
+	"\tAdminUser\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1a\n" +
+	"\bdisabled\x18\x03 \x01(\bR\bdisabled\x12,\n" +
+	"\adevices\x18\x04 \x03(\v2\x12.gophkeeper.DeviceR\adevices\x12@\n" +
+	"\fcertificates\x18\x05 \x03(\v2\x1c.gophkeeper.AdminCertificateR\fcertificates\"E\n" +
+	"\x16AdminListUsersResponse\x12+\n" +
+	"\x05users\x18\x01 \x03(\v2\x15.gophkeeper.AdminUserR\x05users\"j\n" +
+	"\x10AdminRecordStats\x12*\n" +
+	"\x04type\x18\x01 \x01(\x0e2\x16.gophkeeper.RecordTypeR\x04type\x12\x14\n" +
+	"\x05count\x18\x02 \x01(\x03R\x05count\x12\x14\n" +
+	"\x05bytes\x18\x03 \x01(\x03R\x05bytes\"l\n" +
+	"\x0eAdminUserStats\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x03R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x126\n" +
+	"\arecords\x18\x03 \x03(\v2\x1c.gophkeeper.AdminRecordStatsR\arecords\"F\n" +
+	"\x12AdminStatsResponse\x120\n" +
+	"\x05users\x18\x01 \x03(\v2\x1a.gophkeeper.AdminUserStatsR\x05users\"a\n" +
+	"\x1dAdminListCertificatesResponse\x12@\n" +
+	"\fcertificates\x18\x01 \x03(\v2\x1c.gophkeeper.AdminCertificateR\fcertificates*D\n" +
+	"\n" +
+	"RecordType\x12\v\n" +
+	"\aUNKNOWN\x10\x00\x12\f\n" +
+	"\bPASSWORD\x10\x01\x12\b\n" +
+	"\x04TEXT\x10\x02\x12\a\n" +
+	"\x03BIN\x10\x03\x12\b\n" +
+	"\x04BANK\x10\x04*W\n" +
+	"\bChangeOp\x12\x12\n" +
+	"\x0eCHANGE_UNKNOWN\x10\x00\x12\x11\n" +
+	"\rCHANGE_CREATE\x10\x01\x12\x11\n" +
+	"\rCHANGE_UPDATE\x10\x02\x12\x11\n" +
+	"\rCHANGE_DELETE\x10\x032\xb6\x02\n" +
+	"\x06Public\x12E\n" +
+	"\bRegister\x12\x1b.gophkeeper.RegisterRequest\x1a\x1c.gophkeeper.RegisterResponse\x12Q\n" +
+	"\x0eRegisterStatus\x12!.gophkeeper.RegisterStatusRequest\x1a\x1c.gophkeeper.RegisterResponse\x12?\n" +
+	"\x06Enroll\x12\x19.gophkeeper.EnrollRequest\x1a\x1a.gophkeeper.EnrollResponse\x12Q\n" +
+	"\fEnrollStatus\x12\x1f.gophkeeper.EnrollStatusRequest\x1a .gophkeeper.EnrollStatusResponse2\xfd\n" +
+	"\n" +
+	"\aPrivate\x12;\n" +
+	"\aListAll\x12\x16.google.protobuf.Empty\x1a\x18.gophkeeper.ListResponse\x129\n" +
+	"\x04List\x12\x17.gophkeeper.ListRequest\x1a\x18.gophkeeper.ListResponse\x12>\n" +
+	"\x06Create\x12\x1c.gophkeeper.AddRecordRequest\x1a\x16.google.protobuf.Empty\x12C\n" +
+	"\x04Read\x12\x1c.gophkeeper.GetRecordRequest\x1a\x1d.gophkeeper.GetRecordResponse\x12A\n" +
+	"\x06Update\x12\x1f.gophkeeper.UpdateRecordRequest\x1a\x16.google.protobuf.Empty\x12A\n" +
+	"\x06Delete\x12\x1f.gophkeeper.DeleteRecordRequest\x1a\x16.google.protobuf.Empty\x12Q\n" +
+	"\fListVersions\x12\x1f.gophkeeper.ListVersionsRequest\x1a .gophkeeper.ListVersionsResponse\x12@\n" +
+	"\n" +
+	"GetVersion\x12\x1d.gophkeeper.GetVersionRequest\x1a\x13.gophkeeper.Version\x12B\n" +
+	"\tListTrash\x12\x16.google.protobuf.Empty\x1a\x1d.gophkeeper.ListTrashResponse\x12G\n" +
+	"\fRestoreTrash\x12\x1f.gophkeeper.RestoreTrashRequest\x1a\x16.google.protobuf.Empty\x12<\n" +
+	"\n" +
+	"EmptyTrash\x12\x16.google.protobuf.Empty\x1a\x16.google.protobuf.Empty\x12B\n" +
+	"\aChanges\x12\x1a.gophkeeper.ChangesRequest\x1a\x1b.gophkeeper.ChangesResponse\x127\n" +
+	"\x05Watch\x12\x18.gophkeeper.WatchRequest\x1a\x12.gophkeeper.Change0\x01\x12J\n" +
+	"\tUploadBin\x12\x1c.gophkeeper.UploadBinRequest\x1a\x1d.gophkeeper.UploadBinResponse(\x01\x12Z\n" +
+	"\x0fUploadBinStatus\x12\".gophkeeper.UploadBinStatusRequest\x1a#.gophkeeper.UploadBinStatusResponse\x12P\n" +
+	"\vDownloadBin\x12\x1e.gophkeeper.DownloadBinRequest\x1a\x1f.gophkeeper.DownloadBinResponse0\x01\x12F\n" +
+	"\vListDevices\x12\x16.google.protobuf.Empty\x1a\x1f.gophkeeper.ListDevicesResponse\x12I\n" +
+	"\rApproveDevice\x12 .gophkeeper.ApproveDeviceRequest\x1a\x16.google.protobuf.Empty\x12G\n" +
+	"\fRemoveDevice\x12\x1f.gophkeeper.RemoveDeviceRequest\x1a\x16.google.protobuf.Empty\x12<\n" +
+	"\x05Renew\x12\x18.gophkeeper.RenewRequest\x1a\x19.gophkeeper.RenewResponse2\xb8\x03\n" +
+	"\x05Admin\x12G\n" +
+	"\tListUsers\x12\x16.google.protobuf.Empty\x1a\".gophkeeper.AdminListUsersResponse\x12>\n" +
+	"\aGetUser\x12\x1c.gophkeeper.AdminUserRequest\x1a\x15.gophkeeper.AdminUser\x12B\n" +
+	"\n" +
+	"DeleteUser\x12\x1c.gophkeeper.AdminUserRequest\x1a\x16.google.protobuf.Empty\x12J\n" +
+	"\vDisableUser\x12#.gophkeeper.AdminDisableUserRequest\x1a\x16.google.protobuf.Empty\x12?\n" +
+	"\x05Stats\x12\x16.google.protobuf.Empty\x1a\x1e.gophkeeper.AdminStatsResponse\x12U\n" +
+	"\x10ListCertificates\x12\x16.google.protobuf.Empty\x1a).gophkeeper.AdminListCertificatesResponseB\x12Z\x10gophkeeper/protob\beditionsp\xe8\a"
+
+var (
+	file_proto_gophkeeper_proto_rawDescOnce sync.Once
+	file_proto_gophkeeper_proto_rawDescData []byte
+)
+
+func file_proto_gophkeeper_proto_rawDescGZIP() []byte {
+	file_proto_gophkeeper_proto_rawDescOnce.Do(func() {
+		file_proto_gophkeeper_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_gophkeeper_proto_rawDesc), len(file_proto_gophkeeper_proto_rawDesc)))
+	})
+	return file_proto_gophkeeper_proto_rawDescData
+}
+
+var file_proto_gophkeeper_proto_enumTypes = make([]protoimpl.EnumInfo, 2)
+var file_proto_gophkeeper_proto_msgTypes = make([]protoimpl.MessageInfo, 53)
+var file_proto_gophkeeper_proto_goTypes = []any{
+	(RecordType)(0),                       // 0: gophkeeper.RecordType
+	(ChangeOp)(0),                         // 1: gophkeeper.ChangeOp
+	(*RegisterRequest)(nil),               // 2: gophkeeper.RegisterRequest
+	(*RegisterResponse)(nil),              // 3: gophkeeper.RegisterResponse
+	(*RegisterStatusRequest)(nil),         // 4: gophkeeper.RegisterStatusRequest
+	(*EnrollRequest)(nil),                 // 5: gophkeeper.EnrollRequest
+	(*EnrollResponse)(nil),                // 6: gophkeeper.EnrollResponse
+	(*EnrollStatusRequest)(nil),           // 7: gophkeeper.EnrollStatusRequest
+	(*EnrollStatusResponse)(nil),          // 8: gophkeeper.EnrollStatusResponse
+	(*Device)(nil),                        // 9: gophkeeper.Device
+	(*PendingDevice)(nil),                 // 10: gophkeeper.PendingDevice
+	(*ListDevicesResponse)(nil),           // 11: gophkeeper.ListDevicesResponse
+	(*ApproveDeviceRequest)(nil),          // 12: gophkeeper.ApproveDeviceRequest
+	(*RemoveDeviceRequest)(nil),           // 13: gophkeeper.RemoveDeviceRequest
+	(*PasswordRecord)(nil),                // 14: gophkeeper.PasswordRecord
+	(*TextRecord)(nil),                    // 15: gophkeeper.TextRecord
+	(*BinRecord)(nil),                     // 16: gophkeeper.BinRecord
+	(*BankRecord)(nil),                    // 17: gophkeeper.BankRecord
+	(*Record)(nil),                        // 18: gophkeeper.Record
+	(*ListRequest)(nil),                   // 19: gophkeeper.ListRequest
+	(*ListResponse)(nil),                  // 20: gophkeeper.ListResponse
+	(*AddRecordRequest)(nil),              // 21: gophkeeper.AddRecordRequest
+	(*GetRecordRequest)(nil),              // 22: gophkeeper.GetRecordRequest
+	(*GetRecordResponse)(nil),             // 23: gophkeeper.GetRecordResponse
+	(*UpdateRecordRequest)(nil),           // 24: gophkeeper.UpdateRecordRequest
+	(*DeleteRecordRequest)(nil),           // 25: gophkeeper.DeleteRecordRequest
+	(*ListVersionsRequest)(nil),           // 26: gophkeeper.ListVersionsRequest
+	(*Version)(nil),                       // 27: gophkeeper.Version
+	(*ListVersionsResponse)(nil),          // 28: gophkeeper.ListVersionsResponse
+	(*GetVersionRequest)(nil),             // 29: gophkeeper.GetVersionRequest
+	(*TrashItem)(nil),                     // 30: gophkeeper.TrashItem
+	(*ListTrashResponse)(nil),             // 31: gophkeeper.ListTrashResponse
+	(*RestoreTrashRequest)(nil),           // 32: gophkeeper.RestoreTrashRequest
+	(*ChangesRequest)(nil),                // 33: gophkeeper.ChangesRequest
+	(*Change)(nil),                        // 34: gophkeeper.Change
+	(*ChangesResponse)(nil),               // 35: gophkeeper.ChangesResponse
+	(*WatchRequest)(nil),                  // 36: gophkeeper.WatchRequest
+	(*UploadBinHeader)(nil),               // 37: gophkeeper.UploadBinHeader
+	(*UploadBinRequest)(nil),              // 38: gophkeeper.UploadBinRequest
+	(*UploadBinResponse)(nil),             // 39: gophkeeper.UploadBinResponse
+	(*UploadBinStatusRequest)(nil),        // 40: gophkeeper.UploadBinStatusRequest
+	(*UploadBinStatusResponse)(nil),       // 41: gophkeeper.UploadBinStatusResponse
+	(*DownloadBinRequest)(nil),            // 42: gophkeeper.DownloadBinRequest
+	(*DownloadBinResponse)(nil),           // 43: gophkeeper.DownloadBinResponse
+	(*RenewRequest)(nil),                  // 44: gophkeeper.RenewRequest
+	(*RenewResponse)(nil),                 // 45: gophkeeper.RenewResponse
+	(*AdminUserRequest)(nil),              // 46: gophkeeper.AdminUserRequest
+	(*AdminDisableUserRequest)(nil),       // 47: gophkeeper.AdminDisableUserRequest
+	(*AdminCertificate)(nil),              // 48: gophkeeper.AdminCertificate
+	(*AdminUser)(nil),                     // 49: gophkeeper.AdminUser
+	(*AdminListUsersResponse)(nil),        // 50: gophkeeper.AdminListUsersResponse
+	(*AdminRecordStats)(nil),              // 51: gophkeeper.AdminRecordStats
+	(*AdminUserStats)(nil),                // 52: gophkeeper.AdminUserStats
+	(*AdminStatsResponse)(nil),            // 53: gophkeeper.AdminStatsResponse
+	(*AdminListCertificatesResponse)(nil), // 54: gophkeeper.AdminListCertificatesResponse
+	(*emptypb.Empty)(nil),                 // 55: google.protobuf.Empty
+}
+var file_proto_gophkeeper_proto_depIdxs = []int32{
+	9,  // 0: gophkeeper.ListDevicesResponse.devices:type_name -> gophkeeper.Device
+	10, // 1: gophkeeper.ListDevicesResponse.pending:type_name -> gophkeeper.PendingDevice
+	14, // 2: gophkeeper.Record.password:type_name -> gophkeeper.PasswordRecord
+	15, // 3: gophkeeper.Record.text:type_name -> gophkeeper.TextRecord
+	16, // 4: gophkeeper.Record.bin:type_name -> gophkeeper.BinRecord
+	17, // 5: gophkeeper.Record.bank:type_name -> gophkeeper.BankRecord
+	0,  // 6: gophkeeper.ListRequest.type:type_name -> gophkeeper.RecordType
+	18, // 7: gophkeeper.ListResponse.items:type_name -> gophkeeper.Record
+	0,  // 8: gophkeeper.AddRecordRequest.type:type_name -> gophkeeper.RecordType
+	18, // 9: gophkeeper.AddRecordRequest.item:type_name -> gophkeeper.Record
+	0,  // 10: gophkeeper.GetRecordRequest.type:type_name -> gophkeeper.RecordType
+	0,  // 11: gophkeeper.GetRecordResponse.type:type_name -> gophkeeper.RecordType
+	18, // 12: gophkeeper.GetRecordResponse.item:type_name -> gophkeeper.Record
+	0,  // 13: gophkeeper.UpdateRecordRequest.type:type_name -> gophkeeper.RecordType
+	18, // 14: gophkeeper.UpdateRecordRequest.item:type_name -> gophkeeper.Record
+	0,  // 15: gophkeeper.DeleteRecordRequest.type:type_name -> gophkeeper.RecordType
+	0,  // 16: gophkeeper.ListVersionsRequest.type:type_name -> gophkeeper.RecordType
+	18, // 17: gophkeeper.Version.item:type_name -> gophkeeper.Record
+	27, // 18: gophkeeper.ListVersionsResponse.versions:type_name -> gophkeeper.Version
+	0,  // 19: gophkeeper.GetVersionRequest.type:type_name -> gophkeeper.RecordType
+	0,  // 20: gophkeeper.TrashItem.type:type_name -> gophkeeper.RecordType
+	18, // 21: gophkeeper.TrashItem.item:type_name -> gophkeeper.Record
+	30, // 22: gophkeeper.ListTrashResponse.items:type_name -> gophkeeper.TrashItem
+	0,  // 23: gophkeeper.RestoreTrashRequest.type:type_name -> gophkeeper.RecordType
+	1,  // 24: gophkeeper.Change.op:type_name -> gophkeeper.ChangeOp
+	0,  // 25: gophkeeper.Change.type:type_name -> gophkeeper.RecordType
+	18, // 26: gophkeeper.Change.item:type_name -> gophkeeper.Record
+	34, // 27: gophkeeper.ChangesResponse.changes:type_name -> gophkeeper.Change
+	37, // 28: gophkeeper.UploadBinRequest.header:type_name -> gophkeeper.UploadBinHeader
+	9,  // 29: gophkeeper.AdminUser.devices:type_name -> gophkeeper.Device
+	48, // 30: gophkeeper.AdminUser.certificates:type_name -> gophkeeper.AdminCertificate
+	49, // 31: gophkeeper.AdminListUsersResponse.users:type_name -> gophkeeper.AdminUser
+	0,  // 32: gophkeeper.AdminRecordStats.type:type_name -> gophkeeper.RecordType
+	51, // 33: gophkeeper.AdminUserStats.records:type_name -> gophkeeper.AdminRecordStats
+	52, // 34: gophkeeper.AdminStatsResponse.users:type_name -> gophkeeper.AdminUserStats
+	48, // 35: gophkeeper.AdminListCertificatesResponse.certificates:type_name -> gophkeeper.AdminCertificate
+	2,  // 36: gophkeeper.Public.Register:input_type -> gophkeeper.RegisterRequest
+	4,  // 37: gophkeeper.Public.RegisterStatus:input_type -> gophkeeper.RegisterStatusRequest
+	5,  // 38: gophkeeper.Public.Enroll:input_type -> gophkeeper.EnrollRequest
+	7,  // 39: gophkeeper.Public.EnrollStatus:input_type -> gophkeeper.EnrollStatusRequest
+	55, // 40: gophkeeper.Private.ListAll:input_type -> google.protobuf.Empty
+	19, // 41: gophkeeper.Private.List:input_type -> gophkeeper.ListRequest
+	21, // 42: gophkeeper.Private.Create:input_type -> gophkeeper.AddRecordRequest
+	22, // 43: gophkeeper.Private.Read:input_type -> gophkeeper.GetRecordRequest
+	24, // 44: gophkeeper.Private.Update:input_type -> gophkeeper.UpdateRecordRequest
+	25, // 45: gophkeeper.Private.Delete:input_type -> gophkeeper.DeleteRecordRequest
+	26, // 46: gophkeeper.Private.ListVersions:input_type -> gophkeeper.ListVersionsRequest
+	29, // 47: gophkeeper.Private.GetVersion:input_type -> gophkeeper.GetVersionRequest
+	55, // 48: gophkeeper.Private.ListTrash:input_type -> google.protobuf.Empty
+	32, // 49: gophkeeper.Private.RestoreTrash:input_type -> gophkeeper.RestoreTrashRequest
+	55, // 50: gophkeeper.Private.EmptyTrash:input_type -> google.protobuf.Empty
+	33, // 51: gophkeeper.Private.Changes:input_type -> gophkeeper.ChangesRequest
+	36, // 52: gophkeeper.Private.Watch:input_type -> gophkeeper.WatchRequest
+	38, // 53: gophkeeper.Private.UploadBin:input_type -> gophkeeper.UploadBinRequest
+	40, // 54: gophkeeper.Private.UploadBinStatus:input_type -> gophkeeper.UploadBinStatusRequest
+	42, // 55: gophkeeper.Private.DownloadBin:input_type -> gophkeeper.DownloadBinRequest
+	55, // 56: gophkeeper.Private.ListDevices:input_type -> google.protobuf.Empty
+	12, // 57: gophkeeper.Private.ApproveDevice:input_type -> gophkeeper.ApproveDeviceRequest
+	13, // 58: gophkeeper.Private.RemoveDevice:input_type -> gophkeeper.RemoveDeviceRequest
+	44, // 59: gophkeeper.Private.Renew:input_type -> gophkeeper.RenewRequest
+	55, // 60: gophkeeper.Admin.ListUsers:input_type -> google.protobuf.Empty
+	46, // 61: gophkeeper.Admin.GetUser:input_type -> gophkeeper.AdminUserRequest
+	46, // 62: gophkeeper.Admin.DeleteUser:input_type -> gophkeeper.AdminUserRequest
+	47, // 63: gophkeeper.Admin.DisableUser:input_type -> gophkeeper.AdminDisableUserRequest
+	55, // 64: gophkeeper.Admin.Stats:input_type -> google.protobuf.Empty
+	55, // 65: gophkeeper.Admin.ListCertificates:input_type -> google.protobuf.Empty
+	3,  // 66: gophkeeper.Public.Register:output_type -> gophkeeper.RegisterResponse
+	3,  // 67: gophkeeper.Public.RegisterStatus:output_type -> gophkeeper.RegisterResponse
+	6,  // 68: gophkeeper.Public.Enroll:output_type -> gophkeeper.EnrollResponse
+	8,  // 69: gophkeeper.Public.EnrollStatus:output_type -> gophkeeper.EnrollStatusResponse
+	20, // 70: gophkeeper.Private.ListAll:output_type -> gophkeeper.ListResponse
+	20, // 71: gophkeeper.Private.List:output_type -> gophkeeper.ListResponse
+	55, // 72: gophkeeper.Private.Create:output_type -> google.protobuf.Empty
+	23, // 73: gophkeeper.Private.Read:output_type -> gophkeeper.GetRecordResponse
+	55, // 74: gophkeeper.Private.Update:output_type -> google.protobuf.Empty
+	55, // 75: gophkeeper.Private.Delete:output_type -> google.protobuf.Empty
+	28, // 76: gophkeeper.Private.ListVersions:output_type -> gophkeeper.ListVersionsResponse
+	27, // 77: gophkeeper.Private.GetVersion:output_type -> gophkeeper.Version
+	31, // 78: gophkeeper.Private.ListTrash:output_type -> gophkeeper.ListTrashResponse
+	55, // 79: gophkeeper.Private.RestoreTrash:output_type -> google.protobuf.Empty
+	55, // 80: gophkeeper.Private.EmptyTrash:output_type -> google.protobuf.Empty
+	35, // 81: gophkeeper.Private.Changes:output_type -> gophkeeper.ChangesResponse
+	34, // 82: gophkeeper.Private.Watch:output_type -> gophkeeper.Change
+	39, // 83: gophkeeper.Private.UploadBin:output_type -> gophkeeper.UploadBinResponse
+	41, // 84: gophkeeper.Private.UploadBinStatus:output_type -> gophkeeper.UploadBinStatusResponse
+	43, // 85: gophkeeper.Private.DownloadBin:output_type -> gophkeeper.DownloadBinResponse
+	11, // 86: gophkeeper.Private.ListDevices:output_type -> gophkeeper.ListDevicesResponse
+	55, // 87: gophkeeper.Private.ApproveDevice:output_type -> google.protobuf.Empty
+	55, // 88: gophkeeper.Private.RemoveDevice:output_type -> google.protobuf.Empty
+	45, // 89: gophkeeper.Private.Renew:output_type -> gophkeeper.RenewResponse
+	50, // 90: gophkeeper.Admin.ListUsers:output_type -> gophkeeper.AdminListUsersResponse
+	49, // 91: gophkeeper.Admin.GetUser:output_type -> gophkeeper.AdminUser
+	55, // 92: gophkeeper.Admin.DeleteUser:output_type -> google.protobuf.Empty
+	55, // 93: gophkeeper.Admin.DisableUser:output_type -> google.protobuf.Empty
+	53, // 94: gophkeeper.Admin.Stats:output_type -> gophkeeper.AdminStatsResponse
+	54, // 95: gophkeeper.Admin.ListCertificates:output_type -> gophkeeper.AdminListCertificatesResponse
+	66, // [66:96] is the sub-list for method output_type
+	36, // [36:66] is the sub-list for method input_type
+	36, // [36:36] is the sub-list for extension type_name
+	36, // [36:36] is the sub-list for extension extendee
+	0,  // [0:36] is the sub-list for field type_name
+}
+
+func init() { file_proto_gophkeeper_proto_init() }
+func file_proto_gophkeeper_proto_init() {
+	if File_proto_gophkeeper_proto != nil {
+		return
+	}
+	file_proto_gophkeeper_proto_msgTypes[16].OneofWrappers = []any{
+		(*Record_Password)(nil),
+		(*Record_Text)(nil),
+		(*Record_Bin)(nil),
+		(*Record_Bank)(nil),
+	}
+	file_proto_gophkeeper_proto_msgTypes[36].OneofWrappers = []any{
+		(*UploadBinRequest_Header)(nil),
+		(*UploadBinRequest_Chunk)(nil),
 	}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_gophkeeper_proto_rawDesc), len(file_proto_gophkeeper_proto_rawDesc)),
-			NumEnums:      1,
-			NumMessages:   9,
+			NumEnums:      2,
+			NumMessages:   53,
 			NumExtensions: 0,
-			NumServices:   2,
+			NumServices:   3,
 		},
 		GoTypes:           file_proto_gophkeeper_proto_goTypes,
 		DependencyIndexes: file_proto_gophkeeper_proto_depIdxs,
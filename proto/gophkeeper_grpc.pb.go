@@ -20,7 +20,10 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	Public_Register_FullMethodName = "/gophkeeper.Public/Register"
+	Public_Register_FullMethodName       = "/gophkeeper.Public/Register"
+	Public_RegisterStatus_FullMethodName = "/gophkeeper.Public/RegisterStatus"
+	Public_Enroll_FullMethodName         = "/gophkeeper.Public/Enroll"
+	Public_EnrollStatus_FullMethodName   = "/gophkeeper.Public/EnrollStatus"
 )
 
 // PublicClient is the client API for Public service.
@@ -28,6 +31,9 @@ const (
 // For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
 type PublicClient interface {
 	Register(ctx context.Context, in *RegisterRequest, opts ...grpc.CallOption) (*RegisterResponse, error)
+	RegisterStatus(ctx context.Context, in *RegisterStatusRequest, opts ...grpc.CallOption) (*RegisterResponse, error)
+	Enroll(ctx context.Context, in *EnrollRequest, opts ...grpc.CallOption) (*EnrollResponse, error)
+	EnrollStatus(ctx context.Context, in *EnrollStatusRequest, opts ...grpc.CallOption) (*EnrollStatusResponse, error)
 }
 
 type publicClient struct {
@@ -48,11 +54,44 @@ func (c *publicClient) Register(ctx context.Context, in *RegisterRequest, opts .
 	return out, nil
 }
 
+func (c *publicClient) RegisterStatus(ctx context.Context, in *RegisterStatusRequest, opts ...grpc.CallOption) (*RegisterResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RegisterResponse)
+	err := c.cc.Invoke(ctx, Public_RegisterStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *publicClient) Enroll(ctx context.Context, in *EnrollRequest, opts ...grpc.CallOption) (*EnrollResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EnrollResponse)
+	err := c.cc.Invoke(ctx, Public_Enroll_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *publicClient) EnrollStatus(ctx context.Context, in *EnrollStatusRequest, opts ...grpc.CallOption) (*EnrollStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(EnrollStatusResponse)
+	err := c.cc.Invoke(ctx, Public_EnrollStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PublicServer is the server API for Public service.
 // All implementations must embed UnimplementedPublicServer
 // for forward compatibility.
 type PublicServer interface {
 	Register(context.Context, *RegisterRequest) (*RegisterResponse, error)
+	RegisterStatus(context.Context, *RegisterStatusRequest) (*RegisterResponse, error)
+	Enroll(context.Context, *EnrollRequest) (*EnrollResponse, error)
+	EnrollStatus(context.Context, *EnrollStatusRequest) (*EnrollStatusResponse, error)
 	mustEmbedUnimplementedPublicServer()
 }
 
@@ -66,6 +105,15 @@ type UnimplementedPublicServer struct{}
 func (UnimplementedPublicServer) Register(context.Context, *RegisterRequest) (*RegisterResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Register not implemented")
 }
+func (UnimplementedPublicServer) RegisterStatus(context.Context, *RegisterStatusRequest) (*RegisterResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RegisterStatus not implemented")
+}
+func (UnimplementedPublicServer) Enroll(context.Context, *EnrollRequest) (*EnrollResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Enroll not implemented")
+}
+func (UnimplementedPublicServer) EnrollStatus(context.Context, *EnrollStatusRequest) (*EnrollStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EnrollStatus not implemented")
+}
 func (UnimplementedPublicServer) mustEmbedUnimplementedPublicServer() {}
 func (UnimplementedPublicServer) testEmbeddedByValue()                {}
 
@@ -105,6 +153,60 @@ func _Public_Register_Handler(srv interface{}, ctx context.Context, dec func(int
 	return interceptor(ctx, in, info, handler)
 }
 
+func _Public_RegisterStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RegisterStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PublicServer).RegisterStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Public_RegisterStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PublicServer).RegisterStatus(ctx, req.(*RegisterStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Public_Enroll_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EnrollRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PublicServer).Enroll(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Public_Enroll_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PublicServer).Enroll(ctx, req.(*EnrollRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Public_EnrollStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(EnrollStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PublicServer).EnrollStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Public_EnrollStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PublicServer).EnrollStatus(ctx, req.(*EnrollStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // Public_ServiceDesc is the grpc.ServiceDesc for Public service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -116,18 +218,44 @@ var Public_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Register",
 			Handler:    _Public_Register_Handler,
 		},
+		{
+			MethodName: "RegisterStatus",
+			Handler:    _Public_RegisterStatus_Handler,
+		},
+		{
+			MethodName: "Enroll",
+			Handler:    _Public_Enroll_Handler,
+		},
+		{
+			MethodName: "EnrollStatus",
+			Handler:    _Public_EnrollStatus_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/gophkeeper.proto",
 }
 
 const (
-	Private_ListAll_FullMethodName = "/gophkeeper.Private/ListAll"
-	Private_List_FullMethodName    = "/gophkeeper.Private/List"
-	Private_Create_FullMethodName  = "/gophkeeper.Private/Create"
-	Private_Read_FullMethodName    = "/gophkeeper.Private/Read"
-	Private_Update_FullMethodName  = "/gophkeeper.Private/Update"
-	Private_Delete_FullMethodName  = "/gophkeeper.Private/Delete"
+	Private_ListAll_FullMethodName         = "/gophkeeper.Private/ListAll"
+	Private_List_FullMethodName            = "/gophkeeper.Private/List"
+	Private_Create_FullMethodName          = "/gophkeeper.Private/Create"
+	Private_Read_FullMethodName            = "/gophkeeper.Private/Read"
+	Private_Update_FullMethodName          = "/gophkeeper.Private/Update"
+	Private_Delete_FullMethodName          = "/gophkeeper.Private/Delete"
+	Private_ListVersions_FullMethodName    = "/gophkeeper.Private/ListVersions"
+	Private_GetVersion_FullMethodName      = "/gophkeeper.Private/GetVersion"
+	Private_ListTrash_FullMethodName       = "/gophkeeper.Private/ListTrash"
+	Private_RestoreTrash_FullMethodName    = "/gophkeeper.Private/RestoreTrash"
+	Private_EmptyTrash_FullMethodName      = "/gophkeeper.Private/EmptyTrash"
+	Private_Changes_FullMethodName         = "/gophkeeper.Private/Changes"
+	Private_Watch_FullMethodName           = "/gophkeeper.Private/Watch"
+	Private_UploadBin_FullMethodName       = "/gophkeeper.Private/UploadBin"
+	Private_UploadBinStatus_FullMethodName = "/gophkeeper.Private/UploadBinStatus"
+	Private_DownloadBin_FullMethodName     = "/gophkeeper.Private/DownloadBin"
+	Private_ListDevices_FullMethodName     = "/gophkeeper.Private/ListDevices"
+	Private_ApproveDevice_FullMethodName   = "/gophkeeper.Private/ApproveDevice"
+	Private_RemoveDevice_FullMethodName    = "/gophkeeper.Private/RemoveDevice"
+	Private_Renew_FullMethodName           = "/gophkeeper.Private/Renew"
 )
 
 // PrivateClient is the client API for Private service.
@@ -140,6 +268,21 @@ type PrivateClient interface {
 	Read(ctx context.Context, in *GetRecordRequest, opts ...grpc.CallOption) (*GetRecordResponse, error)
 	Update(ctx context.Context, in *UpdateRecordRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
 	Delete(ctx context.Context, in *DeleteRecordRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	ListVersions(ctx context.Context, in *ListVersionsRequest, opts ...grpc.CallOption) (*ListVersionsResponse, error)
+	GetVersion(ctx context.Context, in *GetVersionRequest, opts ...grpc.CallOption) (*Version, error)
+	ListTrash(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListTrashResponse, error)
+	RestoreTrash(ctx context.Context, in *RestoreTrashRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	EmptyTrash(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	Changes(ctx context.Context, in *ChangesRequest, opts ...grpc.CallOption) (*ChangesResponse, error)
+	// Watch streams changes of records of user as they happen, changes are sent without item.
+	Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Change], error)
+	UploadBin(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UploadBinRequest, UploadBinResponse], error)
+	UploadBinStatus(ctx context.Context, in *UploadBinStatusRequest, opts ...grpc.CallOption) (*UploadBinStatusResponse, error)
+	DownloadBin(ctx context.Context, in *DownloadBinRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[DownloadBinResponse], error)
+	ListDevices(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListDevicesResponse, error)
+	ApproveDevice(ctx context.Context, in *ApproveDeviceRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	RemoveDevice(ctx context.Context, in *RemoveDeviceRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	Renew(ctx context.Context, in *RenewRequest, opts ...grpc.CallOption) (*RenewResponse, error)
 }
 
 type privateClient struct {
@@ -210,6 +353,167 @@ func (c *privateClient) Delete(ctx context.Context, in *DeleteRecordRequest, opt
 	return out, nil
 }
 
+func (c *privateClient) ListVersions(ctx context.Context, in *ListVersionsRequest, opts ...grpc.CallOption) (*ListVersionsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListVersionsResponse)
+	err := c.cc.Invoke(ctx, Private_ListVersions_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *privateClient) GetVersion(ctx context.Context, in *GetVersionRequest, opts ...grpc.CallOption) (*Version, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(Version)
+	err := c.cc.Invoke(ctx, Private_GetVersion_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *privateClient) ListTrash(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListTrashResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListTrashResponse)
+	err := c.cc.Invoke(ctx, Private_ListTrash_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *privateClient) RestoreTrash(ctx context.Context, in *RestoreTrashRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Private_RestoreTrash_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *privateClient) EmptyTrash(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Private_EmptyTrash_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *privateClient) Changes(ctx context.Context, in *ChangesRequest, opts ...grpc.CallOption) (*ChangesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ChangesResponse)
+	err := c.cc.Invoke(ctx, Private_Changes_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *privateClient) Watch(ctx context.Context, in *WatchRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[Change], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Private_ServiceDesc.Streams[0], Private_Watch_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[WatchRequest, Change]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Private_WatchClient = grpc.ServerStreamingClient[Change]
+
+func (c *privateClient) UploadBin(ctx context.Context, opts ...grpc.CallOption) (grpc.ClientStreamingClient[UploadBinRequest, UploadBinResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Private_ServiceDesc.Streams[1], Private_UploadBin_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[UploadBinRequest, UploadBinResponse]{ClientStream: stream}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Private_UploadBinClient = grpc.ClientStreamingClient[UploadBinRequest, UploadBinResponse]
+
+func (c *privateClient) UploadBinStatus(ctx context.Context, in *UploadBinStatusRequest, opts ...grpc.CallOption) (*UploadBinStatusResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UploadBinStatusResponse)
+	err := c.cc.Invoke(ctx, Private_UploadBinStatus_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *privateClient) DownloadBin(ctx context.Context, in *DownloadBinRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[DownloadBinResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &Private_ServiceDesc.Streams[2], Private_DownloadBin_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[DownloadBinRequest, DownloadBinResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Private_DownloadBinClient = grpc.ServerStreamingClient[DownloadBinResponse]
+
+func (c *privateClient) ListDevices(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*ListDevicesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListDevicesResponse)
+	err := c.cc.Invoke(ctx, Private_ListDevices_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *privateClient) ApproveDevice(ctx context.Context, in *ApproveDeviceRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Private_ApproveDevice_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *privateClient) RemoveDevice(ctx context.Context, in *RemoveDeviceRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Private_RemoveDevice_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *privateClient) Renew(ctx context.Context, in *RenewRequest, opts ...grpc.CallOption) (*RenewResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RenewResponse)
+	err := c.cc.Invoke(ctx, Private_Renew_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // PrivateServer is the server API for Private service.
 // All implementations must embed UnimplementedPrivateServer
 // for forward compatibility.
@@ -220,6 +524,21 @@ type PrivateServer interface {
 	Read(context.Context, *GetRecordRequest) (*GetRecordResponse, error)
 	Update(context.Context, *UpdateRecordRequest) (*emptypb.Empty, error)
 	Delete(context.Context, *DeleteRecordRequest) (*emptypb.Empty, error)
+	ListVersions(context.Context, *ListVersionsRequest) (*ListVersionsResponse, error)
+	GetVersion(context.Context, *GetVersionRequest) (*Version, error)
+	ListTrash(context.Context, *emptypb.Empty) (*ListTrashResponse, error)
+	RestoreTrash(context.Context, *RestoreTrashRequest) (*emptypb.Empty, error)
+	EmptyTrash(context.Context, *emptypb.Empty) (*emptypb.Empty, error)
+	Changes(context.Context, *ChangesRequest) (*ChangesResponse, error)
+	// Watch streams changes of records of user as they happen, changes are sent without item.
+	Watch(*WatchRequest, grpc.ServerStreamingServer[Change]) error
+	UploadBin(grpc.ClientStreamingServer[UploadBinRequest, UploadBinResponse]) error
+	UploadBinStatus(context.Context, *UploadBinStatusRequest) (*UploadBinStatusResponse, error)
+	DownloadBin(*DownloadBinRequest, grpc.ServerStreamingServer[DownloadBinResponse]) error
+	ListDevices(context.Context, *emptypb.Empty) (*ListDevicesResponse, error)
+	ApproveDevice(context.Context, *ApproveDeviceRequest) (*emptypb.Empty, error)
+	RemoveDevice(context.Context, *RemoveDeviceRequest) (*emptypb.Empty, error)
+	Renew(context.Context, *RenewRequest) (*RenewResponse, error)
 	mustEmbedUnimplementedPrivateServer()
 }
 
@@ -248,6 +567,48 @@ func (UnimplementedPrivateServer) Update(context.Context, *UpdateRecordRequest)
 func (UnimplementedPrivateServer) Delete(context.Context, *DeleteRecordRequest) (*emptypb.Empty, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
 }
+func (UnimplementedPrivateServer) ListVersions(context.Context, *ListVersionsRequest) (*ListVersionsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListVersions not implemented")
+}
+func (UnimplementedPrivateServer) GetVersion(context.Context, *GetVersionRequest) (*Version, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetVersion not implemented")
+}
+func (UnimplementedPrivateServer) ListTrash(context.Context, *emptypb.Empty) (*ListTrashResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListTrash not implemented")
+}
+func (UnimplementedPrivateServer) RestoreTrash(context.Context, *RestoreTrashRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RestoreTrash not implemented")
+}
+func (UnimplementedPrivateServer) EmptyTrash(context.Context, *emptypb.Empty) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method EmptyTrash not implemented")
+}
+func (UnimplementedPrivateServer) Changes(context.Context, *ChangesRequest) (*ChangesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Changes not implemented")
+}
+func (UnimplementedPrivateServer) Watch(*WatchRequest, grpc.ServerStreamingServer[Change]) error {
+	return status.Errorf(codes.Unimplemented, "method Watch not implemented")
+}
+func (UnimplementedPrivateServer) UploadBin(grpc.ClientStreamingServer[UploadBinRequest, UploadBinResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method UploadBin not implemented")
+}
+func (UnimplementedPrivateServer) UploadBinStatus(context.Context, *UploadBinStatusRequest) (*UploadBinStatusResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UploadBinStatus not implemented")
+}
+func (UnimplementedPrivateServer) DownloadBin(*DownloadBinRequest, grpc.ServerStreamingServer[DownloadBinResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method DownloadBin not implemented")
+}
+func (UnimplementedPrivateServer) ListDevices(context.Context, *emptypb.Empty) (*ListDevicesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListDevices not implemented")
+}
+func (UnimplementedPrivateServer) ApproveDevice(context.Context, *ApproveDeviceRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ApproveDevice not implemented")
+}
+func (UnimplementedPrivateServer) RemoveDevice(context.Context, *RemoveDeviceRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method RemoveDevice not implemented")
+}
+func (UnimplementedPrivateServer) Renew(context.Context, *RenewRequest) (*RenewResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Renew not implemented")
+}
 func (UnimplementedPrivateServer) mustEmbedUnimplementedPrivateServer() {}
 func (UnimplementedPrivateServer) testEmbeddedByValue()                 {}
 
@@ -377,36 +738,619 @@ func _Private_Delete_Handler(srv interface{}, ctx context.Context, dec func(inte
 	return interceptor(ctx, in, info, handler)
 }
 
-// Private_ServiceDesc is the grpc.ServiceDesc for Private service.
-// It's only intended for direct use with grpc.RegisterService,
-// and not to be introspected or modified (even as a copy)
-var Private_ServiceDesc = grpc.ServiceDesc{
-	ServiceName: "gophkeeper.Private",
-	HandlerType: (*PrivateServer)(nil),
-	Methods: []grpc.MethodDesc{
-		{
-			MethodName: "ListAll",
-			Handler:    _Private_ListAll_Handler,
-		},
-		{
-			MethodName: "List",
-			Handler:    _Private_List_Handler,
-		},
-		{
-			MethodName: "Create",
-			Handler:    _Private_Create_Handler,
-		},
-		{
-			MethodName: "Read",
-			Handler:    _Private_Read_Handler,
-		},
-		{
-			MethodName: "Update",
-			Handler:    _Private_Update_Handler,
-		},
-		{
-			MethodName: "Delete",
-			Handler:    _Private_Delete_Handler,
+func _Private_ListVersions_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListVersionsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PrivateServer).ListVersions(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Private_ListVersions_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PrivateServer).ListVersions(ctx, req.(*ListVersionsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Private_GetVersion_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetVersionRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PrivateServer).GetVersion(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Private_GetVersion_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PrivateServer).GetVersion(ctx, req.(*GetVersionRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Private_ListTrash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PrivateServer).ListTrash(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Private_ListTrash_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PrivateServer).ListTrash(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Private_RestoreTrash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RestoreTrashRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PrivateServer).RestoreTrash(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Private_RestoreTrash_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PrivateServer).RestoreTrash(ctx, req.(*RestoreTrashRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Private_EmptyTrash_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PrivateServer).EmptyTrash(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Private_EmptyTrash_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PrivateServer).EmptyTrash(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Private_Changes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ChangesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PrivateServer).Changes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Private_Changes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PrivateServer).Changes(ctx, req.(*ChangesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Private_Watch_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PrivateServer).Watch(m, &grpc.GenericServerStream[WatchRequest, Change]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Private_WatchServer = grpc.ServerStreamingServer[Change]
+
+func _Private_UploadBin_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(PrivateServer).UploadBin(&grpc.GenericServerStream[UploadBinRequest, UploadBinResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Private_UploadBinServer = grpc.ClientStreamingServer[UploadBinRequest, UploadBinResponse]
+
+func _Private_UploadBinStatus_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UploadBinStatusRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PrivateServer).UploadBinStatus(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Private_UploadBinStatus_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PrivateServer).UploadBinStatus(ctx, req.(*UploadBinStatusRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Private_DownloadBin_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(DownloadBinRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PrivateServer).DownloadBin(m, &grpc.GenericServerStream[DownloadBinRequest, DownloadBinResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type Private_DownloadBinServer = grpc.ServerStreamingServer[DownloadBinResponse]
+
+func _Private_ListDevices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PrivateServer).ListDevices(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Private_ListDevices_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PrivateServer).ListDevices(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Private_ApproveDevice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ApproveDeviceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PrivateServer).ApproveDevice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Private_ApproveDevice_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PrivateServer).ApproveDevice(ctx, req.(*ApproveDeviceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Private_RemoveDevice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RemoveDeviceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PrivateServer).RemoveDevice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Private_RemoveDevice_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PrivateServer).RemoveDevice(ctx, req.(*RemoveDeviceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Private_Renew_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RenewRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PrivateServer).Renew(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Private_Renew_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PrivateServer).Renew(ctx, req.(*RenewRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Private_ServiceDesc is the grpc.ServiceDesc for Private service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Private_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "gophkeeper.Private",
+	HandlerType: (*PrivateServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListAll",
+			Handler:    _Private_ListAll_Handler,
+		},
+		{
+			MethodName: "List",
+			Handler:    _Private_List_Handler,
+		},
+		{
+			MethodName: "Create",
+			Handler:    _Private_Create_Handler,
+		},
+		{
+			MethodName: "Read",
+			Handler:    _Private_Read_Handler,
+		},
+		{
+			MethodName: "Update",
+			Handler:    _Private_Update_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _Private_Delete_Handler,
+		},
+		{
+			MethodName: "ListVersions",
+			Handler:    _Private_ListVersions_Handler,
+		},
+		{
+			MethodName: "GetVersion",
+			Handler:    _Private_GetVersion_Handler,
+		},
+		{
+			MethodName: "ListTrash",
+			Handler:    _Private_ListTrash_Handler,
+		},
+		{
+			MethodName: "RestoreTrash",
+			Handler:    _Private_RestoreTrash_Handler,
+		},
+		{
+			MethodName: "EmptyTrash",
+			Handler:    _Private_EmptyTrash_Handler,
+		},
+		{
+			MethodName: "Changes",
+			Handler:    _Private_Changes_Handler,
+		},
+		{
+			MethodName: "UploadBinStatus",
+			Handler:    _Private_UploadBinStatus_Handler,
+		},
+		{
+			MethodName: "ListDevices",
+			Handler:    _Private_ListDevices_Handler,
+		},
+		{
+			MethodName: "ApproveDevice",
+			Handler:    _Private_ApproveDevice_Handler,
+		},
+		{
+			MethodName: "RemoveDevice",
+			Handler:    _Private_RemoveDevice_Handler,
+		},
+		{
+			MethodName: "Renew",
+			Handler:    _Private_Renew_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Watch",
+			Handler:       _Private_Watch_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "UploadBin",
+			Handler:       _Private_UploadBin_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "DownloadBin",
+			Handler:       _Private_DownloadBin_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "proto/gophkeeper.proto",
+}
+
+const (
+	Admin_ListUsers_FullMethodName        = "/gophkeeper.Admin/ListUsers"
+	Admin_GetUser_FullMethodName          = "/gophkeeper.Admin/GetUser"
+	Admin_DeleteUser_FullMethodName       = "/gophkeeper.Admin/DeleteUser"
+	Admin_DisableUser_FullMethodName      = "/gophkeeper.Admin/DisableUser"
+	Admin_Stats_FullMethodName            = "/gophkeeper.Admin/Stats"
+	Admin_ListCertificates_FullMethodName = "/gophkeeper.Admin/ListCertificates"
+)
+
+// AdminClient is the client API for Admin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// Admin is served on local unix socket of running server.
+type AdminClient interface {
+	ListUsers(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*AdminListUsersResponse, error)
+	GetUser(ctx context.Context, in *AdminUserRequest, opts ...grpc.CallOption) (*AdminUser, error)
+	DeleteUser(ctx context.Context, in *AdminUserRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	DisableUser(ctx context.Context, in *AdminDisableUserRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+	Stats(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*AdminStatsResponse, error)
+	ListCertificates(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*AdminListCertificatesResponse, error)
+}
+
+type adminClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAdminClient(cc grpc.ClientConnInterface) AdminClient {
+	return &adminClient{cc}
+}
+
+func (c *adminClient) ListUsers(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*AdminListUsersResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AdminListUsersResponse)
+	err := c.cc.Invoke(ctx, Admin_ListUsers_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) GetUser(ctx context.Context, in *AdminUserRequest, opts ...grpc.CallOption) (*AdminUser, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AdminUser)
+	err := c.cc.Invoke(ctx, Admin_GetUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) DeleteUser(ctx context.Context, in *AdminUserRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Admin_DeleteUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) DisableUser(ctx context.Context, in *AdminDisableUserRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, Admin_DisableUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) Stats(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*AdminStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AdminStatsResponse)
+	err := c.cc.Invoke(ctx, Admin_Stats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *adminClient) ListCertificates(ctx context.Context, in *emptypb.Empty, opts ...grpc.CallOption) (*AdminListCertificatesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AdminListCertificatesResponse)
+	err := c.cc.Invoke(ctx, Admin_ListCertificates_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AdminServer is the server API for Admin service.
+// All implementations must embed UnimplementedAdminServer
+// for forward compatibility.
+//
+// Admin is served on local unix socket of running server.
+type AdminServer interface {
+	ListUsers(context.Context, *emptypb.Empty) (*AdminListUsersResponse, error)
+	GetUser(context.Context, *AdminUserRequest) (*AdminUser, error)
+	DeleteUser(context.Context, *AdminUserRequest) (*emptypb.Empty, error)
+	DisableUser(context.Context, *AdminDisableUserRequest) (*emptypb.Empty, error)
+	Stats(context.Context, *emptypb.Empty) (*AdminStatsResponse, error)
+	ListCertificates(context.Context, *emptypb.Empty) (*AdminListCertificatesResponse, error)
+	mustEmbedUnimplementedAdminServer()
+}
+
+// UnimplementedAdminServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAdminServer struct{}
+
+func (UnimplementedAdminServer) ListUsers(context.Context, *emptypb.Empty) (*AdminListUsersResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListUsers not implemented")
+}
+func (UnimplementedAdminServer) GetUser(context.Context, *AdminUserRequest) (*AdminUser, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUser not implemented")
+}
+func (UnimplementedAdminServer) DeleteUser(context.Context, *AdminUserRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteUser not implemented")
+}
+func (UnimplementedAdminServer) DisableUser(context.Context, *AdminDisableUserRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DisableUser not implemented")
+}
+func (UnimplementedAdminServer) Stats(context.Context, *emptypb.Empty) (*AdminStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Stats not implemented")
+}
+func (UnimplementedAdminServer) ListCertificates(context.Context, *emptypb.Empty) (*AdminListCertificatesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListCertificates not implemented")
+}
+func (UnimplementedAdminServer) mustEmbedUnimplementedAdminServer() {}
+func (UnimplementedAdminServer) testEmbeddedByValue()               {}
+
+// UnsafeAdminServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AdminServer will
+// result in compilation errors.
+type UnsafeAdminServer interface {
+	mustEmbedUnimplementedAdminServer()
+}
+
+func RegisterAdminServer(s grpc.ServiceRegistrar, srv AdminServer) {
+	// If the following call pancis, it indicates UnimplementedAdminServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&Admin_ServiceDesc, srv)
+}
+
+func _Admin_ListUsers_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ListUsers(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_ListUsers_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ListUsers(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_GetUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).GetUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_GetUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).GetUser(ctx, req.(*AdminUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_DeleteUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).DeleteUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_DeleteUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).DeleteUser(ctx, req.(*AdminUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_DisableUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminDisableUserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).DisableUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_DisableUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).DisableUser(ctx, req.(*AdminDisableUserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_Stats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).Stats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_Stats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).Stats(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _Admin_ListCertificates_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AdminServer).ListCertificates(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: Admin_ListCertificates_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AdminServer).ListCertificates(ctx, req.(*emptypb.Empty))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// Admin_ServiceDesc is the grpc.ServiceDesc for Admin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var Admin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "gophkeeper.Admin",
+	HandlerType: (*AdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListUsers",
+			Handler:    _Admin_ListUsers_Handler,
+		},
+		{
+			MethodName: "GetUser",
+			Handler:    _Admin_GetUser_Handler,
+		},
+		{
+			MethodName: "DeleteUser",
+			Handler:    _Admin_DeleteUser_Handler,
+		},
+		{
+			MethodName: "DisableUser",
+			Handler:    _Admin_DisableUser_Handler,
+		},
+		{
+			MethodName: "Stats",
+			Handler:    _Admin_Stats_Handler,
+		},
+		{
+			MethodName: "ListCertificates",
+			Handler:    _Admin_ListCertificates_Handler,
 		},
 	},
 	Streams:  []grpc.StreamDesc{},